@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/domain/services"
+	"radioking-app/internal/infrastructure/messaging"
+	"radioking-app/internal/infrastructure/repositories"
+)
+
+// command sous-commande d'administration, lancée à la place du serveur : radioking <nom> [args]
+type command struct {
+	name        string
+	description string
+	run         func(args []string) error
+}
+
+func commands() []command {
+	return []command{
+		{name: "rollups rebuild", description: "recalcule les compteurs de lecture depuis l'historique", run: rebuildRollups},
+		{name: "cuesheet export", description: "exporte le relevé des diffusions d'une période", run: exportCueSheet},
+		{name: "topology declare", description: "déclare la topologie RabbitMQ de la configuration", run: provisionTopology(messaging.TopologyDeclare)},
+		{name: "topology verify", description: "vérifie la topologie RabbitMQ sans la modifier", run: provisionTopology(messaging.TopologyVerify)},
+		{name: "events replay", description: "rejoue le journal des événements dans une projection", run: replayEvents},
+	}
+}
+
+// runCommand exécute la sous-commande désignée par args, retourne le code de sortie du processus
+func runCommand(args []string) int {
+	line := strings.Join(args, " ")
+	for _, cmd := range commands() {
+		if line == cmd.name || strings.HasPrefix(line, cmd.name+" ") {
+			if err := cmd.run(args[len(strings.Fields(cmd.name)):]); err != nil {
+				log.Printf("%s: %v", cmd.name, err)
+				return 1
+			}
+			return 0
+		}
+	}
+
+	log.Printf("Unknown command %q, available commands:", line)
+	for _, cmd := range commands() {
+		log.Printf("  %s\t%s", cmd.name, cmd.description)
+	}
+	return 2
+}
+
+func rebuildRollups(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("unexpected arguments %v", args)
+	}
+
+	dbInstance, err := initDb()
+	if err != nil {
+		return err
+	}
+
+	count, err := services.NewStatsService(repositories.NewStatsRepository(dbInstance)).RebuildRollups()
+	if err != nil {
+		return err
+	}
+	log.Printf("Rebuilt %d play rollups", count)
+	return nil
+}
+
+// exportCueSheet écrit le relevé des diffusions sur la sortie standard ou dans le fichier -output
+func exportCueSheet(args []string) error {
+	flags := flag.NewFlagSet("cuesheet export", flag.ContinueOnError)
+	from := flags.String("from", "", "début de la période (AAAA-MM-JJ ou RFC 3339)")
+	to := flags.String("to", "", "fin de la période, une date est incluse")
+	timezone := flags.String("tz", "", "fuseau IANA des dates et des horaires")
+	format := flags.String("format", string(models.CueSheetCSV), "format du relevé : csv ou fixed")
+	output := flags.String("output", "", "fichier de destination, sortie standard par défaut")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	dbInstance, err := initDb()
+	if err != nil {
+		return err
+	}
+
+	statsService := services.NewStatsService(repositories.NewStatsRepository(dbInstance))
+	cueSheetService := services.NewCueSheetService(repositories.NewCueSheetRepository(dbInstance))
+
+	period, err := statsService.ResolvePeriod(models.StatsQuery{From: *from, To: *to, Timezone: *timezone})
+	if err != nil {
+		return err
+	}
+	sheet, err := cueSheetService.CueSheet(period)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	if err := cueSheetService.Write(&body, sheet, models.CueSheetFormat(*format)); err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = body.WriteTo(os.Stdout)
+	} else {
+		err = os.WriteFile(*output, body.Bytes(), 0o644)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write cue sheet: %w", err)
+	}
+
+	for _, entry := range sheet.Entries {
+		if entry.IsFlagged() {
+			log.Printf("Missing %s for track %d played at %s", strings.Join(entry.Missing, ", "), entry.TrackID, entry.PlayedAt.Format("2006-01-02 15:04:05"))
+		}
+	}
+	log.Printf("Exported %d plays, %d flagged", len(sheet.Entries), sheet.Flagged)
+	return nil
+}
+
+// provisionTopology déclare ou vérifie la topologie selon mode, quel que soit le mode de la configuration
+func provisionTopology(mode string) func(args []string) error {
+	return func(args []string) error {
+		if len(args) > 0 {
+			return fmt.Errorf("unexpected arguments %v", args)
+		}
+		return messaging.ProvisionTopology(loadConfiguration().Messaging.RabbitMQ, mode)
+	}
+}
+
+// replayEvents rejoue dans la projection args[0] les événements du journal d'une période :
+// radioking events replay track-plays [-from] [-to] [-dry-run]
+func replayEvents(args []string) error {
+	dbInstance, err := initDb()
+	if err != nil {
+		return err
+	}
+
+	trackPlays := services.NewTrackPlayConsumerService(nil, services.NewTrackPlayService(repositories.NewTrackPlayRepository(dbInstance)))
+	replayService := services.NewEventReplayService(repositories.NewEventStoreRepository(dbInstance), trackPlays)
+
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("missing projection, available projections: %s", strings.Join(replayService.Projections(), ", "))
+	}
+
+	flags := flag.NewFlagSet("events replay", flag.ContinueOnError)
+	from := flags.String("from", "", "début de la période (AAAA-MM-JJ ou RFC 3339, UTC), début du journal par défaut")
+	to := flags.String("to", "", "fin de la période, une date est incluse ; fin du journal par défaut")
+	dryRun := flags.Bool("dry-run", false, "lit les événements sans les appliquer")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments %v", flags.Args())
+	}
+
+	result, err := replayService.Replay(models.ReplayQuery{Projection: args[0], From: *from, To: *to, DryRun: *dryRun})
+	if err != nil {
+		return err
+	}
+
+	if result.DryRun {
+		log.Printf("Dry run: %d events would be replayed into %s, %d unreadable", result.Events, result.Projection, result.Failed)
+	} else {
+		log.Printf("Replayed %d events into %s: %d applied, %d failed", result.Events, result.Projection, result.Applied, result.Failed)
+	}
+	if stats := trackPlays.Stats(); result.Projection == trackPlays.Name() && !result.DryRun {
+		log.Printf("Track plays: %d plays and %d ends recorded, %d plays and %d ends already recorded",
+			stats.PlayedEvents, stats.EndedEvents, stats.DuplicatePlayedEvents, stats.DuplicateEndedEvents)
+	}
+	if result.Failed > 0 {
+		return fmt.Errorf("%d events could not be replayed", result.Failed)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -24,32 +25,72 @@ import (
 )
 
 func main() {
+	// Administration subcommands run instead of the server
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
 	cfg := loadConfiguration()
+	stationLocation := loadStationLocation(cfg)
 
 	dbInstance, err := initDb()
 
 	// Initialize messaging
-	publisher, consumer, err := initMessaging(cfg)
+	publisher, consumer, deadLetterQueue, err := initMessaging(cfg)
 	if err != nil {
 		panic(err)
 	}
 	defer publisher.Close()
 	defer consumer.Close()
+	defer deadLetterQueue.Close()
 
 	// Initialize repositories
 	playlistRepo := repositories.NewPlaylistRepository(dbInstance)
+	trackRepo := repositories.NewTrackRepository(dbInstance)
+	artistRepo := repositories.NewArtistRepository(dbInstance)
+	albumRepo := repositories.NewAlbumRepository(dbInstance)
 	trackPlayRepo := repositories.NewTrackPlayRepository(dbInstance)
+	playSessionRepo := repositories.NewPlaySessionRepository(dbInstance)
+	clockRepo := repositories.NewClockRepository(dbInstance)
+	gridRepo := repositories.NewGridRepository(dbInstance)
+	rotationRuleRepo := repositories.NewRotationRuleRepository(dbInstance)
+	statsRepo := repositories.NewStatsRepository(dbInstance)
+	cueSheetRepo := repositories.NewCueSheetRepository(dbInstance)
+	outboxRepo := repositories.NewOutboxRepository(dbInstance)
 
 	// Initialize services
-	playlistService := &services.PlaylistService{Repo: playlistRepo}
-	playlistPlayService := services.NewPlaylistPlayService(playlistService, publisher)
+	outboxService := services.NewOutboxService(outboxRepo)
+	playlistService := &services.PlaylistService{Repo: playlistRepo, RecordEvents: true}
+	trackService := services.NewTrackService(trackRepo)
+	trackService.RecordEvents = true
+	artistService := services.NewArtistService(artistRepo, albumRepo)
+	rotationService := services.NewRotationService(rotationRuleRepo, trackPlayRepo, playlistService)
+	playbackEngine := services.NewPlaybackEngine(playSessionRepo, rotationService)
+	playlistPlayService := services.NewPlaylistPlayService(playlistService, playbackEngine)
 	trackPlayService := services.NewTrackPlayService(trackPlayRepo)
+	statsService := services.NewStatsService(statsRepo)
+	deadLetterService := services.NewDeadLetterService(deadLetterQueue)
+	eventSchemaService := services.NewEventSchemaService()
+	cueSheetService := services.NewCueSheetService(cueSheetRepo)
+	clockService := services.NewClockService(clockRepo, gridRepo, playlistService, trackRepo)
+	scheduleService := services.NewScheduleService(gridRepo, playlistService, trackRepo, rotationService, stationLocation)
+	nowPlayingService := services.NewNowPlayingService(playbackEngine, playSessionRepo)
+	scheduler := services.NewScheduler(scheduleService, playlistPlayService, playbackEngine, playSessionRepo, stationLocation)
 
 	// Initialize application service
 	playlistApplicationService := services.NewPlaylistApplicationService(playlistService, playlistPlayService)
 
+	// Initialize studio console service, notified of every session change
+	studioService := services.NewStudioService(playbackEngine, playlistApplicationService, trackService, playSessionRepo)
+	playbackEngine.AddListener(studioService)
+
+	// Initialize outbox relay, woken up by every session change
+	outboxRelay := services.NewOutboxRelay(outboxRepo, publisher, cfg.Messaging.Outbox.PollInterval, cfg.Messaging.Outbox.Retention)
+	playbackEngine.AddListener(outboxRelay)
+
 	// Initialize consumer service
 	consumerService := services.NewTrackPlayConsumerService(consumer, trackPlayService)
+	consumerService.AddListener(nowPlayingService)
 
 	// Start consumer service
 	ctx, cancel := context.WithCancel(context.Background())
@@ -60,6 +101,17 @@ func main() {
 	}
 	defer consumerService.Stop()
 
+	// Publish the events recorded in the outbox
+	outboxRelay.Start(ctx)
+
+	// Resume play sessions interrupted by the last shutdown
+	if err := playbackEngine.Start(ctx); err != nil {
+		log.Printf("Failed to resume play sessions: %v", err)
+	}
+
+	// Air the clocks of the weekly grid at the start of each hour
+	scheduler.Start(ctx)
+
 	// Initialize HTTP router
 	router := chi.NewRouter()
 	router.Use(middleware.Logger)
@@ -74,11 +126,41 @@ func main() {
 	// Initialize handlers
 	handler := handlers.NewPlaylistHandler(playlistService, playlistApplicationService)
 	handler.Routes(router)
+	trackHandler := handlers.NewTrackHandler(trackService)
+	trackHandler.Routes(router)
+	artistHandler := handlers.NewArtistHandler(artistService)
+	artistHandler.Routes(router)
+	sessionHandler := handlers.NewSessionHandler(playbackEngine)
+	sessionHandler.Routes(router)
+	clockHandler := handlers.NewClockHandler(clockService)
+	clockHandler.Routes(router)
+	scheduleHandler := handlers.NewScheduleHandler(scheduleService)
+	scheduleHandler.Routes(router)
+	rotationHandler := handlers.NewRotationHandler(rotationService, scheduleService)
+	rotationHandler.Routes(router)
+	nowPlayingHandler := handlers.NewNowPlayingHandler(nowPlayingService)
+	nowPlayingHandler.Routes(router)
+	studioHandler := handlers.NewStudioHandler(studioService, cfg.Server.AllowedOrigins)
+	studioHandler.Routes(router)
+	statsHandler := handlers.NewStatsHandler(statsService, trackPlayService)
+	statsHandler.Routes(router)
+	cueSheetHandler := handlers.NewCueSheetHandler(statsService, cueSheetService)
+	cueSheetHandler.Routes(router)
+	consumerHandler := handlers.NewConsumerHandler(consumerService)
+	consumerHandler.Routes(router)
+	deadLetterHandler := handlers.NewDeadLetterHandler(deadLetterService)
+	deadLetterHandler.Routes(router)
+	outboxHandler := handlers.NewOutboxHandler(outboxService)
+	outboxHandler.Routes(router)
+	eventSchemaHandler := handlers.NewEventSchemaHandler(eventSchemaService)
+	eventSchemaHandler.Routes(router)
 
 	// Setup graceful shutdown
+	// Requests inherit ctx so that long-lived streams end on shutdown
 	server := &http.Server{
-		Addr:    fmt.Sprintf(":%s", cfg.Server.Port),
-		Handler: router,
+		Addr:        fmt.Sprintf(":%s", cfg.Server.Port),
+		Handler:     router,
+		BaseContext: func(net.Listener) context.Context { return ctx },
 	}
 
 	// Start server in goroutine
@@ -95,6 +177,9 @@ func main() {
 
 	log.Println("Shutting down server...")
 	cancel()
+	scheduler.Wait()
+	playbackEngine.Wait()
+	outboxRelay.Wait()
 
 	// Shutdown server with timeout
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -130,21 +215,45 @@ func loadConfiguration() *config.Config {
 	return cfg
 }
 
-func initMessaging(cfg *config.Config) (messaging.MessagePublisher, messaging.MessageConsumer, error) {
+func loadStationLocation(cfg *config.Config) *time.Location {
+	location, err := time.LoadLocation(cfg.Station.Timezone)
+	if err != nil {
+		panic(fmt.Errorf("failed to load station timezone: %w", err))
+	}
+	return location
+}
+
+func initMessaging(cfg *config.Config) (messaging.MessagePublisher, messaging.MessageConsumer, messaging.DeadLetterQueue, error) {
+	switch cfg.Messaging.Driver {
+	case messaging.DriverMemory:
+		bus := messaging.NewMemoryBus(cfg.Messaging.RabbitMQ)
+		return bus, bus, bus, nil
+	case messaging.DriverRabbitMQ, "":
+	default:
+		return nil, nil, nil, fmt.Errorf("unknown messaging driver %q", cfg.Messaging.Driver)
+	}
+
 	if err := messaging.InitRabbitMQInfrastructure(cfg.Messaging.RabbitMQ); err != nil {
-		return nil, nil, fmt.Errorf("failed to initialize RabbitMQ infrastructure: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to initialize RabbitMQ infrastructure: %w", err)
 	}
 
 	publisher, err := messaging.NewRabbitMQPublisher(cfg.Messaging.RabbitMQ)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to initialize publisher: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to initialize publisher: %w", err)
 	}
 
 	consumer, err := messaging.NewRabbitMQConsumer(cfg.Messaging.RabbitMQ)
 	if err != nil {
 		publisher.Close()
-		return nil, nil, fmt.Errorf("failed to initialize consumer: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to initialize consumer: %w", err)
+	}
+
+	deadLetterQueue, err := messaging.NewRabbitMQDeadLetterQueue(cfg.Messaging.RabbitMQ)
+	if err != nil {
+		publisher.Close()
+		consumer.Close()
+		return nil, nil, nil, fmt.Errorf("failed to initialize dead letter queue: %w", err)
 	}
 
-	return publisher, consumer, nil
+	return publisher, consumer, deadLetterQueue, nil
 }
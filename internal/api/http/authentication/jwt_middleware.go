@@ -48,6 +48,11 @@ const (
 	bearerPrefix         = "Bearer "
 )
 
+// WebSocketBearerProtocol les navigateurs ne peuvent pas envoyer d'en-tête Authorization à l'ouverture
+// d'une WebSocket : le jeton suit ce sous-protocole dans Sec-WebSocket-Protocol, hors de l'URL et donc
+// des journaux d'accès
+const WebSocketBearerProtocol = "bearer"
+
 // NewJWTMiddleware creates a new JWT middleware instance
 func NewJWTMiddleware(keycloakURL, realm string) *JWTMiddleware {
 	return &JWTMiddleware{
@@ -143,6 +148,11 @@ func (j *JWTMiddleware) Middleware() func(http.Handler) http.Handler {
 
 func (j *JWTMiddleware) extractBearerToken(r *http.Request) (string, error) {
 	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" && isWebSocketUpgrade(r) {
+		if token := webSocketProtocolToken(r); token != "" {
+			return token, nil
+		}
+	}
 	if authHeader == "" {
 		return "", fmt.Errorf("missing authorization header")
 	}
@@ -195,6 +205,27 @@ func (j *JWTMiddleware) getSigningKey(token *jwt.Token) (interface{}, error) {
 	return publicKey, nil
 }
 
+// isWebSocketUpgrade indique une demande d'ouverture de WebSocket
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// webSocketProtocolToken jeton proposé après WebSocketBearerProtocol dans Sec-WebSocket-Protocol
+func webSocketProtocolToken(r *http.Request) string {
+	var protocols []string
+	for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, protocol := range strings.Split(header, ",") {
+			protocols = append(protocols, strings.TrimSpace(protocol))
+		}
+	}
+	for i, protocol := range protocols[:max(len(protocols)-1, 0)] {
+		if protocol == WebSocketBearerProtocol {
+			return protocols[i+1]
+		}
+	}
+	return ""
+}
+
 // GetUserClaims extracts user claims from request context
 func GetUserClaims(r *http.Request) (*KeycloakClaims, bool) {
 	claims, ok := r.Context().Value(userClaimsContextKey).(*KeycloakClaims)
@@ -0,0 +1,45 @@
+package authentication
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractBearerToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    string
+		headers   map[string]string
+		wantToken string
+		wantErr   bool
+	}{
+		{name: "authorization header", target: "/playlists", headers: map[string]string{"Authorization": "Bearer abc"}, wantToken: "abc"},
+		{name: "websocket protocol", target: "/studio/ws", headers: map[string]string{"Upgrade": "websocket", "Sec-WebSocket-Protocol": "bearer, abc.def"}, wantToken: "abc.def"},
+		{name: "websocket protocol without token", target: "/studio/ws", headers: map[string]string{"Upgrade": "websocket", "Sec-WebSocket-Protocol": "bearer"}, wantErr: true},
+		{name: "query parameter ignored", target: "/studio/ws?access_token=abc", headers: map[string]string{"Upgrade": "websocket"}, wantErr: true},
+		{name: "protocol outside websocket", target: "/playlists", headers: map[string]string{"Sec-WebSocket-Protocol": "bearer, abc"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			r := httptest.NewRequest("GET", tt.target, nil)
+			for name, value := range tt.headers {
+				r.Header.Set(name, value)
+			}
+
+			// Act
+			token, err := NewJWTMiddleware("", "").extractBearerToken(r)
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantToken, token)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package beans
+
+type ArtistRefApiBean struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type AlbumRefApiBean struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
+
+type PlaylistRefApiBean struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type ArtistResponseApiBean struct {
+	ID        int64                         `json:"id"`
+	Name      string                        `json:"name"`
+	Albums    []AlbumRefApiBean             `json:"albums"`
+	Tracks    []CatalogTrackResponseApiBean `json:"tracks"`
+	Playlists []PlaylistRefApiBean          `json:"playlists"`
+}
+
+type AlbumResponseApiBean struct {
+	ID        int64                         `json:"id"`
+	Title     string                        `json:"title"`
+	Artist    ArtistRefApiBean              `json:"artist"`
+	Tracks    []CatalogTrackResponseApiBean `json:"tracks"`
+	Playlists []PlaylistRefApiBean          `json:"playlists"`
+}
+
+type ArtistListResponse struct {
+	Data []ArtistRefApiBean `json:"data"`
+}
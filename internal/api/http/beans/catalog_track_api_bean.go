@@ -0,0 +1,29 @@
+package beans
+
+import "time"
+
+type CatalogTrackResponseApiBean struct {
+	ID              int64            `json:"id"`
+	Title           string           `json:"title"`
+	Artist          string           `json:"artist"`
+	ArtistID        int64            `json:"artist_id"`
+	Album           *AlbumRefApiBean `json:"album,omitempty"`
+	DurationSeconds int              `json:"duration_seconds"`
+	ISRC            string           `json:"isrc,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+type CatalogTrackRequest struct {
+	Title           string `json:"title" validate:"required,min=1,max=255"`
+	Artist          string `json:"artist" validate:"required,min=1,max=255"`
+	Album           string `json:"album,omitempty" validate:"max=255"`
+	DurationSeconds int    `json:"duration_seconds,omitempty" validate:"omitempty,min=1,max=86400"`
+	ISRC            string `json:"isrc,omitempty" validate:"max=15"`
+}
+
+type TrackPageResponse struct {
+	Data       []CatalogTrackResponseApiBean `json:"data"`
+	Pagination PaginationApiBean             `json:"pagination"`
+	Links      PageLinksApiBean              `json:"links"`
+}
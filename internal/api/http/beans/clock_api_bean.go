@@ -0,0 +1,49 @@
+package beans
+
+import "time"
+
+type ClockSlotApiBean struct {
+	Type            string `json:"type" validate:"required,oneof=playlist jingle rule"`
+	PlaylistID      *int64 `json:"playlist_id,omitempty" validate:"omitempty,min=1"`
+	TrackID         *int64 `json:"track_id,omitempty" validate:"omitempty,min=1"`
+	ArtistID        *int64 `json:"artist_id,omitempty" validate:"omitempty,min=1"`
+	AlbumID         *int64 `json:"album_id,omitempty" validate:"omitempty,min=1"`
+	DurationSeconds int    `json:"duration_seconds,omitempty" validate:"min=0,max=3600"`
+	Shuffle         bool   `json:"shuffle,omitempty"`
+}
+
+type ClockRequest struct {
+	Name  string             `json:"name" validate:"required,min=1,max=255"`
+	Slots []ClockSlotApiBean `json:"slots" validate:"max=60,dive"`
+}
+
+type ClockResponseApiBean struct {
+	ID        int64              `json:"id"`
+	Name      string             `json:"name"`
+	Slots     []ClockSlotApiBean `json:"slots"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+type ClockListResponse struct {
+	Data []ClockResponseApiBean `json:"data"`
+}
+
+type ClockRefApiBean struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type GridAssignRequest struct {
+	ClockID int `json:"clock_id" validate:"required,min=1"`
+}
+
+type GridEntryApiBean struct {
+	Weekday int             `json:"weekday"`
+	Hour    int             `json:"hour"`
+	Clock   ClockRefApiBean `json:"clock"`
+}
+
+type GridResponse struct {
+	Data []GridEntryApiBean `json:"data"`
+}
@@ -0,0 +1,8 @@
+package beans
+
+type ConsumerStatsApiBean struct {
+	PlayedEvents          int64 `json:"played_events"`
+	EndedEvents           int64 `json:"ended_events"`
+	DuplicatePlayedEvents int64 `json:"duplicate_played_events"`
+	DuplicateEndedEvents  int64 `json:"duplicate_ended_events"`
+}
@@ -0,0 +1,35 @@
+package beans
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DeadLetterApiBean Payload contient le message s'il est du JSON valide, RawPayload sinon
+type DeadLetterApiBean struct {
+	MessageID  string          `json:"message_id"`
+	Queue      string          `json:"queue"`
+	RoutingKey string          `json:"routing_key"`
+	Reason     string          `json:"reason"`
+	RetryCount int             `json:"retry_count"`
+	FailedAt   *time.Time      `json:"failed_at,omitempty"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	RawPayload string          `json:"raw_payload,omitempty"`
+}
+
+type DeadLettersResponse struct {
+	DeadLetters []DeadLetterApiBean `json:"dead_letters"`
+}
+
+// DeadLetterReplayRequest sans identifiants, tous les messages écartés sont rejoués
+type DeadLetterReplayRequest struct {
+	IDs []string `json:"ids" validate:"dive,required"`
+}
+
+type DeadLetterReplayResponse struct {
+	Replayed int `json:"replayed"`
+}
+
+type DeadLetterPurgeResponse struct {
+	Purged int `json:"purged"`
+}
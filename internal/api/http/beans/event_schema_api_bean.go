@@ -0,0 +1,12 @@
+package beans
+
+type EventSchemaApiBean struct {
+	Type    string `json:"type"`
+	Version int    `json:"version"`
+	Current bool   `json:"current"`
+	URL     string `json:"url"`
+}
+
+type EventSchemasResponse struct {
+	Schemas []EventSchemaApiBean `json:"schemas"`
+}
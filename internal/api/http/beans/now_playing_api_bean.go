@@ -0,0 +1,15 @@
+package beans
+
+import "time"
+
+type NowPlayingResponseApiBean struct {
+	SessionID      int64                `json:"session_id"`
+	PlaylistID     int64                `json:"playlist_id"`
+	ClockID        *int64               `json:"clock_id,omitempty"`
+	Status         string               `json:"status"`
+	Current        *SessionTrackApiBean `json:"current,omitempty"`
+	StartedAt      *time.Time           `json:"started_at,omitempty"`
+	ElapsedSeconds int                  `json:"elapsed_seconds"`
+	Next           *SessionTrackApiBean `json:"next,omitempty"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+}
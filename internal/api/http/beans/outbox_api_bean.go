@@ -0,0 +1,35 @@
+package beans
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type OutboxMessageApiBean struct {
+	ID            int64           `json:"id"`
+	EventID       string          `json:"event_id"`
+	EventType     string          `json:"event_type"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	NextAttemptAt *time.Time      `json:"next_attempt_at,omitempty"` // Seulement pour les messages pending
+	SentAt        *time.Time      `json:"sent_at,omitempty"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+type OutboxMessagesResponse struct {
+	Messages []OutboxMessageApiBean `json:"messages"`
+}
+
+type OutboxStatsApiBean struct {
+	Pending         int64      `json:"pending"`
+	Sent            int64      `json:"sent"`
+	Failing         int64      `json:"failing"`
+	Failed          int64      `json:"failed"`
+	OldestPendingAt *time.Time `json:"oldest_pending_at,omitempty"`
+}
+
+type OutboxCleanupResponse struct {
+	Deleted int64 `json:"deleted"`
+}
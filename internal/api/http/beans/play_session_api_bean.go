@@ -0,0 +1,25 @@
+package beans
+
+import "time"
+
+type SessionTrackApiBean struct {
+	ID              int64  `json:"id"`
+	Title           string `json:"title"`
+	Artist          string `json:"artist"`
+	Position        int    `json:"position"`
+	DurationSeconds int    `json:"duration_seconds"`
+}
+
+type PlaySessionResponseApiBean struct {
+	ID             int64                `json:"id"`
+	PlaylistID     int64                `json:"playlist_id"`
+	ClockID        *int64               `json:"clock_id,omitempty"`
+	Status         string               `json:"status"`
+	CurrentTrack   *SessionTrackApiBean `json:"current_track,omitempty"`
+	CurrentIndex   int                  `json:"current_index"`
+	TracksCount    int                  `json:"tracks_count"`
+	ElapsedSeconds int                  `json:"elapsed_seconds"`
+	TrackStartedAt *time.Time           `json:"track_started_at,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+}
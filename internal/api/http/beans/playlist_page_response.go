@@ -0,0 +1,29 @@
+package beans
+
+import "time"
+
+type PlaylistSummaryApiBean struct {
+	ID          int64                  `json:"id"`
+	Name        string                 `json:"name"`
+	TracksCount int                    `json:"tracks_count"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+	Tracks      []TrackResponseApiBean `json:"tracks,omitempty"`
+}
+
+type PaginationApiBean struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+type PageLinksApiBean struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+}
+
+type PlaylistPageResponse struct {
+	Data       []PlaylistSummaryApiBean `json:"data"`
+	Pagination PaginationApiBean        `json:"pagination"`
+	Links      PageLinksApiBean         `json:"links"`
+}
@@ -1,7 +1,19 @@
 package beans
 
 type PlaylistPlayResponse struct {
-	Message     string `json:"message"`
-	PlaylistID  int    `json:"playlist_id"`
-	TracksCount int    `json:"tracks_count"`
+	Message     string  `json:"message"`
+	PlaylistID  int     `json:"playlist_id"`
+	SessionID   int64   `json:"session_id"`
+	TracksCount int     `json:"tracks_count"`
+	Shuffle     string  `json:"shuffle"`
+	Repeat      string  `json:"repeat"`
+	Seed        *int64  `json:"seed,omitempty"`
+	TrackIDs    []int64 `json:"track_ids"`
+}
+
+// PlaylistPlayRequest sans body la playlist est jouée une fois, dans l'ordre
+type PlaylistPlayRequest struct {
+	Shuffle string `json:"shuffle" validate:"omitempty,oneof=off random smart"`
+	Repeat  string `json:"repeat" validate:"omitempty,oneof=off one all"`
+	Seed    *int64 `json:"seed" validate:"omitempty,min=0"`
 }
@@ -10,3 +10,12 @@ type PlaylistCreateRequest struct {
 	Name   string               `json:"name" validate:"required,min=1,max=255"`
 	Tracks []TrackCreateRequest `json:"tracks" validate:"max=100,dive"`
 }
+
+type PlaylistUpdateRequest struct {
+	Name   string               `json:"name" validate:"required,min=1,max=255"`
+	Tracks []TrackCreateRequest `json:"tracks" validate:"max=100,dive"`
+}
+
+type PlaylistRenameRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=255"`
+}
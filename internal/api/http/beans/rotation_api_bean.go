@@ -0,0 +1,53 @@
+package beans
+
+import "time"
+
+type RotationRuleRequest struct {
+	Name          string `json:"name" validate:"required,min=1,max=255"`
+	Subject       string `json:"subject" validate:"required,oneof=artist track"`
+	WindowMinutes int    `json:"window_minutes" validate:"required,min=1,max=10080"`
+	MaxPlays      int    `json:"max_plays,omitempty" validate:"omitempty,min=1"`
+	Enabled       *bool  `json:"enabled,omitempty"`
+}
+
+type RotationRuleResponseApiBean struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	Subject       string    `json:"subject"`
+	WindowMinutes int       `json:"window_minutes"`
+	MaxPlays      int       `json:"max_plays"`
+	Enabled       bool      `json:"enabled"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type RotationRuleListResponse struct {
+	Data []RotationRuleResponseApiBean `json:"data"`
+}
+
+type RotationViolationApiBean struct {
+	RuleID        int64     `json:"rule_id"`
+	RuleName      string    `json:"rule_name"`
+	Subject       string    `json:"subject"`
+	WindowMinutes int       `json:"window_minutes"`
+	MaxPlays      int       `json:"max_plays"`
+	Plays         int       `json:"plays"`
+	LastPlayedAt  time.Time `json:"last_played_at"`
+}
+
+// RotationDryRunRequest simule la lecture d'une playlist avec ses options de lecture,
+// ou sans playlist l'heure programmée commençant à starts_at. starts_at vaut maintenant par défaut.
+type RotationDryRunRequest struct {
+	PlaylistID *int       `json:"playlist_id,omitempty" validate:"omitempty,min=1"`
+	Shuffle    string     `json:"shuffle" validate:"omitempty,oneof=off random smart"`
+	Repeat     string     `json:"repeat" validate:"omitempty,oneof=off one all"`
+	Seed       *int64     `json:"seed" validate:"omitempty,min=0"`
+	StartsAt   *time.Time `json:"starts_at,omitempty"`
+}
+
+type RotationDryRunResponse struct {
+	StartsAt        time.Time            `json:"starts_at"`
+	Clock           *ClockRefApiBean     `json:"clock,omitempty"`
+	Items           []ProgramItemApiBean `json:"items"`
+	ViolationsCount int                  `json:"violations_count"`
+}
@@ -0,0 +1,26 @@
+package beans
+
+import "time"
+
+type ProgramItemApiBean struct {
+	StartsAt        time.Time                  `json:"starts_at"`
+	SlotType        string                     `json:"slot_type"`
+	PlaylistID      int64                      `json:"playlist_id,omitempty"`
+	TrackID         int64                      `json:"track_id"`
+	Title           string                     `json:"title"`
+	Artist          string                     `json:"artist"`
+	DurationSeconds int                        `json:"duration_seconds"`
+	Violations      []RotationViolationApiBean `json:"violations,omitempty"`
+}
+
+type HourProgramApiBean struct {
+	StartsAt time.Time            `json:"starts_at"`
+	Clock    *ClockRefApiBean     `json:"clock,omitempty"`
+	Items    []ProgramItemApiBean `json:"items"`
+}
+
+type SchedulePreviewResponse struct {
+	Date     string               `json:"date"`
+	Timezone string               `json:"timezone"`
+	Hours    []HourProgramApiBean `json:"hours"`
+}
@@ -0,0 +1,85 @@
+package beans
+
+import "time"
+
+// StatsPeriodApiBean période résolue des statistiques, from incluse et to exclue
+type StatsPeriodApiBean struct {
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Timezone string    `json:"timezone"`
+}
+
+type TrackPlayCountApiBean struct {
+	TrackID       int64  `json:"track_id"`
+	Title         string `json:"title"`
+	Artist        string `json:"artist"`
+	Plays         int    `json:"plays"`
+	CompletePlays int    `json:"complete_plays"`
+}
+
+type TopTracksResponse struct {
+	StatsPeriodApiBean
+	Tracks []TrackPlayCountApiBean `json:"tracks"`
+}
+
+type ArtistPlayCountApiBean struct {
+	ArtistID int64  `json:"artist_id"`
+	Name     string `json:"name"`
+	Plays    int    `json:"plays"`
+}
+
+type TopArtistsResponse struct {
+	StatsPeriodApiBean
+	Artists []ArtistPlayCountApiBean `json:"artists"`
+}
+
+type PlaylistPlayCountApiBean struct {
+	PlaylistID    int64  `json:"playlist_id"`
+	Name          string `json:"name"`
+	Plays         int    `json:"plays"`
+	CompletePlays int    `json:"complete_plays"`
+	PlayedSeconds int    `json:"played_seconds"`
+}
+
+type PlaylistTotalsResponse struct {
+	StatsPeriodApiBean
+	Playlists []PlaylistPlayCountApiBean `json:"playlists"`
+}
+
+type HourPlaysApiBean struct {
+	Hour  int `json:"hour"`
+	Plays int `json:"plays"`
+}
+
+type HourlyStatsResponse struct {
+	StatsPeriodApiBean
+	Hours []HourPlaysApiBean `json:"hours"`
+}
+
+type WeekdayPlaysApiBean struct {
+	Weekday int    `json:"weekday"` // 0 = dimanche ... 6 = samedi, comme la grille
+	Name    string `json:"name"`
+	Plays   int    `json:"plays"`
+}
+
+type WeekdayStatsResponse struct {
+	StatsPeriodApiBean
+	Weekdays []WeekdayPlaysApiBean `json:"weekdays"`
+}
+
+type TrackPlayApiBean struct {
+	ID            int64      `json:"id"`
+	PlaylistID    int64      `json:"playlist_id"`
+	TrackID       int64      `json:"track_id"`
+	Position      int        `json:"position"`
+	SessionID     *int64     `json:"session_id,omitempty"`
+	PlayedAt      time.Time  `json:"played_at"`
+	EndedAt       *time.Time `json:"ended_at,omitempty"`
+	PlayedSeconds *int       `json:"played_seconds,omitempty"`
+	EndReason     string     `json:"end_reason,omitempty"`
+}
+
+type TrackPlaysResponse struct {
+	StatsPeriodApiBean
+	Plays []TrackPlayApiBean `json:"plays"`
+}
@@ -0,0 +1,30 @@
+package beans
+
+// StudioCommandApiBean message envoyé par la console du studio. ID est renvoyé tel quel dans la
+// réponse pour que la console associe la réponse à sa commande.
+type StudioCommandApiBean struct {
+	ID         string `json:"id"`
+	Type       string `json:"type" validate:"required,oneof=play pause resume skip back stop insert"`
+	SessionID  int64  `json:"session_id" validate:"required_unless=Type play,omitempty,min=1"`
+	PlaylistID int    `json:"playlist_id" validate:"required_if=Type play,omitempty,min=1"`
+	TrackID    int    `json:"track_id" validate:"required_if=Type insert,omitempty,min=1"`
+	Shuffle    string `json:"shuffle" validate:"omitempty,oneof=off random smart"`
+	Repeat     string `json:"repeat" validate:"omitempty,oneof=off one all"`
+	Seed       *int64 `json:"seed" validate:"omitempty,min=0"`
+}
+
+// StudioSessionApiBean état d'une session et sa file de lecture complète
+type StudioSessionApiBean struct {
+	PlaySessionResponseApiBean
+	Queue []SessionTrackApiBean `json:"queue"`
+}
+
+// StudioMessageApiBean message envoyé à la console : "snapshot" à la connexion avec les sessions
+// actives, "session" à chaque changement d'état, "ack" ou "error" en réponse à une commande
+type StudioMessageApiBean struct {
+	Type     string                 `json:"type"`
+	ID       string                 `json:"id,omitempty"`
+	Session  *StudioSessionApiBean  `json:"session,omitempty"`
+	Sessions []StudioSessionApiBean `json:"sessions,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+}
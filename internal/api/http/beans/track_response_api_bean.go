@@ -1,12 +1,30 @@
 package beans
 
 type TrackResponseApiBean struct {
-	ID     int64  `json:"id"`
-	Title  string `json:"title"`
-	Artist string `json:"artist"`
+	ID              int64  `json:"id"`
+	Title           string `json:"title"`
+	Artist          string `json:"artist"`
+	Position        int    `json:"position"`
+	DurationSeconds int    `json:"duration_seconds"`
 }
 
+// TrackCreateRequest une track avec ID référence le catalogue, sinon elle est retrouvée ou créée par titre et artiste
 type TrackCreateRequest struct {
-	Title  string `json:"title" validate:"required,min=1,max=255"`
-	Artist string `json:"artist" validate:"required,min=1,max=255"`
+	ID              int64  `json:"id,omitempty" validate:"omitempty,min=1"`
+	Title           string `json:"title" validate:"required_without=ID,max=255"`
+	Artist          string `json:"artist" validate:"required_without=ID,max=255"`
+	DurationSeconds int    `json:"duration_seconds,omitempty" validate:"omitempty,min=1,max=86400"`
+}
+
+// TrackAddRequest sans position la track est ajoutée en fin de playlist
+type TrackAddRequest struct {
+	ID              int64  `json:"id,omitempty" validate:"omitempty,min=1"`
+	Title           string `json:"title" validate:"required_without=ID,max=255"`
+	Artist          string `json:"artist" validate:"required_without=ID,max=255"`
+	DurationSeconds int    `json:"duration_seconds,omitempty" validate:"omitempty,min=1,max=86400"`
+	Position        *int   `json:"position" validate:"omitempty,min=0"`
+}
+
+type TrackMoveRequest struct {
+	Position *int `json:"position" validate:"required,min=0"`
 }
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"radioking-app/internal/api/http/beans"
+	"radioking-app/internal/domain/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// ArtistHandler expose les artistes et albums du catalogue
+type ArtistHandler struct {
+	service services.IArtistService
+}
+
+func NewArtistHandler(service services.IArtistService) *ArtistHandler {
+	return &ArtistHandler{service: service}
+}
+
+func (handler *ArtistHandler) Routes(router *chi.Mux) chi.Router {
+	router.Get("/artists", handler.SearchArtists)
+	router.Get("/artists/{id}", handler.GetArtist)
+	router.Get("/albums/{id}", handler.GetAlbum)
+	return router
+}
+
+func (handler *ArtistHandler) SearchArtists(w http.ResponseWriter, r *http.Request) {
+	artists, err := handler.service.SearchArtists(r.URL.Query().Get("name"))
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	resp := beans.ArtistListResponse{Data: make([]beans.ArtistRefApiBean, 0, len(artists))}
+	for _, artist := range artists {
+		resp.Data = append(resp.Data, beans.ArtistRefApiBean{ID: artist.ID, Name: artist.Name})
+	}
+
+	render.JSON(w, r, resp)
+}
+
+func (handler *ArtistHandler) GetArtist(w http.ResponseWriter, r *http.Request) {
+	id, ok := extractIDParam(w, r, IdParameter, "artist")
+	if !ok {
+		return
+	}
+
+	overview, err := handler.service.GetArtist(id)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.JSON(w, r, toArtistResponse(overview))
+}
+
+func (handler *ArtistHandler) GetAlbum(w http.ResponseWriter, r *http.Request) {
+	id, ok := extractIDParam(w, r, IdParameter, "album")
+	if !ok {
+		return
+	}
+
+	overview, err := handler.service.GetAlbum(id)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.JSON(w, r, toAlbumResponse(overview))
+}
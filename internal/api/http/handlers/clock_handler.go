@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"net/http"
+	"radioking-app/internal/api/http/beans"
+	"radioking-app/internal/domain/services"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// ClockHandler expose les clocks et leur programmation dans la grille de la semaine
+type ClockHandler struct {
+	service services.IClockService
+}
+
+func NewClockHandler(service services.IClockService) *ClockHandler {
+	return &ClockHandler{service: service}
+}
+
+func (handler *ClockHandler) Routes(router *chi.Mux) chi.Router {
+	router.Post("/clocks", handler.CreateClock)
+	router.Get("/clocks", handler.ListClocks)
+	router.Get("/clocks/{id}", handler.GetClock)
+	router.Put("/clocks/{id}", handler.UpdateClock)
+	router.Delete("/clocks/{id}", handler.DeleteClock)
+	router.Get("/grid", handler.GetGrid)
+	router.Put("/grid/{weekday}/{hour}", handler.AssignClock)
+	router.Delete("/grid/{weekday}/{hour}", handler.UnassignClock)
+	return router
+}
+
+func (handler *ClockHandler) CreateClock(w http.ResponseWriter, r *http.Request) {
+	var req beans.ClockRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	clock := toClock(req)
+	if err := handler.service.CreateClock(&clock); err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, toClockResponse(&clock))
+}
+
+func (handler *ClockHandler) ListClocks(w http.ResponseWriter, r *http.Request) {
+	clocks, err := handler.service.ListClocks()
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	resp := beans.ClockListResponse{Data: make([]beans.ClockResponseApiBean, 0, len(clocks))}
+	for _, clock := range clocks {
+		resp.Data = append(resp.Data, toClockResponse(clock))
+	}
+
+	render.JSON(w, r, resp)
+}
+
+func (handler *ClockHandler) GetClock(w http.ResponseWriter, r *http.Request) {
+	id, ok := extractIDParam(w, r, IdParameter, "clock")
+	if !ok {
+		return
+	}
+
+	clock, err := handler.service.GetClock(id)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.JSON(w, r, toClockResponse(clock))
+}
+
+func (handler *ClockHandler) UpdateClock(w http.ResponseWriter, r *http.Request) {
+	id, ok := extractIDParam(w, r, IdParameter, "clock")
+	if !ok {
+		return
+	}
+
+	var req beans.ClockRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	clock := toClock(req)
+	if err := handler.service.UpdateClock(id, &clock); err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.JSON(w, r, toClockResponse(&clock))
+}
+
+func (handler *ClockHandler) DeleteClock(w http.ResponseWriter, r *http.Request) {
+	id, ok := extractIDParam(w, r, IdParameter, "clock")
+	if !ok {
+		return
+	}
+
+	if err := handler.service.DeleteClock(id); err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *ClockHandler) GetGrid(w http.ResponseWriter, r *http.Request) {
+	entries, err := handler.service.GetGrid()
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	resp := beans.GridResponse{Data: make([]beans.GridEntryApiBean, 0, len(entries))}
+	for _, entry := range entries {
+		resp.Data = append(resp.Data, toGridEntry(entry))
+	}
+
+	render.JSON(w, r, resp)
+}
+
+func (handler *ClockHandler) AssignClock(w http.ResponseWriter, r *http.Request) {
+	weekday, hour, ok := extractGridHour(w, r)
+	if !ok {
+		return
+	}
+
+	var req beans.GridAssignRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	entry, err := handler.service.AssignClock(weekday, hour, req.ClockID)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.JSON(w, r, toGridEntry(entry))
+}
+
+func (handler *ClockHandler) UnassignClock(w http.ResponseWriter, r *http.Request) {
+	weekday, hour, ok := extractGridHour(w, r)
+	if !ok {
+		return
+	}
+
+	if err := handler.service.UnassignClock(weekday, hour); err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// extractGridHour lit le jour (0 = dimanche) et l'heure de la grille depuis l'URL
+func extractGridHour(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	weekday, err := strconv.Atoi(chi.URLParam(r, "weekday"))
+	if err != nil {
+		handleError(w, "Invalid weekday format", http.StatusBadRequest, err)
+		return 0, 0, false
+	}
+
+	hour, err := strconv.Atoi(chi.URLParam(r, "hour"))
+	if err != nil {
+		handleError(w, "Invalid hour format", http.StatusBadRequest, err)
+		return 0, 0, false
+	}
+	return weekday, hour, true
+}
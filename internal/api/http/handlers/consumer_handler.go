@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+	"radioking-app/internal/api/http/beans"
+	"radioking-app/internal/domain/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// ConsumerHandler expose l'état du consommateur des événements de lecture
+type ConsumerHandler struct {
+	service services.ITrackPlayConsumerService
+}
+
+func NewConsumerHandler(service services.ITrackPlayConsumerService) *ConsumerHandler {
+	return &ConsumerHandler{service: service}
+}
+
+func (handler *ConsumerHandler) Routes(router *chi.Mux) chi.Router {
+	router.Get("/admin/consumer/stats", handler.GetStats)
+	return router
+}
+
+// GetStats retourne les événements traités et les redélivrances ignorées depuis le démarrage
+func (handler *ConsumerHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, beans.ConsumerStatsApiBean(handler.service.Stats()))
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/domain/services"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// CueSheetFlaggedHeader nombre de diffusions auxquelles il manque des métadonnées exigées
+const CueSheetFlaggedHeader = "X-Cue-Sheet-Flagged"
+
+// CueSheetHandler exporte le relevé des diffusions destiné aux sociétés de gestion collective.
+// La période suit les paramètres from, to et tz des statistiques.
+type CueSheetHandler struct {
+	statsService    services.IStatsService
+	cueSheetService services.ICueSheetService
+}
+
+func NewCueSheetHandler(statsService services.IStatsService, cueSheetService services.ICueSheetService) *CueSheetHandler {
+	return &CueSheetHandler{statsService: statsService, cueSheetService: cueSheetService}
+}
+
+func (handler *CueSheetHandler) Routes(router *chi.Mux) chi.Router {
+	router.Get("/exports/cue-sheet", handler.Export)
+	return router
+}
+
+// Export télécharge le relevé de la période en CSV (par défaut) ou à largeur fixe (format=fixed)
+func (handler *CueSheetHandler) Export(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	period, err := handler.statsService.ResolvePeriod(models.StatsQuery{
+		From:     query.Get("from"),
+		To:       query.Get("to"),
+		Timezone: query.Get("tz"),
+	})
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	format := models.CueSheetFormat(query.Get("format"))
+	if format == "" {
+		format = models.CueSheetCSV
+	}
+
+	sheet, err := handler.cueSheetService.CueSheet(period)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	var body bytes.Buffer
+	if err := handler.cueSheetService.Write(&body, sheet, format); err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	contentType, extension := "text/csv; charset=utf-8", "csv"
+	if format == models.CueSheetFixedWidth {
+		contentType, extension = "text/plain; charset=us-ascii", "txt"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, cueSheetFileName(period, extension)))
+	w.Header().Set(CueSheetFlaggedHeader, strconv.Itoa(sheet.Flagged))
+	if _, err := body.WriteTo(w); err != nil {
+		log.Printf("Failed to write cue sheet: %v", err)
+	}
+}
+
+// cueSheetFileName nomme le fichier d'après le premier et le dernier jour de la période
+func cueSheetFileName(period models.StatsPeriod, extension string) string {
+	from := period.From.In(period.Location)
+	last := period.To.In(period.Location).Add(-time.Nanosecond)
+	return fmt.Sprintf("cue-sheet-%s-%s.%s", from.Format("20060102"), last.Format("20060102"), extension)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"radioking-app/internal/api/http/beans"
+	"radioking-app/internal/domain/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// DeadLetterHandler administre les messages écartés par le consommateur
+type DeadLetterHandler struct {
+	service services.IDeadLetterService
+}
+
+func NewDeadLetterHandler(service services.IDeadLetterService) *DeadLetterHandler {
+	return &DeadLetterHandler{service: service}
+}
+
+func (handler *DeadLetterHandler) Routes(router *chi.Mux) chi.Router {
+	router.Route("/admin/dead-letters", func(r chi.Router) {
+		r.Get("/", handler.List)
+		r.Post("/replay", handler.Replay)
+		r.Delete("/", handler.Purge)
+	})
+	return router
+}
+
+// List retourne les limit premiers messages écartés, sans les retirer de la queue
+func (handler *DeadLetterHandler) List(w http.ResponseWriter, r *http.Request) {
+	limit, ok := extractLimit(w, r)
+	if !ok {
+		return
+	}
+
+	letters, err := handler.service.List(limit)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+	render.JSON(w, r, toDeadLettersResponse(letters))
+}
+
+// Replay renvoie dans leur queue d'origine les messages demandés, ou tous sans body
+func (handler *DeadLetterHandler) Replay(w http.ResponseWriter, r *http.Request) {
+	var req beans.DeadLetterReplayRequest
+	if !decodeOptionalRequest(w, r, &req) {
+		return
+	}
+
+	replayed, err := handler.service.Replay(req.IDs)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+	render.JSON(w, r, beans.DeadLetterReplayResponse{Replayed: replayed})
+}
+
+// Purge supprime tous les messages écartés
+func (handler *DeadLetterHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	purged, err := handler.service.Purge()
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+	render.JSON(w, r, beans.DeadLetterPurgeResponse{Purged: purged})
+}
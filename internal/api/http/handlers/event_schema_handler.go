@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/domain/services"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// EventSchemaHandler publie les schémas JSON des payloads des événements
+type EventSchemaHandler struct {
+	service services.IEventSchemaService
+}
+
+func NewEventSchemaHandler(service services.IEventSchemaService) *EventSchemaHandler {
+	return &EventSchemaHandler{service: service}
+}
+
+func (handler *EventSchemaHandler) Routes(router *chi.Mux) chi.Router {
+	router.Route("/events/schemas", func(r chi.Router) {
+		r.Get("/", handler.List)
+		r.Get("/{type}/{version}", handler.Get)
+	})
+	return router
+}
+
+// List retourne les schémas publiés de chaque type d'événement
+func (handler *EventSchemaHandler) List(w http.ResponseWriter, r *http.Request) {
+	refs, err := handler.service.List()
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+	render.JSON(w, r, toEventSchemasResponse(refs))
+}
+
+// Get retourne le schéma d'un type d'événement dans une version
+func (handler *EventSchemaHandler) Get(w http.ResponseWriter, r *http.Request) {
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil {
+		handleError(w, "Invalid schema version format", http.StatusBadRequest, err)
+		return
+	}
+
+	schema, err := handler.service.Get(models.EventType(chi.URLParam(r, "type")), version)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.Write(schema)
+}
+
+func eventSchemaURL(ref models.EventSchemaRef) string {
+	return fmt.Sprintf("/events/schemas/%s/%d", ref.Type, ref.Version)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"radioking-app/internal/api/http/beans"
+	"strconv"
+
+	domainErrors "radioking-app/internal/domain/errors"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+const IdParameter = "id"
+const mappingError = "Response mapping error"
+
+var validate = validator.New()
+
+// decodeRequest décode le body JSON dans req et le valide, l'erreur est écrite dans la réponse si besoin
+func decodeRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		handleError(w, "Invalid JSON payload", http.StatusBadRequest, err)
+		return false
+	}
+
+	if err := validate.Struct(req); err != nil {
+		handleError(w, "Validation failed", http.StatusBadRequest, err)
+		return false
+	}
+	return true
+}
+
+// decodeOptionalRequest comme decodeRequest, mais un body vide laisse req à sa valeur par défaut
+func decodeOptionalRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil && !errors.Is(err, io.EOF) {
+		handleError(w, "Invalid JSON payload", http.StatusBadRequest, err)
+		return false
+	}
+
+	if err := validate.Struct(req); err != nil {
+		handleError(w, "Validation failed", http.StatusBadRequest, err)
+		return false
+	}
+	return true
+}
+
+// extractIDParam helper pour extraire et valider un ID numérique depuis l'URL
+func extractIDParam(w http.ResponseWriter, r *http.Request, param string, resource string) (int, bool) {
+	idStr := chi.URLParam(r, param)
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		handleError(w, "Invalid "+resource+" ID format", http.StatusBadRequest, err)
+		return 0, false
+	}
+	return id, true
+}
+
+func writeJSONError(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(beans.ErrorResponse{Error: message})
+}
+
+func handleError(w http.ResponseWriter, message string, statusCode int, err error) {
+	log.Printf("Handler error: %s - %v", message, err)
+	writeJSONError(w, message, statusCode)
+}
+
+func handleBusinessError(w http.ResponseWriter, err error) {
+	message, statusCode := businessErrorResponse(err)
+	writeJSONError(w, message, statusCode)
+}
+
+// businessErrorResponse message et statut HTTP exposés au client pour une erreur métier
+func businessErrorResponse(err error) (string, int) {
+	var businessErr *domainErrors.BusinessError
+	if errors.As(err, &businessErr) {
+		log.Printf("Business error: %v", err)
+		switch {
+		case businessErr.IsValidation():
+			return businessErr.Error(), http.StatusBadRequest
+		case businessErr.IsNotFound():
+			return businessErr.Error(), http.StatusNotFound
+		case businessErr.IsConflict():
+			return businessErr.Error(), http.StatusConflict
+		}
+	}
+	return "Internal server error", http.StatusInternalServerError
+}
+
+// extractLimit lit le paramètre limit, 0 s'il est absent pour laisser le service appliquer la valeur par défaut
+func extractLimit(w http.ResponseWriter, r *http.Request) (int, bool) {
+	limitStr := r.URL.Query().Get("limit")
+	if limitStr == "" {
+		return 0, true
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil {
+		handleError(w, "Invalid limit parameter", http.StatusBadRequest, err)
+		return 0, false
+	}
+	return limit, true
+}
+
+func newPagination(limit int, nextCursor string) beans.PaginationApiBean {
+	return beans.PaginationApiBean{
+		Limit:      limit,
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != "",
+	}
+}
+
+// newPageLinks construit le lien de la page suivante en conservant les autres paramètres de la requête
+func newPageLinks(r *http.Request, nextCursor string) beans.PageLinksApiBean {
+	links := beans.PageLinksApiBean{Self: r.URL.RequestURI()}
+	if nextCursor != "" {
+		next := *r.URL
+		params := next.Query()
+		params.Set("cursor", nextCursor)
+		next.RawQuery = params.Encode()
+		links.Next = next.RequestURI()
+	}
+	return links
+}
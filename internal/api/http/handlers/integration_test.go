@@ -1,22 +1,35 @@
 package handlers
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"radioking-app/internal/api/http/beans"
 	"radioking-app/internal/config"
+	"radioking-app/internal/domain/constants"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
 	"radioking-app/internal/domain/services"
 	"radioking-app/internal/infrastructure/db"
+	"radioking-app/internal/infrastructure/messaging"
 	"radioking-app/internal/infrastructure/repositories"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/gorm"
@@ -24,8 +37,19 @@ import (
 
 // Test constants
 const (
-	PlaylistsEndpoint = "/playlists"
-	ContentTypeJSON   = "application/json"
+	PlaylistsEndpoint  = "/playlists"
+	TracksEndpoint     = "/tracks"
+	ArtistsEndpoint    = "/artists"
+	AlbumsEndpoint     = "/albums"
+	SessionsEndpoint   = "/sessions"
+	ClocksEndpoint     = "/clocks"
+	GridEndpoint       = "/grid"
+	PreviewEndpoint    = "/schedule/preview"
+	RotationEndpoint   = "/rotation"
+	NowPlayingEndpoint = "/now-playing"
+	StatsEndpoint      = "/stats"
+	CueSheetEndpoint   = "/exports/cue-sheet"
+	ContentTypeJSON    = "application/json"
 
 	// Test data
 	TestPlaylistName1 = "Playlist 1"
@@ -37,6 +61,7 @@ const (
 	TestArtist1Name   = "Artist 1"
 	TestArtist2Name   = "Artist 2"
 	TestArtist3Name   = "Artist 3"
+	TestStudioOrigin  = "https://studio.example.com"
 
 	// Error messages
 	ValidationErrorMsg = "Validation"
@@ -50,8 +75,68 @@ const (
 
 type IntegrationTestSuite struct {
 	suite.Suite
-	router *chi.Mux
-	db     *gorm.DB
+	router     *chi.Mux
+	db         *gorm.DB
+	nowPlaying *services.NowPlayingService
+	studio     *services.StudioService
+	deadLetter *fakeDeadLetterQueue
+}
+
+// fakeDeadLetterQueue file de messages écartés en mémoire, un message rejoué est retiré
+type fakeDeadLetterQueue struct {
+	letters  []models.DeadLetter
+	replayed []models.DeadLetter
+}
+
+func (q *fakeDeadLetterQueue) Peek(limit int) ([]models.DeadLetter, error) {
+	return q.letters[:min(limit, len(q.letters))], nil
+}
+
+func (q *fakeDeadLetterQueue) Replay(ids []string) (int, error) {
+	var kept []models.DeadLetter
+	before := len(q.replayed)
+	for _, letter := range q.letters {
+		if len(ids) == 0 || slices.Contains(ids, letter.MessageID) {
+			q.replayed = append(q.replayed, letter)
+		} else {
+			kept = append(kept, letter)
+		}
+	}
+	q.letters = kept
+	return len(q.replayed) - before, nil
+}
+
+func (q *fakeDeadLetterQueue) Purge() (int, error) {
+	purged := len(q.letters)
+	q.letters = nil
+	return purged, nil
+}
+
+func (q *fakeDeadLetterQueue) Close() error {
+	return nil
+}
+
+// recordingPublisher enregistre les événements publiés, failing simule un broker indisponible
+// et unroutable un type d'événement qu'aucune queue ne reçoit
+type recordingPublisher struct {
+	failing    bool
+	unroutable models.EventType
+	events     []models.Event
+}
+
+func (p *recordingPublisher) Publish(event models.Event) error {
+	if p.failing {
+		return errors.New("broker unavailable")
+	}
+	if event.Type == p.unroutable {
+		return fmt.Errorf("%w: %s", messaging.ErrUnroutable, event.Type)
+	}
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *recordingPublisher) Close() error {
+	return nil
 }
 
 func (suite *IntegrationTestSuite) SetupSuite() {
@@ -63,7 +148,8 @@ func (suite *IntegrationTestSuite) SetupSuite() {
 	suite.Require().NoError(err)
 	suite.Require().False(cfg.Auth.Enabled, "Auth should be disabled for integration tests")
 
-	// Setup test database
+	// Setup test database : test.db est créé dans un répertoire temporaire, qui devient le répertoire courant
+	suite.T().Chdir(suite.T().TempDir())
 	testDB, err := db.InitDb()
 	suite.Require().NoError(err)
 	suite.db = testDB
@@ -77,10 +163,52 @@ func (suite *IntegrationTestSuite) SetupSuite() {
 	// Setup handlers
 	repo := repositories.NewPlaylistRepository(testDB)
 	service := services.PlaylistService{Repo: repo}
-	playService := services.NewPlaylistPlayService(&service, nil) // pas besoin du publisher pour les tests
+	playService := services.NewPlaylistPlayService(&service, nil) // pas besoin du moteur de lecture pour les tests
 	appService := services.NewPlaylistApplicationService(&service, playService)
 	handler := NewPlaylistHandler(&service, appService)
 	handler.Routes(router)
+	trackHandler := NewTrackHandler(services.NewTrackService(repositories.NewTrackRepository(testDB)))
+	trackHandler.Routes(router)
+	artistService := services.NewArtistService(repositories.NewArtistRepository(testDB), repositories.NewAlbumRepository(testDB))
+	artistHandler := NewArtistHandler(artistService)
+	artistHandler.Routes(router)
+	rotationService := services.NewRotationService(repositories.NewRotationRuleRepository(testDB), repositories.NewTrackPlayRepository(testDB), &service)
+	// Le moteur n'est pas démarré : les sessions sont créées directement en base
+	playSessionRepo := repositories.NewPlaySessionRepository(testDB)
+	engine := services.NewPlaybackEngine(playSessionRepo, rotationService)
+	sessionHandler := NewSessionHandler(engine)
+	sessionHandler.Routes(router)
+	suite.nowPlaying = services.NewNowPlayingService(engine, playSessionRepo)
+	nowPlayingHandler := NewNowPlayingHandler(suite.nowPlaying)
+	nowPlayingHandler.Routes(router)
+	suite.studio = services.NewStudioService(engine, appService, services.NewTrackService(repositories.NewTrackRepository(testDB)), playSessionRepo)
+	engine.AddListener(suite.studio)
+	studioHandler := NewStudioHandler(suite.studio, []string{TestStudioOrigin})
+	studioHandler.Routes(router)
+	statsHandler := NewStatsHandler(services.NewStatsService(repositories.NewStatsRepository(testDB)),
+		services.NewTrackPlayService(repositories.NewTrackPlayRepository(testDB)))
+	statsHandler.Routes(router)
+	cueSheetHandler := NewCueSheetHandler(services.NewStatsService(repositories.NewStatsRepository(testDB)),
+		services.NewCueSheetService(repositories.NewCueSheetRepository(testDB)))
+	cueSheetHandler.Routes(router)
+	trackRepo := repositories.NewTrackRepository(testDB)
+	gridRepo := repositories.NewGridRepository(testDB)
+	clockHandler := NewClockHandler(services.NewClockService(repositories.NewClockRepository(testDB), gridRepo, &service, trackRepo))
+	clockHandler.Routes(router)
+	station, err := time.LoadLocation("Europe/Paris")
+	suite.Require().NoError(err)
+	scheduleService := services.NewScheduleService(gridRepo, &service, trackRepo, rotationService, station)
+	scheduleHandler := NewScheduleHandler(scheduleService)
+	scheduleHandler.Routes(router)
+	rotationHandler := NewRotationHandler(rotationService, scheduleService)
+	rotationHandler.Routes(router)
+	suite.deadLetter = &fakeDeadLetterQueue{}
+	deadLetterHandler := NewDeadLetterHandler(services.NewDeadLetterService(suite.deadLetter))
+	deadLetterHandler.Routes(router)
+	outboxHandler := NewOutboxHandler(services.NewOutboxService(repositories.NewOutboxRepository(testDB)))
+	outboxHandler.Routes(router)
+	eventSchemaHandler := NewEventSchemaHandler(services.NewEventSchemaService())
+	eventSchemaHandler.Routes(router)
 
 	suite.router = router
 }
@@ -88,14 +216,43 @@ func (suite *IntegrationTestSuite) SetupSuite() {
 func (suite *IntegrationTestSuite) TearDownSuite() {
 	// Clean up environment variable
 	_ = os.Unsetenv("RADIOKING_AUTH_ENABLED")
+
+	if sqlDB, err := suite.db.DB(); err == nil {
+		_ = sqlDB.Close()
+	}
 }
 
 func (suite *IntegrationTestSuite) SetupTest() {
 	// Clean database before each test
-	err := suite.db.Exec("DELETE FROM tracks").Error
+	err := suite.db.Exec("DELETE FROM track_plays").Error
+	suite.Require().NoError(err)
+	err = suite.db.Exec("DELETE FROM playlist_tracks").Error
+	suite.Require().NoError(err)
+	err = suite.db.Exec("DELETE FROM tracks").Error
 	suite.Require().NoError(err)
 	err = suite.db.Exec("DELETE FROM playlists").Error
 	suite.Require().NoError(err)
+	err = suite.db.Exec("DELETE FROM albums").Error
+	suite.Require().NoError(err)
+	err = suite.db.Exec("DELETE FROM artists").Error
+	suite.Require().NoError(err)
+	err = suite.db.Exec("DELETE FROM play_sessions").Error
+	suite.Require().NoError(err)
+	err = suite.db.Exec("DELETE FROM grid_entries").Error
+	suite.Require().NoError(err)
+	err = suite.db.Exec("DELETE FROM clock_slots").Error
+	suite.Require().NoError(err)
+	err = suite.db.Exec("DELETE FROM clocks").Error
+	suite.Require().NoError(err)
+	err = suite.db.Exec("DELETE FROM rotation_rules").Error
+	suite.Require().NoError(err)
+	err = suite.db.Exec("DELETE FROM play_rollups").Error
+	suite.Require().NoError(err)
+	err = suite.db.Exec("DELETE FROM outbox_messages").Error
+	suite.Require().NoError(err)
+	err = suite.db.Exec("DELETE FROM stored_events").Error
+	suite.Require().NoError(err)
+	*suite.deadLetter = fakeDeadLetterQueue{}
 }
 
 // Helper Methods
@@ -110,10 +267,14 @@ func (suite *IntegrationTestSuite) createTestPlaylist(name string, tracks []bean
 }
 
 func (suite *IntegrationTestSuite) makePostRequest(endpoint string, payload interface{}) *httptest.ResponseRecorder {
+	return suite.makeJSONRequest("POST", endpoint, payload)
+}
+
+func (suite *IntegrationTestSuite) makeJSONRequest(method, endpoint string, payload interface{}) *httptest.ResponseRecorder {
 	body, err := json.Marshal(payload)
 	suite.Require().NoError(err)
 
-	req := httptest.NewRequest("POST", endpoint, bytes.NewReader(body))
+	req := httptest.NewRequest(method, endpoint, bytes.NewReader(body))
 	req.Header.Set("Content-Type", ContentTypeJSON)
 	rr := httptest.NewRecorder()
 
@@ -129,6 +290,14 @@ func (suite *IntegrationTestSuite) makeGetRequest(endpoint string) *httptest.Res
 	return rr
 }
 
+func (suite *IntegrationTestSuite) makeDeleteRequest(endpoint string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("DELETE", endpoint, nil)
+	rr := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(rr, req)
+	return rr
+}
+
 func (suite *IntegrationTestSuite) parsePlaylistResponse(rr *httptest.ResponseRecorder) beans.PlaylistResponseApiBean {
 	var response beans.PlaylistResponseApiBean
 	err := json.Unmarshal(rr.Body.Bytes(), &response)
@@ -136,8 +305,15 @@ func (suite *IntegrationTestSuite) parsePlaylistResponse(rr *httptest.ResponseRe
 	return response
 }
 
-func (suite *IntegrationTestSuite) parsePlaylistsResponse(rr *httptest.ResponseRecorder) []beans.PlaylistResponseApiBean {
-	var response []beans.PlaylistResponseApiBean
+func (suite *IntegrationTestSuite) parsePlaylistsResponse(rr *httptest.ResponseRecorder) beans.PlaylistPageResponse {
+	var response beans.PlaylistPageResponse
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	suite.Require().NoError(err)
+	return response
+}
+
+func (suite *IntegrationTestSuite) parseTracksResponse(rr *httptest.ResponseRecorder) []beans.TrackResponseApiBean {
+	var response []beans.TrackResponseApiBean
 	err := json.Unmarshal(rr.Body.Bytes(), &response)
 	suite.Require().NoError(err)
 	return response
@@ -226,11 +402,142 @@ func (suite *IntegrationTestSuite) TestGetPlaylists_Success() {
 	assert.Equal(suite.T(), http.StatusOK, rr.Code)
 
 	response := suite.parsePlaylistsResponse(rr)
-	assert.Len(suite.T(), response, 2)
-	assert.Equal(suite.T(), TestPlaylistName1, response[0].Name)
-	assert.Len(suite.T(), response[0].Tracks, 1)
-	assert.Equal(suite.T(), TestPlaylistName2, response[1].Name)
-	assert.Len(suite.T(), response[1].Tracks, 2)
+	assert.Len(suite.T(), response.Data, 2)
+	assert.Equal(suite.T(), TestPlaylistName1, response.Data[0].Name)
+	assert.Equal(suite.T(), 1, response.Data[0].TracksCount)
+	assert.Empty(suite.T(), response.Data[0].Tracks)
+	assert.Equal(suite.T(), TestPlaylistName2, response.Data[1].Name)
+	assert.Equal(suite.T(), 2, response.Data[1].TracksCount)
+	assert.False(suite.T(), response.Pagination.HasMore)
+}
+
+func (suite *IntegrationTestSuite) TestGetPlaylists_WithTracks() {
+	// Arrange
+	testPlaylist := suite.buildTestPlaylist()
+	suite.createTestPlaylist(testPlaylist.Name, testPlaylist.Tracks)
+
+	// Act
+	rr := suite.makeGetRequest(PlaylistsEndpoint + "?with_tracks=true")
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, rr.Code)
+
+	response := suite.parsePlaylistsResponse(rr)
+	assert.Len(suite.T(), response.Data, 1)
+	assert.Len(suite.T(), response.Data[0].Tracks, 2)
+}
+
+func (suite *IntegrationTestSuite) TestGetPlaylists_Pagination() {
+	// Arrange
+	for i := 1; i <= 5; i++ {
+		suite.createTestPlaylist(fmt.Sprintf("Playlist %d", i), nil)
+	}
+
+	// Act
+	var names []string
+	next := PlaylistsEndpoint + "?limit=2&sort=created_at&order=desc"
+	pages := 0
+	for next != "" {
+		rr := suite.makeGetRequest(next)
+		suite.Require().Equal(http.StatusOK, rr.Code)
+
+		response := suite.parsePlaylistsResponse(rr)
+		for _, playlist := range response.Data {
+			names = append(names, playlist.Name)
+		}
+		next = response.Links.Next
+		pages++
+	}
+
+	// Assert
+	assert.Equal(suite.T(), 3, pages)
+	assert.Equal(suite.T(), []string{"Playlist 5", "Playlist 4", "Playlist 3", "Playlist 2", "Playlist 1"}, names)
+}
+
+func (suite *IntegrationTestSuite) TestGetPlaylists_PaginationAcrossDSTChange() {
+	// Arrange : serveur à Paris, playlists créées autour du passage à l'heure d'hiver (27/10/2024 à 01:00 UTC)
+	paris, err := time.LoadLocation("Europe/Paris")
+	suite.Require().NoError(err)
+	local := time.Local
+	time.Local = paris
+	defer func() { time.Local = local }()
+
+	createdAt := []time.Time{
+		time.Date(2024, 10, 27, 0, 30, 0, 0, time.UTC), // 02:30 CEST
+		time.Date(2024, 10, 27, 1, 15, 0, 0, time.UTC), // 02:15 CET
+		time.Date(2024, 10, 27, 1, 45, 0, 0, time.UTC), // 02:45 CET
+	}
+	for i, at := range createdAt {
+		id := suite.createTestPlaylist(fmt.Sprintf("Playlist %d", i+1), nil)
+
+		var stored int64
+		suite.Require().NoError(suite.db.Model(&models.Playlist{}).
+			Where("id = ? AND created_at LIKE ?", id, "%+00:00").
+			Count(&stored).Error)
+		suite.Require().Equal(int64(1), stored, "created_at doit être enregistré en UTC")
+
+		suite.Require().NoError(suite.db.Model(&models.Playlist{}).Where("id = ?", id).UpdateColumn("created_at", at).Error)
+	}
+
+	// Act
+	var names []string
+	next := PlaylistsEndpoint + "?limit=1&sort=created_at&order=asc"
+	for next != "" {
+		rr := suite.makeGetRequest(next)
+		suite.Require().Equal(http.StatusOK, rr.Code)
+
+		response := suite.parsePlaylistsResponse(rr)
+		for _, playlist := range response.Data {
+			names = append(names, playlist.Name)
+		}
+		next = response.Links.Next
+	}
+
+	// Assert
+	assert.Equal(suite.T(), []string{"Playlist 1", "Playlist 2", "Playlist 3"}, names)
+}
+
+func (suite *IntegrationTestSuite) TestGetPlaylists_NameFilter() {
+	// Arrange
+	suite.createTestPlaylist("Morning Show", nil)
+	suite.createTestPlaylist("Evening Show", nil)
+	suite.createTestPlaylist("Night Mix", nil)
+
+	// Act
+	rr := suite.makeGetRequest(PlaylistsEndpoint + "?name=show")
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, rr.Code)
+
+	response := suite.parsePlaylistsResponse(rr)
+	assert.Len(suite.T(), response.Data, 2)
+}
+
+func (suite *IntegrationTestSuite) TestGetPlaylists_NameFilterMatchesWildcardsLiterally() {
+	// Arrange
+	suite.createTestPlaylist("100% Hits", nil)
+	suite.createTestPlaylist("1000 Hits", nil)
+	suite.createTestPlaylist("Top_50", nil)
+	suite.createTestPlaylist("Top 50", nil)
+
+	for _, filter := range []string{"100%25", "p_5"} {
+		// Act
+		rr := suite.makeGetRequest(PlaylistsEndpoint + "?name=" + filter)
+
+		// Assert
+		assert.Equal(suite.T(), http.StatusOK, rr.Code)
+
+		response := suite.parsePlaylistsResponse(rr)
+		assert.Len(suite.T(), response.Data, 1, filter)
+	}
+}
+
+func (suite *IntegrationTestSuite) TestGetPlaylists_InvalidCursor() {
+	// Act
+	rr := suite.makeGetRequest(PlaylistsEndpoint + "?cursor=not-a-cursor")
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, rr.Code)
 }
 
 func (suite *IntegrationTestSuite) TestGetPlaylists_Empty() {
@@ -243,7 +550,7 @@ func (suite *IntegrationTestSuite) TestGetPlaylists_Empty() {
 	assert.Equal(suite.T(), http.StatusOK, rr.Code)
 
 	response := suite.parsePlaylistsResponse(rr)
-	assert.Len(suite.T(), response, 0)
+	assert.Len(suite.T(), response.Data, 0)
 }
 
 func (suite *IntegrationTestSuite) TestGetPlaylistByID_Success() {
@@ -285,6 +592,1453 @@ func (suite *IntegrationTestSuite) TestGetPlaylistByID_InvalidID() {
 	assert.Contains(suite.T(), errorResponse.Error, InvalidIDErrorMsg)
 }
 
+func (suite *IntegrationTestSuite) TestUpdatePlaylist_Success() {
+	// Arrange
+	testPlaylist := suite.buildTestPlaylist()
+	playlistID := suite.createTestPlaylist(testPlaylist.Name, testPlaylist.Tracks)
+	created := suite.parsePlaylistResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d", PlaylistsEndpoint, playlistID)))
+
+	requestBody := beans.PlaylistUpdateRequest{
+		Name: TestPlaylistName2,
+		Tracks: []beans.TrackCreateRequest{
+			{ID: created.Tracks[0].ID},
+			{Title: TestSong3Title, Artist: TestArtist3Name},
+		},
+	}
+
+	// Act
+	rr := suite.makeJSONRequest("PUT", fmt.Sprintf("%s/%d", PlaylistsEndpoint, playlistID), requestBody)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, rr.Code)
+
+	response := suite.parsePlaylistResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d", PlaylistsEndpoint, playlistID)))
+	assert.Equal(suite.T(), TestPlaylistName2, response.Name)
+	assert.Len(suite.T(), response.Tracks, 2)
+	assert.Equal(suite.T(), created.Tracks[0].ID, response.Tracks[0].ID)
+	assert.Equal(suite.T(), TestSong3Title, response.Tracks[1].Title)
+}
+
+func (suite *IntegrationTestSuite) TestUpdatePlaylist_EmptyName() {
+	// Arrange
+	testPlaylist := suite.buildTestPlaylist()
+	playlistID := suite.createTestPlaylist(testPlaylist.Name, testPlaylist.Tracks)
+
+	// Act
+	rr := suite.makeJSONRequest("PUT", fmt.Sprintf("%s/%d", PlaylistsEndpoint, playlistID), beans.PlaylistUpdateRequest{})
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, rr.Code)
+}
+
+func (suite *IntegrationTestSuite) TestRenamePlaylist_Success() {
+	// Arrange
+	testPlaylist := suite.buildTestPlaylist()
+	playlistID := suite.createTestPlaylist(testPlaylist.Name, testPlaylist.Tracks)
+	var entries []models.PlaylistTrack
+	suite.Require().NoError(suite.db.Where("playlist_id = ?", playlistID).Order("id").Find(&entries).Error)
+
+	// Act
+	rr := suite.makeJSONRequest("PATCH", fmt.Sprintf("%s/%d", PlaylistsEndpoint, playlistID),
+		beans.PlaylistRenameRequest{Name: TestPlaylistName1})
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, rr.Code)
+
+	response := suite.parsePlaylistResponse(rr)
+	assert.Equal(suite.T(), TestPlaylistName1, response.Name)
+	assert.Len(suite.T(), response.Tracks, 2)
+
+	// Seul le nom est modifié, les entrées de la playlist ne sont pas recréées
+	var renamed []models.PlaylistTrack
+	suite.Require().NoError(suite.db.Where("playlist_id = ?", playlistID).Order("id").Find(&renamed).Error)
+	assert.Equal(suite.T(), entries, renamed)
+}
+
+func (suite *IntegrationTestSuite) TestDeletePlaylist_Success() {
+	// Arrange
+	testPlaylist := suite.buildTestPlaylist()
+	playlistID := suite.createTestPlaylist(testPlaylist.Name, testPlaylist.Tracks)
+	playlist := suite.parsePlaylistResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d", PlaylistsEndpoint, playlistID)))
+	suite.Require().NoError(suite.db.Create(&models.TrackPlay{
+		PlaylistID: int64(playlistID),
+		TrackID:    playlist.Tracks[0].ID,
+		PlayedAt:   time.Now(),
+	}).Error)
+
+	// Act
+	rr := suite.makeDeleteRequest(fmt.Sprintf("%s/%d", PlaylistsEndpoint, playlistID))
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusNoContent, rr.Code)
+	assert.Equal(suite.T(), http.StatusNotFound, suite.makeGetRequest(fmt.Sprintf("%s/%d", PlaylistsEndpoint, playlistID)).Code)
+
+	var tracksCount, playsCount int64
+	suite.db.Model(&models.PlaylistTrack{}).Where("playlist_id = ?", playlistID).Count(&tracksCount)
+	suite.db.Model(&models.TrackPlay{}).Where("playlist_id = ?", playlistID).Count(&playsCount)
+	assert.Zero(suite.T(), tracksCount)
+	assert.Equal(suite.T(), int64(1), playsCount, "l'historique de diffusion est conservé")
+
+	// La playlist supprimée garde son nom pour l'historique
+	var deleted models.Playlist
+	suite.Require().NoError(suite.db.Unscoped().First(&deleted, playlistID).Error)
+	assert.Equal(suite.T(), testPlaylist.Name, deleted.Name)
+	assert.True(suite.T(), deleted.DeletedAt.Valid)
+	assert.Equal(suite.T(), http.StatusNotFound, suite.makeDeleteRequest(fmt.Sprintf("%s/%d", PlaylistsEndpoint, playlistID)).Code)
+}
+
+func (suite *IntegrationTestSuite) TestDeletePlaylist_NotFound() {
+	// Act
+	rr := suite.makeDeleteRequest(fmt.Sprintf("%s/%d", PlaylistsEndpoint, NonExistentID))
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusNotFound, rr.Code)
+}
+
+func (suite *IntegrationTestSuite) TestAddTrack_InsertAtPosition() {
+	// Arrange
+	testPlaylist := suite.buildTestPlaylist()
+	playlistID := suite.createTestPlaylist(testPlaylist.Name, testPlaylist.Tracks)
+	position := 1
+
+	// Act
+	rr := suite.makePostRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID),
+		beans.TrackAddRequest{Title: TestSong3Title, Artist: TestArtist3Name, Position: &position})
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusCreated, rr.Code)
+
+	tracks := suite.parseTracksResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID)))
+	assert.Len(suite.T(), tracks, 3)
+	assert.Equal(suite.T(), []string{TestSong1Title, TestSong3Title, TestSong2Title},
+		[]string{tracks[0].Title, tracks[1].Title, tracks[2].Title})
+	for i, track := range tracks {
+		assert.Equal(suite.T(), i, track.Position)
+	}
+}
+
+func (suite *IntegrationTestSuite) TestAddTrack_TooManyTracks() {
+	// Arrange
+	tracks := make([]beans.TrackCreateRequest, constants.MaxTracksPerPlaylist)
+	for i := range tracks {
+		tracks[i] = suite.buildTrack(fmt.Sprintf("Song %d", i), TestArtist1Name)
+	}
+	playlistID := suite.createTestPlaylist(TestPlaylistName, tracks)
+
+	// Act
+	rr := suite.makePostRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID),
+		beans.TrackAddRequest{Title: TestSong1Title, Artist: TestArtist2Name})
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, rr.Code)
+
+	stored := suite.parseTracksResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID)))
+	assert.Len(suite.T(), stored, constants.MaxTracksPerPlaylist)
+}
+
+func (suite *IntegrationTestSuite) TestMoveTrack_Success() {
+	// Arrange
+	playlistID := suite.createTestPlaylist(TestPlaylistName, []beans.TrackCreateRequest{
+		suite.buildTrack(TestSong1Title, TestArtist1Name),
+		suite.buildTrack(TestSong2Title, TestArtist2Name),
+		suite.buildTrack(TestSong3Title, TestArtist3Name),
+	})
+	tracks := suite.parseTracksResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID)))
+	position := 0
+
+	// Act
+	rr := suite.makeJSONRequest("PATCH", fmt.Sprintf("%s/%d/tracks/%d", PlaylistsEndpoint, playlistID, tracks[2].ID),
+		beans.TrackMoveRequest{Position: &position})
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, rr.Code)
+
+	moved := suite.parseTracksResponse(rr)
+	assert.Equal(suite.T(), []string{TestSong3Title, TestSong1Title, TestSong2Title},
+		[]string{moved[0].Title, moved[1].Title, moved[2].Title})
+}
+
+func (suite *IntegrationTestSuite) TestRemoveTrack_Success() {
+	// Arrange
+	playlistID := suite.createTestPlaylist(TestPlaylistName, []beans.TrackCreateRequest{
+		suite.buildTrack(TestSong1Title, TestArtist1Name),
+		suite.buildTrack(TestSong2Title, TestArtist2Name),
+		suite.buildTrack(TestSong3Title, TestArtist3Name),
+	})
+	tracks := suite.parseTracksResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID)))
+
+	// Act
+	rr := suite.makeDeleteRequest(fmt.Sprintf("%s/%d/tracks/%d", PlaylistsEndpoint, playlistID, tracks[0].ID))
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusNoContent, rr.Code)
+
+	remaining := suite.parseTracksResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID)))
+	assert.Len(suite.T(), remaining, 2)
+	assert.Equal(suite.T(), TestSong2Title, remaining[0].Title)
+	assert.Equal(suite.T(), 0, remaining[0].Position)
+	assert.Equal(suite.T(), 1, remaining[1].Position)
+}
+
+func (suite *IntegrationTestSuite) TestRemoveTrack_NotFound() {
+	// Arrange
+	testPlaylist := suite.buildTestPlaylist()
+	playlistID := suite.createTestPlaylist(testPlaylist.Name, testPlaylist.Tracks)
+
+	// Act
+	rr := suite.makeDeleteRequest(fmt.Sprintf("%s/%d/tracks/%d", PlaylistsEndpoint, playlistID, NonExistentID))
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusNotFound, rr.Code)
+}
+
+func (suite *IntegrationTestSuite) TestCatalog_SharedTrackAcrossPlaylists() {
+	// Arrange
+	suite.createTestPlaylist(TestPlaylistName1, []beans.TrackCreateRequest{
+		suite.buildTrack(TestSong1Title, TestArtist1Name),
+	})
+
+	// Act
+	playlistID := suite.createTestPlaylist(TestPlaylistName2, []beans.TrackCreateRequest{
+		suite.buildTrack(TestSong1Title, TestArtist1Name),
+		suite.buildTrack(TestSong2Title, TestArtist2Name),
+	})
+
+	// Assert
+	rr := suite.makeGetRequest(TracksEndpoint)
+	assert.Equal(suite.T(), http.StatusOK, rr.Code)
+
+	var catalog beans.TrackPageResponse
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &catalog))
+	assert.Len(suite.T(), catalog.Data, 2)
+
+	playlist := suite.parsePlaylistResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d", PlaylistsEndpoint, playlistID)))
+	assert.Equal(suite.T(), catalog.Data[0].ID, playlist.Tracks[0].ID)
+}
+
+func (suite *IntegrationTestSuite) TestCatalog_CreateTrackConflict() {
+	// Arrange
+	request := beans.CatalogTrackRequest{Title: TestSong1Title, Artist: TestArtist1Name}
+	suite.Require().Equal(http.StatusCreated, suite.makePostRequest(TracksEndpoint, request).Code)
+
+	// Act
+	rr := suite.makePostRequest(TracksEndpoint, request)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusConflict, rr.Code)
+}
+
+func (suite *IntegrationTestSuite) TestCatalog_ISRC() {
+	// Act
+	rr := suite.makePostRequest(TracksEndpoint, beans.CatalogTrackRequest{Title: TestSong1Title, Artist: TestArtist1Name, ISRC: "gb-um7-10-29604"})
+	invalid := suite.makePostRequest(TracksEndpoint, beans.CatalogTrackRequest{Title: TestSong2Title, Artist: TestArtist2Name, ISRC: "GB-UM7"})
+
+	// Assert
+	suite.Require().Equal(http.StatusCreated, rr.Code)
+	var track beans.CatalogTrackResponseApiBean
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &track))
+	assert.Equal(suite.T(), "GBUM71029604", track.ISRC)
+	assert.Equal(suite.T(), http.StatusBadRequest, invalid.Code)
+}
+
+func (suite *IntegrationTestSuite) TestCatalog_AddTrackByID() {
+	// Arrange
+	rr := suite.makePostRequest(TracksEndpoint, beans.CatalogTrackRequest{Title: TestSong3Title, Artist: TestArtist3Name})
+	suite.Require().Equal(http.StatusCreated, rr.Code)
+	var track beans.CatalogTrackResponseApiBean
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &track))
+
+	testPlaylist := suite.buildTestPlaylist()
+	playlistID := suite.createTestPlaylist(testPlaylist.Name, testPlaylist.Tracks)
+
+	// Act
+	rr = suite.makePostRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID), beans.TrackAddRequest{ID: track.ID})
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusCreated, rr.Code)
+
+	duplicate := suite.makePostRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID), beans.TrackAddRequest{ID: track.ID})
+	assert.Equal(suite.T(), http.StatusConflict, duplicate.Code)
+
+	tracks := suite.parseTracksResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID)))
+	assert.Len(suite.T(), tracks, 3)
+	assert.Equal(suite.T(), TestSong3Title, tracks[2].Title)
+}
+
+func (suite *IntegrationTestSuite) TestCatalog_DeleteTrackRemovesItFromPlaylists() {
+	// Arrange
+	playlistID := suite.createTestPlaylist(TestPlaylistName, []beans.TrackCreateRequest{
+		suite.buildTrack(TestSong1Title, TestArtist1Name),
+		suite.buildTrack(TestSong2Title, TestArtist2Name),
+	})
+	tracks := suite.parseTracksResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID)))
+
+	// Act
+	rr := suite.makeDeleteRequest(fmt.Sprintf("%s/%d", TracksEndpoint, tracks[0].ID))
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusNoContent, rr.Code)
+
+	remaining := suite.parseTracksResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID)))
+	assert.Len(suite.T(), remaining, 1)
+	assert.Equal(suite.T(), TestSong2Title, remaining[0].Title)
+	assert.Equal(suite.T(), 0, remaining[0].Position)
+	assert.Equal(suite.T(), http.StatusNotFound, suite.makeGetRequest(fmt.Sprintf("%s/%d", TracksEndpoint, tracks[0].ID)).Code)
+	assert.Equal(suite.T(), http.StatusNotFound, suite.makeDeleteRequest(fmt.Sprintf("%s/%d", TracksEndpoint, tracks[0].ID)).Code)
+
+	// La track supprimée garde son titre pour l'historique de lecture
+	var deleted models.Track
+	suite.Require().NoError(suite.db.Unscoped().First(&deleted, tracks[0].ID).Error)
+	assert.Equal(suite.T(), TestSong1Title, deleted.Title)
+	assert.True(suite.T(), deleted.DeletedAt.Valid)
+}
+
+func (suite *IntegrationTestSuite) TestArtist_NormalizedNameMatching() {
+	// Arrange
+	rr := suite.makePostRequest(TracksEndpoint, beans.CatalogTrackRequest{Title: TestSong1Title, Artist: "Queen", Album: "A Night at the Opera"})
+	suite.Require().Equal(http.StatusCreated, rr.Code)
+	var first beans.CatalogTrackResponseApiBean
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &first))
+
+	// Act
+	rr = suite.makePostRequest(TracksEndpoint, beans.CatalogTrackRequest{Title: TestSong2Title, Artist: " queen ", Album: "a night at the  opera"})
+
+	// Assert
+	suite.Require().Equal(http.StatusCreated, rr.Code)
+	var second beans.CatalogTrackResponseApiBean
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &second))
+	assert.Equal(suite.T(), first.ArtistID, second.ArtistID)
+	assert.Equal(suite.T(), "Queen", second.Artist)
+	suite.Require().NotNil(second.Album)
+	assert.Equal(suite.T(), first.Album.ID, second.Album.ID)
+
+	duplicate := suite.makePostRequest(TracksEndpoint, beans.CatalogTrackRequest{Title: " song 1", Artist: "QUEEN"})
+	assert.Equal(suite.T(), http.StatusConflict, duplicate.Code)
+}
+
+func (suite *IntegrationTestSuite) TestArtist_GetWithTracksAndPlaylists() {
+	// Arrange
+	suite.createTestPlaylist(TestPlaylistName1, []beans.TrackCreateRequest{
+		suite.buildTrack(TestSong1Title, TestArtist1Name),
+		suite.buildTrack(TestSong2Title, TestArtist2Name),
+	})
+	suite.createTestPlaylist(TestPlaylistName2, []beans.TrackCreateRequest{
+		suite.buildTrack(TestSong3Title, "artist  1"),
+	})
+
+	rr := suite.makeGetRequest(ArtistsEndpoint + "?name=ARTIST%201")
+	suite.Require().Equal(http.StatusOK, rr.Code)
+	var artists beans.ArtistListResponse
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &artists))
+	suite.Require().Len(artists.Data, 1)
+
+	// Act
+	rr = suite.makeGetRequest(fmt.Sprintf("%s/%d", ArtistsEndpoint, artists.Data[0].ID))
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, rr.Code)
+	var artist beans.ArtistResponseApiBean
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &artist))
+	assert.Equal(suite.T(), TestArtist1Name, artist.Name)
+	assert.Len(suite.T(), artist.Tracks, 2)
+	suite.Require().Len(artist.Playlists, 2)
+	assert.Equal(suite.T(), TestPlaylistName1, artist.Playlists[0].Name)
+}
+
+func (suite *IntegrationTestSuite) TestAlbum_GetWithTracksAndPlaylists() {
+	// Arrange
+	rr := suite.makePostRequest(TracksEndpoint, beans.CatalogTrackRequest{Title: TestSong1Title, Artist: TestArtist1Name, Album: "Album"})
+	suite.Require().Equal(http.StatusCreated, rr.Code)
+	var track beans.CatalogTrackResponseApiBean
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &track))
+	suite.Require().NotNil(track.Album)
+
+	playlistID := suite.createTestPlaylist(TestPlaylistName, nil)
+	rr = suite.makePostRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID), beans.TrackAddRequest{ID: track.ID})
+	suite.Require().Equal(http.StatusCreated, rr.Code)
+
+	// Act
+	rr = suite.makeGetRequest(fmt.Sprintf("%s/%d", AlbumsEndpoint, track.Album.ID))
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, rr.Code)
+	var album beans.AlbumResponseApiBean
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &album))
+	assert.Equal(suite.T(), track.ArtistID, album.Artist.ID)
+	suite.Require().Len(album.Tracks, 1)
+	assert.Equal(suite.T(), TestSong1Title, album.Tracks[0].Title)
+	suite.Require().Len(album.Playlists, 1)
+	assert.Equal(suite.T(), int64(playlistID), album.Playlists[0].ID)
+}
+
+func (suite *IntegrationTestSuite) TestArtist_NotFound() {
+	// Act
+	rr := suite.makeGetRequest(fmt.Sprintf("%s/%d", ArtistsEndpoint, NonExistentID))
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusNotFound, rr.Code)
+	assert.Equal(suite.T(), http.StatusNotFound, suite.makeGetRequest(fmt.Sprintf("%s/%d", AlbumsEndpoint, NonExistentID)).Code)
+}
+
+func (suite *IntegrationTestSuite) TestGetSession_ReportsCurrentTrack() {
+	// Arrange
+	startedAt := time.Now().Add(-45 * time.Second)
+	session := models.PlaySession{
+		PlaylistID:     1,
+		Status:         models.PlaySessionPaused,
+		CurrentIndex:   1,
+		Sequence:       2,
+		TrackStartedAt: &startedAt,
+		TrackElapsedMs: 30000,
+		Items: []models.PlaySessionItem{
+			{TrackID: 1, Title: TestSong1Title, Artist: TestArtist1Name, Position: 0, DurationSeconds: 180},
+			{TrackID: 2, Title: TestSong2Title, Artist: TestArtist2Name, Position: 1, DurationSeconds: 200},
+		},
+	}
+	suite.Require().NoError(suite.db.Create(&session).Error)
+
+	// Act
+	rr := suite.makeGetRequest(fmt.Sprintf("%s/%d", SessionsEndpoint, session.ID))
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, rr.Code)
+	var resp beans.PlaySessionResponseApiBean
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(suite.T(), "paused", resp.Status)
+	suite.Require().NotNil(resp.CurrentTrack)
+	assert.Equal(suite.T(), TestSong2Title, resp.CurrentTrack.Title)
+	assert.Equal(suite.T(), 30, resp.ElapsedSeconds)
+	assert.Equal(suite.T(), 2, resp.TracksCount)
+}
+
+func (suite *IntegrationTestSuite) TestSessionCommand_FinishedSession() {
+	// Arrange
+	session := models.PlaySession{PlaylistID: 1, Status: models.PlaySessionFinished}
+	suite.Require().NoError(suite.db.Create(&session).Error)
+
+	// Act
+	rr := suite.makePostRequest(fmt.Sprintf("%s/%d/pause", SessionsEndpoint, session.ID), nil)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusConflict, rr.Code)
+	assert.Equal(suite.T(), http.StatusNotFound, suite.makePostRequest(fmt.Sprintf("%s/%d/stop", SessionsEndpoint, NonExistentID), nil).Code)
+}
+
+func (suite *IntegrationTestSuite) TestPlayPlaylist_InvalidOptions() {
+	// Arrange
+	testPlaylist := suite.buildTestPlaylist()
+	playlistID := suite.createTestPlaylist(testPlaylist.Name, testPlaylist.Tracks)
+
+	// Act
+	rr := suite.makePostRequest(fmt.Sprintf("%s/%d/play", PlaylistsEndpoint, playlistID), beans.PlaylistPlayRequest{Shuffle: "sometimes"})
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, rr.Code)
+	assert.Contains(suite.T(), suite.parseErrorResponse(rr).Error, ValidationErrorMsg)
+}
+
+func (suite *IntegrationTestSuite) TestPlayPlaylist_EventsDeliveredThroughMemoryBus() {
+	// Arrange : le moteur, le relais de l'outbox et le consommateur reliés par le bus en mémoire
+	cfg, err := config.Load()
+	suite.Require().NoError(err)
+	bus := messaging.NewMemoryBus(cfg.Messaging.RabbitMQ)
+	defer bus.Close()
+
+	outboxRepo := repositories.NewOutboxRepository(suite.db)
+	playlistService := &services.PlaylistService{Repo: repositories.NewPlaylistRepository(suite.db), RecordEvents: true}
+	trackPlayRepo := repositories.NewTrackPlayRepository(suite.db)
+	rotationService := services.NewRotationService(repositories.NewRotationRuleRepository(suite.db), trackPlayRepo, playlistService)
+	engine := services.NewPlaybackEngine(repositories.NewPlaySessionRepository(suite.db), rotationService)
+	relay := services.NewOutboxRelay(outboxRepo, bus, time.Second, 0)
+	engine.AddListener(relay)
+	consumerService := services.NewTrackPlayConsumerService(bus, services.NewTrackPlayService(trackPlayRepo))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	suite.Require().NoError(consumerService.Start(ctx))
+	relay.Start(ctx)
+	suite.Require().NoError(engine.Start(ctx))
+
+	router := chi.NewRouter()
+	appService := services.NewPlaylistApplicationService(playlistService, services.NewPlaylistPlayService(playlistService, engine))
+	NewPlaylistHandler(playlistService, appService).Routes(router)
+	NewSessionHandler(engine).Routes(router)
+
+	testPlaylist := suite.buildTestPlaylist()
+	body, err := json.Marshal(suite.buildPlaylistRequest(testPlaylist.Name, testPlaylist.Tracks...))
+	suite.Require().NoError(err)
+
+	// Act : la playlist est créée, sa première track démarre puis la session est arrêtée
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", PlaylistsEndpoint, bytes.NewReader(body))
+	req.Header.Set("Content-Type", ContentTypeJSON)
+	router.ServeHTTP(rr, req)
+	suite.Require().Equal(http.StatusCreated, rr.Code)
+	playlistID := int(suite.parsePlaylistResponse(rr).ID)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", fmt.Sprintf("%s/%d/play", PlaylistsEndpoint, playlistID), nil))
+	suite.Require().Equal(http.StatusOK, rr.Code)
+	var play beans.PlaylistPlayResponse
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &play))
+	assert.Len(suite.T(), play.TrackIDs, len(testPlaylist.Tracks))
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", fmt.Sprintf("%s/%d/stop", SessionsEndpoint, play.SessionID), nil))
+	suite.Require().Equal(http.StatusOK, rr.Code)
+
+	// Assert : les deux événements ont traversé l'outbox et le bus jusqu'à l'historique
+	suite.Require().Eventually(func() bool {
+		stats := consumerService.Stats()
+		return stats.PlayedEvents == 1 && stats.EndedEvents == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	engine.Wait()
+	relay.Wait()
+	consumerService.Stop()
+
+	var plays []models.TrackPlay
+	suite.Require().NoError(suite.db.Find(&plays).Error)
+	suite.Require().Len(plays, 1)
+	assert.Equal(suite.T(), int64(playlistID), plays[0].PlaylistID)
+	suite.Require().NotNil(plays[0].SessionID)
+	assert.Equal(suite.T(), play.SessionID, *plays[0].SessionID)
+	assert.Equal(suite.T(), models.TrackEndStopped, plays[0].EndReason)
+	assert.NotNil(suite.T(), plays[0].EndedAt)
+
+	// Les événements des playlists, sans queue souscrite, sont publiés sans être conservés par le bus
+	var eventTypes []string
+	for _, message := range suite.getOutboxMessages("status=sent") {
+		eventTypes = append(eventTypes, message.EventType)
+	}
+	assert.ElementsMatch(suite.T(), []string{"playlist.created", "playlist.played", "track.played", "track.ended"}, eventTypes)
+	assert.Empty(suite.T(), suite.getOutboxMessages("status=pending"))
+}
+
+func (suite *IntegrationTestSuite) createTestClock(request beans.ClockRequest) beans.ClockResponseApiBean {
+	rr := suite.makePostRequest(ClocksEndpoint, request)
+	suite.Require().Equal(http.StatusCreated, rr.Code)
+
+	var clock beans.ClockResponseApiBean
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &clock))
+	return clock
+}
+
+func (suite *IntegrationTestSuite) TestClock_CreateAndUpdate() {
+	// Arrange
+	testPlaylist := suite.buildTestPlaylist()
+	playlistID := int64(suite.createTestPlaylist(testPlaylist.Name, testPlaylist.Tracks))
+	clock := suite.createTestClock(beans.ClockRequest{
+		Name:  "Morning",
+		Slots: []beans.ClockSlotApiBean{{Type: "playlist", PlaylistID: &playlistID, DurationSeconds: 1200}},
+	})
+
+	// Act
+	rr := suite.makeJSONRequest("PUT", fmt.Sprintf("%s/%d", ClocksEndpoint, clock.ID), beans.ClockRequest{
+		Name: "Morning show",
+		Slots: []beans.ClockSlotApiBean{
+			{Type: "rule", DurationSeconds: 600},
+			{Type: "playlist", PlaylistID: &playlistID, Shuffle: true},
+		},
+	})
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, rr.Code)
+	rr = suite.makeGetRequest(fmt.Sprintf("%s/%d", ClocksEndpoint, clock.ID))
+	var updated beans.ClockResponseApiBean
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &updated))
+	assert.Equal(suite.T(), "Morning show", updated.Name)
+	suite.Require().Len(updated.Slots, 2)
+	assert.Equal(suite.T(), "rule", updated.Slots[0].Type)
+	assert.Equal(suite.T(), playlistID, *updated.Slots[1].PlaylistID)
+}
+
+func (suite *IntegrationTestSuite) TestClock_UnknownPlaylist() {
+	// Arrange
+	unknownID := int64(NonExistentID)
+
+	// Act
+	rr := suite.makePostRequest(ClocksEndpoint, beans.ClockRequest{
+		Name:  "Morning",
+		Slots: []beans.ClockSlotApiBean{{Type: "playlist", PlaylistID: &unknownID}},
+	})
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, rr.Code)
+	assert.Contains(suite.T(), suite.parseErrorResponse(rr).Error, NotFoundErrorMsg)
+}
+
+func (suite *IntegrationTestSuite) TestGrid_AssignAndDeleteClock() {
+	// Arrange
+	clock := suite.createTestClock(beans.ClockRequest{Name: "Night", Slots: []beans.ClockSlotApiBean{{Type: "rule", DurationSeconds: 3600}}})
+
+	// Act
+	rr := suite.makeJSONRequest("PUT", GridEndpoint+"/1/22", beans.GridAssignRequest{ClockID: int(clock.ID)})
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, rr.Code)
+	var grid beans.GridResponse
+	suite.Require().NoError(json.Unmarshal(suite.makeGetRequest(GridEndpoint).Body.Bytes(), &grid))
+	suite.Require().Len(grid.Data, 1)
+	assert.Equal(suite.T(), 22, grid.Data[0].Hour)
+	assert.Equal(suite.T(), "Night", grid.Data[0].Clock.Name)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.makeJSONRequest("PUT", GridEndpoint+"/7/22", beans.GridAssignRequest{ClockID: int(clock.ID)}).Code)
+
+	// La suppression de la clock libère la grille
+	assert.Equal(suite.T(), http.StatusNoContent, suite.makeDeleteRequest(fmt.Sprintf("%s/%d", ClocksEndpoint, clock.ID)).Code)
+	suite.Require().NoError(json.Unmarshal(suite.makeGetRequest(GridEndpoint).Body.Bytes(), &grid))
+	assert.Empty(suite.T(), grid.Data)
+}
+
+func (suite *IntegrationTestSuite) TestSchedulePreview() {
+	// Arrange
+	rr := suite.makePostRequest(TracksEndpoint, beans.CatalogTrackRequest{Title: "Station ID", Artist: "Radio", DurationSeconds: 10})
+	suite.Require().Equal(http.StatusCreated, rr.Code)
+	var jingle beans.CatalogTrackResponseApiBean
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &jingle))
+
+	testPlaylist := suite.buildTestPlaylist()
+	playlistID := int64(suite.createTestPlaylist(testPlaylist.Name, testPlaylist.Tracks))
+	clock := suite.createTestClock(beans.ClockRequest{
+		Name: "Morning",
+		Slots: []beans.ClockSlotApiBean{
+			{Type: "jingle", TrackID: &jingle.ID},
+			{Type: "playlist", PlaylistID: &playlistID},
+		},
+	})
+	// 2024-01-15 est un lundi
+	suite.Require().Equal(http.StatusOK, suite.makeJSONRequest("PUT", GridEndpoint+"/1/8", beans.GridAssignRequest{ClockID: int(clock.ID)}).Code)
+
+	// Act
+	rr = suite.makeGetRequest(PreviewEndpoint + "?date=2024-01-15&tz=Europe/Paris")
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, rr.Code)
+	var preview beans.SchedulePreviewResponse
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &preview))
+	suite.Require().Len(preview.Hours, 24)
+	assert.Nil(suite.T(), preview.Hours[7].Clock)
+
+	morning := preview.Hours[8]
+	suite.Require().NotNil(morning.Clock)
+	suite.Require().Len(morning.Items, 1+len(testPlaylist.Tracks))
+	assert.Equal(suite.T(), "jingle", morning.Items[0].SlotType)
+	assert.Equal(suite.T(), playlistID, morning.Items[1].PlaylistID)
+	assert.Equal(suite.T(), 10*time.Second, morning.Items[1].StartsAt.Sub(morning.Items[0].StartsAt))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.makeGetRequest(PreviewEndpoint+"?date=15/01/2024").Code)
+}
+
+func (suite *IntegrationTestSuite) TestRotationRule_CreateDisabled() {
+	// Arrange
+	enabled := false
+	request := beans.RotationRuleRequest{Name: "Artist separation", Subject: "artist", WindowMinutes: 30, Enabled: &enabled}
+
+	// Act
+	rr := suite.makePostRequest(RotationEndpoint+"/rules", request)
+
+	// Assert
+	suite.Require().Equal(http.StatusCreated, rr.Code)
+	var rule beans.RotationRuleResponseApiBean
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &rule))
+	assert.Equal(suite.T(), 1, rule.MaxPlays)
+
+	rr = suite.makeGetRequest(fmt.Sprintf("%s/rules/%d", RotationEndpoint, rule.ID))
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &rule))
+	assert.False(suite.T(), rule.Enabled)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.makePostRequest(RotationEndpoint+"/rules", beans.RotationRuleRequest{Name: "x", Subject: "album", WindowMinutes: 30}).Code)
+}
+
+func (suite *IntegrationTestSuite) TestRotationDryRun_ReportsViolationsFromHistory() {
+	// Arrange
+	playlistID := suite.createTestPlaylist(TestPlaylistName, []beans.TrackCreateRequest{
+		suite.buildTrack(TestSong1Title, TestArtist1Name),
+		suite.buildTrack(TestSong2Title, TestArtist2Name),
+	})
+	tracks := suite.parseTracksResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID)))
+
+	startsAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	played := models.TrackPlay{PlaylistID: int64(playlistID), TrackID: tracks[1].ID, PlayedAt: startsAt.Add(-10 * time.Minute)}
+	suite.Require().NoError(suite.db.Create(&played).Error)
+
+	rule := beans.RotationRuleRequest{Name: "Artist separation", Subject: "artist", WindowMinutes: 30}
+	suite.Require().Equal(http.StatusCreated, suite.makePostRequest(RotationEndpoint+"/rules", rule).Code)
+
+	// Act
+	rr := suite.makePostRequest(RotationEndpoint+"/dry-run", beans.RotationDryRunRequest{PlaylistID: &playlistID, StartsAt: &startsAt})
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, rr.Code)
+	var report beans.RotationDryRunResponse
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &report))
+	suite.Require().Len(report.Items, 2)
+	assert.Equal(suite.T(), 1, report.ViolationsCount)
+	assert.Empty(suite.T(), report.Items[0].Violations)
+	suite.Require().Len(report.Items[1].Violations, 1)
+	assert.Equal(suite.T(), "Artist separation", report.Items[1].Violations[0].RuleName)
+
+	// Une file mélangée est réordonnée pour jouer d'abord l'artiste qui n'enfreint pas la règle
+	for seed := int64(0); seed < 5; seed++ {
+		rr = suite.makePostRequest(RotationEndpoint+"/dry-run", beans.RotationDryRunRequest{PlaylistID: &playlistID, Shuffle: "random", Seed: &seed, StartsAt: &startsAt})
+		suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &report))
+		assert.Equal(suite.T(), tracks[0].ID, report.Items[0].TrackID)
+	}
+}
+
+func (suite *IntegrationTestSuite) createOnAirSession(clockID *int64) models.PlaySession {
+	startedAt := time.Now().Add(-20 * time.Second)
+	session := models.PlaySession{
+		PlaylistID:     1,
+		ClockID:        clockID,
+		Status:         models.PlaySessionPlaying,
+		Sequence:       1,
+		TrackStartedAt: &startedAt,
+		TrackResumedAt: &startedAt,
+		Items: []models.PlaySessionItem{
+			{TrackID: 1, Title: TestSong1Title, Artist: TestArtist1Name, Position: 0, DurationSeconds: 180},
+			{TrackID: 2, Title: TestSong2Title, Artist: TestArtist2Name, Position: 1, DurationSeconds: 200},
+		},
+	}
+	suite.Require().NoError(suite.db.Create(&session).Error)
+	return session
+}
+
+func (suite *IntegrationTestSuite) TestNowPlaying_StationAndSession() {
+	// Arrange
+	clockID := int64(1)
+	suite.createOnAirSession(nil)
+	scheduled := suite.createOnAirSession(&clockID)
+
+	// Act
+	station := suite.makeGetRequest(NowPlayingEndpoint)
+	bySession := suite.makeGetRequest(fmt.Sprintf("%s?session_id=%d", NowPlayingEndpoint, scheduled.ID))
+
+	// Assert
+	for _, rr := range []*httptest.ResponseRecorder{station, bySession} {
+		assert.Equal(suite.T(), http.StatusOK, rr.Code)
+		var resp beans.NowPlayingResponseApiBean
+		suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(suite.T(), scheduled.ID, resp.SessionID)
+		suite.Require().NotNil(resp.Current)
+		assert.Equal(suite.T(), TestSong1Title, resp.Current.Title)
+		suite.Require().NotNil(resp.Next)
+		assert.Equal(suite.T(), TestSong2Title, resp.Next.Title)
+	}
+}
+
+func (suite *IntegrationTestSuite) TestNowPlaying_NothingOnAir() {
+	// Act
+	rr := suite.makeGetRequest(NowPlayingEndpoint)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusNotFound, rr.Code)
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.makeGetRequest(NowPlayingEndpoint+"?session_id="+InvalidIDStr).Code)
+}
+
+// readSSEEvent lit le flux jusqu'au prochain événement et retourne son nom
+func readSSEEvent(reader *bufio.Reader) (string, error) {
+	event := ""
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		line = strings.TrimSpace(line)
+		if name, ok := strings.CutPrefix(line, "event: "); ok {
+			event = name
+		}
+		if line == "" && event != "" {
+			return event, nil
+		}
+	}
+}
+
+func (suite *IntegrationTestSuite) TestNowPlaying_StreamPushesTrackChanges() {
+	// Arrange
+	session := suite.createOnAirSession(nil)
+	server := httptest.NewServer(suite.router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + NowPlayingEndpoint + "/stream")
+	suite.Require().NoError(err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(suite.T(), "text/event-stream", resp.Header.Get("Content-Type"))
+	reader := bufio.NewReader(resp.Body)
+
+	// Le premier événement décrit l'état courant et garantit que l'abonnement est en place
+	event, err := readSSEEvent(reader)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "now-playing", event)
+
+	// Act
+	suite.nowPlaying.TrackPlayed(models.TrackPlayedEvent{SessionID: session.ID, TrackID: 2, PlayedAt: time.Now()})
+	played, err := readSSEEvent(reader)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(suite.db.Model(&session).Update("status", models.PlaySessionStopped).Error)
+	suite.nowPlaying.TrackEnded(models.TrackEndedEvent{SessionID: session.ID, TrackID: 2, Reason: models.TrackEndStopped})
+	stopped, err := readSSEEvent(reader)
+	suite.Require().NoError(err)
+
+	// Assert
+	assert.Equal(suite.T(), "now-playing", played)
+	assert.Equal(suite.T(), "off-air", stopped)
+}
+
+func (suite *IntegrationTestSuite) readStudioMessage(conn *websocket.Conn) beans.StudioMessageApiBean {
+	var message beans.StudioMessageApiBean
+	suite.Require().NoError(conn.SetReadDeadline(time.Now().Add(time.Second)))
+	suite.Require().NoError(conn.ReadJSON(&message))
+	return message
+}
+
+func (suite *IntegrationTestSuite) TestStudio_SnapshotCommandsAndUpdates() {
+	// Arrange
+	session := suite.createOnAirSession(nil)
+	server := httptest.NewServer(suite.router)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/studio/ws", nil)
+	suite.Require().NoError(err)
+	defer func() { _ = conn.Close() }()
+
+	// Assert : la connexion commence par les sessions actives et leur file
+	snapshot := suite.readStudioMessage(conn)
+	assert.Equal(suite.T(), "snapshot", snapshot.Type)
+	suite.Require().Len(snapshot.Sessions, 1)
+	assert.Equal(suite.T(), session.ID, snapshot.Sessions[0].ID)
+	assert.Len(suite.T(), snapshot.Sessions[0].Queue, 2)
+
+	// Act & Assert : une commande invalide est rejetée sans fermer la connexion
+	suite.Require().NoError(conn.WriteJSON(beans.StudioCommandApiBean{ID: "c1", Type: "insert", SessionID: session.ID}))
+	rejected := suite.readStudioMessage(conn)
+	assert.Equal(suite.T(), "error", rejected.Type)
+	assert.Equal(suite.T(), "c1", rejected.ID)
+
+	// La session n'est pas jouée par le moteur : la commande échoue comme via l'API HTTP
+	suite.Require().NoError(conn.WriteJSON(beans.StudioCommandApiBean{ID: "c2", Type: "skip", SessionID: session.ID}))
+	conflict := suite.readStudioMessage(conn)
+	assert.Equal(suite.T(), "error", conflict.Type)
+	assert.Equal(suite.T(), "c2", conflict.ID)
+	assert.Contains(suite.T(), conflict.Error, "play session is over")
+
+	// Les changements d'état des sessions sont poussés à la console
+	session.Status = models.PlaySessionPaused
+	suite.studio.SessionChanged(session)
+	update := suite.readStudioMessage(conn)
+	assert.Equal(suite.T(), "session", update.Type)
+	suite.Require().NotNil(update.Session)
+	assert.Equal(suite.T(), "paused", update.Session.Status)
+}
+
+func (suite *IntegrationTestSuite) TestStudio_ChecksOriginAndAcceptsBearerProtocol() {
+	// Arrange
+	server := httptest.NewServer(suite.router)
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/studio/ws"
+	dialer := websocket.Dialer{Subprotocols: []string{"bearer", "token"}}
+
+	tests := []struct {
+		origin     string
+		wantStatus int
+	}{
+		{origin: TestStudioOrigin, wantStatus: http.StatusSwitchingProtocols},
+		{origin: server.URL, wantStatus: http.StatusSwitchingProtocols},
+		{origin: "https://evil.example.com", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		// Act
+		conn, resp, err := dialer.Dial(url, http.Header{"Origin": {tt.origin}})
+
+		// Assert
+		suite.Require().NotNil(resp, tt.origin)
+		assert.Equal(suite.T(), tt.wantStatus, resp.StatusCode, tt.origin)
+		if tt.wantStatus != http.StatusSwitchingProtocols {
+			assert.Error(suite.T(), err)
+			continue
+		}
+		suite.Require().NoError(err)
+		assert.Equal(suite.T(), "bearer", conn.Subprotocol(), "le jeton n'est pas renvoyé")
+		_ = conn.Close()
+	}
+}
+
+func (suite *IntegrationTestSuite) TestStats_TopTracksArtistsAndHistograms() {
+	// Arrange : 3 lectures de Song 1 et 1 de Song 2, le lundi 15 janvier 2024 à Paris
+	playlistID := suite.createTestPlaylist(TestPlaylistName, []beans.TrackCreateRequest{
+		suite.buildTrack(TestSong1Title, TestArtist1Name),
+		suite.buildTrack(TestSong2Title, TestArtist2Name),
+	})
+	tracks := suite.parseTracksResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID)))
+	suite.Require().Len(tracks, 2)
+
+	paris, err := time.LoadLocation("Europe/Paris")
+	suite.Require().NoError(err)
+	plays := []models.TrackPlay{
+		{PlaylistID: int64(playlistID), TrackID: tracks[0].ID, PlayedAt: time.Date(2024, 1, 15, 8, 0, 0, 0, paris), EndReason: models.TrackEndCompleted},
+		{PlaylistID: int64(playlistID), TrackID: tracks[0].ID, PlayedAt: time.Date(2024, 1, 15, 8, 30, 0, 0, paris)},
+		{PlaylistID: int64(playlistID), TrackID: tracks[0].ID, PlayedAt: time.Date(2024, 1, 15, 23, 30, 0, 0, paris)},
+		{PlaylistID: int64(playlistID), TrackID: tracks[1].ID, PlayedAt: time.Date(2024, 1, 15, 9, 0, 0, 0, paris)},
+		// Hors période
+		{PlaylistID: int64(playlistID), TrackID: tracks[1].ID, PlayedAt: time.Date(2024, 1, 16, 9, 0, 0, 0, paris)},
+	}
+	playRepo := repositories.NewTrackPlayRepository(suite.db)
+	for i := range plays {
+		suite.Require().NoError(playRepo.Create(&plays[i]))
+	}
+	period := "from=2024-01-15&to=2024-01-15&tz=Europe/Paris"
+
+	// Act
+	topTracks := suite.makeGetRequest(StatsEndpoint + "/top-tracks?limit=1&" + period)
+	topArtists := suite.makeGetRequest(StatsEndpoint + "/top-artists?" + period)
+	hourly := suite.makeGetRequest(StatsEndpoint + "/hourly?" + period)
+	weekdays := suite.makeGetRequest(StatsEndpoint + "/weekdays?" + period)
+	playlists := suite.makeGetRequest(StatsEndpoint + "/playlists?" + period)
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, topTracks.Code)
+	var tracksResp beans.TopTracksResponse
+	suite.Require().NoError(json.Unmarshal(topTracks.Body.Bytes(), &tracksResp))
+	assert.Equal(suite.T(), "Europe/Paris", tracksResp.Timezone)
+	suite.Require().Len(tracksResp.Tracks, 1)
+	assert.Equal(suite.T(), TestSong1Title, tracksResp.Tracks[0].Title)
+	assert.Equal(suite.T(), 3, tracksResp.Tracks[0].Plays)
+	assert.Equal(suite.T(), 1, tracksResp.Tracks[0].CompletePlays)
+
+	var artistsResp beans.TopArtistsResponse
+	suite.Require().NoError(json.Unmarshal(topArtists.Body.Bytes(), &artistsResp))
+	suite.Require().Len(artistsResp.Artists, 2)
+	assert.Equal(suite.T(), TestArtist1Name, artistsResp.Artists[0].Name)
+	assert.Equal(suite.T(), 1, artistsResp.Artists[1].Plays)
+
+	var hourlyResp beans.HourlyStatsResponse
+	suite.Require().NoError(json.Unmarshal(hourly.Body.Bytes(), &hourlyResp))
+	suite.Require().Len(hourlyResp.Hours, 24)
+	assert.Equal(suite.T(), 2, hourlyResp.Hours[8].Plays)
+	assert.Equal(suite.T(), 1, hourlyResp.Hours[23].Plays)
+
+	var weekdaysResp beans.WeekdayStatsResponse
+	suite.Require().NoError(json.Unmarshal(weekdays.Body.Bytes(), &weekdaysResp))
+	suite.Require().Len(weekdaysResp.Weekdays, 7)
+	assert.Equal(suite.T(), 4, weekdaysResp.Weekdays[time.Monday].Plays)
+
+	var playlistsResp beans.PlaylistTotalsResponse
+	suite.Require().NoError(json.Unmarshal(playlists.Body.Bytes(), &playlistsResp))
+	suite.Require().Len(playlistsResp.Playlists, 1)
+	assert.Equal(suite.T(), TestPlaylistName, playlistsResp.Playlists[0].Name)
+	assert.Equal(suite.T(), 4, playlistsResp.Playlists[0].Plays)
+
+	// L'historique brut suit la même période, les lectures les plus récentes en premier
+	rr := suite.makeGetRequest(fmt.Sprintf("%s/tracks/%d/plays?%s", StatsEndpoint, tracks[1].ID, period))
+	suite.Require().Equal(http.StatusOK, rr.Code)
+	var trackHistory beans.TrackPlaysResponse
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &trackHistory))
+	assert.Len(suite.T(), trackHistory.Plays, 1)
+
+	rr = suite.makeGetRequest(fmt.Sprintf("%s/playlists/%d/plays?limit=2&%s", StatsEndpoint, playlistID, period))
+	suite.Require().Equal(http.StatusOK, rr.Code)
+	var playlistHistory beans.TrackPlaysResponse
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &playlistHistory))
+	assert.Equal(suite.T(), "Europe/Paris", playlistHistory.Timezone)
+	suite.Require().Len(playlistHistory.Plays, 2)
+	assert.True(suite.T(), plays[2].PlayedAt.Equal(playlistHistory.Plays[0].PlayedAt))
+	assert.True(suite.T(), plays[3].PlayedAt.Equal(playlistHistory.Plays[1].PlayedAt))
+
+	rr = suite.makeGetRequest(fmt.Sprintf("%s/playlists/%d/plays?from=tomorrow", StatsEndpoint, playlistID))
+	assert.Equal(suite.T(), http.StatusBadRequest, rr.Code)
+}
+
+func (suite *IntegrationTestSuite) getPlaylistTotals(query string) []beans.PlaylistPlayCountApiBean {
+	rr := suite.makeGetRequest(StatsEndpoint + "/playlists?" + query)
+	suite.Require().Equal(http.StatusOK, rr.Code)
+	var resp beans.PlaylistTotalsResponse
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	return resp.Playlists
+}
+
+func (suite *IntegrationTestSuite) TestStats_TopArtistsIgnoresTracksWithoutArtist() {
+	// Arrange : la seconde track n'est rattachée à aucun artiste du catalogue
+	playlistID := suite.createTestPlaylist(TestPlaylistName, []beans.TrackCreateRequest{
+		suite.buildTrack(TestSong1Title, TestArtist1Name),
+		suite.buildTrack(TestSong2Title, TestArtist2Name),
+	})
+	tracks := suite.parseTracksResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID)))
+	suite.Require().Len(tracks, 2)
+	suite.Require().NoError(suite.db.Model(&models.Track{}).Where("id = ?", tracks[1].ID).Update("artist_id", 0).Error)
+
+	playRepo := repositories.NewTrackPlayRepository(suite.db)
+	for i, track := range []beans.TrackResponseApiBean{tracks[0], tracks[1], tracks[1]} {
+		suite.Require().NoError(playRepo.Create(&models.TrackPlay{PlaylistID: int64(playlistID), TrackID: track.ID,
+			PlayedAt: time.Date(2024, 1, 15, 10, i, 0, 0, time.UTC)}))
+	}
+	_, err := services.NewStatsService(repositories.NewStatsRepository(suite.db)).RebuildRollups()
+	suite.Require().NoError(err)
+
+	// Act : une période alignée sur les heures lit les compteurs, l'autre l'historique
+	fromRollups := suite.makeGetRequest(StatsEndpoint + "/top-artists?from=2024-01-15&to=2024-01-15&tz=UTC")
+	fromHistory := suite.makeGetRequest(StatsEndpoint + "/top-artists?from=2024-01-15T09:59:59Z&to=2024-01-15T10:59:59Z")
+
+	// Assert
+	var rollupsResp, historyResp beans.TopArtistsResponse
+	suite.Require().NoError(json.Unmarshal(fromRollups.Body.Bytes(), &rollupsResp))
+	suite.Require().NoError(json.Unmarshal(fromHistory.Body.Bytes(), &historyResp))
+	suite.Require().Len(rollupsResp.Artists, 1)
+	assert.Equal(suite.T(), TestArtist1Name, rollupsResp.Artists[0].Name)
+	assert.Equal(suite.T(), 1, rollupsResp.Artists[0].Plays)
+	assert.Equal(suite.T(), rollupsResp.Artists, historyResp.Artists)
+}
+
+func (suite *IntegrationTestSuite) TestStats_HistogramsInHalfHourTimezone() {
+	// Arrange : à Kolkata (UTC+5h30), 18h20 UTC le dimanche est 23h50 le dimanche
+	// et 18h40 UTC est 0h10 le lundi, bien que les deux lectures soient dans la même heure UTC
+	playlistID := suite.createTestPlaylist(TestPlaylistName, []beans.TrackCreateRequest{suite.buildTrack(TestSong1Title, TestArtist1Name)})
+	tracks := suite.parseTracksResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID)))
+	suite.Require().Len(tracks, 1)
+
+	playRepo := repositories.NewTrackPlayRepository(suite.db)
+	for _, playedAt := range []time.Time{
+		time.Date(2024, 1, 14, 18, 20, 0, 0, time.UTC),
+		time.Date(2024, 1, 14, 18, 40, 0, 0, time.UTC),
+	} {
+		suite.Require().NoError(playRepo.Create(&models.TrackPlay{PlaylistID: int64(playlistID), TrackID: tracks[0].ID, PlayedAt: playedAt}))
+	}
+	period := "from=2024-01-14&to=2024-01-15&tz=Asia/Kolkata"
+
+	// Act
+	hourly := suite.makeGetRequest(StatsEndpoint + "/hourly?" + period)
+	weekdays := suite.makeGetRequest(StatsEndpoint + "/weekdays?" + period)
+
+	// Assert
+	var hourlyResp beans.HourlyStatsResponse
+	suite.Require().NoError(json.Unmarshal(hourly.Body.Bytes(), &hourlyResp))
+	suite.Require().Len(hourlyResp.Hours, 24)
+	assert.Equal(suite.T(), 1, hourlyResp.Hours[23].Plays)
+	assert.Equal(suite.T(), 1, hourlyResp.Hours[0].Plays)
+
+	var weekdaysResp beans.WeekdayStatsResponse
+	suite.Require().NoError(json.Unmarshal(weekdays.Body.Bytes(), &weekdaysResp))
+	suite.Require().Len(weekdaysResp.Weekdays, 7)
+	assert.Equal(suite.T(), 1, weekdaysResp.Weekdays[time.Sunday].Plays)
+	assert.Equal(suite.T(), 1, weekdaysResp.Weekdays[time.Monday].Plays)
+}
+
+func (suite *IntegrationTestSuite) TestStats_RollupsFollowHistory() {
+	// Arrange : deux lectures de session, dont la fin de la première est reçue deux fois
+	// et celle de la seconde avant son démarrage
+	playlistID := suite.createTestPlaylist(TestPlaylistName, []beans.TrackCreateRequest{
+		suite.buildTrack(TestSong1Title, TestArtist1Name),
+		suite.buildTrack(TestSong2Title, TestArtist2Name),
+	})
+	tracks := suite.parseTracksResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID)))
+	suite.Require().Len(tracks, 2)
+
+	playService := services.NewTrackPlayService(repositories.NewTrackPlayRepository(suite.db))
+	startedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	played := models.TrackPlayedEvent{SessionID: 1, Sequence: 1, PlaylistID: int64(playlistID), TrackID: tracks[0].ID, PlayedAt: startedAt}
+	ended := models.TrackEndedEvent{SessionID: 1, Sequence: 1, PlaylistID: int64(playlistID), TrackID: tracks[0].ID,
+		PlayedAt: startedAt, EndedAt: startedAt.Add(3 * time.Minute), PlayedSeconds: 180, Reason: models.TrackEndCompleted}
+	skipped := models.TrackEndedEvent{SessionID: 1, Sequence: 2, PlaylistID: int64(playlistID), TrackID: tracks[1].ID,
+		PlayedAt: startedAt.Add(3 * time.Minute), EndedAt: startedAt.Add(4 * time.Minute), PlayedSeconds: 60, Reason: models.TrackEndSkipped}
+	late := models.TrackPlayedEvent{SessionID: 1, Sequence: 2, PlaylistID: int64(playlistID), TrackID: tracks[1].ID, PlayedAt: startedAt.Add(3 * time.Minute)}
+
+	// Act
+	suite.Require().NoError(playService.RecordTrackPlay(played))
+	suite.Require().NoError(playService.RecordTrackEnd(ended))
+	suite.Require().NoError(playService.RecordTrackEnd(ended))
+	suite.Require().NoError(playService.RecordTrackEnd(skipped))
+	suite.Require().NoError(playService.RecordTrackPlay(late))
+
+	// Assert : les compteurs (période alignée sur les heures) égalent l'historique (période quelconque)
+	fromRollups := suite.getPlaylistTotals("from=2024-01-15&to=2024-01-15&tz=UTC")
+	fromHistory := suite.getPlaylistTotals("from=2024-01-15T09:59:59Z&to=2024-01-15T10:59:59Z")
+	suite.Require().Len(fromRollups, 1)
+	assert.Equal(suite.T(), fromHistory, fromRollups)
+	assert.Equal(suite.T(), 2, fromRollups[0].Plays)
+	assert.Equal(suite.T(), 1, fromRollups[0].CompletePlays)
+	assert.Equal(suite.T(), 240, fromRollups[0].PlayedSeconds)
+
+	// La reconstruction depuis l'historique retrouve les mêmes compteurs
+	count, err := services.NewStatsService(repositories.NewStatsRepository(suite.db)).RebuildRollups()
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 10, count) // 2 tracks, 2 artistes et 1 playlist, par heure et par jour
+	assert.Equal(suite.T(), fromRollups, suite.getPlaylistTotals("from=2024-01-15&to=2024-01-15&tz=UTC"))
+
+	// Supprimer une track conserve ses lectures dans l'historique et les compteurs
+	suite.Require().Equal(http.StatusNoContent, suite.makeDeleteRequest(fmt.Sprintf("%s/%d", TracksEndpoint, tracks[1].ID)).Code)
+	assert.Equal(suite.T(), fromRollups, suite.getPlaylistTotals("from=2024-01-15&to=2024-01-15&tz=UTC"))
+	assert.Equal(suite.T(), fromHistory, suite.getPlaylistTotals("from=2024-01-15T09:59:59Z&to=2024-01-15T10:59:59Z"))
+}
+
+func (suite *IntegrationTestSuite) TestStats_RollupsCountArtistOfTrackDeletedMidPlay() {
+	// Arrange : la track est supprimée entre le début et la fin de sa lecture
+	playlistID := suite.createTestPlaylist(TestPlaylistName, []beans.TrackCreateRequest{suite.buildTrack(TestSong1Title, TestArtist1Name)})
+	tracks := suite.parseTracksResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID)))
+	suite.Require().Len(tracks, 1)
+
+	playService := services.NewTrackPlayService(repositories.NewTrackPlayRepository(suite.db))
+	startedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	suite.Require().NoError(playService.RecordTrackPlay(models.TrackPlayedEvent{SessionID: 1, Sequence: 1,
+		PlaylistID: int64(playlistID), TrackID: tracks[0].ID, PlayedAt: startedAt}))
+	suite.Require().Equal(http.StatusNoContent, suite.makeDeleteRequest(fmt.Sprintf("%s/%d", TracksEndpoint, tracks[0].ID)).Code)
+
+	// Act
+	suite.Require().NoError(playService.RecordTrackEnd(models.TrackEndedEvent{SessionID: 1, Sequence: 1,
+		PlaylistID: int64(playlistID), TrackID: tracks[0].ID, PlayedAt: startedAt, EndedAt: startedAt.Add(3 * time.Minute),
+		PlayedSeconds: 180, Reason: models.TrackEndCompleted}))
+
+	// Assert : les compteurs tenus au fil des lectures égalent ceux reconstruits depuis l'historique
+	var live []models.PlayRollup
+	suite.Require().NoError(suite.db.Order("subject, granularity, subject_id").Find(&live).Error)
+	_, err := services.NewStatsService(repositories.NewStatsRepository(suite.db)).RebuildRollups()
+	suite.Require().NoError(err)
+	var rebuilt []models.PlayRollup
+	suite.Require().NoError(suite.db.Order("subject, granularity, subject_id").Find(&rebuilt).Error)
+
+	assert.Len(suite.T(), live, 6) // track, artiste et playlist, par heure et par jour
+	for i := range live {
+		live[i].ID = 0
+	}
+	for i := range rebuilt {
+		rebuilt[i].ID = 0
+	}
+	assert.Equal(suite.T(), live, rebuilt)
+}
+
+func (suite *IntegrationTestSuite) TestCueSheet_ExportFlagsMissingMetadata() {
+	// Arrange : une track complète et une track sans ISRC ni durée, jouées le 15 janvier 2024
+	rr := suite.makePostRequest(TracksEndpoint, beans.CatalogTrackRequest{
+		Title: TestSong1Title, Artist: TestArtist1Name, DurationSeconds: 200, ISRC: "FRZ031400123",
+	})
+	suite.Require().Equal(http.StatusCreated, rr.Code)
+	var complete beans.CatalogTrackResponseApiBean
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &complete))
+	playlistID := suite.createTestPlaylist(TestPlaylistName, []beans.TrackCreateRequest{{ID: complete.ID}, suite.buildTrack(TestSong2Title, TestArtist2Name)})
+	tracks := suite.parseTracksResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID)))
+	suite.Require().Len(tracks, 2)
+
+	playedSeconds := 200
+	plays := []models.TrackPlay{
+		{PlaylistID: int64(playlistID), TrackID: tracks[0].ID, PlayedAt: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), PlayedSeconds: &playedSeconds},
+		{PlaylistID: int64(playlistID), TrackID: tracks[1].ID, PlayedAt: time.Date(2024, 1, 15, 9, 4, 0, 0, time.UTC)},
+	}
+	playRepo := repositories.NewTrackPlayRepository(suite.db)
+	for i := range plays {
+		suite.Require().NoError(playRepo.Create(&plays[i]))
+	}
+	period := "from=2024-01-15&to=2024-01-15&tz=UTC"
+
+	// Act
+	csvExport := suite.makeGetRequest(CueSheetEndpoint + "?" + period)
+	fixedExport := suite.makeGetRequest(CueSheetEndpoint + "?format=fixed&" + period)
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, csvExport.Code)
+	assert.Equal(suite.T(), "text/csv; charset=utf-8", csvExport.Header().Get("Content-Type"))
+	assert.Contains(suite.T(), csvExport.Header().Get("Content-Disposition"), "cue-sheet-20240115-20240115.csv")
+	assert.Equal(suite.T(), "1", csvExport.Header().Get(CueSheetFlaggedHeader))
+	records, err := csv.NewReader(csvExport.Body).ReadAll()
+	suite.Require().NoError(err)
+	suite.Require().Len(records, 3)
+	assert.Equal(suite.T(), []string{"2024-01-15T09:00:00Z", TestSong1Title, TestArtist1Name, "", "FRZ031400123",
+		"200", "200", strconv.Itoa(playlistID), TestPlaylistName, ""}, records[1])
+	assert.Equal(suite.T(), "isrc;duration", records[2][9])
+
+	suite.Require().Equal(http.StatusOK, fixedExport.Code)
+	lines := strings.Split(strings.TrimSpace(fixedExport.Body.String()), "\n")
+	suite.Require().Len(lines, 4)
+	assert.True(suite.T(), strings.HasPrefix(lines[1], "D20240115090000FRZ031400123"))
+	assert.Equal(suite.T(), "T000002000001", lines[3])
+}
+
+func (suite *IntegrationTestSuite) TestCueSheet_InvalidFormat() {
+	// Act
+	rr := suite.makeGetRequest(CueSheetEndpoint + "?format=pdf")
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, rr.Code)
+}
+
+func (suite *IntegrationTestSuite) TestTrackPlay_RedeliveredEventsRecordedOnce() {
+	// Arrange : une lecture hors session, puis une lecture de session dont la fin arrive avant le début
+	playlistID := suite.createTestPlaylist(TestPlaylistName, []beans.TrackCreateRequest{suite.buildTrack(TestSong1Title, TestArtist1Name)})
+	tracks := suite.parseTracksResponse(suite.makeGetRequest(fmt.Sprintf("%s/%d/tracks", PlaylistsEndpoint, playlistID)))
+	suite.Require().Len(tracks, 1)
+
+	playService := services.NewTrackPlayService(repositories.NewTrackPlayRepository(suite.db))
+	startedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	standalone := models.TrackPlayedEvent{PlaylistID: int64(playlistID), TrackID: tracks[0].ID, PlayedAt: startedAt, EventID: "standalone-played"}
+	ended := models.TrackEndedEvent{SessionID: 7, Sequence: 1, PlaylistID: int64(playlistID), TrackID: tracks[0].ID,
+		PlayedAt: startedAt, EndedAt: startedAt.Add(time.Minute), PlayedSeconds: 60, Reason: models.TrackEndSkipped, EventID: "session-ended"}
+	late := models.TrackPlayedEvent{SessionID: 7, Sequence: 1, PlaylistID: int64(playlistID), TrackID: tracks[0].ID, PlayedAt: startedAt, EventID: "session-played"}
+
+	// Act & Assert
+	suite.Require().NoError(playService.RecordTrackPlay(standalone))
+	assert.ErrorIs(suite.T(), playService.RecordTrackPlay(standalone), domainErrors.ErrDuplicateEvent)
+
+	suite.Require().NoError(playService.RecordTrackEnd(ended))
+	assert.ErrorIs(suite.T(), playService.RecordTrackEnd(ended), domainErrors.ErrDuplicateEvent)
+	suite.Require().NoError(playService.RecordTrackPlay(late))
+	assert.ErrorIs(suite.T(), playService.RecordTrackPlay(late), domainErrors.ErrDuplicateEvent)
+
+	day := models.StatsPeriod{From: startedAt.Truncate(24 * time.Hour), To: startedAt.Truncate(24 * time.Hour).Add(24 * time.Hour), Location: time.UTC}
+	plays, err := playService.GetTrackPlays(int(tracks[0].ID), day, 0)
+	suite.Require().NoError(err)
+	suite.Require().Len(plays, 2)
+	totals := suite.getPlaylistTotals("from=2024-01-15&to=2024-01-15&tz=UTC")
+	suite.Require().Len(totals, 1)
+	assert.Equal(suite.T(), 2, totals[0].Plays)
+	assert.Equal(suite.T(), 60, totals[0].PlayedSeconds)
+}
+
+func (suite *IntegrationTestSuite) TestDeadLetters_ListReplayAndPurge() {
+	// Arrange : un événement en échec après ses tentatives et un message illisible
+	suite.deadLetter.letters = []models.DeadLetter{
+		{MessageID: "failed", Queue: "track_played", Reason: "database locked", RetryCount: 5, Body: []byte(`{"track_id":1}`)},
+		{MessageID: "poison", Queue: "track_played", Reason: "failed to unmarshal", Body: []byte("not json")},
+		{MessageID: "other", Queue: "track_ended", Reason: "database locked", RetryCount: 5, Body: []byte(`{}`)},
+	}
+
+	// Act
+	listed := suite.makeGetRequest("/admin/dead-letters?limit=2")
+	replayed := suite.makePostRequest("/admin/dead-letters/replay", beans.DeadLetterReplayRequest{IDs: []string{"failed"}})
+	purged := suite.makeDeleteRequest("/admin/dead-letters")
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, listed.Code)
+	var letters beans.DeadLettersResponse
+	suite.Require().NoError(json.Unmarshal(listed.Body.Bytes(), &letters))
+	suite.Require().Len(letters.DeadLetters, 2)
+	assert.JSONEq(suite.T(), `{"track_id":1}`, string(letters.DeadLetters[0].Payload))
+	assert.Equal(suite.T(), 5, letters.DeadLetters[0].RetryCount)
+	assert.Equal(suite.T(), "not json", letters.DeadLetters[1].RawPayload)
+
+	suite.Require().Equal(http.StatusOK, replayed.Code)
+	assert.JSONEq(suite.T(), `{"replayed":1}`, replayed.Body.String())
+	suite.Require().Equal(http.StatusOK, purged.Code)
+	assert.JSONEq(suite.T(), `{"purged":2}`, purged.Body.String())
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.makeGetRequest("/admin/dead-letters?limit=1000").Code)
+}
+
+func (suite *IntegrationTestSuite) TestEventStore_ReplayRebuildsTrackPlays() {
+	// Arrange : une lecture enregistrée dans le journal avec l'état de sa session
+	session := suite.createOnAirSession(nil)
+	sessionRepo := repositories.NewPlaySessionRepository(suite.db)
+	playedAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	played, err := models.NewTrackPlayedOutboxMessage(models.TrackPlayedEvent{PlaylistID: 1, SessionID: session.ID, Sequence: 1,
+		TrackID: 1, PlayedAt: playedAt, EventID: "store-played"})
+	suite.Require().NoError(err)
+	ended, err := models.NewTrackEndedOutboxMessage(models.TrackEndedEvent{PlaylistID: 1, SessionID: session.ID, Sequence: 1,
+		TrackID: 1, PlayedAt: playedAt, EndedAt: playedAt.Add(3 * time.Minute), PlayedSeconds: 180,
+		Reason: models.TrackEndCompleted, EventID: "store-ended"})
+	suite.Require().NoError(err)
+	suite.Require().NoError(sessionRepo.Update(&session, played))
+	suite.Require().NoError(sessionRepo.Update(&session, ended))
+
+	trackPlays := services.NewTrackPlayConsumerService(nil, services.NewTrackPlayService(repositories.NewTrackPlayRepository(suite.db)))
+	replay := services.NewEventReplayService(repositories.NewEventStoreRepository(suite.db), trackPlays)
+
+	// Act
+	dryRun, err := replay.Replay(models.ReplayQuery{Projection: "track-plays", From: "2025-03-01", To: "2025-03-01", DryRun: true})
+	suite.Require().NoError(err)
+	first, err := replay.Replay(models.ReplayQuery{Projection: "track-plays", From: "2025-03-01"})
+	suite.Require().NoError(err)
+	second, err := replay.Replay(models.ReplayQuery{Projection: "track-plays"})
+	suite.Require().NoError(err)
+	outside, err := replay.Replay(models.ReplayQuery{Projection: "track-plays", From: "2025-03-02"})
+	suite.Require().NoError(err)
+
+	// Assert
+	assert.Equal(suite.T(), models.ReplayResult{Projection: "track-plays", DryRun: true, Events: 2}, dryRun)
+	assert.Equal(suite.T(), models.ReplayResult{Projection: "track-plays", Events: 2, Applied: 2}, first)
+	assert.Equal(suite.T(), 2, second.Applied, "un événement rejoué deux fois est ignoré")
+	assert.Equal(suite.T(), 0, outside.Events)
+	assert.Equal(suite.T(), models.ConsumerStats{PlayedEvents: 1, EndedEvents: 1, DuplicatePlayedEvents: 1, DuplicateEndedEvents: 1},
+		trackPlays.Stats())
+
+	var plays []models.TrackPlay
+	suite.Require().NoError(suite.db.Find(&plays).Error)
+	suite.Require().Len(plays, 1)
+	assert.Equal(suite.T(), models.TrackEndCompleted, plays[0].EndReason)
+	suite.Require().NotNil(plays[0].PlayedSeconds)
+	assert.Equal(suite.T(), 180, *plays[0].PlayedSeconds)
+
+	// Le journal n'est alimenté qu'avec les messages de l'outbox, dans la même transaction
+	assert.Error(suite.T(), sessionRepo.Update(&session, played))
+	var stored int64
+	suite.Require().NoError(suite.db.Model(&models.StoredEvent{}).Count(&stored).Error)
+	assert.Equal(suite.T(), int64(2), stored)
+}
+
+func (suite *IntegrationTestSuite) TestEventSchemas_ListAndGet() {
+	// Act
+	listed := suite.makeGetRequest("/events/schemas")
+	schema := suite.makeGetRequest("/events/schemas/track.played/1")
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, listed.Code)
+	var resp beans.EventSchemasResponse
+	suite.Require().NoError(json.Unmarshal(listed.Body.Bytes(), &resp))
+	// playlist.played est publié en version 1 puis 2
+	suite.Require().Len(resp.Schemas, len(models.EventTypes())+1)
+	assert.Contains(suite.T(), resp.Schemas, beans.EventSchemaApiBean{
+		Type: "track.played", Version: 1, Current: true, URL: "/events/schemas/track.played/1",
+	})
+	assert.Contains(suite.T(), resp.Schemas, beans.EventSchemaApiBean{
+		Type: "playlist.played", Version: 1, Current: false, URL: "/events/schemas/playlist.played/1",
+	})
+
+	suite.Require().Equal(http.StatusOK, schema.Code)
+	assert.Equal(suite.T(), "application/schema+json", schema.Header().Get("Content-Type"))
+	assert.Contains(suite.T(), schema.Body.String(), `"track_title"`)
+	assert.Equal(suite.T(), http.StatusNotFound, suite.makeGetRequest("/events/schemas/track.played/9").Code)
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.makeGetRequest("/events/schemas/track.played/v1").Code)
+}
+
+func (suite *IntegrationTestSuite) getOutboxMessages(query string) []beans.OutboxMessageApiBean {
+	rr := suite.makeGetRequest("/admin/outbox?" + query)
+	suite.Require().Equal(http.StatusOK, rr.Code)
+	var resp beans.OutboxMessagesResponse
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	return resp.Messages
+}
+
+func (suite *IntegrationTestSuite) TestOutbox_SessionEventsRecordedWithSessionAndRelayed() {
+	// Arrange : la fin de la première track et le démarrage de la suivante
+	session := suite.createOnAirSession(nil)
+	sessionRepo := repositories.NewPlaySessionRepository(suite.db)
+	ended, err := models.NewTrackEndedOutboxMessage(models.TrackEndedEvent{SessionID: session.ID, Sequence: 1, TrackID: 1,
+		Reason: models.TrackEndCompleted, EventID: "outbox-ended"})
+	suite.Require().NoError(err)
+	played, err := models.NewTrackPlayedOutboxMessage(models.TrackPlayedEvent{SessionID: session.ID, Sequence: 2, TrackID: 2, EventID: "outbox-played"})
+	suite.Require().NoError(err)
+
+	// Act & Assert : l'état de la session et ses événements sont enregistrés ensemble
+	session.CurrentIndex = 1
+	session.Sequence = 2
+	suite.Require().NoError(sessionRepo.Update(&session, ended, played))
+
+	session.Status = models.PlaySessionStopped
+	assert.Error(suite.T(), sessionRepo.Update(&session, played), "un événement déjà enregistré annule la transaction")
+	var stored models.PlaySession
+	suite.Require().NoError(suite.db.First(&stored, session.ID).Error)
+	assert.Equal(suite.T(), models.PlaySessionPlaying, stored.Status)
+	assert.Equal(suite.T(), 1, stored.CurrentIndex)
+
+	pending := suite.getOutboxMessages("status=pending")
+	suite.Require().Len(pending, 2)
+	assert.Equal(suite.T(), "outbox-played", pending[0].EventID)
+	assert.Equal(suite.T(), "track.played", pending[0].EventType)
+
+	// Le broker indisponible laisse les messages pending avec leur erreur
+	outboxRepo := repositories.NewOutboxRepository(suite.db)
+	publisher := &recordingPublisher{failing: true}
+	relay := services.NewOutboxRelay(outboxRepo, publisher, time.Second, 0)
+	published, err := relay.RelayPending()
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, published)
+
+	rr := suite.makeGetRequest("/admin/outbox/events/outbox-ended")
+	suite.Require().Equal(http.StatusOK, rr.Code)
+	var failed beans.OutboxMessageApiBean
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &failed))
+	assert.Equal(suite.T(), "pending", failed.Status)
+	assert.Equal(suite.T(), 1, failed.Attempts)
+	assert.Equal(suite.T(), "broker unavailable", failed.LastError)
+	suite.Require().NotNil(failed.NextAttemptAt)
+
+	// Le passage suivant ne publie pas le message suivant avant la nouvelle tentative du premier
+	publisher.failing = false
+	published, err = relay.RelayPending()
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, published)
+	assert.Empty(suite.T(), publisher.events)
+	assert.Len(suite.T(), suite.getOutboxMessages("status=pending"), 2)
+
+	// Une fois la tentative arrivée, les messages sont publiés dans leur ordre d'enregistrement
+	suite.Require().NoError(suite.db.Model(&models.OutboxMessage{}).Where("1 = 1").
+		Update("next_attempt_at", time.Now().UTC().Add(-time.Second)).Error)
+	published, err = relay.RelayPending()
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 2, published)
+	suite.Require().Len(publisher.events, 2)
+	assert.Equal(suite.T(), "outbox-ended", publisher.events[0].ID)
+	assert.Equal(suite.T(), models.EventTrackPlayed, publisher.events[1].Type)
+
+	rr = suite.makeGetRequest("/admin/outbox/stats")
+	suite.Require().Equal(http.StatusOK, rr.Code)
+	var stats beans.OutboxStatsApiBean
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &stats))
+	assert.Equal(suite.T(), int64(0), stats.Pending)
+	assert.Equal(suite.T(), int64(2), stats.Sent)
+	assert.Nil(suite.T(), stats.OldestPendingAt)
+
+	// Seuls les messages publiés depuis plus de older_than sont supprimés
+	rr = suite.makeDeleteRequest("/admin/outbox?older_than=1h")
+	suite.Require().Equal(http.StatusOK, rr.Code)
+	assert.JSONEq(suite.T(), `{"deleted":0}`, rr.Body.String())
+	suite.Require().NoError(suite.db.Model(&models.OutboxMessage{}).Where("event_id = ?", "outbox-ended").
+		Update("sent_at", time.Now().UTC().Add(-2*time.Hour)).Error)
+	rr = suite.makeDeleteRequest("/admin/outbox?older_than=1h")
+	assert.JSONEq(suite.T(), `{"deleted":1}`, rr.Body.String())
+	assert.Len(suite.T(), suite.getOutboxMessages(""), 1)
+}
+
+func (suite *IntegrationTestSuite) TestOutbox_UnroutableMessageFailedWithoutBlockingOutbox() {
+	// Arrange
+	session := suite.createOnAirSession(nil)
+	sessionRepo := repositories.NewPlaySessionRepository(suite.db)
+	ended, err := models.NewTrackEndedOutboxMessage(models.TrackEndedEvent{SessionID: session.ID, Sequence: 1, TrackID: 1,
+		Reason: models.TrackEndCompleted, EventID: "unroutable-ended"})
+	suite.Require().NoError(err)
+	played, err := models.NewTrackPlayedOutboxMessage(models.TrackPlayedEvent{SessionID: session.ID, Sequence: 2, TrackID: 2, EventID: "routed-played"})
+	suite.Require().NoError(err)
+	session.CurrentIndex = 1
+	session.Sequence = 2
+	suite.Require().NoError(sessionRepo.Update(&session, ended, played))
+	publisher := &recordingPublisher{unroutable: models.EventTrackEnded}
+	relay := services.NewOutboxRelay(repositories.NewOutboxRepository(suite.db), publisher, time.Second, 0)
+
+	// Act
+	published, err := relay.RelayPending()
+
+	// Assert : le message renvoyé par le broker n'est plus retenté, le suivant est publié
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, published)
+	suite.Require().Len(publisher.events, 1)
+	assert.Equal(suite.T(), "routed-played", publisher.events[0].ID)
+
+	failed := suite.getOutboxMessages("status=failed")
+	suite.Require().Len(failed, 1)
+	assert.Equal(suite.T(), "unroutable-ended", failed[0].EventID)
+	assert.Contains(suite.T(), failed[0].LastError, "not routed")
+	assert.Nil(suite.T(), failed[0].NextAttemptAt)
+
+	rr := suite.makeGetRequest("/admin/outbox/stats")
+	suite.Require().Equal(http.StatusOK, rr.Code)
+	var stats beans.OutboxStatsApiBean
+	suite.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &stats))
+	assert.Equal(suite.T(), int64(0), stats.Pending)
+	assert.Equal(suite.T(), int64(1), stats.Failed)
+}
+
+func (suite *IntegrationTestSuite) TestOutbox_InvalidParameters() {
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.makeGetRequest("/admin/outbox?status=lost").Code)
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.makeGetRequest("/admin/outbox?limit=1000").Code)
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.makeDeleteRequest("/admin/outbox").Code)
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.makeDeleteRequest("/admin/outbox?older_than=-1h").Code)
+	assert.Equal(suite.T(), http.StatusNotFound, suite.makeGetRequest("/admin/outbox/events/unknown").Code)
+}
+
+func (suite *IntegrationTestSuite) TestStats_InvalidParameters() {
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.makeGetRequest(StatsEndpoint+"/top-tracks?tz=Mars/Olympus").Code)
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.makeGetRequest(StatsEndpoint+"/hourly?from=2024-01-16&to=2024-01-15").Code)
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.makeGetRequest(StatsEndpoint+"/top-artists?limit=1000").Code)
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.makeGetRequest(StatsEndpoint+"/weekdays?from=yesterday").Code)
+}
+
 func TestIntegrationTestSuite(t *testing.T) {
 	suite.Run(t, new(IntegrationTestSuite))
 }
@@ -0,0 +1,418 @@
+package handlers
+
+import (
+	"encoding/json"
+	"radioking-app/internal/api/http/beans"
+	"radioking-app/internal/domain/models"
+	"time"
+)
+
+// toPlaylistTracks une track référencée par ID est reprise du catalogue,
+// sinon elle est retrouvée ou créée à partir de son titre et de son artiste
+func toPlaylistTracks(requests []beans.TrackCreateRequest) []models.PlaylistTrack {
+	entries := make([]models.PlaylistTrack, 0, len(requests))
+	for _, req := range requests {
+		entries = append(entries, models.PlaylistTrack{
+			TrackID: req.ID,
+			Track:   models.Track{Title: req.Title, Artist: req.Artist, DurationSeconds: req.DurationSeconds},
+		})
+	}
+	return entries
+}
+
+func toTrackResponses(entries []models.PlaylistTrack) []beans.TrackResponseApiBean {
+	tracks := make([]beans.TrackResponseApiBean, 0, len(entries))
+	for _, entry := range entries {
+		tracks = append(tracks, beans.TrackResponseApiBean{
+			ID:              entry.TrackID,
+			Title:           entry.Track.Title,
+			Artist:          entry.Track.Artist,
+			Position:        entry.Position,
+			DurationSeconds: entry.Track.DurationSeconds,
+		})
+	}
+	return tracks
+}
+
+func toPlaylistResponse(playlist *models.Playlist) beans.PlaylistResponseApiBean {
+	return beans.PlaylistResponseApiBean{
+		ID:     playlist.ID,
+		Name:   playlist.Name,
+		Tracks: toTrackResponses(playlist.Tracks),
+	}
+}
+
+func toPlaylistSummary(playlist *models.Playlist) beans.PlaylistSummaryApiBean {
+	summary := beans.PlaylistSummaryApiBean{
+		ID:          playlist.ID,
+		Name:        playlist.Name,
+		TracksCount: playlist.TracksCount,
+		CreatedAt:   playlist.CreatedAt,
+		UpdatedAt:   playlist.UpdatedAt,
+	}
+	if len(playlist.Tracks) > 0 {
+		summary.Tracks = toTrackResponses(playlist.Tracks)
+	}
+	return summary
+}
+
+// toCatalogTrack l'album est optionnel, un titre vide détache la track de son album
+func toCatalogTrack(req beans.CatalogTrackRequest) models.Track {
+	track := models.Track{Title: req.Title, Artist: req.Artist, DurationSeconds: req.DurationSeconds, ISRC: req.ISRC}
+	if req.Album != "" {
+		track.Album = &models.Album{Title: req.Album}
+	}
+	return track
+}
+
+func toCatalogTrackResponse(track *models.Track) beans.CatalogTrackResponseApiBean {
+	resp := beans.CatalogTrackResponseApiBean{
+		ID:              track.ID,
+		Title:           track.Title,
+		Artist:          track.Artist,
+		ArtistID:        track.ArtistID,
+		DurationSeconds: track.DurationSeconds,
+		ISRC:            track.ISRC,
+		CreatedAt:       track.CreatedAt,
+		UpdatedAt:       track.UpdatedAt,
+	}
+	if track.Album != nil {
+		resp.Album = &beans.AlbumRefApiBean{ID: track.Album.ID, Title: track.Album.Title}
+	}
+	return resp
+}
+
+func toCatalogTrackResponses(tracks []*models.Track) []beans.CatalogTrackResponseApiBean {
+	responses := make([]beans.CatalogTrackResponseApiBean, 0, len(tracks))
+	for _, track := range tracks {
+		responses = append(responses, toCatalogTrackResponse(track))
+	}
+	return responses
+}
+
+func toPlaylistRefs(playlists []*models.Playlist) []beans.PlaylistRefApiBean {
+	refs := make([]beans.PlaylistRefApiBean, 0, len(playlists))
+	for _, playlist := range playlists {
+		refs = append(refs, beans.PlaylistRefApiBean{ID: playlist.ID, Name: playlist.Name})
+	}
+	return refs
+}
+
+func toArtistResponse(overview *models.ArtistOverview) beans.ArtistResponseApiBean {
+	albums := make([]beans.AlbumRefApiBean, 0, len(overview.Albums))
+	for _, album := range overview.Albums {
+		albums = append(albums, beans.AlbumRefApiBean{ID: album.ID, Title: album.Title})
+	}
+
+	return beans.ArtistResponseApiBean{
+		ID:        overview.Artist.ID,
+		Name:      overview.Artist.Name,
+		Albums:    albums,
+		Tracks:    toCatalogTrackResponses(overview.Tracks),
+		Playlists: toPlaylistRefs(overview.Playlists),
+	}
+}
+
+func toAlbumResponse(overview *models.AlbumOverview) beans.AlbumResponseApiBean {
+	album := overview.Album
+	// Les tracks d'un album sont toutes rattachées à celui-ci
+	for _, track := range overview.Tracks {
+		track.Album = album
+	}
+
+	return beans.AlbumResponseApiBean{
+		ID:        album.ID,
+		Title:     album.Title,
+		Artist:    beans.ArtistRefApiBean{ID: album.Artist.ID, Name: album.Artist.Name},
+		Tracks:    toCatalogTrackResponses(overview.Tracks),
+		Playlists: toPlaylistRefs(overview.Playlists),
+	}
+}
+
+// toPlaySessionResponse le temps écoulé de la track courante est calculé à l'instant now
+func toPlaySessionResponse(session *models.PlaySession, now time.Time) beans.PlaySessionResponseApiBean {
+	resp := beans.PlaySessionResponseApiBean{
+		ID:             session.ID,
+		PlaylistID:     session.PlaylistID,
+		ClockID:        session.ClockID,
+		Status:         string(session.Status),
+		CurrentIndex:   session.CurrentIndex,
+		TracksCount:    len(session.Items),
+		TrackStartedAt: session.TrackStartedAt,
+		CreatedAt:      session.CreatedAt,
+		UpdatedAt:      session.UpdatedAt,
+	}
+
+	if item := session.CurrentItem(); item != nil && session.IsActive() {
+		resp.CurrentTrack = toSessionTrack(item)
+		resp.ElapsedSeconds = int(min(session.Elapsed(now), item.Duration()) / time.Second)
+	}
+	return resp
+}
+
+func toClock(req beans.ClockRequest) models.Clock {
+	clock := models.Clock{Name: req.Name, Slots: make([]models.ClockSlot, 0, len(req.Slots))}
+	for _, slot := range req.Slots {
+		clock.Slots = append(clock.Slots, models.ClockSlot{
+			Type:            models.ClockSlotType(slot.Type),
+			PlaylistID:      slot.PlaylistID,
+			TrackID:         slot.TrackID,
+			ArtistID:        slot.ArtistID,
+			AlbumID:         slot.AlbumID,
+			DurationSeconds: slot.DurationSeconds,
+			Shuffle:         slot.Shuffle,
+		})
+	}
+	return clock
+}
+
+func toClockResponse(clock *models.Clock) beans.ClockResponseApiBean {
+	slots := make([]beans.ClockSlotApiBean, 0, len(clock.Slots))
+	for _, slot := range clock.Slots {
+		slots = append(slots, beans.ClockSlotApiBean{
+			Type:            string(slot.Type),
+			PlaylistID:      slot.PlaylistID,
+			TrackID:         slot.TrackID,
+			ArtistID:        slot.ArtistID,
+			AlbumID:         slot.AlbumID,
+			DurationSeconds: slot.DurationSeconds,
+			Shuffle:         slot.Shuffle,
+		})
+	}
+
+	return beans.ClockResponseApiBean{
+		ID:        clock.ID,
+		Name:      clock.Name,
+		Slots:     slots,
+		CreatedAt: clock.CreatedAt,
+		UpdatedAt: clock.UpdatedAt,
+	}
+}
+
+func toGridEntry(entry *models.GridEntry) beans.GridEntryApiBean {
+	resp := beans.GridEntryApiBean{Weekday: entry.Weekday, Hour: entry.Hour, Clock: beans.ClockRefApiBean{ID: entry.ClockID}}
+	if entry.Clock != nil {
+		resp.Clock.Name = entry.Clock.Name
+	}
+	return resp
+}
+
+func toHourProgram(program *models.HourProgram) beans.HourProgramApiBean {
+	resp := beans.HourProgramApiBean{
+		StartsAt: program.StartsAt,
+		Items:    make([]beans.ProgramItemApiBean, 0, len(program.Items)),
+	}
+	if program.Clock != nil {
+		resp.Clock = &beans.ClockRefApiBean{ID: program.Clock.ID, Name: program.Clock.Name}
+	}
+
+	for _, item := range program.Items {
+		resp.Items = append(resp.Items, toProgramItem(item))
+	}
+	return resp
+}
+
+func toProgramItem(item models.ProgramItem) beans.ProgramItemApiBean {
+	resp := beans.ProgramItemApiBean{
+		StartsAt:        item.StartsAt,
+		SlotType:        string(item.SlotType),
+		PlaylistID:      item.PlaylistID,
+		TrackID:         item.Track.ID,
+		Title:           item.Track.Title,
+		Artist:          item.Track.Artist,
+		DurationSeconds: item.DurationSeconds,
+	}
+	for _, violation := range item.Violations {
+		resp.Violations = append(resp.Violations, beans.RotationViolationApiBean{
+			RuleID:        violation.Rule.ID,
+			RuleName:      violation.Rule.Name,
+			Subject:       string(violation.Rule.Subject),
+			WindowMinutes: violation.Rule.WindowMinutes,
+			MaxPlays:      violation.Rule.MaxPlays,
+			Plays:         violation.Plays,
+			LastPlayedAt:  violation.LastPlayedAt,
+		})
+	}
+	return resp
+}
+
+func toRotationRule(req beans.RotationRuleRequest) models.RotationRule {
+	rule := models.RotationRule{
+		Name:          req.Name,
+		Subject:       models.RotationSubject(req.Subject),
+		WindowMinutes: req.WindowMinutes,
+		MaxPlays:      req.MaxPlays,
+		Enabled:       true,
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	return rule
+}
+
+func toRotationRuleResponse(rule *models.RotationRule) beans.RotationRuleResponseApiBean {
+	return beans.RotationRuleResponseApiBean{
+		ID:            rule.ID,
+		Name:          rule.Name,
+		Subject:       string(rule.Subject),
+		WindowMinutes: rule.WindowMinutes,
+		MaxPlays:      rule.MaxPlays,
+		Enabled:       rule.Enabled,
+		CreatedAt:     rule.CreatedAt,
+		UpdatedAt:     rule.UpdatedAt,
+	}
+}
+
+func toRotationDryRunResponse(report *models.RotationReport) beans.RotationDryRunResponse {
+	resp := beans.RotationDryRunResponse{
+		StartsAt:        report.StartsAt,
+		Items:           make([]beans.ProgramItemApiBean, 0, len(report.Items)),
+		ViolationsCount: report.ViolationsCount,
+	}
+	if report.Clock != nil {
+		resp.Clock = &beans.ClockRefApiBean{ID: report.Clock.ID, Name: report.Clock.Name}
+	}
+	for _, item := range report.Items {
+		resp.Items = append(resp.Items, toProgramItem(item))
+	}
+	return resp
+}
+
+func toSessionTrack(item *models.PlaySessionItem) *beans.SessionTrackApiBean {
+	if item == nil {
+		return nil
+	}
+	return &beans.SessionTrackApiBean{
+		ID:              item.TrackID,
+		Title:           item.Title,
+		Artist:          item.Artist,
+		Position:        item.Position,
+		DurationSeconds: item.DurationSeconds,
+	}
+}
+
+func toNowPlayingResponse(nowPlaying *models.NowPlaying) beans.NowPlayingResponseApiBean {
+	return beans.NowPlayingResponseApiBean{
+		SessionID:      nowPlaying.SessionID,
+		PlaylistID:     nowPlaying.PlaylistID,
+		ClockID:        nowPlaying.ClockID,
+		Status:         string(nowPlaying.Status),
+		Current:        toSessionTrack(nowPlaying.Current),
+		StartedAt:      nowPlaying.StartedAt,
+		ElapsedSeconds: nowPlaying.ElapsedSeconds,
+		Next:           toSessionTrack(nowPlaying.Next),
+		UpdatedAt:      nowPlaying.UpdatedAt,
+	}
+}
+
+func toStudioSession(session *models.PlaySession, now time.Time) beans.StudioSessionApiBean {
+	resp := beans.StudioSessionApiBean{
+		PlaySessionResponseApiBean: toPlaySessionResponse(session, now),
+		Queue:                      make([]beans.SessionTrackApiBean, 0, len(session.Items)),
+	}
+	for i := range session.Items {
+		resp.Queue = append(resp.Queue, *toSessionTrack(&session.Items[i]))
+	}
+	return resp
+}
+
+func toStudioCommand(req beans.StudioCommandApiBean, operator string) models.StudioCommand {
+	return models.StudioCommand{
+		Type:       models.StudioCommandType(req.Type),
+		SessionID:  req.SessionID,
+		PlaylistID: req.PlaylistID,
+		TrackID:    req.TrackID,
+		Options: models.PlayOptions{
+			Shuffle: models.ShuffleMode(req.Shuffle),
+			Repeat:  models.RepeatMode(req.Repeat),
+			Seed:    req.Seed,
+		},
+		Operator: operator,
+	}
+}
+
+func toStatsPeriod(period models.StatsPeriod) beans.StatsPeriodApiBean {
+	return beans.StatsPeriodApiBean{From: period.From, To: period.To, Timezone: period.Location.String()}
+}
+
+func toTrackPlaysResponse(period models.StatsPeriod, plays []*models.TrackPlay) beans.TrackPlaysResponse {
+	resp := beans.TrackPlaysResponse{StatsPeriodApiBean: toStatsPeriod(period), Plays: make([]beans.TrackPlayApiBean, 0, len(plays))}
+	for _, play := range plays {
+		resp.Plays = append(resp.Plays, beans.TrackPlayApiBean{
+			ID:            play.ID,
+			PlaylistID:    play.PlaylistID,
+			TrackID:       play.TrackID,
+			Position:      play.Position,
+			SessionID:     play.SessionID,
+			PlayedAt:      play.PlayedAt,
+			EndedAt:       play.EndedAt,
+			PlayedSeconds: play.PlayedSeconds,
+			EndReason:     string(play.EndReason),
+		})
+	}
+	return resp
+}
+
+func toDeadLettersResponse(letters []models.DeadLetter) beans.DeadLettersResponse {
+	resp := beans.DeadLettersResponse{DeadLetters: make([]beans.DeadLetterApiBean, 0, len(letters))}
+	for _, letter := range letters {
+		bean := beans.DeadLetterApiBean{
+			MessageID:  letter.MessageID,
+			Queue:      letter.Queue,
+			RoutingKey: letter.RoutingKey,
+			Reason:     letter.Reason,
+			RetryCount: letter.RetryCount,
+		}
+		if !letter.FailedAt.IsZero() {
+			failedAt := letter.FailedAt
+			bean.FailedAt = &failedAt
+		}
+		if json.Valid(letter.Body) {
+			bean.Payload = json.RawMessage(letter.Body)
+		} else {
+			bean.RawPayload = string(letter.Body)
+		}
+		resp.DeadLetters = append(resp.DeadLetters, bean)
+	}
+	return resp
+}
+
+func toEventSchemasResponse(refs []models.EventSchemaRef) beans.EventSchemasResponse {
+	resp := beans.EventSchemasResponse{Schemas: make([]beans.EventSchemaApiBean, 0, len(refs))}
+	for _, ref := range refs {
+		resp.Schemas = append(resp.Schemas, beans.EventSchemaApiBean{
+			Type:    string(ref.Type),
+			Version: ref.Version,
+			Current: ref.Current,
+			URL:     eventSchemaURL(ref),
+		})
+	}
+	return resp
+}
+
+func toOutboxMessagesResponse(messages []*models.OutboxMessage) beans.OutboxMessagesResponse {
+	resp := beans.OutboxMessagesResponse{Messages: make([]beans.OutboxMessageApiBean, 0, len(messages))}
+	for _, message := range messages {
+		resp.Messages = append(resp.Messages, toOutboxMessageApiBean(message))
+	}
+	return resp
+}
+
+func toOutboxMessageApiBean(message *models.OutboxMessage) beans.OutboxMessageApiBean {
+	bean := beans.OutboxMessageApiBean{
+		ID:        message.ID,
+		EventID:   message.EventID,
+		EventType: string(message.EventType),
+		Status:    string(message.Status),
+		Attempts:  message.Attempts,
+		LastError: message.LastError,
+		CreatedAt: message.CreatedAt,
+		SentAt:    message.SentAt,
+		Payload:   json.RawMessage(message.Payload),
+	}
+	if message.Status == models.OutboxPending {
+		nextAttemptAt := message.NextAttemptAt
+		bean.NextAttemptAt = &nextAttemptAt
+	}
+	return bean
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"radioking-app/internal/domain/services"
+	"strconv"
+	"time"
+
+	domainErrors "radioking-app/internal/domain/errors"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// keepAliveInterval intervalle des commentaires SSE qui empêchent les proxies de couper le flux
+const keepAliveInterval = 15 * time.Second
+
+// NowPlayingHandler expose ce qui est à l'antenne, en lecture ponctuelle ou en flux Server-Sent Events
+type NowPlayingHandler struct {
+	service services.INowPlayingService
+}
+
+func NewNowPlayingHandler(service services.INowPlayingService) *NowPlayingHandler {
+	return &NowPlayingHandler{service: service}
+}
+
+func (handler *NowPlayingHandler) Routes(router *chi.Mux) chi.Router {
+	router.Get("/now-playing", handler.GetNowPlaying)
+	router.Get("/now-playing/stream", handler.Stream)
+	return router
+}
+
+// GetNowPlaying retourne la track courante et la suivante de l'antenne, ou de la session session_id
+func (handler *NowPlayingHandler) GetNowPlaying(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := extractSessionFilter(w, r)
+	if !ok {
+		return
+	}
+
+	nowPlaying, err := handler.service.GetNowPlaying(sessionID)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.JSON(w, r, toNowPlayingResponse(nowPlaying))
+}
+
+// Stream pousse un événement "now-playing" à chaque changement de track de l'antenne, ou de la
+// session session_id. Le premier événement décrit l'état courant ; "off-air" signale que plus
+// rien n'est à l'antenne.
+func (handler *NowPlayingHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := extractSessionFilter(w, r)
+	if !ok {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		handleError(w, "Streaming unsupported", http.StatusInternalServerError, errors.New("response writer cannot flush"))
+		return
+	}
+
+	updates, unsubscribe := handler.service.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	handler.pushNowPlaying(w, sessionID)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case update, open := <-updates:
+			if !open {
+				return
+			}
+			if sessionID != 0 {
+				if update.SessionID != sessionID {
+					continue
+				}
+				writeSSEEvent(w, "now-playing", toNowPlayingResponse(&update))
+			} else {
+				// L'antenne peut passer d'une session à l'autre : son état est recalculé
+				handler.pushNowPlaying(w, 0)
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func (handler *NowPlayingHandler) pushNowPlaying(w http.ResponseWriter, sessionID int64) {
+	nowPlaying, err := handler.service.GetNowPlaying(sessionID)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrNothingOnAir) {
+			writeSSEEvent(w, "off-air", struct{}{})
+			return
+		}
+		log.Printf("Failed to get now playing: %v", err)
+		return
+	}
+	writeSSEEvent(w, "now-playing", toNowPlayingResponse(nowPlaying))
+}
+
+// extractSessionFilter lit le paramètre session_id, 0 s'il est absent pour désigner l'antenne
+func extractSessionFilter(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	value := r.URL.Query().Get("session_id")
+	if value == "" {
+		return 0, true
+	}
+
+	sessionID, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || sessionID <= 0 {
+		handleError(w, "Invalid session ID format", http.StatusBadRequest, err)
+		return 0, false
+	}
+	return sessionID, true
+}
+
+func writeSSEEvent(w http.ResponseWriter, event string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Failed to encode %s event: %v", event, err)
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"radioking-app/internal/api/http/beans"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/domain/services"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// OutboxHandler expose la livraison des événements enregistrés dans l'outbox
+type OutboxHandler struct {
+	service services.IOutboxService
+}
+
+func NewOutboxHandler(service services.IOutboxService) *OutboxHandler {
+	return &OutboxHandler{service: service}
+}
+
+func (handler *OutboxHandler) Routes(router *chi.Mux) chi.Router {
+	router.Route("/admin/outbox", func(r chi.Router) {
+		r.Get("/", handler.List)
+		r.Get("/stats", handler.GetStats)
+		r.Get("/events/{eventId}", handler.GetByEventID)
+		r.Delete("/", handler.Cleanup)
+	})
+	return router
+}
+
+// List retourne les messages les plus récents, filtrés par le paramètre status (pending ou sent)
+func (handler *OutboxHandler) List(w http.ResponseWriter, r *http.Request) {
+	limit, ok := extractLimit(w, r)
+	if !ok {
+		return
+	}
+
+	messages, err := handler.service.List(models.OutboxStatus(r.URL.Query().Get("status")), limit)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+	render.JSON(w, r, toOutboxMessagesResponse(messages))
+}
+
+func (handler *OutboxHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := handler.service.Stats()
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+	render.JSON(w, r, beans.OutboxStatsApiBean(stats))
+}
+
+// GetByEventID retourne l'état de livraison d'un événement à partir de son event_id
+func (handler *OutboxHandler) GetByEventID(w http.ResponseWriter, r *http.Request) {
+	message, err := handler.service.GetByEventID(chi.URLParam(r, "eventId"))
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+	render.JSON(w, r, toOutboxMessageApiBean(message))
+}
+
+// Cleanup supprime les messages publiés depuis plus que la durée older_than (par exemple 72h)
+func (handler *OutboxHandler) Cleanup(w http.ResponseWriter, r *http.Request) {
+	olderThan, err := time.ParseDuration(r.URL.Query().Get("older_than"))
+	if err != nil {
+		handleError(w, "Invalid older_than parameter", http.StatusBadRequest, err)
+		return
+	}
+
+	deleted, err := handler.service.Cleanup(olderThan)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+	render.JSON(w, r, beans.OutboxCleanupResponse{Deleted: deleted})
+}
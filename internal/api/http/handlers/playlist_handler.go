@@ -1,27 +1,19 @@
 package handlers
 
 import (
-	"encoding/json"
-	"errors"
-	"log"
 	"net/http"
 	"radioking-app/internal/api/http/beans"
+	"radioking-app/internal/domain/constants"
 	"radioking-app/internal/domain/models"
 	"radioking-app/internal/domain/services"
 	"strconv"
 
-	domainErrors "radioking-app/internal/domain/errors"
-
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator/v10"
 	"github.com/jinzhu/copier"
 )
 
-const IdParameter = "id"
-const mappingError = "Response mapping error"
-
-var validate = validator.New()
+const TrackIdParameter = "trackId"
 
 type PlaylistHandler struct {
 	service            services.IPlaylistService
@@ -39,64 +31,159 @@ func (handler *PlaylistHandler) Routes(router *chi.Mux) chi.Router {
 	router.Post("/playlists", handler.CreatePlaylist)
 	router.Get("/playlists", handler.ListPlaylists)
 	router.Get("/playlists/{id}", handler.GetPlaylist)
+	router.Put("/playlists/{id}", handler.UpdatePlaylist)
+	router.Patch("/playlists/{id}", handler.RenamePlaylist)
+	router.Delete("/playlists/{id}", handler.DeletePlaylist)
+	router.Get("/playlists/{id}/tracks", handler.ListTracks)
+	router.Post("/playlists/{id}/tracks", handler.AddTrack)
+	router.Patch("/playlists/{id}/tracks/{trackId}", handler.MoveTrack)
+	router.Delete("/playlists/{id}/tracks/{trackId}", handler.RemoveTrack)
 	router.Post("/playlists/{id}/play", handler.PlayPlaylist)
 	return router
 }
 
 func (handler *PlaylistHandler) CreatePlaylist(w http.ResponseWriter, r *http.Request) {
 	var req beans.PlaylistCreateRequest
-
-	playlist, done := mapRequest(w, r, req, handler)
-	if done {
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
+	playlist := models.Playlist{Name: req.Name, Tracks: toPlaylistTracks(req.Tracks)}
 	if err := handler.service.CreatePlaylist(&playlist); err != nil {
-		handler.handleBusinessError(w, err)
+		handleBusinessError(w, err)
 		return
 	}
 
-	var resp beans.PlaylistResponseApiBean
-	if err := copier.Copy(&resp, &playlist); err != nil {
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, toPlaylistResponse(&playlist))
+}
 
-		handler.handleError(w, mappingError, http.StatusInternalServerError, err)
+func (handler *PlaylistHandler) ListPlaylists(w http.ResponseWriter, r *http.Request) {
+	query, ok := handler.extractListQuery(w, r)
+	if !ok {
 		return
 	}
 
-	render.Status(r, http.StatusCreated)
+	page, err := handler.service.ListPlaylists(query)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	if query.Limit == 0 {
+		query.Limit = constants.DefaultPageSize
+	}
+
+	resp := beans.PlaylistPageResponse{
+		Data:       make([]beans.PlaylistSummaryApiBean, 0, len(page.Playlists)),
+		Pagination: newPagination(query.Limit, page.NextCursor),
+		Links:      newPageLinks(r, page.NextCursor),
+	}
+	for _, playlist := range page.Playlists {
+		resp.Data = append(resp.Data, toPlaylistSummary(playlist))
+	}
+
 	render.JSON(w, r, resp)
 }
 
-func mapRequest(w http.ResponseWriter, r *http.Request, req beans.PlaylistCreateRequest, handler *PlaylistHandler) (models.Playlist, bool) {
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		handler.handleError(w, "Invalid JSON payload", http.StatusBadRequest, err)
-		return models.Playlist{}, true
+// extractListQuery lit les paramètres de pagination, filtre et tri du listing
+func (handler *PlaylistHandler) extractListQuery(w http.ResponseWriter, r *http.Request) (models.PlaylistListQuery, bool) {
+	params := r.URL.Query()
+	query := models.PlaylistListQuery{
+		Cursor: params.Get("cursor"),
+		Name:   params.Get("name"),
+		SortBy: params.Get("sort"),
+		Order:  params.Get("order"),
 	}
 
-	if err := validate.Struct(req); err != nil {
-		handler.handleError(w, "Validation failed", http.StatusBadRequest, err)
-		return models.Playlist{}, true
+	limit, ok := extractLimit(w, r)
+	if !ok {
+		return query, false
 	}
+	query.Limit = limit
 
-	var playlist models.Playlist
-	if err := copier.Copy(&playlist, &req); err != nil {
-		handler.handleError(w, "Internal mapping error", http.StatusInternalServerError, err)
-		return models.Playlist{}, true
+	if withTracksStr := params.Get("with_tracks"); withTracksStr != "" {
+		withTracks, err := strconv.ParseBool(withTracksStr)
+		if err != nil {
+			handleError(w, "Invalid with_tracks parameter", http.StatusBadRequest, err)
+			return query, false
+		}
+		query.WithTracks = withTracks
 	}
-	return playlist, false
+
+	return query, true
 }
 
-func (handler *PlaylistHandler) ListPlaylists(w http.ResponseWriter, r *http.Request) {
-	playlists, err := handler.service.ListPlaylists()
+func (handler *PlaylistHandler) GetPlaylist(w http.ResponseWriter, r *http.Request) {
+	id, ok := handler.extractPlaylistID(w, r)
+	if !ok {
+		return
+	}
+
+	playlist, err := handler.service.GetPlaylist(id)
 	if err != nil {
-		handler.handleBusinessError(w, err)
+		handleBusinessError(w, err)
 		return
 	}
 
-	render.JSON(w, r, playlists)
+	render.JSON(w, r, toPlaylistResponse(playlist))
 }
 
-func (handler *PlaylistHandler) GetPlaylist(w http.ResponseWriter, r *http.Request) {
+func (handler *PlaylistHandler) UpdatePlaylist(w http.ResponseWriter, r *http.Request) {
+	id, ok := handler.extractPlaylistID(w, r)
+	if !ok {
+		return
+	}
+
+	var req beans.PlaylistUpdateRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	playlist := models.Playlist{Name: req.Name, Tracks: toPlaylistTracks(req.Tracks)}
+	if err := handler.service.UpdatePlaylist(id, &playlist); err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.JSON(w, r, toPlaylistResponse(&playlist))
+}
+
+func (handler *PlaylistHandler) RenamePlaylist(w http.ResponseWriter, r *http.Request) {
+	id, ok := handler.extractPlaylistID(w, r)
+	if !ok {
+		return
+	}
+
+	var req beans.PlaylistRenameRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	playlist, err := handler.service.RenamePlaylist(id, req.Name)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.JSON(w, r, toPlaylistResponse(playlist))
+}
+
+func (handler *PlaylistHandler) DeletePlaylist(w http.ResponseWriter, r *http.Request) {
+	id, ok := handler.extractPlaylistID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := handler.service.DeletePlaylist(id); err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *PlaylistHandler) ListTracks(w http.ResponseWriter, r *http.Request) {
 	id, ok := handler.extractPlaylistID(w, r)
 	if !ok {
 		return
@@ -104,17 +191,77 @@ func (handler *PlaylistHandler) GetPlaylist(w http.ResponseWriter, r *http.Reque
 
 	playlist, err := handler.service.GetPlaylist(id)
 	if err != nil {
-		handler.handleBusinessError(w, err)
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.JSON(w, r, toTrackResponses(playlist.Tracks))
+}
+
+func (handler *PlaylistHandler) AddTrack(w http.ResponseWriter, r *http.Request) {
+	id, ok := handler.extractPlaylistID(w, r)
+	if !ok {
 		return
 	}
 
-	var resp beans.PlaylistResponseApiBean
-	if err := copier.Copy(&resp, playlist); err != nil {
-		handler.handleError(w, mappingError, http.StatusInternalServerError, err)
+	var req beans.TrackAddRequest
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
-	render.JSON(w, r, resp)
+	entry := models.PlaylistTrack{
+		TrackID: req.ID,
+		Track:   models.Track{Title: req.Title, Artist: req.Artist, DurationSeconds: req.DurationSeconds},
+	}
+	if err := handler.service.AddTrack(id, &entry, req.Position); err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, toTrackResponses([]models.PlaylistTrack{entry})[0])
+}
+
+func (handler *PlaylistHandler) MoveTrack(w http.ResponseWriter, r *http.Request) {
+	id, ok := handler.extractPlaylistID(w, r)
+	if !ok {
+		return
+	}
+	trackID, ok := handler.extractTrackID(w, r)
+	if !ok {
+		return
+	}
+
+	var req beans.TrackMoveRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	playlist, err := handler.service.MoveTrack(id, trackID, *req.Position)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.JSON(w, r, toTrackResponses(playlist.Tracks))
+}
+
+func (handler *PlaylistHandler) RemoveTrack(w http.ResponseWriter, r *http.Request) {
+	id, ok := handler.extractPlaylistID(w, r)
+	if !ok {
+		return
+	}
+	trackID, ok := handler.extractTrackID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := handler.service.RemoveTrack(id, trackID); err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
 func (handler *PlaylistHandler) PlayPlaylist(w http.ResponseWriter, r *http.Request) {
@@ -123,15 +270,25 @@ func (handler *PlaylistHandler) PlayPlaylist(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	result, err := handler.applicationService.PlayPlaylist(id)
+	var req beans.PlaylistPlayRequest
+	if !decodeOptionalRequest(w, r, &req) {
+		return
+	}
+
+	options := models.PlayOptions{
+		Shuffle: models.ShuffleMode(req.Shuffle),
+		Repeat:  models.RepeatMode(req.Repeat),
+		Seed:    req.Seed,
+	}
+	result, err := handler.applicationService.PlayPlaylist(id, options)
 	if err != nil {
-		handler.handleBusinessError(w, err)
+		handleBusinessError(w, err)
 		return
 	}
 
 	var resp beans.PlaylistPlayResponse
 	if err := copier.Copy(&resp, result); err != nil {
-		handler.handleError(w, mappingError, http.StatusInternalServerError, err)
+		handleError(w, mappingError, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -141,39 +298,10 @@ func (handler *PlaylistHandler) PlayPlaylist(w http.ResponseWriter, r *http.Requ
 
 // extractPlaylistID helper pour extraire et valider l'ID depuis l'URL
 func (handler *PlaylistHandler) extractPlaylistID(w http.ResponseWriter, r *http.Request) (int, bool) {
-	idStr := chi.URLParam(r, IdParameter)
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		handler.handleError(w, "Invalid playlist ID format", http.StatusBadRequest, err)
-		return 0, false
-	}
-	return id, true
+	return extractIDParam(w, r, IdParameter, "playlist")
 }
 
-func (handler *PlaylistHandler) writeJSONError(w http.ResponseWriter, message string, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(beans.ErrorResponse{Error: message})
-}
-
-func (handler *PlaylistHandler) handleError(w http.ResponseWriter, message string, statusCode int, err error) {
-	log.Printf("Handler error: %s - %v", message, err)
-	handler.writeJSONError(w, message, statusCode)
-}
-
-func (handler *PlaylistHandler) handleBusinessError(w http.ResponseWriter, err error) {
-	var businessErr *domainErrors.BusinessError
-	if errors.As(err, &businessErr) {
-		log.Printf("Business error: %v", err)
-		switch {
-		case businessErr.IsValidation():
-			handler.writeJSONError(w, businessErr.Error(), http.StatusBadRequest)
-		case businessErr.IsNotFound():
-			handler.writeJSONError(w, businessErr.Error(), http.StatusNotFound)
-		default:
-			handler.writeJSONError(w, "Internal server error", http.StatusInternalServerError)
-		}
-		return
-	}
-	handler.writeJSONError(w, "Internal server error", http.StatusInternalServerError)
+// extractTrackID helper pour extraire et valider l'ID de la track depuis l'URL
+func (handler *PlaylistHandler) extractTrackID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	return extractIDParam(w, r, TrackIdParameter, "track")
 }
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"net/http"
+	"radioking-app/internal/api/http/beans"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/domain/services"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// RotationHandler expose les règles de rotation et leur simulation
+type RotationHandler struct {
+	service  services.IRotationService
+	schedule services.IScheduleService
+}
+
+func NewRotationHandler(service services.IRotationService, schedule services.IScheduleService) *RotationHandler {
+	return &RotationHandler{service: service, schedule: schedule}
+}
+
+func (handler *RotationHandler) Routes(router *chi.Mux) chi.Router {
+	router.Post("/rotation/rules", handler.CreateRule)
+	router.Get("/rotation/rules", handler.ListRules)
+	router.Get("/rotation/rules/{id}", handler.GetRule)
+	router.Put("/rotation/rules/{id}", handler.UpdateRule)
+	router.Delete("/rotation/rules/{id}", handler.DeleteRule)
+	router.Post("/rotation/dry-run", handler.DryRun)
+	return router
+}
+
+func (handler *RotationHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	var req beans.RotationRuleRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	rule := toRotationRule(req)
+	if err := handler.service.CreateRule(&rule); err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, toRotationRuleResponse(&rule))
+}
+
+func (handler *RotationHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := handler.service.ListRules()
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	resp := beans.RotationRuleListResponse{Data: make([]beans.RotationRuleResponseApiBean, 0, len(rules))}
+	for _, rule := range rules {
+		resp.Data = append(resp.Data, toRotationRuleResponse(rule))
+	}
+
+	render.JSON(w, r, resp)
+}
+
+func (handler *RotationHandler) GetRule(w http.ResponseWriter, r *http.Request) {
+	id, ok := extractIDParam(w, r, IdParameter, "rotation rule")
+	if !ok {
+		return
+	}
+
+	rule, err := handler.service.GetRule(id)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.JSON(w, r, toRotationRuleResponse(rule))
+}
+
+func (handler *RotationHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	id, ok := extractIDParam(w, r, IdParameter, "rotation rule")
+	if !ok {
+		return
+	}
+
+	var req beans.RotationRuleRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	rule := toRotationRule(req)
+	if err := handler.service.UpdateRule(id, &rule); err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.JSON(w, r, toRotationRuleResponse(&rule))
+}
+
+func (handler *RotationHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	id, ok := extractIDParam(w, r, IdParameter, "rotation rule")
+	if !ok {
+		return
+	}
+
+	if err := handler.service.DeleteRule(id); err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DryRun simule la file de lecture d'une playlist ou d'une heure programmée et rapporte
+// les règles de rotation enfreintes, sans rien jouer
+func (handler *RotationHandler) DryRun(w http.ResponseWriter, r *http.Request) {
+	var req beans.RotationDryRunRequest
+	if !decodeOptionalRequest(w, r, &req) {
+		return
+	}
+
+	startsAt := time.Now()
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+
+	var report *models.RotationReport
+	var err error
+	if req.PlaylistID != nil {
+		options := models.PlayOptions{
+			Shuffle: models.ShuffleMode(req.Shuffle),
+			Repeat:  models.RepeatMode(req.Repeat),
+			Seed:    req.Seed,
+		}
+		report, err = handler.service.DryRunPlaylist(*req.PlaylistID, options, startsAt)
+	} else {
+		report, err = handler.schedule.DryRunHour(startsAt)
+	}
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.JSON(w, r, toRotationDryRunResponse(report))
+}
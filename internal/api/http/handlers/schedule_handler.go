@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"radioking-app/internal/api/http/beans"
+	"radioking-app/internal/domain/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// ScheduleHandler expose la prévisualisation de la programmation
+type ScheduleHandler struct {
+	service services.IScheduleService
+}
+
+func NewScheduleHandler(service services.IScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{service: service}
+}
+
+func (handler *ScheduleHandler) Routes(router *chi.Mux) chi.Router {
+	router.Get("/schedule/preview", handler.Preview)
+	return router
+}
+
+// Preview retourne ce qui passera à l'antenne heure par heure pour la date demandée (AAAA-MM-JJ)
+func (handler *ScheduleHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	date := r.URL.Query().Get("date")
+	timezone := r.URL.Query().Get("tz")
+
+	programs, err := handler.service.Preview(date, timezone)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	resp := beans.SchedulePreviewResponse{
+		Date:     date,
+		Timezone: timezone,
+		Hours:    make([]beans.HourProgramApiBean, 0, len(programs)),
+	}
+	if resp.Timezone == "" && len(programs) > 0 {
+		resp.Timezone = programs[0].StartsAt.Location().String()
+	}
+	for _, program := range programs {
+		resp.Hours = append(resp.Hours, toHourProgram(program))
+	}
+
+	render.JSON(w, r, resp)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/domain/services"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// SessionHandler expose les sessions de lecture et leur pilotage
+type SessionHandler struct {
+	engine services.IPlaybackEngine
+}
+
+func NewSessionHandler(engine services.IPlaybackEngine) *SessionHandler {
+	return &SessionHandler{engine: engine}
+}
+
+func (handler *SessionHandler) Routes(router *chi.Mux) chi.Router {
+	router.Get("/sessions/{id}", handler.GetSession)
+	router.Post("/sessions/{id}/pause", handler.command(handler.engine.Pause))
+	router.Post("/sessions/{id}/resume", handler.command(handler.engine.Resume))
+	router.Post("/sessions/{id}/skip", handler.command(handler.engine.Skip))
+	router.Post("/sessions/{id}/back", handler.command(handler.engine.Back))
+	router.Post("/sessions/{id}/stop", handler.command(handler.engine.Stop))
+	return router
+}
+
+func (handler *SessionHandler) GetSession(w http.ResponseWriter, r *http.Request) {
+	id, ok := extractIDParam(w, r, IdParameter, "session")
+	if !ok {
+		return
+	}
+
+	session, err := handler.engine.GetSession(int64(id))
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.JSON(w, r, toPlaySessionResponse(session, time.Now()))
+}
+
+// command retourne un handler appliquant l'action à la session puis renvoyant son nouvel état
+func (handler *SessionHandler) command(action func(id int64) (*models.PlaySession, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := extractIDParam(w, r, IdParameter, "session")
+		if !ok {
+			return
+		}
+
+		session, err := action(int64(id))
+		if err != nil {
+			handleBusinessError(w, err)
+			return
+		}
+
+		render.JSON(w, r, toPlaySessionResponse(session, time.Now()))
+	}
+}
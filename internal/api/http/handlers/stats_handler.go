@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"net/http"
+	"radioking-app/internal/api/http/beans"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/domain/services"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// StatsHandler expose les statistiques de lecture. Chaque route accepte les paramètres from, to
+// (date AAAA-MM-JJ ou RFC 3339) et tz (fuseau IANA).
+type StatsHandler struct {
+	service          services.IStatsService
+	trackPlayService services.ITrackPlayService
+}
+
+func NewStatsHandler(service services.IStatsService, trackPlayService services.ITrackPlayService) *StatsHandler {
+	return &StatsHandler{service: service, trackPlayService: trackPlayService}
+}
+
+func (handler *StatsHandler) Routes(router *chi.Mux) chi.Router {
+	router.Route("/stats", func(r chi.Router) {
+		r.Get("/top-tracks", handler.TopTracks)
+		r.Get("/top-artists", handler.TopArtists)
+		r.Get("/playlists", handler.PlaylistTotals)
+		r.Get("/hourly", handler.HourlyPlays)
+		r.Get("/weekdays", handler.WeekdayPlays)
+		r.Get("/playlists/{id}/plays", handler.PlaylistPlays)
+		r.Get("/tracks/{id}/plays", handler.TrackPlays)
+	})
+	return router
+}
+
+// TopTracks retourne les limit tracks les plus jouées de la période
+func (handler *StatsHandler) TopTracks(w http.ResponseWriter, r *http.Request) {
+	period, limit, ok := handler.extractRanking(w, r)
+	if !ok {
+		return
+	}
+
+	counts, err := handler.service.TopTracks(period, limit)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	resp := beans.TopTracksResponse{StatsPeriodApiBean: toStatsPeriod(period), Tracks: make([]beans.TrackPlayCountApiBean, 0, len(counts))}
+	for _, count := range counts {
+		resp.Tracks = append(resp.Tracks, beans.TrackPlayCountApiBean(count))
+	}
+	render.JSON(w, r, resp)
+}
+
+// TopArtists retourne les limit artistes les plus joués de la période
+func (handler *StatsHandler) TopArtists(w http.ResponseWriter, r *http.Request) {
+	period, limit, ok := handler.extractRanking(w, r)
+	if !ok {
+		return
+	}
+
+	counts, err := handler.service.TopArtists(period, limit)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	resp := beans.TopArtistsResponse{StatsPeriodApiBean: toStatsPeriod(period), Artists: make([]beans.ArtistPlayCountApiBean, 0, len(counts))}
+	for _, count := range counts {
+		resp.Artists = append(resp.Artists, beans.ArtistPlayCountApiBean(count))
+	}
+	render.JSON(w, r, resp)
+}
+
+// PlaylistTotals retourne les totaux de lecture de chaque playlist jouée sur la période
+func (handler *StatsHandler) PlaylistTotals(w http.ResponseWriter, r *http.Request) {
+	period, ok := handler.extractPeriod(w, r)
+	if !ok {
+		return
+	}
+
+	counts, err := handler.service.PlaylistTotals(period)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	resp := beans.PlaylistTotalsResponse{StatsPeriodApiBean: toStatsPeriod(period), Playlists: make([]beans.PlaylistPlayCountApiBean, 0, len(counts))}
+	for _, count := range counts {
+		resp.Playlists = append(resp.Playlists, beans.PlaylistPlayCountApiBean(count))
+	}
+	render.JSON(w, r, resp)
+}
+
+// HourlyPlays retourne le nombre de lectures de chaque heure du jour
+func (handler *StatsHandler) HourlyPlays(w http.ResponseWriter, r *http.Request) {
+	period, ok := handler.extractPeriod(w, r)
+	if !ok {
+		return
+	}
+
+	counts, err := handler.service.HourlyPlays(period)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	resp := beans.HourlyStatsResponse{StatsPeriodApiBean: toStatsPeriod(period), Hours: make([]beans.HourPlaysApiBean, 0, len(counts))}
+	for hour, plays := range counts {
+		resp.Hours = append(resp.Hours, beans.HourPlaysApiBean{Hour: hour, Plays: plays})
+	}
+	render.JSON(w, r, resp)
+}
+
+// WeekdayPlays retourne le nombre de lectures de chaque jour de la semaine
+func (handler *StatsHandler) WeekdayPlays(w http.ResponseWriter, r *http.Request) {
+	period, ok := handler.extractPeriod(w, r)
+	if !ok {
+		return
+	}
+
+	counts, err := handler.service.WeekdayPlays(period)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	resp := beans.WeekdayStatsResponse{StatsPeriodApiBean: toStatsPeriod(period), Weekdays: make([]beans.WeekdayPlaysApiBean, 0, len(counts))}
+	for weekday, plays := range counts {
+		resp.Weekdays = append(resp.Weekdays, beans.WeekdayPlaysApiBean{Weekday: weekday, Name: time.Weekday(weekday).String(), Plays: plays})
+	}
+	render.JSON(w, r, resp)
+}
+
+// PlaylistPlays retourne les limit dernières lectures d'une playlist sur la période, les plus récentes en premier
+func (handler *StatsHandler) PlaylistPlays(w http.ResponseWriter, r *http.Request) {
+	id, ok := extractIDParam(w, r, IdParameter, "playlist")
+	if !ok {
+		return
+	}
+	period, limit, ok := handler.extractRanking(w, r)
+	if !ok {
+		return
+	}
+
+	plays, err := handler.trackPlayService.GetPlaylistPlays(id, period, limit)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+	render.JSON(w, r, toTrackPlaysResponse(period, plays))
+}
+
+// TrackPlays retourne les limit dernières lectures d'une track sur la période, les plus récentes en premier
+func (handler *StatsHandler) TrackPlays(w http.ResponseWriter, r *http.Request) {
+	id, ok := extractIDParam(w, r, IdParameter, "track")
+	if !ok {
+		return
+	}
+	period, limit, ok := handler.extractRanking(w, r)
+	if !ok {
+		return
+	}
+
+	plays, err := handler.trackPlayService.GetTrackPlays(id, period, limit)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+	render.JSON(w, r, toTrackPlaysResponse(period, plays))
+}
+
+func (handler *StatsHandler) extractPeriod(w http.ResponseWriter, r *http.Request) (models.StatsPeriod, bool) {
+	query := r.URL.Query()
+	period, err := handler.service.ResolvePeriod(models.StatsQuery{
+		From:     query.Get("from"),
+		To:       query.Get("to"),
+		Timezone: query.Get("tz"),
+	})
+	if err != nil {
+		handleBusinessError(w, err)
+		return models.StatsPeriod{}, false
+	}
+	return period, true
+}
+
+func (handler *StatsHandler) extractRanking(w http.ResponseWriter, r *http.Request) (models.StatsPeriod, int, bool) {
+	limit, ok := extractLimit(w, r)
+	if !ok {
+		return models.StatsPeriod{}, 0, false
+	}
+	period, ok := handler.extractPeriod(w, r)
+	return period, limit, ok
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"radioking-app/internal/api/http/authentication"
+	"radioking-app/internal/api/http/beans"
+	"radioking-app/internal/domain/services"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	studioPongWait     = 60 * time.Second
+	studioPingPeriod   = studioPongWait / 2
+	studioWriteWait    = 10 * time.Second
+	studioMaxMessage   = 4096
+	anonymousOperator  = "anonymous"
+	studioInvalidInput = "Invalid studio command"
+)
+
+// StudioHandler console des opérateurs : une WebSocket diffuse l'état des sessions et reçoit
+// les commandes de pilotage. Un navigateur ne peut ouvrir la console que depuis l'origine du
+// serveur ou une origine de allowedOrigins.
+type StudioHandler struct {
+	service        services.IStudioService
+	allowedOrigins []string
+	upgrader       websocket.Upgrader
+}
+
+func NewStudioHandler(service services.IStudioService, allowedOrigins []string) *StudioHandler {
+	handler := &StudioHandler{service: service, allowedOrigins: allowedOrigins}
+	handler.upgrader = websocket.Upgrader{
+		// Le sous-protocole qui porte le jeton est accepté, le jeton n'est jamais renvoyé
+		Subprotocols: []string{authentication.WebSocketBearerProtocol},
+		CheckOrigin:  handler.checkOrigin,
+	}
+	return handler
+}
+
+// checkOrigin accepte les clients hors navigateur, sans en-tête Origin, l'origine du serveur
+// et les origines autorisées
+func (handler *StudioHandler) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range handler.allowedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	log.Printf("Studio connection refused from origin %s", origin)
+	return false
+}
+
+func (handler *StudioHandler) Routes(router *chi.Mux) chi.Router {
+	router.Get("/studio/ws", handler.Connect)
+	return router
+}
+
+// Connect ouvre la WebSocket de la console. Le premier message "snapshot" décrit les sessions
+// actives, chaque changement d'état est ensuite poussé dans un message "session".
+func (handler *StudioHandler) Connect(w http.ResponseWriter, r *http.Request) {
+	operator := anonymousOperator
+	if claims, ok := authentication.GetUserClaims(r); ok && claims.PreferredUsername != "" {
+		operator = claims.PreferredUsername
+	}
+
+	conn, err := handler.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("Failed to open studio connection: %v", err)
+		return
+	}
+	defer conn.Close()
+	log.Printf("Studio console opened by %s", operator)
+
+	// L'abonnement précède l'instantané pour ne perdre aucun changement
+	updates, unsubscribe := handler.service.Subscribe()
+	defer unsubscribe()
+
+	sessions, err := handler.service.ActiveSessions()
+	if err != nil {
+		message, _ := businessErrorResponse(err)
+		_ = writeStudioMessage(conn, beans.StudioMessageApiBean{Type: "error", Error: message})
+		return
+	}
+	snapshot := beans.StudioMessageApiBean{Type: "snapshot", Sessions: make([]beans.StudioSessionApiBean, 0, len(sessions))}
+	for _, session := range sessions {
+		snapshot.Sessions = append(snapshot.Sessions, toStudioSession(session, time.Now()))
+	}
+	if err := writeStudioMessage(conn, snapshot); err != nil {
+		return
+	}
+
+	commands := make(chan beans.StudioCommandApiBean)
+	closed := make(chan struct{})
+	stop := make(chan struct{})
+	defer close(stop)
+	go readStudioCommands(conn, commands, closed, stop)
+
+	ping := time.NewTicker(studioPingPeriod)
+	defer ping.Stop()
+
+	for {
+		var message beans.StudioMessageApiBean
+		select {
+		case <-r.Context().Done():
+			_ = conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(studioWriteWait))
+			return
+		case <-closed:
+			log.Printf("Studio console closed by %s", operator)
+			return
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(studioWriteWait)); err != nil {
+				return
+			}
+			continue
+		case session, open := <-updates:
+			if !open {
+				return
+			}
+			state := toStudioSession(&session, time.Now())
+			message = beans.StudioMessageApiBean{Type: "session", Session: &state}
+		case req := <-commands:
+			message = handler.execute(req, operator)
+		}
+
+		if err := writeStudioMessage(conn, message); err != nil {
+			log.Printf("Failed to write to studio console of %s: %v", operator, err)
+			return
+		}
+	}
+}
+
+// execute applique la commande et construit la réponse "ack" ou "error" qui lui correspond
+func (handler *StudioHandler) execute(req beans.StudioCommandApiBean, operator string) beans.StudioMessageApiBean {
+	if err := validate.Struct(req); err != nil {
+		log.Printf("Handler error: %s - %v", studioInvalidInput, err)
+		return beans.StudioMessageApiBean{Type: "error", ID: req.ID, Error: studioInvalidInput}
+	}
+
+	session, err := handler.service.Execute(toStudioCommand(req, operator))
+	if err != nil {
+		message, _ := businessErrorResponse(err)
+		return beans.StudioMessageApiBean{Type: "error", ID: req.ID, Error: message}
+	}
+
+	state := toStudioSession(session, time.Now())
+	return beans.StudioMessageApiBean{Type: "ack", ID: req.ID, Session: &state}
+}
+
+// readStudioCommands lit les commandes de la console jusqu'à la fermeture de la connexion.
+// Un message illisible donne une commande vide, rejetée à la validation.
+func readStudioCommands(conn *websocket.Conn, commands chan<- beans.StudioCommandApiBean,
+	closed chan<- struct{}, stop <-chan struct{}) {
+	defer close(closed)
+
+	conn.SetReadLimit(studioMaxMessage)
+	_ = conn.SetReadDeadline(time.Now().Add(studioPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(studioPongWait))
+	})
+
+	for {
+		_, payload, err := conn.ReadMessage()
+		if err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) &&
+				!errors.Is(err, websocket.ErrCloseSent) {
+				log.Printf("Studio connection error: %v", err)
+			}
+			return
+		}
+
+		var req beans.StudioCommandApiBean
+		if err := json.Unmarshal(payload, &req); err != nil {
+			log.Printf("Handler error: %s - %v", studioInvalidInput, err)
+		}
+		select {
+		case commands <- req:
+		case <-stop:
+			return
+		}
+	}
+}
+
+func writeStudioMessage(conn *websocket.Conn, message beans.StudioMessageApiBean) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(studioWriteWait))
+	return conn.WriteJSON(message)
+}
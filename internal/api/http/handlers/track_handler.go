@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"net/http"
+	"radioking-app/internal/api/http/beans"
+	"radioking-app/internal/domain/constants"
+	"radioking-app/internal/domain/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// TrackHandler expose le catalogue de tracks
+type TrackHandler struct {
+	service services.ITrackService
+}
+
+func NewTrackHandler(service services.ITrackService) *TrackHandler {
+	return &TrackHandler{service: service}
+}
+
+func (handler *TrackHandler) Routes(router *chi.Mux) chi.Router {
+	router.Post("/tracks", handler.CreateTrack)
+	router.Get("/tracks", handler.ListTracks)
+	router.Get("/tracks/{id}", handler.GetTrack)
+	router.Put("/tracks/{id}", handler.UpdateTrack)
+	router.Delete("/tracks/{id}", handler.DeleteTrack)
+	return router
+}
+
+func (handler *TrackHandler) CreateTrack(w http.ResponseWriter, r *http.Request) {
+	var req beans.CatalogTrackRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	track := toCatalogTrack(req)
+	if err := handler.service.CreateTrack(&track); err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, toCatalogTrackResponse(&track))
+}
+
+func (handler *TrackHandler) ListTracks(w http.ResponseWriter, r *http.Request) {
+	limit, ok := extractLimit(w, r)
+	if !ok {
+		return
+	}
+
+	cursor := r.URL.Query().Get("cursor")
+	page, err := handler.service.ListTracks(limit, cursor)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	if limit == 0 {
+		limit = constants.DefaultPageSize
+	}
+
+	resp := beans.TrackPageResponse{
+		Data:       toCatalogTrackResponses(page.Tracks),
+		Pagination: newPagination(limit, page.NextCursor),
+		Links:      newPageLinks(r, page.NextCursor),
+	}
+
+	render.JSON(w, r, resp)
+}
+
+func (handler *TrackHandler) GetTrack(w http.ResponseWriter, r *http.Request) {
+	id, ok := extractIDParam(w, r, IdParameter, "track")
+	if !ok {
+		return
+	}
+
+	track, err := handler.service.GetTrack(id)
+	if err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.JSON(w, r, toCatalogTrackResponse(track))
+}
+
+func (handler *TrackHandler) UpdateTrack(w http.ResponseWriter, r *http.Request) {
+	id, ok := extractIDParam(w, r, IdParameter, "track")
+	if !ok {
+		return
+	}
+
+	var req beans.CatalogTrackRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	track := toCatalogTrack(req)
+	if err := handler.service.UpdateTrack(id, &track); err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	render.JSON(w, r, toCatalogTrackResponse(&track))
+}
+
+func (handler *TrackHandler) DeleteTrack(w http.ResponseWriter, r *http.Request) {
+	id, ok := extractIDParam(w, r, IdParameter, "track")
+	if !ok {
+		return
+	}
+
+	if err := handler.service.DeleteTrack(id); err != nil {
+		handleBusinessError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -9,11 +10,15 @@ import (
 type Config struct {
 	Server    ServerConfig    `mapstructure:"server"`
 	Auth      AuthConfig      `mapstructure:"auth"`
+	Station   StationConfig   `mapstructure:"station"`
 	Messaging MessagingConfig `mapstructure:"messaging"`
 }
 
+// ServerConfig AllowedOrigins origines, en plus de celle du serveur, depuis lesquelles un navigateur
+// peut ouvrir la console du studio
 type ServerConfig struct {
-	Port string `mapstructure:"port"`
+	Port           string   `mapstructure:"port"`
+	AllowedOrigins []string `mapstructure:"allowed_origins"`
 }
 
 type AuthConfig struct {
@@ -22,8 +27,24 @@ type AuthConfig struct {
 	Realm       string `mapstructure:"realm"`
 }
 
+// StationConfig Timezone fuseau IANA dans lequel la grille est programmée et diffusée,
+// "Local" pour celui du serveur
+type StationConfig struct {
+	Timezone string `mapstructure:"timezone"`
+}
+
 type MessagingConfig struct {
+	// Driver "rabbitmq", ou "memory" pour un broker en mémoire qui permet de lancer l'application sans RabbitMQ
+	Driver   string         `mapstructure:"driver"`
 	RabbitMQ RabbitMQConfig `mapstructure:"rabbitmq"`
+	Outbox   OutboxConfig   `mapstructure:"outbox"`
+}
+
+// OutboxConfig le relais publie l'outbox toutes les PollInterval et supprime les messages
+// publiés depuis plus de Retention
+type OutboxConfig struct {
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	Retention    time.Duration `mapstructure:"retention"`
 }
 
 type RabbitMQConfig struct {
@@ -31,6 +52,40 @@ type RabbitMQConfig struct {
 	Exchange   string `mapstructure:"exchange"`
 	Queue      string `mapstructure:"queue"`
 	RoutingKey string `mapstructure:"routing_key"`
+
+	EndedQueue      string `mapstructure:"ended_queue"`
+	EndedRoutingKey string `mapstructure:"ended_routing_key"`
+
+	// Chaque autre type d'événement souscrit a sa queue, nommée d'après son type ("playlist_created")
+	// et liée par le type. Seuls les types consommés par l'application doivent y figurer : une queue
+	// que personne ne lit se remplit indéfiniment.
+	SubscribedEvents []string `mapstructure:"subscribed_events"`
+
+	// Un message en échec est retenté MaxRetries fois, après RetryBaseDelay doublé à chaque
+	// tentative sans dépasser RetryMaxDelay, puis il est envoyé dans DeadLetterQueue
+	MaxRetries         int           `mapstructure:"max_retries"`
+	RetryBaseDelay     time.Duration `mapstructure:"retry_base_delay"`
+	RetryMaxDelay      time.Duration `mapstructure:"retry_max_delay"`
+	DeadLetterExchange string        `mapstructure:"dead_letter_exchange"`
+	DeadLetterQueue    string        `mapstructure:"dead_letter_queue"`
+
+	// Après une coupure, la reconnexion est tentée après ReconnectDelay, doublé à chaque échec sans
+	// dépasser ReconnectMaxDelay. Une publication échoue sans confirmation du broker après ConfirmTimeout.
+	ReconnectDelay    time.Duration `mapstructure:"reconnect_delay"`
+	ReconnectMaxDelay time.Duration `mapstructure:"reconnect_max_delay"`
+	ConfirmTimeout    time.Duration `mapstructure:"confirm_timeout"`
+
+	// Chaque queue est traitée par Workers workers, avec au plus Prefetch messages non acquittés.
+	// Les événements d'une même playlist sont traités dans leur ordre de réception.
+	Workers  int `mapstructure:"workers"`
+	Prefetch int `mapstructure:"prefetch"`
+
+	// Topology au démarrage, "declare" crée les exchanges, queues et bindings manquants,
+	// "verify" vérifie seulement leur existence
+	Topology string `mapstructure:"topology"`
+
+	// Les événements sont publiés au format CloudEvents, en mode "structured" ou "binary"
+	CloudEventsMode string `mapstructure:"cloudevents_mode"`
 }
 
 func Load() (*Config, error) {
@@ -44,13 +99,33 @@ func Load() (*Config, error) {
 	viper.SetEnvPrefix("RADIOKING")
 
 	viper.BindEnv("server.port", "RADIOKING_SERVER_PORT")
+	viper.BindEnv("server.allowed_origins", "RADIOKING_SERVER_ALLOWED_ORIGINS")
 	viper.BindEnv("auth.enabled", "RADIOKING_AUTH_ENABLED")
 	viper.BindEnv("auth.keycloak_url", "RADIOKING_AUTH_KEYCLOAK_URL")
 	viper.BindEnv("auth.realm", "RADIOKING_AUTH_REALM")
+	viper.BindEnv("station.timezone", "RADIOKING_STATION_TIMEZONE")
+	viper.BindEnv("messaging.driver", "RADIOKING_MESSAGING_DRIVER")
 	viper.BindEnv("messaging.rabbitmq.url", "RADIOKING_RABBITMQ_URL")
 	viper.BindEnv("messaging.rabbitmq.exchange", "RADIOKING_RABBITMQ_EXCHANGE")
 	viper.BindEnv("messaging.rabbitmq.queue", "RADIOKING_RABBITMQ_QUEUE")
 	viper.BindEnv("messaging.rabbitmq.routing_key", "RADIOKING_RABBITMQ_ROUTING_KEY")
+	viper.BindEnv("messaging.rabbitmq.ended_queue", "RADIOKING_RABBITMQ_ENDED_QUEUE")
+	viper.BindEnv("messaging.rabbitmq.ended_routing_key", "RADIOKING_RABBITMQ_ENDED_ROUTING_KEY")
+	viper.BindEnv("messaging.rabbitmq.subscribed_events", "RADIOKING_RABBITMQ_SUBSCRIBED_EVENTS")
+	viper.BindEnv("messaging.rabbitmq.max_retries", "RADIOKING_RABBITMQ_MAX_RETRIES")
+	viper.BindEnv("messaging.rabbitmq.retry_base_delay", "RADIOKING_RABBITMQ_RETRY_BASE_DELAY")
+	viper.BindEnv("messaging.rabbitmq.retry_max_delay", "RADIOKING_RABBITMQ_RETRY_MAX_DELAY")
+	viper.BindEnv("messaging.rabbitmq.dead_letter_exchange", "RADIOKING_RABBITMQ_DEAD_LETTER_EXCHANGE")
+	viper.BindEnv("messaging.rabbitmq.dead_letter_queue", "RADIOKING_RABBITMQ_DEAD_LETTER_QUEUE")
+	viper.BindEnv("messaging.rabbitmq.reconnect_delay", "RADIOKING_RABBITMQ_RECONNECT_DELAY")
+	viper.BindEnv("messaging.rabbitmq.reconnect_max_delay", "RADIOKING_RABBITMQ_RECONNECT_MAX_DELAY")
+	viper.BindEnv("messaging.rabbitmq.confirm_timeout", "RADIOKING_RABBITMQ_CONFIRM_TIMEOUT")
+	viper.BindEnv("messaging.rabbitmq.workers", "RADIOKING_RABBITMQ_WORKERS")
+	viper.BindEnv("messaging.rabbitmq.prefetch", "RADIOKING_RABBITMQ_PREFETCH")
+	viper.BindEnv("messaging.rabbitmq.topology", "RADIOKING_RABBITMQ_TOPOLOGY")
+	viper.BindEnv("messaging.rabbitmq.cloudevents_mode", "RADIOKING_RABBITMQ_CLOUDEVENTS_MODE")
+	viper.BindEnv("messaging.outbox.poll_interval", "RADIOKING_OUTBOX_POLL_INTERVAL")
+	viper.BindEnv("messaging.outbox.retention", "RADIOKING_OUTBOX_RETENTION")
 
 	setDefaultValues()
 
@@ -70,11 +145,31 @@ func Load() (*Config, error) {
 
 func setDefaultValues() {
 	viper.SetDefault("server.port", "8080")
+	viper.SetDefault("server.allowed_origins", []string{})
 	viper.SetDefault("auth.enabled", true)
 	viper.SetDefault("auth.keycloak_url", "http://localhost:8180")
 	viper.SetDefault("auth.realm", "radioking")
+	viper.SetDefault("station.timezone", "Local")
+	viper.SetDefault("messaging.driver", "rabbitmq")
 	viper.SetDefault("messaging.rabbitmq.url", "amqp://localhost:5672")
 	viper.SetDefault("messaging.rabbitmq.exchange", "playlist_events")
 	viper.SetDefault("messaging.rabbitmq.queue", "track_played")
 	viper.SetDefault("messaging.rabbitmq.routing_key", "track.played")
+	viper.SetDefault("messaging.rabbitmq.ended_queue", "track_ended")
+	viper.SetDefault("messaging.rabbitmq.ended_routing_key", "track.ended")
+	viper.SetDefault("messaging.rabbitmq.subscribed_events", []string{})
+	viper.SetDefault("messaging.rabbitmq.max_retries", 5)
+	viper.SetDefault("messaging.rabbitmq.retry_base_delay", "1s")
+	viper.SetDefault("messaging.rabbitmq.retry_max_delay", "5m")
+	viper.SetDefault("messaging.rabbitmq.dead_letter_exchange", "playlist_events.dlx")
+	viper.SetDefault("messaging.rabbitmq.dead_letter_queue", "playlist_events.dead")
+	viper.SetDefault("messaging.rabbitmq.reconnect_delay", "1s")
+	viper.SetDefault("messaging.rabbitmq.reconnect_max_delay", "30s")
+	viper.SetDefault("messaging.rabbitmq.confirm_timeout", "5s")
+	viper.SetDefault("messaging.rabbitmq.workers", 4)
+	viper.SetDefault("messaging.rabbitmq.prefetch", 20)
+	viper.SetDefault("messaging.rabbitmq.topology", "declare")
+	viper.SetDefault("messaging.rabbitmq.cloudevents_mode", "structured")
+	viper.SetDefault("messaging.outbox.poll_interval", "1s")
+	viper.SetDefault("messaging.outbox.retention", "168h")
 }
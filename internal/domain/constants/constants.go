@@ -4,5 +4,45 @@ const (
 	MaxPlaylistNameLength = 255
 	MaxTrackNameLength    = 255
 	MaxArtistNameLength   = 255
+	MaxAlbumTitleLength   = 255
 	MaxTracksPerPlaylist  = 100
+
+	// Durée appliquée à la lecture des tracks dont la durée n'est pas renseignée
+	DefaultTrackDurationSeconds = 180
+	MaxTrackDurationSeconds     = 24 * 60 * 60
+
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+
+	MaxClockNameLength = 255
+	MaxSlotsPerClock   = 60
+	// Un slot ne peut pas dépasser l'heure de sa clock
+	MaxSlotDurationSeconds = 60 * 60
+	// Nombre maximum de tracks du catalogue parmi lesquelles un slot rule tire ses tracks
+	MaxRuleCandidates = 500
+
+	MaxRotationRuleNameLength = 255
+	// Fenêtre maximale d'une règle de rotation : une semaine
+	MaxRotationWindowMinutes = 7 * 24 * 60
+
+	// Taille maximale de la file d'une session, insertions depuis le studio comprises
+	MaxPlaySessionItems = 1000
+
+	DefaultStatsLimit = 10
+	MaxStatsLimit     = 100
+	// Période des statistiques quand elle n'est pas précisée : les 7 derniers jours
+	DefaultStatsRangeDays = 7
+
+	DefaultDeadLetterLimit = 20
+	MaxDeadLetterLimit     = 100
+
+	DefaultOutboxLimit = 20
+	MaxOutboxLimit     = 100
+	// Nombre de messages publiés par passage du relais de l'outbox
+	OutboxBatchSize = 100
+	// Délai maximal entre deux tentatives de publication d'un message de l'outbox
+	MaxOutboxRetryDelaySeconds = 5 * 60
+
+	// Nombre d'événements lus à la fois dans le journal lors d'un rejeu
+	EventReplayBatchSize = 500
 )
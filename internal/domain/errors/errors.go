@@ -10,6 +10,7 @@ const (
 	ValidationError ErrorType = iota
 	NotFoundError
 	InternalError
+	ConflictError
 )
 
 type BusinessError struct {
@@ -37,6 +38,10 @@ func (e *BusinessError) IsNotFound() bool {
 	return e.Type == NotFoundError
 }
 
+func (e *BusinessError) IsConflict() bool {
+	return e.Type == ConflictError
+}
+
 func NewValidationError(message string) *BusinessError {
 	return &BusinessError{
 		Type:    ValidationError,
@@ -51,6 +56,13 @@ func NewNotFoundError(message string) *BusinessError {
 	}
 }
 
+func NewConflictError(message string) *BusinessError {
+	return &BusinessError{
+		Type:    ConflictError,
+		Message: message,
+	}
+}
+
 func NewInternalError(message string, err error) *BusinessError {
 	return &BusinessError{
 		Type:    InternalError,
@@ -66,4 +78,65 @@ var (
 	ErrEmptyTrackTitle   = NewValidationError("track title cannot be empty")
 	ErrEmptyTrackArtist  = NewValidationError("track artist cannot be empty")
 	ErrPlaylistNotFound  = NewNotFoundError("playlist not found")
+
+	ErrInvalidPageLimit = NewValidationError("invalid page limit")
+	ErrInvalidCursor    = NewValidationError("invalid pagination cursor")
+	ErrInvalidSort      = NewValidationError("invalid sort parameters")
+
+	ErrInvalidTrackPosition   = NewValidationError("invalid track position")
+	ErrInvalidTrackID         = NewValidationError("invalid track ID")
+	ErrInvalidTrackDuration   = NewValidationError("invalid track duration")
+	ErrInvalidISRC            = NewValidationError("invalid ISRC code")
+	ErrTrackNotFound          = NewNotFoundError("track not found")
+	ErrTrackAlreadyExists     = NewConflictError("track already exists in catalog")
+	ErrTrackAlreadyInPlaylist = NewConflictError("track already in playlist")
+
+	ErrInvalidArtistID  = NewValidationError("invalid artist ID")
+	ErrEmptyArtistQuery = NewValidationError("artist name to search cannot be empty")
+	ErrArtistNotFound   = NewNotFoundError("artist not found")
+	ErrInvalidAlbumID   = NewValidationError("invalid album ID")
+	ErrAlbumNotFound    = NewNotFoundError("album not found")
+
+	ErrInvalidPlaySessionID  = NewValidationError("invalid play session ID")
+	ErrInvalidPlayOptions    = NewValidationError("invalid play options")
+	ErrPlaySessionNotFound   = NewNotFoundError("play session not found")
+	ErrPlaySessionNotPlaying = NewConflictError("play session is not playing")
+	ErrPlaySessionNotPaused  = NewConflictError("play session is not paused")
+	ErrPlaySessionNotActive  = NewConflictError("play session is over")
+	ErrPlaySessionQueueFull  = NewConflictError("play session queue is full")
+	ErrUnknownStudioCommand  = NewValidationError("unknown studio command")
+
+	ErrInvalidClockID    = NewValidationError("invalid clock ID")
+	ErrEmptyClockName    = NewValidationError("clock name cannot be empty")
+	ErrTooManyClockSlots = NewValidationError("clock cannot have more than allowed slots")
+	ErrClockNotFound     = NewNotFoundError("clock not found")
+	ErrInvalidGridHour   = NewValidationError("invalid weekday or hour")
+	ErrGridHourNotFound  = NewNotFoundError("no clock scheduled at this hour")
+	ErrInvalidDate       = NewValidationError("invalid date")
+	ErrInvalidTimezone   = NewValidationError("invalid timezone")
+
+	ErrInvalidRotationRuleID = NewValidationError("invalid rotation rule ID")
+	ErrInvalidRotationRule   = NewValidationError("invalid rotation rule")
+	ErrRotationRuleNotFound  = NewNotFoundError("rotation rule not found")
+
+	ErrNothingOnAir = NewNotFoundError("nothing is on air")
+
+	ErrInvalidStatsRange = NewValidationError("invalid stats time range")
+	ErrInvalidStatsLimit = NewValidationError("invalid stats limit")
+
+	ErrInvalidCueSheetFormat = NewValidationError("invalid cue sheet format")
+
+	ErrDuplicateEvent = NewConflictError("event already recorded")
+
+	ErrInvalidDeadLetterLimit = NewValidationError("invalid dead letter limit")
+
+	ErrInvalidOutboxLimit     = NewValidationError("invalid outbox limit")
+	ErrInvalidOutboxStatus    = NewValidationError("invalid outbox status")
+	ErrInvalidOutboxRetention = NewValidationError("invalid outbox retention")
+	ErrOutboxMessageNotFound  = NewNotFoundError("outbox message not found")
+
+	ErrEventSchemaNotFound = NewNotFoundError("event schema not found")
+
+	ErrUnknownProjection  = NewValidationError("unknown projection")
+	ErrInvalidReplayRange = NewValidationError("invalid replay time range")
 )
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Artist représente un artiste du catalogue, identifié par son nom normalisé
+type Artist struct {
+	ID             int64  `gorm:"primaryKey;autoIncrement"`
+	Name           string `gorm:"size:255;not null"`
+	NormalizedName string `gorm:"size:255;not null;uniqueIndex"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Album représente un album d'un artiste, identifié par son titre normalisé
+type Album struct {
+	ID              int64  `gorm:"primaryKey;autoIncrement"`
+	Title           string `gorm:"size:255;not null"`
+	NormalizedTitle string `gorm:"size:255;not null;uniqueIndex:idx_albums_artist_title"`
+	ArtistID        int64  `gorm:"not null;uniqueIndex:idx_albums_artist_title"`
+	Artist          Artist `gorm:"foreignKey:ArtistID"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// ArtistOverview regroupe un artiste avec ses tracks, ses albums et les playlists où il apparaît
+type ArtistOverview struct {
+	Artist    *Artist
+	Albums    []*Album
+	Tracks    []*Track
+	Playlists []*Playlist
+}
+
+// AlbumOverview regroupe un album avec ses tracks et les playlists où il apparaît
+type AlbumOverview struct {
+	Album     *Album
+	Tracks    []*Track
+	Playlists []*Playlist
+}
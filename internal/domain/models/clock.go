@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+type ClockSlotType string
+
+const (
+	ClockSlotPlaylist ClockSlotType = "playlist" // Tracks d'une playlist, dans l'ordre ou mélangées
+	ClockSlotJingle   ClockSlotType = "jingle"   // Une track du catalogue jouée une fois
+	ClockSlotRule     ClockSlotType = "rule"     // Tracks du catalogue tirées selon l'artiste ou l'album
+)
+
+// Clock modèle d'une heure d'antenne, découpée en slots joués dans l'ordre
+type Clock struct {
+	ID        int64       `gorm:"primaryKey;autoIncrement"`
+	Name      string      `gorm:"size:255;not null"`
+	Slots     []ClockSlot `gorm:"foreignKey:ClockID"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ClockSlot segment d'une clock. DurationSeconds borne le temps d'antenne des slots playlist
+// et rule : des tracks sont ajoutées tant que la durée n'est pas atteinte, 0 joue toute la playlist.
+type ClockSlot struct {
+	ID              int64         `gorm:"primaryKey;autoIncrement"`
+	ClockID         int64         `gorm:"not null;index"`
+	Position        int           `gorm:"not null;default:0"`
+	Type            ClockSlotType `gorm:"size:20;not null"`
+	PlaylistID      *int64        // Slot playlist
+	TrackID         *int64        // Slot jingle
+	ArtistID        *int64        // Filtre optionnel d'un slot rule
+	AlbumID         *int64        // Filtre optionnel d'un slot rule
+	DurationSeconds int           `gorm:"not null;default:0"`
+	Shuffle         bool          `gorm:"not null;default:false"`
+}
+
+// GridEntry affecte une clock à une heure de la semaine. Weekday suit time.Weekday (0 = dimanche).
+type GridEntry struct {
+	ID        int64 `gorm:"primaryKey;autoIncrement"`
+	Weekday   int   `gorm:"not null;uniqueIndex:idx_grid_entries_weekday_hour"`
+	Hour      int   `gorm:"not null;uniqueIndex:idx_grid_entries_weekday_hour"`
+	ClockID   int64 `gorm:"not null;index"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Clock *Clock `gorm:"foreignKey:ClockID"`
+}
+
+// HourProgram ce qui passe à l'antenne pendant une heure de la grille, Clock est nil si l'heure
+// n'est pas programmée
+type HourProgram struct {
+	StartsAt time.Time
+	Clock    *Clock
+	Items    []ProgramItem
+}
+
+// ProgramItem track programmée, PlaylistID vaut 0 pour les jingles et les tracks tirées par une règle
+type ProgramItem struct {
+	StartsAt        time.Time
+	SlotType        ClockSlotType
+	PlaylistID      int64
+	Track           Track
+	DurationSeconds int
+	Violations      []RotationViolation // Règles de rotation enfreintes par ce passage
+}
+
+// TrackCriteria filtre les tracks du catalogue, un critère nil n'est pas appliqué
+type TrackCriteria struct {
+	ArtistID *int64
+	AlbumID  *int64
+	Limit    int
+}
@@ -0,0 +1,10 @@
+package models
+
+// ConsumerStats compteurs des événements consommés depuis le démarrage du service. Les doublons
+// sont des redélivrances d'événements déjà enregistrés, acquittées sans être traitées à nouveau.
+type ConsumerStats struct {
+	PlayedEvents          int64
+	EndedEvents           int64
+	DuplicatePlayedEvents int64
+	DuplicateEndedEvents  int64
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// CueSheetFormat mise en forme du relevé de diffusion
+type CueSheetFormat string
+
+const (
+	CueSheetCSV        CueSheetFormat = "csv"
+	CueSheetFixedWidth CueSheetFormat = "fixed"
+)
+
+// Métadonnées exigées par les sociétés de gestion collective pour chaque diffusion
+const (
+	CueSheetFieldTitle    = "title"
+	CueSheetFieldArtist   = "artist"
+	CueSheetFieldISRC     = "isrc"
+	CueSheetFieldDuration = "duration"
+)
+
+// CueSheetEntry diffusion d'une track avec les métadonnées du catalogue. PlayedSeconds est nul
+// tant que la fin de la lecture n'est pas connue ; Missing liste les métadonnées exigées absentes.
+type CueSheetEntry struct {
+	PlayedAt        time.Time
+	PlayedSeconds   *int
+	TrackID         int64
+	Title           string
+	Artist          string
+	Album           string
+	ISRC            string
+	DurationSeconds int
+	PlaylistID      int64
+	PlaylistName    string
+	Missing         []string
+}
+
+// IsFlagged indique si la diffusion ne peut pas être déclarée en l'état
+func (e *CueSheetEntry) IsFlagged() bool {
+	return len(e.Missing) > 0
+}
+
+// CueSheet relevé des diffusions d'une période, par ordre chronologique
+type CueSheet struct {
+	Period  StatsPeriod
+	Entries []CueSheetEntry
+	Flagged int
+}
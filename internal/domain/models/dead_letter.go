@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// DeadLetter message écarté après l'échec de son décodage ou de toutes ses tentatives de traitement.
+// Queue est la queue d'origine, où le message est republié s'il est rejoué.
+type DeadLetter struct {
+	MessageID  string
+	Queue      string
+	RoutingKey string
+	Reason     string
+	RetryCount int
+	FailedAt   time.Time
+	Body       []byte
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EventType type d'un événement publié, qui détermine sa clé de routage
+type EventType string
+
+const (
+	EventTrackPlayed     EventType = "track.played"
+	EventTrackEnded      EventType = "track.ended"
+	EventPlaylistCreated EventType = "playlist.created"
+	EventPlaylistUpdated EventType = "playlist.updated"
+	EventPlaylistDeleted EventType = "playlist.deleted"
+	EventPlaylistPlayed  EventType = "playlist.played"
+)
+
+// eventVersions version courante du schéma du payload de chaque type. Un changement incompatible d'un
+// payload incrémente sa version, publie son nouveau schéma et ajoute un upcaster côté consommateur.
+var eventVersions = map[EventType]int{
+	EventTrackPlayed:     1,
+	EventTrackEnded:      1,
+	EventPlaylistCreated: 1,
+	EventPlaylistUpdated: 1,
+	EventPlaylistDeleted: 1,
+	EventPlaylistPlayed:  2,
+}
+
+// EventTypes types des événements publiés par l'application
+func EventTypes() []EventType {
+	return []EventType{EventTrackPlayed, EventTrackEnded, EventPlaylistCreated, EventPlaylistUpdated,
+		EventPlaylistDeleted, EventPlaylistPlayed}
+}
+
+// Version version courante du schéma du payload, 0 pour un type inconnu
+func (t EventType) Version() int {
+	return eventVersions[t]
+}
+
+// Event enveloppe des événements publiés : le type et la version décrivent le payload.
+// Les événements de même PartitionKey, la playlist concernée, sont traités dans leur ordre de publication.
+type Event struct {
+	ID           string          `json:"id"`
+	Type         EventType       `json:"type"`
+	Version      int             `json:"version"`
+	Time         time.Time       `json:"time"`
+	PartitionKey int64           `json:"partition_key,omitempty"`
+	Payload      json.RawMessage `json:"payload"`
+}
+
+func NewEvent(eventType EventType, id string, at time.Time, partitionKey int64, payload any) (Event, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+	return Event{
+		ID:           id,
+		Type:         eventType,
+		Version:      eventType.Version(),
+		Time:         at.UTC(),
+		PartitionKey: partitionKey,
+		Payload:      raw,
+	}, nil
+}
+
+// DecodePayload décode le payload dans target
+func (e Event) DecodePayload(target any) error {
+	if err := json.Unmarshal(e.Payload, target); err != nil {
+		return fmt.Errorf("failed to unmarshal %s event payload: %w", e.Type, err)
+	}
+	return nil
+}
+
+func (e TrackPlayedEvent) Envelope() (Event, error) {
+	return NewEvent(EventTrackPlayed, e.EventID, e.PlayedAt, e.PlaylistID, e)
+}
+
+func (e TrackEndedEvent) Envelope() (Event, error) {
+	return NewEvent(EventTrackEnded, e.EventID, e.EndedAt, e.PlaylistID, e)
+}
+
+// EventSchemaRef schéma publié du payload d'un type d'événement dans une version
+type EventSchemaRef struct {
+	Type    EventType
+	Version int
+	Current bool // Version émise par l'application
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// StoredEvent événement du journal des événements du domaine, ajouté à son émission et jamais modifié.
+// Position donne l'ordre d'émission.
+type StoredEvent struct {
+	Position     int64     `gorm:"primaryKey;autoIncrement"`
+	EventID      string    `gorm:"size:36;not null;uniqueIndex"`
+	EventType    EventType `gorm:"size:50;not null;index"`
+	Version      int       `gorm:"not null"`
+	PartitionKey int64     `gorm:"not null;default:0"`
+	OccurredAt   time.Time `gorm:"not null;index"`
+	Payload      string    `gorm:"type:text;not null"`
+	RecordedAt   time.Time `gorm:"not null"`
+}
+
+func NewStoredEvent(event Event) StoredEvent {
+	return StoredEvent{
+		EventID:      event.ID,
+		EventType:    event.Type,
+		Version:      event.Version,
+		PartitionKey: event.PartitionKey,
+		OccurredAt:   event.Time.UTC(),
+		Payload:      string(event.Payload),
+	}
+}
+
+// Event événement tel qu'émis, dans la version de son payload
+func (e StoredEvent) Event() Event {
+	return Event{
+		ID:           e.EventID,
+		Type:         e.EventType,
+		Version:      e.Version,
+		Time:         e.OccurredAt,
+		PartitionKey: e.PartitionKey,
+		Payload:      json.RawMessage(e.Payload),
+	}
+}
+
+// EventStoreFilter sélection des événements du journal : types parmi Types, survenus dans [From, To).
+// Une borne nulle n'est pas appliquée.
+type EventStoreFilter struct {
+	Types []EventType
+	From  time.Time
+	To    time.Time
+}
+
+// ReplayQuery rejeu des événements survenus entre From et To (AAAA-MM-JJ ou RFC 3339, une date de fin
+// est incluse) dans la projection Projection. En DryRun, les événements sont lus sans être appliqués.
+type ReplayQuery struct {
+	Projection string
+	From       string
+	To         string
+	DryRun     bool
+}
+
+type ReplayResult struct {
+	Projection string
+	DryRun     bool
+	Events     int // Événements lus
+	Applied    int
+	Failed     int
+}
@@ -5,11 +5,58 @@ import "time"
 // TrackPlayedEvent représente l'événement envoyé dans RabbitMQ quand une track est jouée
 // Cette structure est destinée à la sérialisation JSON pour le messaging
 type TrackPlayedEvent struct {
+	PlaylistID      int64       `json:"playlist_id"`
+	TrackID         int64       `json:"track_id"`
+	TrackTitle      string      `json:"track_title"`
+	Artist          string      `json:"artist"`
+	ArtistID        int64       `json:"artist_id"`
+	Position        int         `json:"position"` // Position dans la playlist (0-based)
+	SessionID       int64       `json:"session_id,omitempty"`
+	Sequence        int         `json:"sequence,omitempty"` // Rang de la lecture dans la session
+	DurationSeconds int         `json:"duration_seconds"`
+	Shuffle         ShuffleMode `json:"shuffle,omitempty"`
+	Repeat          RepeatMode  `json:"repeat,omitempty"`
+	Seed            *int64      `json:"seed,omitempty"`
+	PlayedAt        time.Time   `json:"played_at"` // Démarrage effectif de la track
+	EventID         string      `json:"event_id"`  // UUID unique pour l'événement
+}
+
+// TrackEndedEvent représente l'événement envoyé quand la lecture d'une track d'une session se termine,
+// jusqu'au bout ou interrompue (skip, retour, arrêt)
+type TrackEndedEvent struct {
+	SessionID     int64          `json:"session_id"`
+	Sequence      int            `json:"sequence"` // Rang de la lecture dans la session
+	PlaylistID    int64          `json:"playlist_id"`
+	TrackID       int64          `json:"track_id"`
+	Position      int            `json:"position"`
+	PlayedAt      time.Time      `json:"played_at"`
+	EndedAt       time.Time      `json:"ended_at"`
+	PlayedSeconds int            `json:"played_seconds"`
+	Reason        TrackEndReason `json:"reason"`
+	EventID       string         `json:"event_id"`
+}
+
+// PlaylistChangedEvent état d'une playlist après sa création ou sa modification, tracks comprises
+type PlaylistChangedEvent struct {
 	PlaylistID int64     `json:"playlist_id"`
-	TrackID    int64     `json:"track_id"`
-	TrackTitle string    `json:"track_title"`
-	Artist     string    `json:"artist"`
-	Position   int       `json:"position"` // Position dans la playlist (0-based)
-	PlayedAt   time.Time `json:"played_at"`
-	EventID    string    `json:"event_id"` // UUID unique pour l'événement
+	Name       string    `json:"name"`
+	TrackIDs   []int64   `json:"track_ids"` // Tracks du catalogue dans l'ordre de la playlist
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type PlaylistDeletedEvent struct {
+	PlaylistID int64     `json:"playlist_id"`
+	DeletedAt  time.Time `json:"deleted_at"`
+}
+
+// PlaylistPlayedEvent démarrage d'une session de lecture de la playlist
+type PlaylistPlayedEvent struct {
+	PlaylistID  int64       `json:"playlist_id"`
+	SessionID   int64       `json:"session_id"`
+	TracksCount int         `json:"tracks_count"`
+	Shuffle     ShuffleMode `json:"shuffle"`
+	Repeat      RepeatMode  `json:"repeat"`
+	Seed        *int64      `json:"seed,omitempty"`
+	TrackIDs    []int64     `json:"track_ids"` // Tracks de la file dans l'ordre joué
+	PlayedAt    time.Time   `json:"played_at"`
 }
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// CleanName retire les espaces superflus d'un nom saisi librement
+func CleanName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// NormalizeISRC retourne un code ISRC sans tirets ni espaces, en majuscules ("fr-z03-14-00123" devient "FRZ031400123")
+func NormalizeISRC(isrc string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(isrc))
+}
+
+// NormalizeName retourne la clé de comparaison d'un nom d'artiste ou d'album :
+// espaces nettoyés, casse et accents ignorés ("Beyoncé " et "beyonce" sont équivalents)
+func NormalizeName(name string) string {
+	stripAccents := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	normalized, _, err := transform.String(stripAccents, CleanName(name))
+	if err != nil {
+		normalized = CleanName(name)
+	}
+	return strings.ToLower(normalized)
+}
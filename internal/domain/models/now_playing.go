@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// NowPlaying ce qui est à l'antenne pour une session : la track courante et la suivante.
+// Current est nil une fois la session arrêtée ou terminée, Next est nil en fin de file.
+type NowPlaying struct {
+	SessionID      int64
+	PlaylistID     int64
+	ClockID        *int64
+	Status         PlaySessionStatus
+	Current        *PlaySessionItem
+	StartedAt      *time.Time
+	ElapsedSeconds int
+	Next           *PlaySessionItem
+	UpdatedAt      time.Time
+}
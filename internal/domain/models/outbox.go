@@ -0,0 +1,78 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxPending OutboxStatus = "pending"
+	OutboxSent    OutboxStatus = "sent"
+	OutboxFailed  OutboxStatus = "failed" // Message que le broker ne peut pas livrer, il n'est plus retenté
+)
+
+// OutboxMessage événement enregistré dans la même transaction que le changement d'état qui le produit,
+// puis publié par le relais. Un message en échec reste pending jusqu'à NextAttemptAt, un message
+// qu'aucune queue ne reçoit passe failed.
+type OutboxMessage struct {
+	ID            int64        `gorm:"primaryKey;autoIncrement"`
+	EventID       string       `gorm:"size:36;not null;uniqueIndex"`
+	EventType     EventType    `gorm:"size:50;not null"`
+	Payload       string       `gorm:"type:text;not null"` // Enveloppe de l'événement
+	Status        OutboxStatus `gorm:"size:20;not null;index:idx_outbox_status_next_attempt,priority:1"`
+	Attempts      int          `gorm:"not null;default:0"`
+	LastError     string       `gorm:"size:500"`
+	NextAttemptAt time.Time    `gorm:"not null;index:idx_outbox_status_next_attempt,priority:2"`
+	CreatedAt     time.Time
+	SentAt        *time.Time
+}
+
+func NewOutboxMessage(event Event) (OutboxMessage, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return OutboxMessage{}, fmt.Errorf("failed to marshal %s event: %w", event.Type, err)
+	}
+	return OutboxMessage{
+		EventID:   event.ID,
+		EventType: event.Type,
+		Payload:   string(payload),
+		Status:    OutboxPending,
+	}, nil
+}
+
+func NewTrackPlayedOutboxMessage(event TrackPlayedEvent) (OutboxMessage, error) {
+	envelope, err := event.Envelope()
+	if err != nil {
+		return OutboxMessage{}, err
+	}
+	return NewOutboxMessage(envelope)
+}
+
+func NewTrackEndedOutboxMessage(event TrackEndedEvent) (OutboxMessage, error) {
+	envelope, err := event.Envelope()
+	if err != nil {
+		return OutboxMessage{}, err
+	}
+	return NewOutboxMessage(envelope)
+}
+
+// Event enveloppe de l'événement à publier
+func (m OutboxMessage) Event() (Event, error) {
+	var event Event
+	if err := json.Unmarshal([]byte(m.Payload), &event); err != nil {
+		return Event{}, fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+	return event, nil
+}
+
+// OutboxStats état de livraison des événements de l'outbox
+type OutboxStats struct {
+	Pending         int64
+	Sent            int64
+	Failing         int64 // Messages pending dont au moins une publication a échoué
+	Failed          int64
+	OldestPendingAt *time.Time
+}
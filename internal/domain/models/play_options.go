@@ -0,0 +1,26 @@
+package models
+
+type ShuffleMode string
+
+const (
+	ShuffleOff    ShuffleMode = "off"
+	ShuffleRandom ShuffleMode = "random"
+	ShuffleSmart  ShuffleMode = "smart" // Évite d'enchaîner deux tracks du même artiste
+)
+
+type RepeatMode string
+
+const (
+	RepeatOff RepeatMode = "off"
+	RepeatOne RepeatMode = "one"
+	RepeatAll RepeatMode = "all"
+)
+
+// PlayOptions ordre et répétition d'une lecture. Seed rend le mélange reproductible :
+// une même playlist jouée avec le même seed est mélangée dans le même ordre.
+type PlayOptions struct {
+	Shuffle ShuffleMode
+	Repeat  RepeatMode
+	Seed    *int64
+	ClockID *int64 // Renseigné par le scheduler pour les heures programmées
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+type RollupSubject string
+
+const (
+	RollupTrack    RollupSubject = "track"
+	RollupArtist   RollupSubject = "artist"
+	RollupPlaylist RollupSubject = "playlist"
+)
+
+type RollupGranularity string
+
+const (
+	RollupHour RollupGranularity = "hour"
+	RollupDay  RollupGranularity = "day"
+)
+
+// PlayRollup compteurs de lecture pré-agrégés d'une track, d'un artiste ou d'une playlist sur
+// une heure ou un jour UTC commençant à BucketStart. Ils sont tenus à jour avec track_plays.
+type PlayRollup struct {
+	ID            int64             `gorm:"primaryKey;autoIncrement"`
+	Subject       RollupSubject     `gorm:"size:10;not null;uniqueIndex:idx_play_rollups_bucket,priority:1"`
+	Granularity   RollupGranularity `gorm:"size:5;not null;uniqueIndex:idx_play_rollups_bucket,priority:2"`
+	BucketStart   time.Time         `gorm:"not null;uniqueIndex:idx_play_rollups_bucket,priority:3"`
+	SubjectID     int64             `gorm:"not null;uniqueIndex:idx_play_rollups_bucket,priority:4"`
+	Plays         int               `gorm:"not null;default:0"`
+	CompletePlays int               `gorm:"not null;default:0"`
+	PlayedSeconds int               `gorm:"not null;default:0"`
+}
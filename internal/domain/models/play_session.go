@@ -0,0 +1,88 @@
+package models
+
+import "time"
+
+type PlaySessionStatus string
+
+const (
+	PlaySessionPlaying  PlaySessionStatus = "playing"
+	PlaySessionPaused   PlaySessionStatus = "paused"
+	PlaySessionStopped  PlaySessionStatus = "stopped"
+	PlaySessionFinished PlaySessionStatus = "finished"
+)
+
+// PlaySessionItem copie d'une track de la file de lecture, figée et mélangée au lancement de la session
+type PlaySessionItem struct {
+	PlaylistID      int64  `json:"playlist_id,omitempty"` // Playlist d'origine, 0 pour un jingle ou une track tirée par une règle
+	TrackID         int64  `json:"track_id"`
+	Title           string `json:"title"`
+	Artist          string `json:"artist"`
+	ArtistID        int64  `json:"artist_id"`
+	Position        int    `json:"position"`
+	DurationSeconds int    `json:"duration_seconds"`
+}
+
+func (item PlaySessionItem) Duration() time.Duration {
+	return time.Duration(item.DurationSeconds) * time.Second
+}
+
+// SourcePlaylistID playlist d'origine de la track, celle de la session pour les sessions
+// créées avant que les tracks ne portent leur playlist
+func (item PlaySessionItem) SourcePlaylistID(sessionPlaylistID int64) int64 {
+	if item.PlaylistID != 0 {
+		return item.PlaylistID
+	}
+	return sessionPlaylistID
+}
+
+// PlaySession état persisté d'une lecture de playlist, pour la reprendre au redémarrage du service.
+// TrackStartedAt est nil tant que la track courante n'a pas démarré, TrackResumedAt est nil
+// pendant une pause et TrackElapsedMs cumule le temps écoulé avant la dernière pause.
+type PlaySession struct {
+	ID             int64             `gorm:"primaryKey;autoIncrement"`
+	PlaylistID     int64             `gorm:"not null;index"`
+	ClockID        *int64            `gorm:"index"` // Clock de la grille à l'origine d'une session programmée
+	Status         PlaySessionStatus `gorm:"size:20;not null;index"`
+	Items          []PlaySessionItem `gorm:"serializer:json"`
+	Shuffle        ShuffleMode       `gorm:"size:10;not null;default:'off'"`
+	Repeat         RepeatMode        `gorm:"size:10;not null;default:'off'"`
+	Seed           *int64
+	CurrentIndex   int `gorm:"not null;default:0"`
+	Sequence       int `gorm:"not null;default:0"` // Nombre de tracks démarrées
+	TrackStartedAt *time.Time
+	TrackResumedAt *time.Time
+	TrackElapsedMs int64 `gorm:"not null;default:0"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TrackIDs tracks de la file dans l'ordre où elles sont jouées
+func (s *PlaySession) TrackIDs() []int64 {
+	ids := make([]int64, 0, len(s.Items))
+	for _, item := range s.Items {
+		ids = append(ids, item.TrackID)
+	}
+	return ids
+}
+
+// IsActive une session en lecture ou en pause peut encore être pilotée
+func (s *PlaySession) IsActive() bool {
+	return s.Status == PlaySessionPlaying || s.Status == PlaySessionPaused
+}
+
+// CurrentItem retourne la track courante, nil une fois la file terminée
+func (s *PlaySession) CurrentItem() *PlaySessionItem {
+	if s.CurrentIndex < 0 || s.CurrentIndex >= len(s.Items) {
+		return nil
+	}
+	return &s.Items[s.CurrentIndex]
+}
+
+// Elapsed temps écoulé de la track courante, pauses exclues
+func (s *PlaySession) Elapsed(now time.Time) time.Duration {
+	elapsed := time.Duration(s.TrackElapsedMs) * time.Millisecond
+	if s.TrackResumedAt != nil {
+		elapsed += now.Sub(*s.TrackResumedAt)
+	}
+	return elapsed
+}
@@ -2,12 +2,19 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
+// Playlist supprimée logiquement : l'historique de lecture continue de la référencer avec son nom
 type Playlist struct {
-	ID        int64   `gorm:"primaryKey;autoIncrement"`
-	Name      string  `gorm:"size:255;not null"`
-	Tracks    []Track `gorm:"foreignKey:PlaylistID"`
+	ID        int64           `gorm:"primaryKey;autoIncrement"`
+	Name      string          `gorm:"size:255;not null"`
+	Tracks    []PlaylistTrack `gorm:"foreignKey:PlaylistID"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+
+	// TracksCount est calculé au listing, il n'est pas persisté
+	TracksCount int `gorm:"->;-:migration"`
 }
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+const (
+	PlaylistSortCreatedAt = "created_at"
+	PlaylistSortUpdatedAt = "updated_at"
+
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// PlaylistListQuery représente les critères de listing des playlists
+type PlaylistListQuery struct {
+	Limit      int
+	Cursor     string // Curseur opaque retourné par la page précédente
+	Name       string // Filtre sur le nom (contient, insensible à la casse)
+	SortBy     string
+	Order      string
+	WithTracks bool // Précharge les tracks, sinon seul le nombre de tracks est renseigné
+}
+
+// PlaylistCursor position de la dernière playlist d'une page dans le tri demandé
+type PlaylistCursor struct {
+	SortValue time.Time `json:"v"`
+	ID        int64     `json:"id"`
+}
+
+type PlaylistPage struct {
+	Playlists  []*Playlist
+	NextCursor string // Vide s'il n'y a pas de page suivante
+}
+
+type TrackPage struct {
+	Tracks     []*Track
+	NextCursor string // Vide s'il n'y a pas de page suivante
+}
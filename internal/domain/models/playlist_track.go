@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// PlaylistTrack est la table de jointure entre une playlist et une track du catalogue
+type PlaylistTrack struct {
+	ID         int64 `gorm:"primaryKey;autoIncrement"`
+	PlaylistID int64 `gorm:"not null;uniqueIndex:idx_playlist_tracks_playlist_track"`
+	TrackID    int64 `gorm:"not null;uniqueIndex:idx_playlist_tracks_playlist_track;index"`
+	Position   int   `gorm:"not null;default:0"` // Position dans la playlist (0-based)
+	CreatedAt  time.Time
+
+	Track Track `gorm:"foreignKey:TrackID"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+type RotationSubject string
+
+const (
+	RotationArtist RotationSubject = "artist"
+	RotationTrack  RotationSubject = "track"
+)
+
+// RotationRule limite les passages d'un même artiste ou d'une même track : au plus MaxPlays
+// passages sur une fenêtre glissante de WindowMinutes. Avec MaxPlays à 1, la règle impose une
+// séparation, par exemple pas deux fois le même artiste en 30 minutes.
+type RotationRule struct {
+	ID            int64           `gorm:"primaryKey;autoIncrement"`
+	Name          string          `gorm:"size:255;not null"`
+	Subject       RotationSubject `gorm:"size:20;not null"`
+	WindowMinutes int             `gorm:"not null"`
+	MaxPlays      int             `gorm:"not null;default:1"`
+	Enabled       bool            `gorm:"not null"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (r *RotationRule) Window() time.Duration {
+	return time.Duration(r.WindowMinutes) * time.Minute
+}
+
+// RotationViolation règle enfreinte par un passage : Plays passages du même artiste ou de la même
+// track ont déjà eu lieu, ou sont prévus, dans la fenêtre de la règle
+type RotationViolation struct {
+	Rule         RotationRule
+	Plays        int
+	LastPlayedAt time.Time
+}
+
+// RotationReport file de lecture simulée, avec les règles enfreintes par chaque passage.
+// Clock est renseignée pour la simulation d'une heure programmée.
+type RotationReport struct {
+	StartsAt        time.Time
+	Clock           *Clock
+	Items           []ProgramItem
+	ViolationsCount int
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// StatsQuery période demandée pour les statistiques. From et To acceptent une date (AAAA-MM-JJ,
+// To incluse) ou un horodatage RFC 3339 ; les dates et les histogrammes suivent Timezone.
+type StatsQuery struct {
+	From     string
+	To       string
+	Timezone string
+}
+
+// StatsPeriod période résolue des statistiques, From incluse et To exclue
+type StatsPeriod struct {
+	From     time.Time
+	To       time.Time
+	Location *time.Location
+}
+
+// TrackPlayCount nombre de lectures d'une track, CompletePlays compte celles jouées jusqu'au bout
+type TrackPlayCount struct {
+	TrackID       int64
+	Title         string
+	Artist        string
+	Plays         int
+	CompletePlays int
+}
+
+type ArtistPlayCount struct {
+	ArtistID int64
+	Name     string
+	Plays    int
+}
+
+// PlaylistPlayCount totaux de lecture d'une playlist, Name est vide si elle a été supprimée
+type PlaylistPlayCount struct {
+	PlaylistID    int64
+	Name          string
+	Plays         int
+	CompletePlays int
+	PlayedSeconds int
+}
+
+// PlayBucket nombre de lectures de l'heure UTC commençant à Start
+type PlayBucket struct {
+	Start time.Time
+	Plays int
+}
@@ -0,0 +1,24 @@
+package models
+
+type StudioCommandType string
+
+const (
+	StudioPlay   StudioCommandType = "play"
+	StudioPause  StudioCommandType = "pause"
+	StudioResume StudioCommandType = "resume"
+	StudioSkip   StudioCommandType = "skip"
+	StudioBack   StudioCommandType = "back"
+	StudioStop   StudioCommandType = "stop"
+	StudioInsert StudioCommandType = "insert"
+)
+
+// StudioCommand commande d'un opérateur depuis la console du studio.
+// PlaylistID et Options ne servent qu'à play, TrackID qu'à insert, SessionID à toutes les autres.
+type StudioCommand struct {
+	Type       StudioCommandType
+	SessionID  int64
+	PlaylistID int
+	TrackID    int
+	Options    PlayOptions
+	Operator   string
+}
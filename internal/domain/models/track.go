@@ -2,13 +2,25 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
+// Track représente une track du catalogue, partagée entre les playlists.
+// Artist conserve le nom affiché de l'artiste référencé par ArtistID. ISRC identifie
+// l'enregistrement dans les déclarations de diffusion, il est vide s'il n'est pas connu.
+// Une track supprimée ne l'est que logiquement : l'historique de lecture la référence toujours.
 type Track struct {
-	ID         int64  `gorm:"primaryKey;autoIncrement"`
-	PlaylistID int64  `gorm:"index;not null"`
-	Title      string `gorm:"size:255;not null"`
-	Artist     string `gorm:"size:255;not null"`
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID              int64  `gorm:"primaryKey;autoIncrement"`
+	Title           string `gorm:"size:255;not null"`
+	NormalizedTitle string `gorm:"size:255;not null;default:'';index:idx_tracks_artist_title,priority:2"`
+	Artist          string `gorm:"size:255;not null"`
+	ArtistID        int64  `gorm:"not null;default:0;index:idx_tracks_artist_title,priority:1"`
+	DurationSeconds int    `gorm:"not null;default:0"`
+	ISRC            string `gorm:"column:isrc;size:12;not null;default:''"`
+	AlbumID         *int64 `gorm:"index"`
+	Album           *Album `gorm:"foreignKey:AlbumID"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	DeletedAt       gorm.DeletedAt `gorm:"index"`
 }
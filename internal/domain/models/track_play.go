@@ -2,15 +2,39 @@ package models
 
 import "time"
 
+type TrackEndReason string
+
+const (
+	TrackEndCompleted TrackEndReason = "completed"
+	TrackEndSkipped   TrackEndReason = "skipped"
+	TrackEndBack      TrackEndReason = "back"
+	TrackEndStopped   TrackEndReason = "stopped"
+)
+
+// TrackPlay lecture d'une track. Les lectures issues d'une session sont identifiées par
+// SessionID et Sequence ; EndReason distingue les lectures complètes des lectures partielles.
+// EventID et EndEventID identifient les événements de début et de fin déjà enregistrés.
 type TrackPlay struct {
-	ID         int64     `gorm:"primaryKey"`
-	PlaylistID int64     `gorm:"not null;index"`
-	TrackID    int64     `gorm:"not null;index"`
-	Position   int       `gorm:"not null"`
-	PlayedAt   time.Time `gorm:"not null;index"`
-	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	ID            int64     `gorm:"primaryKey"`
+	PlaylistID    int64     `gorm:"not null;index"`
+	TrackID       int64     `gorm:"not null;index"`
+	Position      int       `gorm:"not null"`
+	PlayedAt      time.Time `gorm:"not null;index"`
+	SessionID     *int64    `gorm:"uniqueIndex:idx_track_plays_session_sequence"`
+	Sequence      int       `gorm:"not null;default:0;uniqueIndex:idx_track_plays_session_sequence"`
+	EndedAt       *time.Time
+	PlayedSeconds *int
+	EndReason     TrackEndReason `gorm:"size:20"`
+	EventID       *string        `gorm:"size:36;uniqueIndex"`
+	EndEventID    *string        `gorm:"size:36;uniqueIndex"`
+	CreatedAt     time.Time      `gorm:"autoCreateTime"`
 
 	// Relations
 	Playlist Playlist `gorm:"foreignKey:PlaylistID"`
 	Track    Track    `gorm:"foreignKey:TrackID"`
 }
+
+// IsComplete indique si la track a été jouée jusqu'au bout
+func (p *TrackPlay) IsComplete() bool {
+	return p.EndReason == TrackEndCompleted
+}
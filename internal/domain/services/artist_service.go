@@ -0,0 +1,83 @@
+package services
+
+import (
+	"errors"
+	"radioking-app/internal/domain/constants"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/repositories"
+
+	"gorm.io/gorm"
+)
+
+// ArtistService expose les artistes et albums, créés à la volée avec les tracks du catalogue
+type ArtistService struct {
+	artistRepo repositories.IArtistRepository
+	albumRepo  repositories.IAlbumRepository
+}
+
+func NewArtistService(artistRepo repositories.IArtistRepository, albumRepo repositories.IAlbumRepository) *ArtistService {
+	return &ArtistService{artistRepo: artistRepo, albumRepo: albumRepo}
+}
+
+// SearchArtists recherche les artistes par nom, sans tenir compte de la casse, des accents ni des espaces
+func (s *ArtistService) SearchArtists(name string) ([]*models.Artist, error) {
+	if models.NormalizeName(name) == "" {
+		return nil, domainErrors.ErrEmptyArtistQuery
+	}
+
+	artists, err := s.artistRepo.SearchByName(name, constants.MaxPageSize)
+	if err != nil {
+		return nil, domainErrors.NewInternalError("failed to search artists", err)
+	}
+	return artists, nil
+}
+
+func (s *ArtistService) GetArtist(id int) (*models.ArtistOverview, error) {
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidArtistID
+	}
+
+	artist, err := s.artistRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainErrors.ErrArtistNotFound
+		}
+		return nil, domainErrors.NewInternalError("failed to get artist", err)
+	}
+
+	overview := &models.ArtistOverview{Artist: artist}
+	if overview.Albums, err = s.artistRepo.ListAlbums(artist.ID); err != nil {
+		return nil, domainErrors.NewInternalError("failed to get artist albums", err)
+	}
+	if overview.Tracks, err = s.artistRepo.ListTracks(artist.ID); err != nil {
+		return nil, domainErrors.NewInternalError("failed to get artist tracks", err)
+	}
+	if overview.Playlists, err = s.artistRepo.ListPlaylists(artist.ID); err != nil {
+		return nil, domainErrors.NewInternalError("failed to get artist playlists", err)
+	}
+	return overview, nil
+}
+
+func (s *ArtistService) GetAlbum(id int) (*models.AlbumOverview, error) {
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidAlbumID
+	}
+
+	album, err := s.albumRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainErrors.ErrAlbumNotFound
+		}
+		return nil, domainErrors.NewInternalError("failed to get album", err)
+	}
+
+	overview := &models.AlbumOverview{Album: album}
+	if overview.Tracks, err = s.albumRepo.ListTracks(album.ID); err != nil {
+		return nil, domainErrors.NewInternalError("failed to get album tracks", err)
+	}
+	if overview.Playlists, err = s.albumRepo.ListPlaylists(album.ID); err != nil {
+		return nil, domainErrors.NewInternalError("failed to get album playlists", err)
+	}
+	return overview, nil
+}
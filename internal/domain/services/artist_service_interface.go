@@ -0,0 +1,9 @@
+package services
+
+import "radioking-app/internal/domain/models"
+
+type IArtistService interface {
+	SearchArtists(name string) ([]*models.Artist, error)
+	GetArtist(id int) (*models.ArtistOverview, error)
+	GetAlbum(id int) (*models.AlbumOverview, error)
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"log"
+	"sync"
+)
+
+// subscriberBuffer nombre de mises à jour en attente par abonné avant que les suivantes ne soient perdues
+const subscriberBuffer = 16
+
+// broadcaster diffuse des mises à jour à des abonnés sans jamais bloquer l'émetteur :
+// un abonné trop lent perd les mises à jour qui dépassent sa file d'attente.
+type broadcaster[T any] struct {
+	name string
+
+	mu          sync.Mutex
+	subscribers map[chan T]struct{}
+}
+
+func newBroadcaster[T any](name string) *broadcaster[T] {
+	return &broadcaster[T]{name: name, subscribers: make(map[chan T]struct{})}
+}
+
+// subscribe retourne les mises à jour à venir et la fonction de désabonnement
+func (b *broadcaster[T]) subscribe() (<-chan T, func()) {
+	updates := make(chan T, subscriberBuffer)
+
+	b.mu.Lock()
+	b.subscribers[updates] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[updates]; ok {
+			delete(b.subscribers, updates)
+			close(updates)
+		}
+	}
+	return updates, unsubscribe
+}
+
+func (b *broadcaster[T]) publish(update T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for updates := range b.subscribers {
+		select {
+		case updates <- update:
+		default:
+			log.Printf("%s subscriber is too slow, update dropped", b.name)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"radioking-app/internal/domain/constants"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/repositories"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ClockService gère les clocks et leur programmation dans la grille de la semaine
+type ClockService struct {
+	clockRepo       repositories.IClockRepository
+	gridRepo        repositories.IGridRepository
+	playlistService IPlaylistService
+	trackRepo       repositories.ITrackRepository
+}
+
+func NewClockService(clockRepo repositories.IClockRepository, gridRepo repositories.IGridRepository,
+	playlistService IPlaylistService, trackRepo repositories.ITrackRepository) *ClockService {
+	return &ClockService{
+		clockRepo:       clockRepo,
+		gridRepo:        gridRepo,
+		playlistService: playlistService,
+		trackRepo:       trackRepo,
+	}
+}
+
+func (s *ClockService) CreateClock(clock *models.Clock) error {
+	if err := s.validateClock(clock); err != nil {
+		return err
+	}
+
+	if err := s.clockRepo.Create(clock); err != nil {
+		return domainErrors.NewInternalError("failed to create clock", err)
+	}
+	return nil
+}
+
+func (s *ClockService) ListClocks() ([]*models.Clock, error) {
+	clocks, err := s.clockRepo.List()
+	if err != nil {
+		return nil, domainErrors.NewInternalError("failed to list clocks", err)
+	}
+	return clocks, nil
+}
+
+func (s *ClockService) GetClock(id int) (*models.Clock, error) {
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidClockID
+	}
+
+	clock, err := s.clockRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainErrors.ErrClockNotFound
+		}
+		return nil, domainErrors.NewInternalError("failed to get clock", err)
+	}
+	return clock, nil
+}
+
+func (s *ClockService) UpdateClock(id int, clock *models.Clock) error {
+	existing, err := s.GetClock(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validateClock(clock); err != nil {
+		return err
+	}
+
+	clock.ID = existing.ID
+	clock.CreatedAt = existing.CreatedAt
+	if err := s.clockRepo.Update(clock); err != nil {
+		return domainErrors.NewInternalError("failed to update clock", err)
+	}
+	return nil
+}
+
+// DeleteClock supprime la clock et libère les heures de la grille où elle était programmée
+func (s *ClockService) DeleteClock(id int) error {
+	if id <= 0 {
+		return domainErrors.ErrInvalidClockID
+	}
+
+	if err := s.clockRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domainErrors.ErrClockNotFound
+		}
+		return domainErrors.NewInternalError("failed to delete clock", err)
+	}
+	return nil
+}
+
+func (s *ClockService) GetGrid() ([]*models.GridEntry, error) {
+	entries, err := s.gridRepo.List()
+	if err != nil {
+		return nil, domainErrors.NewInternalError("failed to get grid", err)
+	}
+	return entries, nil
+}
+
+// AssignClock programme la clock à une heure de la semaine, en remplaçant la clock déjà programmée
+func (s *ClockService) AssignClock(weekday int, hour int, clockID int) (*models.GridEntry, error) {
+	if err := validateGridHour(weekday, hour); err != nil {
+		return nil, err
+	}
+
+	clock, err := s.GetClock(clockID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &models.GridEntry{Weekday: weekday, Hour: hour, ClockID: clock.ID}
+	if err := s.gridRepo.Assign(entry); err != nil {
+		return nil, domainErrors.NewInternalError("failed to assign clock", err)
+	}
+	entry.Clock = clock
+	return entry, nil
+}
+
+func (s *ClockService) UnassignClock(weekday int, hour int) error {
+	if err := validateGridHour(weekday, hour); err != nil {
+		return err
+	}
+
+	if err := s.gridRepo.Delete(weekday, hour); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domainErrors.ErrGridHourNotFound
+		}
+		return domainErrors.NewInternalError("failed to unassign clock", err)
+	}
+	return nil
+}
+
+func validateGridHour(weekday int, hour int) error {
+	if weekday < 0 || weekday > 6 || hour < 0 || hour > 23 {
+		return domainErrors.ErrInvalidGridHour
+	}
+	return nil
+}
+
+func (s *ClockService) validateClock(clock *models.Clock) error {
+	clock.Name = strings.TrimSpace(clock.Name)
+	if clock.Name == "" {
+		return domainErrors.ErrEmptyClockName
+	}
+	if len(clock.Name) > constants.MaxClockNameLength {
+		return domainErrors.NewValidationError("clock name too long")
+	}
+	if len(clock.Slots) > constants.MaxSlotsPerClock {
+		return domainErrors.ErrTooManyClockSlots
+	}
+
+	for i := range clock.Slots {
+		if err := s.validateSlot(&clock.Slots[i]); err != nil {
+			var businessErr *domainErrors.BusinessError
+			if errors.As(err, &businessErr) && !businessErr.IsValidation() && !businessErr.IsNotFound() {
+				return err
+			}
+			return domainErrors.NewValidationError(fmt.Sprintf("slot %d: %s", i, err.Error()))
+		}
+	}
+	return nil
+}
+
+// validateSlot vérifie que le slot référence une playlist ou une track existante.
+// Les références qui ne concernent pas le type du slot sont ignorées.
+func (s *ClockService) validateSlot(slot *models.ClockSlot) error {
+	if slot.DurationSeconds < 0 || slot.DurationSeconds > constants.MaxSlotDurationSeconds {
+		return errors.New("invalid slot duration")
+	}
+
+	switch slot.Type {
+	case models.ClockSlotPlaylist:
+		slot.TrackID, slot.ArtistID, slot.AlbumID = nil, nil, nil
+		if slot.PlaylistID == nil {
+			return errors.New("playlist slot requires a playlist")
+		}
+		if _, err := s.playlistService.GetPlaylist(int(*slot.PlaylistID)); err != nil {
+			return err
+		}
+	case models.ClockSlotJingle:
+		slot.PlaylistID, slot.ArtistID, slot.AlbumID = nil, nil, nil
+		slot.DurationSeconds, slot.Shuffle = 0, false
+		if slot.TrackID == nil {
+			return errors.New("jingle slot requires a track")
+		}
+		if _, err := s.trackRepo.GetByID(int(*slot.TrackID)); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domainErrors.ErrTrackNotFound
+			}
+			return domainErrors.NewInternalError("failed to get track", err)
+		}
+	case models.ClockSlotRule:
+		slot.PlaylistID, slot.TrackID = nil, nil
+		slot.Shuffle = true
+		if slot.DurationSeconds == 0 {
+			return errors.New("rule slot requires a duration")
+		}
+	default:
+		return errors.New("unknown slot type")
+	}
+	return nil
+}
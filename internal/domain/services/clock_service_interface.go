@@ -0,0 +1,14 @@
+package services
+
+import "radioking-app/internal/domain/models"
+
+type IClockService interface {
+	CreateClock(clock *models.Clock) error
+	ListClocks() ([]*models.Clock, error)
+	GetClock(id int) (*models.Clock, error)
+	UpdateClock(id int, clock *models.Clock) error
+	DeleteClock(id int) error
+	GetGrid() ([]*models.GridEntry, error)
+	AssignClock(weekday int, hour int, clockID int) (*models.GridEntry, error)
+	UnassignClock(weekday int, hour int) error
+}
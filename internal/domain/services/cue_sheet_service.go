@@ -0,0 +1,67 @@
+package services
+
+import (
+	"io"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/repositories"
+	"strings"
+)
+
+// CueSheetService produit le relevé des diffusions à déclarer aux sociétés de gestion collective
+type CueSheetService struct {
+	repo repositories.ICueSheetRepository
+}
+
+func NewCueSheetService(repo repositories.ICueSheetRepository) *CueSheetService {
+	return &CueSheetService{repo: repo}
+}
+
+// CueSheet relevé des diffusions de la période, les horaires sont exprimés dans son fuseau.
+// Les diffusions dont le titre, l'artiste, l'ISRC ou la durée manquent sont signalées.
+func (s *CueSheetService) CueSheet(period models.StatsPeriod) (*models.CueSheet, error) {
+	entries, err := s.repo.Entries(period.From, period.To)
+	if err != nil {
+		return nil, domainErrors.NewInternalError("failed to get cue sheet entries", err)
+	}
+
+	sheet := &models.CueSheet{Period: period, Entries: entries}
+	for i := range sheet.Entries {
+		entry := &sheet.Entries[i]
+		entry.PlayedAt = entry.PlayedAt.In(period.Location)
+		entry.Missing = missingMetadata(entry)
+		if entry.IsFlagged() {
+			sheet.Flagged++
+		}
+	}
+	return sheet, nil
+}
+
+// Write écrit le relevé dans le format demandé
+func (s *CueSheetService) Write(w io.Writer, sheet *models.CueSheet, format models.CueSheetFormat) error {
+	switch format {
+	case models.CueSheetCSV:
+		return writeCueSheetCSV(w, sheet)
+	case models.CueSheetFixedWidth:
+		return writeCueSheetFixedWidth(w, sheet)
+	default:
+		return domainErrors.ErrInvalidCueSheetFormat
+	}
+}
+
+func missingMetadata(entry *models.CueSheetEntry) []string {
+	var missing []string
+	if strings.TrimSpace(entry.Title) == "" {
+		missing = append(missing, models.CueSheetFieldTitle)
+	}
+	if strings.TrimSpace(entry.Artist) == "" {
+		missing = append(missing, models.CueSheetFieldArtist)
+	}
+	if entry.ISRC == "" {
+		missing = append(missing, models.CueSheetFieldISRC)
+	}
+	if entry.DurationSeconds <= 0 {
+		missing = append(missing, models.CueSheetFieldDuration)
+	}
+	return missing
+}
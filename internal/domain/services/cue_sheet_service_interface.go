@@ -0,0 +1,11 @@
+package services
+
+import (
+	"io"
+	"radioking-app/internal/domain/models"
+)
+
+type ICueSheetService interface {
+	CueSheet(period models.StatsPeriod) (*models.CueSheet, error)
+	Write(w io.Writer, sheet *models.CueSheet, format models.CueSheetFormat) error
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCueSheetRepository is a mock implementation of ICueSheetRepository
+type MockCueSheetRepository struct {
+	mock.Mock
+}
+
+func (m *MockCueSheetRepository) Entries(from time.Time, to time.Time) ([]models.CueSheetEntry, error) {
+	args := m.Called(from, to)
+	return args.Get(0).([]models.CueSheetEntry), args.Error(1)
+}
+
+func cueSheetPeriod(t *testing.T) models.StatsPeriod {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	return models.StatsPeriod{
+		From:     time.Date(2024, 1, 15, 0, 0, 0, 0, paris),
+		To:       time.Date(2024, 1, 16, 0, 0, 0, 0, paris),
+		Location: paris,
+	}
+}
+
+func cueSheetEntries() []models.CueSheetEntry {
+	playedSeconds := 354
+	return []models.CueSheetEntry{
+		{
+			PlayedAt: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), PlayedSeconds: &playedSeconds, TrackID: 1,
+			Title: "Bohemian Rhapsody", Artist: "Queen", Album: "A Night at the Opera", ISRC: "GBUM71029604",
+			DurationSeconds: 354, PlaylistID: 1, PlaylistName: "Matin",
+		},
+		{
+			PlayedAt: time.Date(2024, 1, 15, 9, 6, 0, 0, time.UTC), TrackID: 2,
+			Title: "Jingle, \"station\"", Artist: "RadioKing", PlaylistID: 1, PlaylistName: "Matin",
+		},
+	}
+}
+
+func TestCueSheetService_CueSheet(t *testing.T) {
+	// Arrange
+	period := cueSheetPeriod(t)
+	repo := new(MockCueSheetRepository)
+	repo.On("Entries", period.From, period.To).Return(cueSheetEntries(), nil)
+	service := NewCueSheetService(repo)
+
+	// Act
+	sheet, err := service.CueSheet(period)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, sheet.Entries, 2)
+	assert.Equal(t, 10, sheet.Entries[0].PlayedAt.Hour())
+	assert.Empty(t, sheet.Entries[0].Missing)
+	assert.Equal(t, []string{models.CueSheetFieldISRC, models.CueSheetFieldDuration}, sheet.Entries[1].Missing)
+	assert.Equal(t, 1, sheet.Flagged)
+	repo.AssertExpectations(t)
+}
+
+func TestCueSheetService_CueSheetRepositoryError(t *testing.T) {
+	// Arrange
+	period := cueSheetPeriod(t)
+	repo := new(MockCueSheetRepository)
+	repo.On("Entries", period.From, period.To).Return([]models.CueSheetEntry(nil), errors.New("database error"))
+	service := NewCueSheetService(repo)
+
+	// Act
+	sheet, err := service.CueSheet(period)
+
+	// Assert
+	assert.Nil(t, sheet)
+	var businessErr *domainErrors.BusinessError
+	assert.ErrorAs(t, err, &businessErr)
+}
+
+func TestCueSheetService_WriteCSV(t *testing.T) {
+	// Arrange
+	period := cueSheetPeriod(t)
+	repo := new(MockCueSheetRepository)
+	repo.On("Entries", period.From, period.To).Return(cueSheetEntries(), nil)
+	service := NewCueSheetService(repo)
+	sheet, err := service.CueSheet(period)
+	require.NoError(t, err)
+
+	// Act
+	var out bytes.Buffer
+	err = service.Write(&out, sheet, models.CueSheetCSV)
+
+	// Assert
+	require.NoError(t, err)
+	records, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, cueSheetCSVHeader, records[0])
+	assert.Equal(t, []string{"2024-01-15T10:00:00+01:00", "Bohemian Rhapsody", "Queen", "A Night at the Opera", "GBUM71029604",
+		"354", "354", "1", "Matin", ""}, records[1])
+	assert.Equal(t, []string{"2024-01-15T10:06:00+01:00", "Jingle, \"station\"", "RadioKing", "", "",
+		"0", "", "1", "Matin", "isrc;duration"}, records[2])
+}
+
+func TestCueSheetService_WriteFixedWidth(t *testing.T) {
+	// Arrange
+	period := cueSheetPeriod(t)
+	repo := new(MockCueSheetRepository)
+	repo.On("Entries", period.From, period.To).Return(cueSheetEntries(), nil)
+	service := NewCueSheetService(repo)
+	sheet, err := service.CueSheet(period)
+	require.NoError(t, err)
+
+	// Act
+	var out bytes.Buffer
+	err = service.Write(&out, sheet, models.CueSheetFixedWidth)
+
+	// Assert
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "H2024011500000020240116000000000002", lines[0])
+	assert.Len(t, lines[1], 1+14+12+40+40+6+6+1)
+	assert.Len(t, lines[2], len(lines[1]))
+	assert.True(t, strings.HasPrefix(lines[1], "D20240115100000GBUM71029604Bohemian Rhapsody"))
+	assert.True(t, strings.HasSuffix(lines[1], "000354000354 "))
+	assert.True(t, strings.HasSuffix(lines[2], "000000      M"))
+	assert.Equal(t, "T000002000001", lines[3])
+}
+
+func TestCueSheetService_WriteFixedWidthTransliteratesNonASCII(t *testing.T) {
+	// Arrange
+	period := cueSheetPeriod(t)
+	sheet := &models.CueSheet{Period: period, Entries: []models.CueSheetEntry{{
+		PlayedAt: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), Title: "Sœur Déjà-vu ♪", Artist: "Beyoncé\tKnowles",
+		ISRC: "FRZ031400123", DurationSeconds: 200,
+	}}}
+	service := NewCueSheetService(new(MockCueSheetRepository))
+
+	// Act
+	var out bytes.Buffer
+	err := service.Write(&out, sheet, models.CueSheetFixedWidth)
+
+	// Assert
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.Len(t, lines[1], 1+14+12+40+40+6+6+1, "la largeur est comptée en octets")
+	assert.Equal(t, "Soeur Deja-vu ?"+strings.Repeat(" ", 25), lines[1][27:67])
+	assert.Equal(t, "Beyonce Knowles"+strings.Repeat(" ", 25), lines[1][67:107])
+}
+
+func TestCueSheetService_WriteUnknownFormat(t *testing.T) {
+	// Arrange
+	service := NewCueSheetService(new(MockCueSheetRepository))
+
+	// Act
+	err := service.Write(&bytes.Buffer{}, &models.CueSheet{}, "pdf")
+
+	// Assert
+	assert.ErrorIs(t, err, domainErrors.ErrInvalidCueSheetFormat)
+}
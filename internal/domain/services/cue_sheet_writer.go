@@ -0,0 +1,135 @@
+package services
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"radioking-app/internal/domain/models"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+const fixedWidthTimeLayout = "20060102150405"
+
+// Colonnes texte du format à largeur fixe, en octets ASCII
+const (
+	fixedWidthISRC   = 12
+	fixedWidthTitle  = 40
+	fixedWidthArtist = 40
+)
+
+var cueSheetCSVHeader = []string{
+	"played_at", "title", "artist", "album", "isrc", "duration_seconds", "played_seconds", "playlist_id", "playlist", "missing",
+}
+
+// writeCueSheetCSV une ligne par diffusion, missing liste les métadonnées absentes séparées par ";"
+func writeCueSheetCSV(w io.Writer, sheet *models.CueSheet) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(cueSheetCSVHeader); err != nil {
+		return err
+	}
+
+	for _, entry := range sheet.Entries {
+		playedSeconds := ""
+		if entry.PlayedSeconds != nil {
+			playedSeconds = strconv.Itoa(*entry.PlayedSeconds)
+		}
+		record := []string{
+			entry.PlayedAt.Format(time.RFC3339),
+			entry.Title,
+			entry.Artist,
+			entry.Album,
+			entry.ISRC,
+			strconv.Itoa(entry.DurationSeconds),
+			playedSeconds,
+			strconv.FormatInt(entry.PlaylistID, 10),
+			entry.PlaylistName,
+			strings.Join(entry.Missing, ";"),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeCueSheetFixedWidth format texte à largeur fixe, une ligne par enregistrement :
+//
+//	H  début (14, AAAAMMJJHHMMSS)  fin (14)  nombre de diffusions (6)
+//	D  diffusée à (14)  ISRC (12)  titre (40)  artiste (40)  durée (6)  secondes jouées (6)  signalement (1)
+//	T  nombre de diffusions (6)  diffusions signalées (6)
+//
+// Les textes sont translittérés en ASCII puis tronqués ou complétés d'espaces, les nombres complétés de zéros. Les secondes
+// jouées sont laissées en blanc si elles ne sont pas connues ; une diffusion signalée porte "M".
+func writeCueSheetFixedWidth(w io.Writer, sheet *models.CueSheet) error {
+	writer := bufio.NewWriter(w)
+	fmt.Fprintf(writer, "H%s%s%06d\n",
+		sheet.Period.From.In(sheet.Period.Location).Format(fixedWidthTimeLayout),
+		sheet.Period.To.In(sheet.Period.Location).Format(fixedWidthTimeLayout),
+		len(sheet.Entries))
+
+	for _, entry := range sheet.Entries {
+		playedSeconds := strings.Repeat(" ", 6)
+		if entry.PlayedSeconds != nil {
+			playedSeconds = fmt.Sprintf("%06d", *entry.PlayedSeconds)
+		}
+		flag := " "
+		if entry.IsFlagged() {
+			flag = "M"
+		}
+		fmt.Fprintf(writer, "D%s%s%s%s%06d%s%s\n",
+			entry.PlayedAt.Format(fixedWidthTimeLayout),
+			fixedWidthText(entry.ISRC, fixedWidthISRC),
+			fixedWidthText(entry.Title, fixedWidthTitle),
+			fixedWidthText(entry.Artist, fixedWidthArtist),
+			entry.DurationSeconds,
+			playedSeconds,
+			flag)
+	}
+
+	fmt.Fprintf(writer, "T%06d%06d\n", len(sheet.Entries), sheet.Flagged)
+	return writer.Flush()
+}
+
+// fixedWidthText ajuste value à width octets ASCII : les accents sont retirés, les ligatures
+// décomposées, les autres caractères hors ASCII remplacés par "?" et les caractères de contrôle
+// par des espaces
+func fixedWidthText(value string, width int) string {
+	text := asciiText(value)
+	if len(text) > width {
+		text = text[:width]
+	}
+	return text + strings.Repeat(" ", width-len(text))
+}
+
+// asciiLigatures lettres sans décomposition Unicode et leur équivalent ASCII
+var asciiLigatures = strings.NewReplacer(
+	"Æ", "AE", "æ", "ae", "Œ", "OE", "œ", "oe", "ß", "ss", "Ø", "O", "ø", "o", "Đ", "D", "đ", "d", "Ł", "L", "ł", "l",
+)
+
+// asciiText translittère value en ASCII
+func asciiText(value string) string {
+	stripAccents := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	stripped, _, err := transform.String(stripAccents, value)
+	if err != nil {
+		stripped = value
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsControl(r):
+			return ' '
+		case r > unicode.MaxASCII:
+			return '?'
+		}
+		return r
+	}, asciiLigatures.Replace(stripped))
+}
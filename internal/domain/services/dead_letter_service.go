@@ -0,0 +1,50 @@
+package services
+
+import (
+	"radioking-app/internal/domain/constants"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/messaging"
+)
+
+// DeadLetterService administre les messages écartés par le consommateur
+type DeadLetterService struct {
+	queue messaging.DeadLetterQueue
+}
+
+func NewDeadLetterService(queue messaging.DeadLetterQueue) *DeadLetterService {
+	return &DeadLetterService{queue: queue}
+}
+
+// List retourne les premiers messages écartés sans les retirer de la queue
+func (s *DeadLetterService) List(limit int) ([]models.DeadLetter, error) {
+	if limit == 0 {
+		limit = constants.DefaultDeadLetterLimit
+	}
+	if limit < 0 || limit > constants.MaxDeadLetterLimit {
+		return nil, domainErrors.ErrInvalidDeadLetterLimit
+	}
+
+	letters, err := s.queue.Peek(limit)
+	if err != nil {
+		return nil, domainErrors.NewInternalError("failed to list dead letters", err)
+	}
+	return letters, nil
+}
+
+// Replay renvoie les messages d'identifiants ids, ou tous les messages écartés, dans leur queue d'origine
+func (s *DeadLetterService) Replay(ids []string) (int, error) {
+	replayed, err := s.queue.Replay(ids)
+	if err != nil {
+		return replayed, domainErrors.NewInternalError("failed to replay dead letters", err)
+	}
+	return replayed, nil
+}
+
+func (s *DeadLetterService) Purge() (int, error) {
+	purged, err := s.queue.Purge()
+	if err != nil {
+		return 0, domainErrors.NewInternalError("failed to purge dead letters", err)
+	}
+	return purged, nil
+}
@@ -0,0 +1,9 @@
+package services
+
+import "radioking-app/internal/domain/models"
+
+type IDeadLetterService interface {
+	List(limit int) ([]models.DeadLetter, error)
+	Replay(ids []string) (int, error)
+	Purge() (int, error)
+}
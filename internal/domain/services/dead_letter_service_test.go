@@ -0,0 +1,106 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"radioking-app/internal/domain/constants"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDeadLetterQueue is a mock implementation of messaging.DeadLetterQueue
+type MockDeadLetterQueue struct {
+	mock.Mock
+}
+
+func (m *MockDeadLetterQueue) Peek(limit int) ([]models.DeadLetter, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]models.DeadLetter), args.Error(1)
+}
+
+func (m *MockDeadLetterQueue) Replay(ids []string) (int, error) {
+	args := m.Called(ids)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDeadLetterQueue) Purge() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDeadLetterQueue) Close() error {
+	return nil
+}
+
+func TestDeadLetterService_List(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int
+		mockFn    func(*MockDeadLetterQueue)
+		wantCount int
+		wantErr   error
+	}{
+		{
+			name:  "default limit",
+			limit: 0,
+			mockFn: func(queue *MockDeadLetterQueue) {
+				queue.On("Peek", constants.DefaultDeadLetterLimit).Return([]models.DeadLetter{{MessageID: "1"}, {MessageID: "2"}}, nil)
+			},
+			wantCount: 2,
+		},
+		{
+			name:    "limit too high",
+			limit:   constants.MaxDeadLetterLimit + 1,
+			mockFn:  func(queue *MockDeadLetterQueue) {},
+			wantErr: domainErrors.ErrInvalidDeadLetterLimit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			queue := new(MockDeadLetterQueue)
+			tt.mockFn(queue)
+			service := NewDeadLetterService(queue)
+
+			// Act
+			letters, err := service.List(tt.limit)
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, letters, tt.wantCount)
+			}
+			queue.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeadLetterService_ReplayAndPurge(t *testing.T) {
+	// Arrange
+	queue := new(MockDeadLetterQueue)
+	queue.On("Replay", []string{"1"}).Return(1, nil)
+	queue.On("Replay", []string(nil)).Return(0, errors.New("channel closed"))
+	queue.On("Purge").Return(3, nil)
+	service := NewDeadLetterService(queue)
+
+	// Act
+	replayed, replayErr := service.Replay([]string{"1"})
+	_, failedErr := service.Replay(nil)
+	purged, purgeErr := service.Purge()
+
+	// Assert
+	assert.NoError(t, replayErr)
+	assert.Equal(t, 1, replayed)
+	var businessErr *domainErrors.BusinessError
+	assert.ErrorAs(t, failedErr, &businessErr)
+	assert.NoError(t, purgeErr)
+	assert.Equal(t, 3, purged)
+	queue.AssertExpectations(t)
+}
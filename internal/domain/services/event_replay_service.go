@@ -0,0 +1,94 @@
+package services
+
+import (
+	"log"
+	"radioking-app/internal/domain/constants"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/messaging"
+	"radioking-app/internal/infrastructure/repositories"
+	"sort"
+	"time"
+)
+
+// EventReplayService rejoue le journal des événements dans les projections
+type EventReplayService struct {
+	store       repositories.IEventStoreRepository
+	projections map[string]Projection
+}
+
+func NewEventReplayService(store repositories.IEventStoreRepository, projections ...Projection) *EventReplayService {
+	service := &EventReplayService{store: store, projections: make(map[string]Projection, len(projections))}
+	for _, projection := range projections {
+		service.projections[projection.Name()] = projection
+	}
+	return service
+}
+
+// Projections noms des projections qui peuvent être reconstruites
+func (s *EventReplayService) Projections() []string {
+	names := make([]string, 0, len(s.projections))
+	for name := range s.projections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Replay applique à la projection ses événements de la période, convertis dans la version courante de
+// leur payload, dans leur ordre d'enregistrement. Un événement en échec est journalisé et compté sans
+// interrompre le rejeu. En dry-run, les événements sont lus et convertis sans être appliqués.
+func (s *EventReplayService) Replay(query models.ReplayQuery) (models.ReplayResult, error) {
+	projection, ok := s.projections[query.Projection]
+	if !ok {
+		return models.ReplayResult{}, domainErrors.ErrUnknownProjection
+	}
+
+	filter := models.EventStoreFilter{Types: projection.EventTypes()}
+	var err error
+	if query.From != "" {
+		if filter.From, err = parseStatsBound(query.From, time.UTC, false); err != nil {
+			return models.ReplayResult{}, err
+		}
+	}
+	if query.To != "" {
+		if filter.To, err = parseStatsBound(query.To, time.UTC, true); err != nil {
+			return models.ReplayResult{}, err
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return models.ReplayResult{}, domainErrors.ErrInvalidReplayRange
+	}
+
+	result := models.ReplayResult{Projection: projection.Name(), DryRun: query.DryRun}
+	var position int64
+	for {
+		events, err := s.store.ListAfter(filter, position, constants.EventReplayBatchSize)
+		if err != nil {
+			return result, domainErrors.NewInternalError("failed to read event store", err)
+		}
+
+		for _, stored := range events {
+			position = stored.Position
+			result.Events++
+
+			event, err := messaging.Upcast(stored.Event())
+			if err == nil && !query.DryRun {
+				err = projection.Apply(event)
+			}
+			if err != nil {
+				result.Failed++
+				log.Printf("Failed to replay %s event %s (position %d) into %s: %v",
+					stored.EventType, stored.EventID, stored.Position, projection.Name(), err)
+				continue
+			}
+			if !query.DryRun {
+				result.Applied++
+			}
+		}
+
+		if len(events) < constants.EventReplayBatchSize {
+			return result, nil
+		}
+	}
+}
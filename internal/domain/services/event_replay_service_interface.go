@@ -0,0 +1,16 @@
+package services
+
+import "radioking-app/internal/domain/models"
+
+// Projection état dérivé des événements du domaine, reconstruit en lui rejouant le journal des événements.
+// Apply doit accepter un événement déjà appliqué.
+type Projection interface {
+	Name() string
+	EventTypes() []models.EventType
+	Apply(event models.Event) error
+}
+
+type IEventReplayService interface {
+	Projections() []string
+	Replay(query models.ReplayQuery) (models.ReplayResult, error)
+}
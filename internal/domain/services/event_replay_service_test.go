@@ -0,0 +1,158 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"radioking-app/internal/domain/constants"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockEventStoreRepository is a mock implementation of repositories.IEventStoreRepository
+type MockEventStoreRepository struct {
+	mock.Mock
+}
+
+func (m *MockEventStoreRepository) ListAfter(filter models.EventStoreFilter, position int64, limit int) ([]*models.StoredEvent, error) {
+	args := m.Called(filter, position, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.StoredEvent), args.Error(1)
+}
+
+// recordingProjection enregistre les événements appliqués, et échoue sur ceux de failing
+type recordingProjection struct {
+	applied []models.Event
+	failing map[string]bool
+}
+
+func (p *recordingProjection) Name() string {
+	return "plays"
+}
+
+func (p *recordingProjection) EventTypes() []models.EventType {
+	return []models.EventType{models.EventTrackPlayed}
+}
+
+func (p *recordingProjection) Apply(event models.Event) error {
+	if p.failing[event.ID] {
+		return errors.New("database locked")
+	}
+	p.applied = append(p.applied, event)
+	return nil
+}
+
+func newStoredTrackPlayed(position int64, id string, version int) *models.StoredEvent {
+	return &models.StoredEvent{
+		Position:     position,
+		EventID:      id,
+		EventType:    models.EventTrackPlayed,
+		Version:      version,
+		PartitionKey: 1,
+		OccurredAt:   time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+		Payload:      `{"playlist_id":1,"event_id":"` + id + `"}`,
+	}
+}
+
+func TestEventReplayService_ReplaysInOrder(t *testing.T) {
+	// Arrange : un événement antérieur à l'enveloppe, version 0, et un événement en échec
+	store := new(MockEventStoreRepository)
+	filter := models.EventStoreFilter{
+		Types: []models.EventType{models.EventTrackPlayed},
+		From:  time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:    time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC),
+	}
+	store.On("ListAfter", filter, int64(0), constants.EventReplayBatchSize).Return([]*models.StoredEvent{
+		newStoredTrackPlayed(3, "legacy", 0),
+		newStoredTrackPlayed(7, "failing", 1),
+		newStoredTrackPlayed(9, "current", 1),
+	}, nil)
+	projection := &recordingProjection{failing: map[string]bool{"failing": true}}
+	service := NewEventReplayService(store, projection)
+
+	// Act
+	result, err := service.Replay(models.ReplayQuery{Projection: "plays", From: "2025-03-01", To: "2025-03-01"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, models.ReplayResult{Projection: "plays", Events: 3, Applied: 2, Failed: 1}, result)
+	require.Len(t, projection.applied, 2)
+	assert.Equal(t, "legacy", projection.applied[0].ID)
+	assert.Equal(t, 1, projection.applied[0].Version)
+	assert.Equal(t, "current", projection.applied[1].ID)
+	store.AssertExpectations(t)
+}
+
+func TestEventReplayService_DryRunAppliesNothing(t *testing.T) {
+	// Arrange
+	store := new(MockEventStoreRepository)
+	store.On("ListAfter", mock.Anything, int64(0), constants.EventReplayBatchSize).Return([]*models.StoredEvent{
+		newStoredTrackPlayed(1, "current", 1),
+		newStoredTrackPlayed(2, "future", 9),
+	}, nil)
+	projection := &recordingProjection{}
+	service := NewEventReplayService(store, projection)
+
+	// Act
+	result, err := service.Replay(models.ReplayQuery{Projection: "plays", DryRun: true})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, models.ReplayResult{Projection: "plays", DryRun: true, Events: 2, Failed: 1}, result)
+	assert.Empty(t, projection.applied)
+}
+
+func TestEventReplayService_ReadsStoreInBatches(t *testing.T) {
+	// Arrange
+	store := new(MockEventStoreRepository)
+	batch := make([]*models.StoredEvent, 0, constants.EventReplayBatchSize)
+	for i := 1; i <= constants.EventReplayBatchSize; i++ {
+		batch = append(batch, newStoredTrackPlayed(int64(i), "evt", 1))
+	}
+	store.On("ListAfter", mock.Anything, int64(0), constants.EventReplayBatchSize).Return(batch, nil)
+	store.On("ListAfter", mock.Anything, int64(constants.EventReplayBatchSize), constants.EventReplayBatchSize).
+		Return([]*models.StoredEvent{}, nil)
+	service := NewEventReplayService(store, &recordingProjection{})
+
+	// Act
+	result, err := service.Replay(models.ReplayQuery{Projection: "plays"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, constants.EventReplayBatchSize, result.Applied)
+	store.AssertExpectations(t)
+}
+
+func TestEventReplayService_InvalidQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   models.ReplayQuery
+		wantErr error
+	}{
+		{name: "unknown projection", query: models.ReplayQuery{Projection: "unknown"}, wantErr: domainErrors.ErrUnknownProjection},
+		{name: "invalid date", query: models.ReplayQuery{Projection: "plays", From: "yesterday"}, wantErr: domainErrors.ErrInvalidDate},
+		{name: "empty range", query: models.ReplayQuery{Projection: "plays", From: "2025-03-02", To: "2025-03-01"}, wantErr: domainErrors.ErrInvalidReplayRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := new(MockEventStoreRepository)
+			service := NewEventReplayService(store, &recordingProjection{})
+
+			// Act
+			_, err := service.Replay(tt.query)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+			store.AssertNotCalled(t, "ListAfter", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"errors"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/messaging"
+)
+
+// EventSchemaService publie les schémas JSON des payloads des événements
+type EventSchemaService struct{}
+
+func NewEventSchemaService() *EventSchemaService {
+	return &EventSchemaService{}
+}
+
+func (s *EventSchemaService) List() ([]models.EventSchemaRef, error) {
+	refs, err := messaging.EventSchemas()
+	if err != nil {
+		return nil, domainErrors.NewInternalError("failed to list event schemas", err)
+	}
+	return refs, nil
+}
+
+// Get retourne le schéma du payload des événements de type eventType dans version
+func (s *EventSchemaService) Get(eventType models.EventType, version int) ([]byte, error) {
+	schema, err := messaging.EventSchema(eventType, version)
+	if errors.Is(err, messaging.ErrSchemaNotFound) {
+		return nil, domainErrors.ErrEventSchemaNotFound
+	}
+	if err != nil {
+		return nil, domainErrors.NewInternalError("failed to read event schema", err)
+	}
+	return schema, nil
+}
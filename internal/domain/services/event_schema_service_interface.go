@@ -0,0 +1,8 @@
+package services
+
+import "radioking-app/internal/domain/models"
+
+type IEventSchemaService interface {
+	List() ([]models.EventSchemaRef, error)
+	Get(eventType models.EventType, version int) ([]byte, error)
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"log"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/repositories"
+	"time"
+)
+
+// NowPlayingService indique ce qui est à l'antenne et diffuse les changements de track aux abonnés
+// au fil des événements consommés. L'antenne joue l'heure programmée en cours, à défaut la dernière
+// session démarrée.
+type NowPlayingService struct {
+	engine       IPlaybackEngine
+	sessionsRepo repositories.IPlaySessionRepository
+	clock        clock
+	updates      *broadcaster[models.NowPlaying]
+}
+
+func NewNowPlayingService(engine IPlaybackEngine, sessionsRepo repositories.IPlaySessionRepository) *NowPlayingService {
+	return &NowPlayingService{
+		engine:       engine,
+		sessionsRepo: sessionsRepo,
+		clock:        realClock{},
+		updates:      newBroadcaster[models.NowPlaying]("Now playing"),
+	}
+}
+
+// GetNowPlaying retourne la track courante et la suivante de la session, ou de l'antenne si sessionID vaut 0
+func (s *NowPlayingService) GetNowPlaying(sessionID int64) (*models.NowPlaying, error) {
+	if sessionID < 0 {
+		return nil, domainErrors.ErrInvalidPlaySessionID
+	}
+
+	var session *models.PlaySession
+	var err error
+	if sessionID == 0 {
+		session, err = s.onAirSession()
+	} else {
+		session, err = s.engine.GetSession(sessionID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	nowPlaying := newNowPlaying(session, s.clock.Now())
+	return &nowPlaying, nil
+}
+
+// Subscribe retourne les mises à jour de toutes les sessions et la fonction de désabonnement.
+// Un abonné trop lent perd les mises à jour qui dépassent sa file d'attente.
+func (s *NowPlayingService) Subscribe() (<-chan models.NowPlaying, func()) {
+	return s.updates.subscribe()
+}
+
+// TrackPlayed diffuse la nouvelle track courante de la session
+func (s *NowPlayingService) TrackPlayed(event models.TrackPlayedEvent) {
+	now := s.clock.Now()
+	if event.SessionID == 0 {
+		s.updates.publish(models.NowPlaying{
+			PlaylistID: event.PlaylistID,
+			Status:     models.PlaySessionPlaying,
+			Current: &models.PlaySessionItem{
+				PlaylistID:      event.PlaylistID,
+				TrackID:         event.TrackID,
+				Title:           event.TrackTitle,
+				Artist:          event.Artist,
+				ArtistID:        event.ArtistID,
+				Position:        event.Position,
+				DurationSeconds: event.DurationSeconds,
+			},
+			StartedAt: &event.PlayedAt,
+			UpdatedAt: now,
+		})
+		return
+	}
+
+	session, err := s.engine.GetSession(event.SessionID)
+	if err != nil {
+		log.Printf("Failed to load now playing of session %d: %v", event.SessionID, err)
+		return
+	}
+	s.updates.publish(newNowPlaying(session, now))
+}
+
+// TrackEnded diffuse la fin d'une session arrêtée ou terminée. Les autres fins de track sont
+// suivies du démarrage de la suivante, diffusé par TrackPlayed.
+func (s *NowPlayingService) TrackEnded(event models.TrackEndedEvent) {
+	now := s.clock.Now()
+	switch event.Reason {
+	case models.TrackEndStopped:
+		s.updates.publish(models.NowPlaying{
+			SessionID:  event.SessionID,
+			PlaylistID: event.PlaylistID,
+			Status:     models.PlaySessionStopped,
+			UpdatedAt:  now,
+		})
+	case models.TrackEndCompleted:
+		session, err := s.engine.GetSession(event.SessionID)
+		if err != nil {
+			log.Printf("Failed to load now playing of session %d: %v", event.SessionID, err)
+			return
+		}
+		if !session.IsActive() {
+			s.updates.publish(newNowPlaying(session, now))
+		}
+	}
+}
+
+// onAirSession choisit la session programmée en cours, sinon la dernière track démarrée
+func (s *NowPlayingService) onAirSession() (*models.PlaySession, error) {
+	sessions, err := s.sessionsRepo.ListByStatus(models.PlaySessionPlaying, models.PlaySessionPaused)
+	if err != nil {
+		return nil, domainErrors.NewInternalError("failed to get active play sessions", err)
+	}
+
+	var onAir *models.PlaySession
+	for _, session := range sessions {
+		if onAir == nil || onAirRank(session) > onAirRank(onAir) ||
+			(onAirRank(session) == onAirRank(onAir) && startedAfter(session, onAir)) {
+			onAir = session
+		}
+	}
+	if onAir == nil {
+		return nil, domainErrors.ErrNothingOnAir
+	}
+	return onAir, nil
+}
+
+// onAirRank priorité d'une session pour l'antenne : programmée, puis en lecture, puis en pause
+func onAirRank(session *models.PlaySession) int {
+	rank := 0
+	if session.ClockID != nil {
+		rank += 2
+	}
+	if session.Status == models.PlaySessionPlaying {
+		rank++
+	}
+	return rank
+}
+
+func startedAfter(session *models.PlaySession, other *models.PlaySession) bool {
+	if session.TrackStartedAt == nil || other.TrackStartedAt == nil {
+		return session.TrackStartedAt != nil
+	}
+	return session.TrackStartedAt.After(*other.TrackStartedAt)
+}
+
+func newNowPlaying(session *models.PlaySession, now time.Time) models.NowPlaying {
+	nowPlaying := models.NowPlaying{
+		SessionID:  session.ID,
+		PlaylistID: session.PlaylistID,
+		ClockID:    session.ClockID,
+		Status:     session.Status,
+		UpdatedAt:  now,
+	}
+	if !session.IsActive() {
+		return nowPlaying
+	}
+
+	if current := session.CurrentItem(); current != nil {
+		nowPlaying.Current = current
+		nowPlaying.StartedAt = session.TrackStartedAt
+		nowPlaying.ElapsedSeconds = int(min(session.Elapsed(now), current.Duration()) / time.Second)
+	}
+	if next := nextIndex(session, true); next >= 0 && next < len(session.Items) {
+		nowPlaying.Next = &session.Items[next]
+	}
+	return nowPlaying
+}
@@ -0,0 +1,8 @@
+package services
+
+import "radioking-app/internal/domain/models"
+
+type INowPlayingService interface {
+	GetNowPlaying(sessionID int64) (*models.NowPlaying, error)
+	Subscribe() (<-chan models.NowPlaying, func())
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func buildActiveSession(id int64, status models.PlaySessionStatus, startedAt time.Time) *models.PlaySession {
+	return &models.PlaySession{
+		ID:             id,
+		PlaylistID:     id * 10,
+		Status:         status,
+		TrackStartedAt: &startedAt,
+		TrackResumedAt: &startedAt,
+		Items: []models.PlaySessionItem{
+			{TrackID: 1, Title: "First", DurationSeconds: 180},
+			{TrackID: 2, Title: "Second", DurationSeconds: 200},
+		},
+	}
+}
+
+func TestNowPlayingService_GetNowPlaying_OnAirSession(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	clockID := int64(3)
+	scheduled := buildActiveSession(2, models.PlaySessionPlaying, now.Add(-time.Minute))
+	scheduled.ClockID = &clockID
+
+	repo := new(MockPlaySessionRepository)
+	repo.On("ListByStatus", mock.Anything).Return([]*models.PlaySession{
+		buildActiveSession(1, models.PlaySessionPlaying, now.Add(-10*time.Second)),
+		scheduled,
+		buildActiveSession(3, models.PlaySessionPaused, now),
+	}, nil)
+	service := NewNowPlayingService(nil, repo)
+	service.clock = &manualClock{now: now}
+
+	// Act
+	nowPlaying, err := service.GetNowPlaying(0)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), nowPlaying.SessionID)
+	require.NotNil(t, nowPlaying.Current)
+	assert.Equal(t, "First", nowPlaying.Current.Title)
+	assert.Equal(t, 60, nowPlaying.ElapsedSeconds)
+	require.NotNil(t, nowPlaying.Next)
+	assert.Equal(t, "Second", nowPlaying.Next.Title)
+}
+
+func TestNowPlayingService_GetNowPlaying_NothingOnAir(t *testing.T) {
+	// Arrange
+	repo := new(MockPlaySessionRepository)
+	repo.On("ListByStatus", mock.Anything).Return([]*models.PlaySession{}, nil)
+	service := NewNowPlayingService(nil, repo)
+
+	// Act
+	_, err := service.GetNowPlaying(0)
+
+	// Assert
+	assert.ErrorIs(t, err, domainErrors.ErrNothingOnAir)
+}
+
+func TestNowPlayingService_BroadcastsTrackEvents(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	session := buildActiveSession(1, models.PlaySessionPlaying, now)
+	session.CurrentIndex = 1
+
+	repo := new(MockPlaySessionRepository)
+	repo.On("GetByID", int64(1)).Return(session, nil)
+	service := NewNowPlayingService(NewPlaybackEngine(repo, nil), repo)
+	service.clock = &manualClock{now: now}
+
+	updates, unsubscribe := service.Subscribe()
+	defer unsubscribe()
+
+	// Act
+	service.TrackPlayed(models.TrackPlayedEvent{SessionID: 1, TrackID: 2, PlayedAt: now})
+	service.TrackEnded(models.TrackEndedEvent{SessionID: 1, TrackID: 2, Reason: models.TrackEndSkipped})
+	service.TrackEnded(models.TrackEndedEvent{SessionID: 1, TrackID: 2, Reason: models.TrackEndStopped})
+
+	// Assert
+	require.Len(t, updates, 2)
+	played := <-updates
+	require.NotNil(t, played.Current)
+	assert.Equal(t, "Second", played.Current.Title)
+	assert.Nil(t, played.Next)
+
+	stopped := <-updates
+	assert.Equal(t, models.PlaySessionStopped, stopped.Status)
+	assert.Nil(t, stopped.Current)
+}
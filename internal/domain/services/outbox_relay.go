@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"radioking-app/internal/domain/constants"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/messaging"
+	"radioking-app/internal/infrastructure/repositories"
+	"sync"
+	"time"
+)
+
+// outboxCleanupInterval intervalle entre deux suppressions des messages publiés
+const outboxCleanupInterval = time.Hour
+
+// OutboxRelay publie les messages de l'outbox dans leur ordre d'enregistrement. Un message n'est
+// marqué publié qu'une fois confirmé par le broker ; en cas d'échec le passage s'arrête pour ne
+// pas publier les messages suivants avant lui, et le message est retenté avec un délai croissant.
+// Un message qu'aucune queue ne reçoit, ou illisible, passe failed pour ne pas bloquer l'outbox.
+type OutboxRelay struct {
+	repo         repositories.IOutboxRepository
+	publisher    messaging.MessagePublisher
+	clock        clock
+	pollInterval time.Duration
+	retention    time.Duration
+
+	wake chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewOutboxRelay relaie l'outbox toutes les pollInterval et supprime les messages publiés depuis plus
+// de retention. Une retention nulle conserve les messages publiés.
+func NewOutboxRelay(repo repositories.IOutboxRepository, publisher messaging.MessagePublisher,
+	pollInterval, retention time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		repo:         repo,
+		publisher:    publisher,
+		clock:        realClock{},
+		pollInterval: pollInterval,
+		retention:    retention,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// SessionChanged réveille le relais : l'état persisté d'une session s'accompagne souvent d'événements
+func (r *OutboxRelay) SessionChanged(models.PlaySession) {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start relaie l'outbox jusqu'à l'annulation de ctx
+func (r *OutboxRelay) Start(ctx context.Context) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		var lastCleanup time.Time
+		for {
+			_, nextAttemptAt, err := r.relayPending()
+			if err != nil {
+				log.Printf("Failed to relay outbox: %v", err)
+			}
+
+			if r.retention > 0 && r.clock.Now().Sub(lastCleanup) >= outboxCleanupInterval {
+				lastCleanup = r.clock.Now()
+				if _, err := r.repo.DeleteSentBefore(lastCleanup.Add(-r.retention)); err != nil {
+					log.Printf("Failed to clean up outbox: %v", err)
+				}
+			}
+
+			wait := r.pollInterval
+			if !nextAttemptAt.IsZero() {
+				wait = min(wait, nextAttemptAt.Sub(r.clock.Now()))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.wake:
+			case <-r.clock.After(wait):
+			}
+		}
+	}()
+}
+
+// Wait attend l'arrêt du relais, après annulation du contexte passé à Start
+func (r *OutboxRelay) Wait() {
+	r.wg.Wait()
+}
+
+// RelayPending publie les messages pending dans leur ordre d'enregistrement et retourne le nombre
+// de messages publiés. Le passage s'arrête au premier message dont la tentative n'est pas arrivée.
+func (r *OutboxRelay) RelayPending() (int, error) {
+	published, _, err := r.relayPending()
+	return published, err
+}
+
+// relayPending retourne aussi l'échéance du message qui a arrêté le passage, zéro si l'outbox est vide
+func (r *OutboxRelay) relayPending() (int, time.Time, error) {
+	published := 0
+	for {
+		messages, err := r.repo.ListPending(constants.OutboxBatchSize)
+		if err != nil {
+			return published, time.Time{}, err
+		}
+
+		for _, message := range messages {
+			now := r.clock.Now()
+			if message.NextAttemptAt.After(now) {
+				return published, message.NextAttemptAt, nil
+			}
+
+			if err := r.publish(message); err != nil {
+				if errors.Is(err, errUndeliverable) || errors.Is(err, messaging.ErrUnroutable) {
+					log.Printf("Outbox message %d (%s) cannot be delivered: %v", message.ID, message.EventType, err)
+					if markErr := r.repo.MarkUndeliverable(message.ID, err.Error()); markErr != nil {
+						return published, time.Time{}, markErr
+					}
+					continue
+				}
+
+				nextAttemptAt := now.Add(outboxRetryDelay(r.pollInterval, message.Attempts))
+				log.Printf("Failed to publish outbox message %d (%s), next attempt at %s: %v",
+					message.ID, message.EventType, nextAttemptAt.Format(time.RFC3339), err)
+				if markErr := r.repo.MarkFailed(message.ID, err.Error(), nextAttemptAt); markErr != nil {
+					return published, time.Time{}, markErr
+				}
+				return published, nextAttemptAt, nil
+			}
+
+			if err := r.repo.MarkSent(message.ID, r.clock.Now()); err != nil {
+				return published, time.Time{}, err
+			}
+			published++
+		}
+
+		if len(messages) < constants.OutboxBatchSize {
+			return published, time.Time{}, nil
+		}
+	}
+}
+
+// errUndeliverable le message enregistré ne peut pas être relu, il ne sera jamais publié
+var errUndeliverable = errors.New("undeliverable outbox message")
+
+func (r *OutboxRelay) publish(message *models.OutboxMessage) error {
+	event, err := message.Event()
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUndeliverable, err)
+	}
+	return r.publisher.Publish(event)
+}
+
+// outboxRetryDelay délai avant la tentative suivant attempts échecs, doublé à chaque échec
+func outboxRetryDelay(base time.Duration, attempts int) time.Duration {
+	maxDelay := constants.MaxOutboxRetryDelaySeconds * time.Second
+	delay := max(base, time.Second)
+	for i := 0; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
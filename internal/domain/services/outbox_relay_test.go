@@ -0,0 +1,194 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"radioking-app/internal/domain/constants"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/messaging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockOutboxRepository is a mock implementation of IOutboxRepository
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) ListPending(limit int) ([]*models.OutboxMessage, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]*models.OutboxMessage), args.Error(1)
+}
+
+func (m *MockOutboxRepository) MarkSent(id int64, sentAt time.Time) error {
+	args := m.Called(id, sentAt)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) MarkFailed(id int64, lastError string, nextAttemptAt time.Time) error {
+	args := m.Called(id, lastError, nextAttemptAt)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) MarkUndeliverable(id int64, lastError string) error {
+	args := m.Called(id, lastError)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) List(status models.OutboxStatus, limit int) ([]*models.OutboxMessage, error) {
+	args := m.Called(status, limit)
+	return args.Get(0).([]*models.OutboxMessage), args.Error(1)
+}
+
+func (m *MockOutboxRepository) GetByEventID(eventID string) (*models.OutboxMessage, error) {
+	args := m.Called(eventID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OutboxMessage), args.Error(1)
+}
+
+func (m *MockOutboxRepository) Stats() (models.OutboxStats, error) {
+	args := m.Called()
+	return args.Get(0).(models.OutboxStats), args.Error(1)
+}
+
+func (m *MockOutboxRepository) DeleteSentBefore(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockMessagePublisher enregistre les événements publiés, les attentes portent sur leur type
+type MockMessagePublisher struct {
+	mock.Mock
+	events []models.Event
+}
+
+func (m *MockMessagePublisher) Publish(event models.Event) error {
+	m.events = append(m.events, event)
+	args := m.Called(event.Type)
+	return args.Error(0)
+}
+
+func (m *MockMessagePublisher) Close() error {
+	return nil
+}
+
+func newTestOutboxMessages(t *testing.T) []*models.OutboxMessage {
+	played, err := models.NewTrackPlayedOutboxMessage(models.TrackPlayedEvent{TrackID: 10, SessionID: 1, Sequence: 1, EventID: "played-1"})
+	require.NoError(t, err)
+	ended, err := models.NewTrackEndedOutboxMessage(models.TrackEndedEvent{TrackID: 10, SessionID: 1, Sequence: 1, EventID: "ended-1"})
+	require.NoError(t, err)
+	next, err := models.NewTrackPlayedOutboxMessage(models.TrackPlayedEvent{TrackID: 11, SessionID: 1, Sequence: 2, EventID: "played-2"})
+	require.NoError(t, err)
+
+	played.ID, ended.ID, next.ID = 1, 2, 3
+	return []*models.OutboxMessage{&played, &ended, &next}
+}
+
+func TestOutboxRelay_RelayPendingPublishesInOrder(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	repo := new(MockOutboxRepository)
+	repo.On("ListPending", constants.OutboxBatchSize).Return(newTestOutboxMessages(t), nil)
+	repo.On("MarkSent", mock.Anything, now).Return(nil)
+	publisher := new(MockMessagePublisher)
+	publisher.On("Publish", mock.Anything).Return(nil)
+	relay := NewOutboxRelay(repo, publisher, time.Second, 0)
+	relay.clock = &manualClock{now: now}
+
+	// Act
+	published, err := relay.RelayPending()
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 3, published)
+	require.Len(t, publisher.events, 3)
+	assert.Equal(t, "played-1", publisher.events[0].ID)
+	assert.Equal(t, models.EventTrackEnded, publisher.events[1].Type)
+	assert.Equal(t, "ended-1", publisher.events[1].ID)
+	assert.Equal(t, "played-2", publisher.events[2].ID)
+	repo.AssertNumberOfCalls(t, "MarkSent", 3)
+}
+
+func TestOutboxRelay_RelayPendingStopsAtFirstFailure(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	messages := newTestOutboxMessages(t)
+	messages[1].Attempts = 2
+	repo := new(MockOutboxRepository)
+	repo.On("ListPending", constants.OutboxBatchSize).Return(messages, nil)
+	repo.On("MarkSent", int64(1), now).Return(nil)
+	repo.On("MarkFailed", int64(2), "broker unavailable", now.Add(4*time.Second)).Return(nil)
+	publisher := new(MockMessagePublisher)
+	publisher.On("Publish", models.EventTrackPlayed).Return(nil)
+	publisher.On("Publish", models.EventTrackEnded).Return(errors.New("broker unavailable"))
+	relay := NewOutboxRelay(repo, publisher, time.Second, 0)
+	relay.clock = &manualClock{now: now}
+
+	// Act
+	published, err := relay.RelayPending()
+
+	// Assert : le troisième message attend la publication du deuxième
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Len(t, publisher.events, 2)
+	repo.AssertExpectations(t)
+}
+
+func TestOutboxRelay_RelayPendingWaitsForMessageNotDueYet(t *testing.T) {
+	// Arrange : le premier message a échoué, les suivants sont arrivés à échéance
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	messages := newTestOutboxMessages(t)
+	messages[0].NextAttemptAt = now.Add(2 * time.Second)
+	repo := new(MockOutboxRepository)
+	repo.On("ListPending", constants.OutboxBatchSize).Return(messages, nil)
+	publisher := new(MockMessagePublisher)
+	relay := NewOutboxRelay(repo, publisher, time.Second, 0)
+	relay.clock = &manualClock{now: now}
+
+	// Act
+	published, nextAttemptAt, err := relay.relayPending()
+
+	// Assert : aucun message n'est publié avant le premier
+	require.NoError(t, err)
+	assert.Equal(t, 0, published)
+	assert.Equal(t, now.Add(2*time.Second), nextAttemptAt)
+	assert.Empty(t, publisher.events)
+	repo.AssertNotCalled(t, "MarkSent", mock.Anything, mock.Anything)
+}
+
+func TestOutboxRelay_RelayPendingSetsAsideUnroutableMessage(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	repo := new(MockOutboxRepository)
+	repo.On("ListPending", constants.OutboxBatchSize).Return(newTestOutboxMessages(t), nil)
+	repo.On("MarkSent", mock.Anything, now).Return(nil)
+	repo.On("MarkUndeliverable", int64(2), mock.Anything).Return(nil)
+	publisher := new(MockMessagePublisher)
+	publisher.On("Publish", models.EventTrackPlayed).Return(nil)
+	publisher.On("Publish", models.EventTrackEnded).Return(fmt.Errorf("%w: track.ended", messaging.ErrUnroutable))
+	relay := NewOutboxRelay(repo, publisher, time.Second, 0)
+	relay.clock = &manualClock{now: now}
+
+	// Act
+	published, err := relay.RelayPending()
+
+	// Assert : le message non routé ne bloque pas les suivants
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "MarkFailed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOutboxRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Second, outboxRetryDelay(time.Second, 0))
+	assert.Equal(t, 8*time.Second, outboxRetryDelay(time.Second, 3))
+	assert.Equal(t, time.Second, outboxRetryDelay(0, 0))
+	assert.Equal(t, constants.MaxOutboxRetryDelaySeconds*time.Second, outboxRetryDelay(time.Second, 50))
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"radioking-app/internal/domain/constants"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/repositories"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OutboxService suit la livraison des événements enregistrés dans l'outbox
+type OutboxService struct {
+	repo  repositories.IOutboxRepository
+	clock clock
+}
+
+func NewOutboxService(repo repositories.IOutboxRepository) *OutboxService {
+	return &OutboxService{repo: repo, clock: realClock{}}
+}
+
+// List retourne les messages les plus récents, de tous les statuts si status est vide
+func (s *OutboxService) List(status models.OutboxStatus, limit int) ([]*models.OutboxMessage, error) {
+	if limit == 0 {
+		limit = constants.DefaultOutboxLimit
+	}
+	if limit < 0 || limit > constants.MaxOutboxLimit {
+		return nil, domainErrors.ErrInvalidOutboxLimit
+	}
+	if status != "" && status != models.OutboxPending && status != models.OutboxSent && status != models.OutboxFailed {
+		return nil, domainErrors.ErrInvalidOutboxStatus
+	}
+
+	messages, err := s.repo.List(status, limit)
+	if err != nil {
+		return nil, domainErrors.NewInternalError("failed to list outbox messages", err)
+	}
+	return messages, nil
+}
+
+// GetByEventID retourne l'état de livraison de l'événement eventID
+func (s *OutboxService) GetByEventID(eventID string) (*models.OutboxMessage, error) {
+	message, err := s.repo.GetByEventID(eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainErrors.ErrOutboxMessageNotFound
+		}
+		return nil, domainErrors.NewInternalError("failed to get outbox message", err)
+	}
+	return message, nil
+}
+
+func (s *OutboxService) Stats() (models.OutboxStats, error) {
+	stats, err := s.repo.Stats()
+	if err != nil {
+		return models.OutboxStats{}, domainErrors.NewInternalError("failed to get outbox stats", err)
+	}
+	return stats, nil
+}
+
+// Cleanup supprime les messages publiés depuis plus de olderThan, les messages pending sont conservés
+func (s *OutboxService) Cleanup(olderThan time.Duration) (int64, error) {
+	if olderThan <= 0 {
+		return 0, domainErrors.ErrInvalidOutboxRetention
+	}
+
+	deleted, err := s.repo.DeleteSentBefore(s.clock.Now().Add(-olderThan))
+	if err != nil {
+		return 0, domainErrors.NewInternalError("failed to clean up outbox", err)
+	}
+	return deleted, nil
+}
+
+// newOutboxMessage message de l'outbox d'un nouvel événement, à enregistrer dans la transaction du changement qu'il décrit
+func newOutboxMessage(eventType models.EventType, occurredAt time.Time, partitionKey int64, payload any) (models.OutboxMessage, error) {
+	event, err := models.NewEvent(eventType, uuid.New().String(), occurredAt, partitionKey, payload)
+	if err != nil {
+		return models.OutboxMessage{}, err
+	}
+	return models.NewOutboxMessage(event)
+}
@@ -0,0 +1,13 @@
+package services
+
+import (
+	"radioking-app/internal/domain/models"
+	"time"
+)
+
+type IOutboxService interface {
+	List(status models.OutboxStatus, limit int) ([]*models.OutboxMessage, error)
+	GetByEventID(eventID string) (*models.OutboxMessage, error)
+	Stats() (models.OutboxStats, error)
+	Cleanup(olderThan time.Duration) (int64, error)
+}
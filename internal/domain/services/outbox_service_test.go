@@ -0,0 +1,93 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"radioking-app/internal/domain/constants"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestOutboxService_List(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  models.OutboxStatus
+		limit   int
+		mockFn  func(*MockOutboxRepository)
+		wantErr error
+	}{
+		{
+			name:   "default limit",
+			status: models.OutboxPending,
+			mockFn: func(repo *MockOutboxRepository) {
+				repo.On("List", models.OutboxPending, constants.DefaultOutboxLimit).Return([]*models.OutboxMessage{{ID: 1}}, nil)
+			},
+		},
+		{
+			name:    "invalid status",
+			status:  "lost",
+			mockFn:  func(repo *MockOutboxRepository) {},
+			wantErr: domainErrors.ErrInvalidOutboxStatus,
+		},
+		{
+			name:    "limit too high",
+			limit:   constants.MaxOutboxLimit + 1,
+			mockFn:  func(repo *MockOutboxRepository) {},
+			wantErr: domainErrors.ErrInvalidOutboxLimit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockOutboxRepository)
+			tt.mockFn(repo)
+			service := NewOutboxService(repo)
+
+			messages, err := service.List(tt.status, tt.limit)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, messages, 1)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOutboxService_GetByEventIDNotFound(t *testing.T) {
+	// Arrange
+	repo := new(MockOutboxRepository)
+	repo.On("GetByEventID", "unknown").Return(nil, gorm.ErrRecordNotFound)
+	service := NewOutboxService(repo)
+
+	// Act
+	_, err := service.GetByEventID("unknown")
+
+	// Assert
+	assert.ErrorIs(t, err, domainErrors.ErrOutboxMessageNotFound)
+}
+
+func TestOutboxService_Cleanup(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	repo := new(MockOutboxRepository)
+	repo.On("DeleteSentBefore", now.Add(-72*time.Hour)).Return(int64(4), nil)
+	service := NewOutboxService(repo)
+	service.clock = &manualClock{now: now}
+
+	// Act
+	deleted, err := service.Cleanup(72 * time.Hour)
+	_, invalidErr := service.Cleanup(0)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), deleted)
+	assert.ErrorIs(t, invalidErr, domainErrors.ErrInvalidOutboxRetention)
+	repo.AssertExpectations(t)
+}
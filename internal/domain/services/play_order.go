@@ -0,0 +1,151 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+	"time"
+)
+
+// normalizePlayOptions applique les valeurs par défaut et tire un seed quand le mélange
+// est demandé sans seed, pour que l'ordre obtenu puisse être rejoué
+func normalizePlayOptions(options models.PlayOptions) (models.PlayOptions, error) {
+	if options.Shuffle == "" {
+		options.Shuffle = models.ShuffleOff
+	}
+	if options.Repeat == "" {
+		options.Repeat = models.RepeatOff
+	}
+
+	switch options.Shuffle {
+	case models.ShuffleOff:
+		options.Seed = nil
+	case models.ShuffleRandom, models.ShuffleSmart:
+		if options.Seed == nil {
+			seed := rand.Int64N(math.MaxInt32)
+			options.Seed = &seed
+		}
+	default:
+		return options, domainErrors.ErrInvalidPlayOptions
+	}
+
+	switch options.Repeat {
+	case models.RepeatOff, models.RepeatOne, models.RepeatAll:
+	default:
+		return options, domainErrors.ErrInvalidPlayOptions
+	}
+	return options, nil
+}
+
+// planPlaySessionItems construit la file de lecture de la playlist selon les options. Une file
+// mélangée est ensuite réordonnée pour respecter les règles de rotation à partir de startsAt ;
+// l'ordre d'une playlist jouée sans mélange est conservé.
+func planPlaySessionItems(playlist models.Playlist, options models.PlayOptions, rotation IRotationService,
+	startsAt time.Time) ([]models.PlaySessionItem, models.PlayOptions, error) {
+	options, err := normalizePlayOptions(options)
+	if err != nil {
+		return nil, options, err
+	}
+
+	items := orderPlaySessionItems(newPlaySessionItems(playlist), options)
+	if rotation != nil && options.Shuffle != models.ShuffleOff {
+		checker, err := rotation.NewChecker(startsAt)
+		if err != nil {
+			return nil, options, err
+		}
+		items = arrangeForRotation(items, checker, startsAt)
+	}
+	return items, options, nil
+}
+
+// orderPlaySessionItems mélange la file de lecture selon les options, l'ordre de la playlist sinon
+func orderPlaySessionItems(items []models.PlaySessionItem, options models.PlayOptions) []models.PlaySessionItem {
+	if options.Shuffle == models.ShuffleOff || options.Seed == nil {
+		return items
+	}
+
+	random := rand.New(rand.NewPCG(uint64(*options.Seed), 0))
+	random.Shuffle(len(items), func(i, j int) {
+		items[i], items[j] = items[j], items[i]
+	})
+
+	if options.Shuffle == models.ShuffleSmart {
+		return spreadArtists(items)
+	}
+	return items
+}
+
+// spreadArtists reconstruit la file mélangée en prenant à chaque rang la première track d'un autre
+// artiste que la précédente. L'artiste le plus représenté est placé en priorité quand il risque
+// sinon de se retrouver enchaîné en fin de file ; s'il est majoritaire, certains enchaînements
+// restent inévitables.
+func spreadArtists(items []models.PlaySessionItem) []models.PlaySessionItem {
+	remaining := items
+	ordered := make([]models.PlaySessionItem, 0, len(items))
+	previous := ""
+
+	for len(remaining) > 0 {
+		counts := make(map[string]int)
+		dominant := ""
+		for _, item := range remaining {
+			key := artistKey(item)
+			counts[key]++
+			if counts[key] > counts[dominant] {
+				dominant = key
+			}
+		}
+
+		// Prendre un autre artiste ne laisse une solution que si le dominant tient encore
+		// une track sur deux parmi les suivantes
+		mustPlaceDominant := dominant != previous && counts[dominant] > len(remaining)/2
+
+		pick := 0
+		for i, item := range remaining {
+			key := artistKey(item)
+			if (mustPlaceDominant && key == dominant) || (!mustPlaceDominant && key != previous) {
+				pick = i
+				break
+			}
+		}
+
+		ordered = append(ordered, remaining[pick])
+		previous = artistKey(remaining[pick])
+		remaining = append(remaining[:pick:pick], remaining[pick+1:]...)
+	}
+	return ordered
+}
+
+// artistKey identifie l'artiste d'une track, par son nom normalisé si elle n'est rattachée à aucun artiste
+func artistKey(item models.PlaySessionItem) string {
+	if item.ArtistID != 0 {
+		return fmt.Sprintf("id:%d", item.ArtistID)
+	}
+	return "name:" + models.NormalizeName(item.Artist)
+}
+
+// nextIndex rang de la track suivante : la track terminée est rejouée en repeat-one et la file
+// reprend au début en repeat-all. Un skip passe toujours à la track suivante.
+func nextIndex(session *models.PlaySession, completed bool) int {
+	if completed && session.Repeat == models.RepeatOne {
+		return session.CurrentIndex
+	}
+
+	next := session.CurrentIndex + 1
+	if next >= len(session.Items) && session.Repeat == models.RepeatAll {
+		return 0
+	}
+	return next
+}
+
+// previousIndex rang de la track précédente, la première track est redémarrée sauf en repeat-all
+func previousIndex(session *models.PlaySession) int {
+	if session.CurrentIndex > 0 {
+		return session.CurrentIndex - 1
+	}
+	if session.Repeat == models.RepeatAll {
+		return len(session.Items) - 1
+	}
+	return 0
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"testing"
+
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildPlaySessionItems(artistIDs ...int64) []models.PlaySessionItem {
+	items := make([]models.PlaySessionItem, 0, len(artistIDs))
+	for i, artistID := range artistIDs {
+		items = append(items, models.PlaySessionItem{TrackID: int64(i + 1), ArtistID: artistID, Position: i})
+	}
+	return items
+}
+
+func trackIDs(items []models.PlaySessionItem) []int64 {
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.TrackID)
+	}
+	return ids
+}
+
+func TestNormalizePlayOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  models.PlayOptions
+		wantErr  bool
+		wantSeed bool
+	}{
+		{name: "defaults", options: models.PlayOptions{}, wantSeed: false},
+		{name: "shuffle without seed draws one", options: models.PlayOptions{Shuffle: models.ShuffleRandom}, wantSeed: true},
+		{name: "invalid shuffle", options: models.PlayOptions{Shuffle: "sometimes"}, wantErr: true},
+		{name: "invalid repeat", options: models.PlayOptions{Repeat: "twice"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := normalizePlayOptions(tt.options)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domainErrors.ErrInvalidPlayOptions)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, options.Shuffle)
+			assert.NotEmpty(t, options.Repeat)
+			assert.Equal(t, tt.wantSeed, options.Seed != nil)
+		})
+	}
+}
+
+func TestOrderPlaySessionItems_SeededShuffleIsReproducible(t *testing.T) {
+	// Arrange
+	seed := int64(42)
+	options := models.PlayOptions{Shuffle: models.ShuffleRandom, Seed: &seed}
+
+	// Act
+	first := orderPlaySessionItems(buildPlaySessionItems(1, 2, 3, 4, 5, 6, 7, 8), options)
+	second := orderPlaySessionItems(buildPlaySessionItems(1, 2, 3, 4, 5, 6, 7, 8), options)
+
+	// Assert
+	assert.Equal(t, trackIDs(first), trackIDs(second))
+	assert.NotEqual(t, []int64{1, 2, 3, 4, 5, 6, 7, 8}, trackIDs(first))
+	assert.ElementsMatch(t, []int64{1, 2, 3, 4, 5, 6, 7, 8}, trackIDs(first))
+}
+
+func TestOrderPlaySessionItems_SmartShuffleAvoidsSameArtistBackToBack(t *testing.T) {
+	for seed := int64(0); seed < 50; seed++ {
+		options := models.PlayOptions{Shuffle: models.ShuffleSmart, Seed: &seed}
+
+		items := orderPlaySessionItems(buildPlaySessionItems(1, 1, 1, 2, 2, 3, 3, 4), options)
+
+		for i := 1; i < len(items); i++ {
+			assert.NotEqual(t, items[i-1].ArtistID, items[i].ArtistID, "seed %d, position %d", seed, i)
+		}
+	}
+}
+
+func TestNextIndex(t *testing.T) {
+	tests := []struct {
+		name      string
+		repeat    models.RepeatMode
+		current   int
+		completed bool
+		want      int
+	}{
+		{name: "next track", repeat: models.RepeatOff, current: 0, completed: true, want: 1},
+		{name: "end of queue", repeat: models.RepeatOff, current: 2, completed: true, want: 3},
+		{name: "repeat one replays track", repeat: models.RepeatOne, current: 1, completed: true, want: 1},
+		{name: "repeat one skip moves on", repeat: models.RepeatOne, current: 1, completed: false, want: 2},
+		{name: "repeat all wraps", repeat: models.RepeatAll, current: 2, completed: true, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &models.PlaySession{Repeat: tt.repeat, CurrentIndex: tt.current, Items: buildPlaySessionItems(1, 2, 3)}
+
+			assert.Equal(t, tt.want, nextIndex(session, tt.completed))
+		})
+	}
+}
@@ -0,0 +1,489 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"radioking-app/internal/domain/constants"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/repositories"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// clock abstrait le temps pour tester le moteur sans attendre la durée réelle des tracks
+type clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+type sessionAction string
+
+const (
+	actionPause  sessionAction = "pause"
+	actionResume sessionAction = "resume"
+	actionSkip   sessionAction = "skip"
+	actionBack   sessionAction = "back"
+	actionStop   sessionAction = "stop"
+	actionInsert sessionAction = "insert"
+)
+
+// sessionCommand commande envoyée à la goroutine qui joue la session
+type sessionCommand struct {
+	action sessionAction
+	item   models.PlaySessionItem // Track à insérer pour actionInsert
+	reply  chan error
+}
+
+// SessionListener est notifié de chaque changement d'état persisté d'une session
+type SessionListener interface {
+	SessionChanged(session models.PlaySession)
+}
+
+// playback lecture en cours d'une session
+type playback struct {
+	cancel   context.CancelFunc
+	done     chan struct{}
+	commands chan sessionCommand
+}
+
+// PlaybackEngine joue les sessions en temps réel : l'événement d'une track est émis à son
+// démarrage effectif, puis le moteur attend la fin de la track avant de passer à la suivante.
+// Chaque session est pilotée par sa propre goroutine, seule à modifier son état.
+// L'état des sessions est persisté pour reprendre la lecture au redémarrage du service, avec
+// les événements qu'il produit : l'outbox les publie ensuite, même si le broker est indisponible.
+type PlaybackEngine struct {
+	repo      repositories.IPlaySessionRepository
+	rotation  IRotationService
+	clock     clock
+	listeners []SessionListener
+
+	mu       sync.Mutex
+	ctx      context.Context
+	sessions map[int64]*playback
+	wg       sync.WaitGroup
+}
+
+func NewPlaybackEngine(repo repositories.IPlaySessionRepository, rotation IRotationService) *PlaybackEngine {
+	return &PlaybackEngine{
+		repo:     repo,
+		rotation: rotation,
+		clock:    realClock{},
+		ctx:      context.Background(),
+		sessions: make(map[int64]*playback),
+	}
+}
+
+// AddListener abonne listener aux changements d'état des sessions, à appeler avant Start
+func (e *PlaybackEngine) AddListener(listener SessionListener) {
+	e.listeners = append(e.listeners, listener)
+}
+
+// Start reprend les sessions encore actives au dernier arrêt du service.
+// L'annulation de ctx interrompt les lectures sans changer l'état des sessions.
+func (e *PlaybackEngine) Start(ctx context.Context) error {
+	e.mu.Lock()
+	e.ctx = ctx
+	e.mu.Unlock()
+
+	sessions, err := e.repo.ListByStatus(models.PlaySessionPlaying, models.PlaySessionPaused)
+	if err != nil {
+		return fmt.Errorf("failed to load play sessions: %w", err)
+	}
+
+	for _, session := range sessions {
+		log.Printf("Resuming %s play session %d of playlist %d at track %d",
+			session.Status, session.ID, session.PlaylistID, session.CurrentIndex)
+		e.launch(session)
+	}
+	return nil
+}
+
+// Play crée une session figeant la file de lecture de la playlist, démarre sa première track
+// puis poursuit la lecture en arrière-plan. Une file mélangée respecte les règles de rotation
+// quand c'est possible. L'événement playlist.played est enregistré avec le premier état de la session.
+func (e *PlaybackEngine) Play(playlist models.Playlist, options models.PlayOptions) (*models.PlaySession, error) {
+	items, options, err := planPlaySessionItems(playlist, options, e.rotation, e.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.PlaySession{
+		PlaylistID: playlist.ID,
+		Status:     models.PlaySessionPlaying,
+		ClockID:    options.ClockID,
+		Shuffle:    options.Shuffle,
+		Repeat:     options.Repeat,
+		Seed:       options.Seed,
+		Items:      items,
+	}
+	if len(session.Items) == 0 {
+		session.Status = models.PlaySessionFinished
+	}
+
+	if err := e.repo.Create(session); err != nil {
+		return nil, domainErrors.NewInternalError("failed to create play session", err)
+	}
+
+	played := e.playlistPlayed(session, len(playlist.Tracks))
+	if !session.IsActive() {
+		if err := e.save(session, played...); err != nil {
+			return nil, err
+		}
+		return session, nil
+	}
+
+	if err := e.advance(session, played...); err != nil {
+		return nil, err
+	}
+	e.launch(session)
+	return session, nil
+}
+
+// playlistPlayed retourne l'événement de démarrage de la session, persisté avec son premier état
+func (e *PlaybackEngine) playlistPlayed(session *models.PlaySession, tracksCount int) []models.OutboxMessage {
+	playedAt := e.clock.Now().UTC()
+	message, err := newOutboxMessage(models.EventPlaylistPlayed, playedAt, session.PlaylistID, models.PlaylistPlayedEvent{
+		PlaylistID:  session.PlaylistID,
+		SessionID:   session.ID,
+		TracksCount: tracksCount,
+		Shuffle:     session.Shuffle,
+		Repeat:      session.Repeat,
+		Seed:        session.Seed,
+		TrackIDs:    session.TrackIDs(),
+		PlayedAt:    playedAt,
+	})
+	if err != nil {
+		// La lecture démarre même si l'événement n'a pas pu être enregistré
+		log.Printf("Failed to record start of play session %d: %v", session.ID, err)
+		return nil
+	}
+	return []models.OutboxMessage{message}
+}
+
+func (e *PlaybackEngine) GetSession(id int64) (*models.PlaySession, error) {
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidPlaySessionID
+	}
+
+	session, err := e.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainErrors.ErrPlaySessionNotFound
+		}
+		return nil, domainErrors.NewInternalError("failed to get play session", err)
+	}
+	return session, nil
+}
+
+func (e *PlaybackEngine) Pause(id int64) (*models.PlaySession, error) {
+	return e.command(id, actionPause)
+}
+
+func (e *PlaybackEngine) Resume(id int64) (*models.PlaySession, error) {
+	return e.command(id, actionResume)
+}
+
+// Skip interrompt la track courante et démarre la suivante
+func (e *PlaybackEngine) Skip(id int64) (*models.PlaySession, error) {
+	return e.command(id, actionSkip)
+}
+
+// Back interrompt la track courante et redémarre la précédente, ou la track courante en début de file
+func (e *PlaybackEngine) Back(id int64) (*models.PlaySession, error) {
+	return e.command(id, actionBack)
+}
+
+// Stop interrompt la track courante et termine la session
+func (e *PlaybackEngine) Stop(id int64) (*models.PlaySession, error) {
+	return e.command(id, actionStop)
+}
+
+// InsertTrack insère la track dans la file, juste après la track courante
+func (e *PlaybackEngine) InsertTrack(id int64, track models.Track) (*models.PlaySession, error) {
+	item := models.PlaySessionItem{
+		TrackID:         track.ID,
+		Title:           track.Title,
+		Artist:          track.Artist,
+		ArtistID:        track.ArtistID,
+		DurationSeconds: trackDuration(track),
+	}
+	return e.send(id, sessionCommand{action: actionInsert, item: item})
+}
+
+// Wait attend la fin des lectures en cours, après annulation du contexte passé à Start
+func (e *PlaybackEngine) Wait() {
+	e.wg.Wait()
+}
+
+// command transmet l'action à la goroutine de la session puis retourne l'état persisté
+func (e *PlaybackEngine) command(id int64, action sessionAction) (*models.PlaySession, error) {
+	return e.send(id, sessionCommand{action: action})
+}
+
+func (e *PlaybackEngine) send(id int64, cmd sessionCommand) (*models.PlaySession, error) {
+	e.mu.Lock()
+	current, running := e.sessions[id]
+	e.mu.Unlock()
+
+	if running {
+		cmd.reply = make(chan error, 1)
+		select {
+		case current.commands <- cmd:
+			if err := <-cmd.reply; err != nil {
+				return nil, err
+			}
+		case <-current.done:
+			running = false
+		}
+	}
+
+	session, err := e.GetSession(id)
+	if err != nil {
+		return nil, err
+	}
+	if !running {
+		return nil, domainErrors.ErrPlaySessionNotActive
+	}
+	return session, nil
+}
+
+func (e *PlaybackEngine) launch(session *models.PlaySession) {
+	e.mu.Lock()
+	ctx, cancel := context.WithCancel(e.ctx)
+	current := &playback{cancel: cancel, done: make(chan struct{}), commands: make(chan sessionCommand)}
+	e.sessions[session.ID] = current
+	e.mu.Unlock()
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		defer close(current.done)
+		defer func() {
+			e.mu.Lock()
+			delete(e.sessions, session.ID)
+			e.mu.Unlock()
+		}()
+		defer cancel()
+
+		if err := e.run(ctx, current, session); err != nil {
+			log.Printf("Play session %d interrupted: %v", session.ID, err)
+		}
+	}()
+}
+
+// run joue la session à partir de sa track courante. Une track démarrée avant un redémarrage
+// n'est pas republiée : le moteur attend seulement la fin de sa durée.
+func (e *PlaybackEngine) run(ctx context.Context, current *playback, session *models.PlaySession) error {
+	if session.TrackStartedAt == nil {
+		if err := e.advance(session); err != nil {
+			return err
+		}
+	}
+
+	for session.IsActive() {
+		// En pause, seule une commande peut faire avancer la session
+		var trackEnd <-chan time.Time
+		if session.Status == models.PlaySessionPlaying {
+			remaining := session.CurrentItem().Duration() - session.Elapsed(e.clock.Now())
+			trackEnd = e.clock.After(max(remaining, 0))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-trackEnd:
+			ended := e.endTrack(session, models.TrackEndCompleted)
+			session.CurrentIndex = nextIndex(session, true)
+			if err := e.advance(session, ended...); err != nil {
+				return err
+			}
+		case cmd := <-current.commands:
+			cmd.reply <- e.apply(session, cmd)
+		}
+	}
+	return nil
+}
+
+func (e *PlaybackEngine) apply(session *models.PlaySession, cmd sessionCommand) error {
+	now := e.clock.Now()
+	var events []models.OutboxMessage
+
+	switch cmd.action {
+	case actionPause:
+		if session.Status != models.PlaySessionPlaying {
+			return domainErrors.ErrPlaySessionNotPlaying
+		}
+		session.TrackElapsedMs = session.Elapsed(now).Milliseconds()
+		session.TrackResumedAt = nil
+		session.Status = models.PlaySessionPaused
+	case actionResume:
+		if session.Status != models.PlaySessionPaused {
+			return domainErrors.ErrPlaySessionNotPaused
+		}
+		session.TrackResumedAt = &now
+		session.Status = models.PlaySessionPlaying
+	case actionSkip:
+		ended := e.endTrack(session, models.TrackEndSkipped)
+		session.CurrentIndex = nextIndex(session, false)
+		return e.advance(session, ended...)
+	case actionBack:
+		ended := e.endTrack(session, models.TrackEndBack)
+		session.CurrentIndex = previousIndex(session)
+		return e.advance(session, ended...)
+	case actionStop:
+		events = e.endTrack(session, models.TrackEndStopped)
+		session.Status = models.PlaySessionStopped
+	case actionInsert:
+		if len(session.Items) >= constants.MaxPlaySessionItems {
+			return domainErrors.ErrPlaySessionQueueFull
+		}
+		// Position : rang de la track insérée dans la file, elle n'appartient pas à la playlist
+		item := cmd.item
+		item.Position = session.CurrentIndex + 1
+		session.Items = slices.Insert(session.Items, session.CurrentIndex+1, item)
+	}
+
+	return e.save(session, events...)
+}
+
+// advance démarre la track courante, ou termine la session une fois la file épuisée.
+// events, la fin de la track précédente ou le démarrage de la session, est enregistré avec le nouvel état de la session.
+func (e *PlaybackEngine) advance(session *models.PlaySession, events ...models.OutboxMessage) error {
+	item := session.CurrentItem()
+	if item == nil {
+		session.Status = models.PlaySessionFinished
+		if err := e.save(session, events...); err != nil {
+			return err
+		}
+		log.Printf("Play session %d of playlist %d finished", session.ID, session.PlaylistID)
+		return nil
+	}
+	return e.startTrack(session, *item, events...)
+}
+
+// startTrack persiste le démarrage de la track avec son événement
+func (e *PlaybackEngine) startTrack(session *models.PlaySession, item models.PlaySessionItem, events ...models.OutboxMessage) error {
+	startedAt := e.clock.Now()
+	session.Status = models.PlaySessionPlaying
+	session.Sequence++
+	session.TrackStartedAt = &startedAt
+	session.TrackResumedAt = &startedAt
+	session.TrackElapsedMs = 0
+
+	event := models.TrackPlayedEvent{
+		PlaylistID:      item.SourcePlaylistID(session.PlaylistID),
+		TrackID:         item.TrackID,
+		TrackTitle:      item.Title,
+		Artist:          item.Artist,
+		ArtistID:        item.ArtistID,
+		Position:        item.Position,
+		SessionID:       session.ID,
+		Sequence:        session.Sequence,
+		DurationSeconds: item.DurationSeconds,
+		Shuffle:         session.Shuffle,
+		Repeat:          session.Repeat,
+		Seed:            session.Seed,
+		PlayedAt:        startedAt,
+		EventID:         uuid.New().String(),
+	}
+
+	message, err := models.NewTrackPlayedOutboxMessage(event)
+	if err != nil {
+		// La lecture continue même si l'événement n'a pas pu être enregistré
+		log.Printf("Failed to record event for track %d (position %d): %v", item.TrackID, item.Position, err)
+	} else {
+		events = append(events, message)
+	}
+
+	if err := e.save(session, events...); err != nil {
+		return err
+	}
+
+	log.Printf("Started track '%s' by '%s' at position %d", item.Title, item.Artist, item.Position)
+	return nil
+}
+
+// endTrack retourne l'événement de fin de la track courante avec le temps réellement joué.
+// Il est persisté avec l'état de la session par l'étape suivante (démarrage, arrêt ou fin de session).
+func (e *PlaybackEngine) endTrack(session *models.PlaySession, reason models.TrackEndReason) []models.OutboxMessage {
+	item := session.CurrentItem()
+	if item == nil || session.TrackStartedAt == nil {
+		return nil
+	}
+
+	endedAt := e.clock.Now()
+	played := min(session.Elapsed(endedAt), item.Duration())
+	event := models.TrackEndedEvent{
+		SessionID:     session.ID,
+		Sequence:      session.Sequence,
+		PlaylistID:    item.SourcePlaylistID(session.PlaylistID),
+		TrackID:       item.TrackID,
+		Position:      item.Position,
+		PlayedAt:      *session.TrackStartedAt,
+		EndedAt:       endedAt,
+		PlayedSeconds: int(played / time.Second),
+		Reason:        reason,
+		EventID:       uuid.New().String(),
+	}
+
+	session.TrackStartedAt = nil
+	session.TrackResumedAt = nil
+	session.TrackElapsedMs = 0
+
+	message, err := models.NewTrackEndedOutboxMessage(event)
+	if err != nil {
+		log.Printf("Failed to record end of track %d (session %d): %v", item.TrackID, session.ID, err)
+		return nil
+	}
+	return []models.OutboxMessage{message}
+}
+
+func (e *PlaybackEngine) save(session *models.PlaySession, events ...models.OutboxMessage) error {
+	if err := e.repo.Update(session, events...); err != nil {
+		return domainErrors.NewInternalError("failed to save play session", err)
+	}
+
+	// Les listeners reçoivent une copie : la file de la session n'est modifiée que par sa goroutine
+	snapshot := *session
+	snapshot.Items = slices.Clone(session.Items)
+	for _, listener := range e.listeners {
+		listener.SessionChanged(snapshot)
+	}
+	return nil
+}
+
+func newPlaySessionItems(playlist models.Playlist) []models.PlaySessionItem {
+	items := make([]models.PlaySessionItem, 0, len(playlist.Tracks))
+	for _, entry := range playlist.Tracks {
+		items = append(items, models.PlaySessionItem{
+			PlaylistID:      entry.PlaylistID,
+			TrackID:         entry.TrackID,
+			Title:           entry.Track.Title,
+			Artist:          entry.Track.Artist,
+			ArtistID:        entry.Track.ArtistID,
+			Position:        entry.Position,
+			DurationSeconds: trackDuration(entry.Track),
+		})
+	}
+	return items
+}
+
+// trackDuration durée de lecture de la track, la durée par défaut si elle n'est pas renseignée
+func trackDuration(track models.Track) int {
+	if track.DurationSeconds <= 0 {
+		return constants.DefaultTrackDurationSeconds
+	}
+	return track.DurationSeconds
+}
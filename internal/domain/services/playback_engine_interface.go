@@ -0,0 +1,19 @@
+package services
+
+import (
+	"context"
+	"radioking-app/internal/domain/models"
+)
+
+type IPlaybackEngine interface {
+	Start(ctx context.Context) error
+	Play(playlist models.Playlist, options models.PlayOptions) (*models.PlaySession, error)
+	GetSession(id int64) (*models.PlaySession, error)
+	Pause(id int64) (*models.PlaySession, error)
+	Resume(id int64) (*models.PlaySession, error)
+	Skip(id int64) (*models.PlaySession, error)
+	Back(id int64) (*models.PlaySession, error)
+	Stop(id int64) (*models.PlaySession, error)
+	InsertTrack(id int64, track models.Track) (*models.PlaySession, error)
+	Wait()
+}
@@ -0,0 +1,481 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"radioking-app/internal/domain/constants"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockPlaySessionRepository is a mock implementation of IPlaySessionRepository.
+// Les événements enregistrés dans l'outbox avec la session sont décodés dans events, endedEvents et playedSessions.
+type MockPlaySessionRepository struct {
+	mock.Mock
+	events         []models.TrackPlayedEvent
+	endedEvents    []models.TrackEndedEvent
+	playedSessions []models.PlaylistPlayedEvent
+}
+
+func (m *MockPlaySessionRepository) Create(session *models.PlaySession) error {
+	args := m.Called(session)
+	session.ID = 1
+	return args.Error(0)
+}
+
+func (m *MockPlaySessionRepository) GetByID(id int64) (*models.PlaySession, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PlaySession), args.Error(1)
+}
+
+func (m *MockPlaySessionRepository) Update(session *models.PlaySession, events ...models.OutboxMessage) error {
+	args := m.Called(session)
+	if args.Error(0) != nil {
+		return args.Error(0)
+	}
+
+	for _, message := range events {
+		envelope, _ := message.Event()
+		switch envelope.Type {
+		case models.EventTrackPlayed:
+			var event models.TrackPlayedEvent
+			_ = envelope.DecodePayload(&event)
+			m.events = append(m.events, event)
+		case models.EventTrackEnded:
+			var event models.TrackEndedEvent
+			_ = envelope.DecodePayload(&event)
+			m.endedEvents = append(m.endedEvents, event)
+		case models.EventPlaylistPlayed:
+			var event models.PlaylistPlayedEvent
+			_ = envelope.DecodePayload(&event)
+			m.playedSessions = append(m.playedSessions, event)
+		}
+	}
+	return nil
+}
+
+func (m *MockPlaySessionRepository) ListByStatus(statuses ...models.PlaySessionStatus) ([]*models.PlaySession, error) {
+	args := m.Called(statuses)
+	return args.Get(0).([]*models.PlaySession), args.Error(1)
+}
+
+// fakeClock avance instantanément du temps attendu
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// manualClock n'avance que sur demande et ne laisse jamais une track se terminer d'elle-même
+type manualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) After(time.Duration) <-chan time.Time { return make(chan time.Time) }
+
+func (c *manualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// signalClock est un manualClock dont la track courante se termine quand le test l'envoie sur ends
+type signalClock struct {
+	manualClock
+	ends chan time.Time
+}
+
+func (c *signalClock) After(time.Duration) <-chan time.Time { return c.ends }
+
+// End avance l'horloge de d et termine la track courante
+func (c *signalClock) End(d time.Duration) {
+	c.Advance(d)
+	c.ends <- c.Now()
+}
+
+func newTestPlaybackEngine(repo *MockPlaySessionRepository, c clock) *PlaybackEngine {
+	engine := NewPlaybackEngine(repo, nil)
+	engine.clock = c
+	return engine
+}
+
+func TestPlaybackEngine_Play_PublishesEachTrackAtItsStart(t *testing.T) {
+	// Arrange
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	repo := new(MockPlaySessionRepository)
+	repo.On("Create", mock.Anything).Return(nil)
+	repo.On("Update", mock.Anything).Return(nil)
+	engine := newTestPlaybackEngine(repo, &fakeClock{now: start})
+
+	playlist := models.Playlist{ID: 1, Tracks: []models.PlaylistTrack{
+		{TrackID: 10, Position: 0, Track: models.Track{ID: 10, Title: "Song 1", Artist: "Artist 1", DurationSeconds: 200}},
+		{TrackID: 11, Position: 1, Track: models.Track{ID: 11, Title: "Song 2", Artist: "Artist 2"}},
+	}}
+
+	// Act
+	session, err := engine.Play(playlist, models.PlayOptions{})
+	engine.Wait()
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, repo.events, 2)
+	require.Len(t, repo.endedEvents, 2)
+	assert.Equal(t, models.TrackEndCompleted, repo.endedEvents[0].Reason)
+	assert.Equal(t, 200, repo.endedEvents[0].PlayedSeconds)
+	assert.Equal(t, start, repo.events[0].PlayedAt)
+	assert.Equal(t, start.Add(200*time.Second), repo.events[1].PlayedAt)
+	assert.Equal(t, 180, repo.events[1].DurationSeconds)
+	assert.Equal(t, session.ID, repo.events[1].SessionID)
+	assert.Equal(t, models.PlaySessionFinished, session.Status)
+}
+
+func TestPlaybackEngine_Play_RecordsPlaylistPlayedWithFirstTrack(t *testing.T) {
+	// Arrange
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	repo := new(MockPlaySessionRepository)
+	repo.On("Create", mock.Anything).Return(nil)
+	repo.On("Update", mock.Anything).Return(nil)
+	engine := newTestPlaybackEngine(repo, &manualClock{now: start})
+
+	playlist := models.Playlist{ID: 4, Tracks: []models.PlaylistTrack{
+		{TrackID: 10, Position: 0, Track: models.Track{ID: 10, Title: "Song 1", DurationSeconds: 200}},
+		{TrackID: 11, Position: 1, Track: models.Track{ID: 11, Title: "Song 2", DurationSeconds: 200}},
+	}}
+
+	// Act
+	session, err := engine.Play(playlist, models.PlayOptions{Repeat: models.RepeatAll})
+
+	// Assert : le démarrage de la session est enregistré avec la première track, dans la même mise à jour
+	require.NoError(t, err)
+	repo.AssertNumberOfCalls(t, "Update", 1)
+	require.Len(t, repo.playedSessions, 1)
+	require.Len(t, repo.events, 1)
+	assert.Equal(t, models.PlaylistPlayedEvent{
+		PlaylistID:  4,
+		SessionID:   session.ID,
+		TracksCount: 2,
+		Shuffle:     session.Shuffle,
+		Repeat:      models.RepeatAll,
+		TrackIDs:    []int64{10, 11},
+		PlayedAt:    start,
+	}, repo.playedSessions[0])
+
+	repo.On("GetByID", session.ID).Return(session, nil)
+	_, err = engine.Stop(session.ID)
+	require.NoError(t, err)
+	engine.Wait()
+}
+
+func TestPlaybackEngine_Play_EmptyPlaylistRecordsPlaylistPlayed(t *testing.T) {
+	// Arrange
+	repo := new(MockPlaySessionRepository)
+	repo.On("Create", mock.Anything).Return(nil)
+	repo.On("Update", mock.Anything).Return(nil)
+	engine := newTestPlaybackEngine(repo, &manualClock{now: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)})
+
+	// Act
+	session, err := engine.Play(models.Playlist{ID: 4}, models.PlayOptions{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, models.PlaySessionFinished, session.Status)
+	require.Len(t, repo.playedSessions, 1)
+	assert.Equal(t, 0, repo.playedSessions[0].TracksCount)
+	assert.Empty(t, repo.events)
+}
+
+func TestPlaybackEngine_Play_RepeatOneAndSeedInEvents(t *testing.T) {
+	// Arrange
+	repo := new(MockPlaySessionRepository)
+	repo.On("Create", mock.Anything).Return(nil)
+	repo.On("Update", mock.Anything).Return(nil)
+	c := &signalClock{manualClock: manualClock{now: time.Now()}, ends: make(chan time.Time)}
+	engine := newTestPlaybackEngine(repo, c)
+
+	seed := int64(7)
+	playlist := models.Playlist{ID: 1, Tracks: []models.PlaylistTrack{
+		{TrackID: 10, Track: models.Track{ID: 10, DurationSeconds: 180}},
+		{TrackID: 11, Track: models.Track{ID: 11, DurationSeconds: 180}},
+	}}
+
+	// Act
+	session, err := engine.Play(playlist, models.PlayOptions{Shuffle: models.ShuffleRandom, Repeat: models.RepeatOne, Seed: &seed})
+	require.NoError(t, err)
+	repo.On("GetByID", session.ID).Return(session, nil)
+	first := session.CurrentItem()
+	c.End(180 * time.Second)
+	_, err = engine.Stop(session.ID)
+	require.NoError(t, err)
+	engine.Wait()
+
+	// Assert : la track terminée est rejouée à la même position
+	require.Len(t, repo.events, 2)
+	require.NotEmpty(t, repo.endedEvents)
+	assert.Equal(t, models.TrackEndCompleted, repo.endedEvents[0].Reason)
+	for _, event := range repo.events {
+		assert.Equal(t, first.TrackID, event.TrackID)
+		assert.Equal(t, first.Position, event.Position)
+		assert.Equal(t, models.ShuffleRandom, event.Shuffle)
+		assert.Equal(t, models.RepeatOne, event.Repeat)
+		assert.Equal(t, &seed, event.Seed)
+	}
+	assert.Equal(t, repo.events[0].PlayedAt.Add(180*time.Second), repo.events[1].PlayedAt)
+
+	_, err = engine.Play(playlist, models.PlayOptions{Repeat: "forever"})
+	assert.ErrorIs(t, err, domainErrors.ErrInvalidPlayOptions)
+}
+
+func TestPlaybackEngine_Play_RecordsOrderArrangedForRotation(t *testing.T) {
+	// Arrange : 4 tracks de 10 minutes d'artistes différents, l'artiste de la première track
+	// du mélange seedé vient d'être joué
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	seed := int64(42)
+	options := models.PlayOptions{Shuffle: models.ShuffleRandom, Seed: &seed}
+	playlist := models.Playlist{ID: 1}
+	for i := int64(1); i <= 4; i++ {
+		playlist.Tracks = append(playlist.Tracks, models.PlaylistTrack{PlaylistID: 1, TrackID: i, Position: int(i - 1),
+			Track: models.Track{ID: i, ArtistID: i * 10, DurationSeconds: 600}})
+	}
+	seeded := trackIDs(orderPlaySessionItems(newPlaySessionItems(playlist), options))
+
+	ruleRepo := new(MockRotationRuleRepository)
+	ruleRepo.On("List", true).Return([]*models.RotationRule{artistSeparation}, nil)
+	playRepo := new(MockTrackPlayRepository)
+	playRepo.On("ListSince", now.Add(-30*time.Minute)).Return([]*models.TrackPlay{
+		{TrackID: 99, PlayedAt: now.Add(-5 * time.Minute), Track: models.Track{ArtistID: seeded[0] * 10}},
+	}, nil)
+	repo := new(MockPlaySessionRepository)
+	repo.On("Create", mock.Anything).Return(nil)
+	repo.On("Update", mock.Anything).Return(nil)
+	engine := NewPlaybackEngine(repo, NewRotationService(ruleRepo, playRepo, &PlaylistService{}))
+	engine.clock = &manualClock{now: now}
+
+	// Act
+	session, err := engine.Play(playlist, options)
+	require.NoError(t, err)
+	repo.On("GetByID", session.ID).Return(session, nil)
+	_, err = engine.Stop(session.ID)
+	require.NoError(t, err)
+	engine.Wait()
+
+	// Assert : la première track du mélange attend la fin de la fenêtre de 30 minutes de son artiste,
+	// et l'ordre joué est enregistré avec la session et son événement de démarrage
+	played := append(seeded[1:4:4], seeded[0])
+	assert.Equal(t, played, session.TrackIDs())
+	require.Len(t, repo.playedSessions, 1)
+	assert.Equal(t, played, repo.playedSessions[0].TrackIDs)
+	assert.Equal(t, &seed, repo.playedSessions[0].Seed)
+	require.NotEmpty(t, repo.events)
+	assert.Equal(t, played[0], repo.events[0].TrackID)
+}
+
+func TestPlaybackEngine_Start_ResumesInterruptedSession(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	startedAt := now.Add(-100 * time.Second)
+	session := &models.PlaySession{
+		ID:             5,
+		PlaylistID:     1,
+		Status:         models.PlaySessionPlaying,
+		CurrentIndex:   0,
+		TrackStartedAt: &startedAt,
+		TrackResumedAt: &startedAt,
+		Items: []models.PlaySessionItem{
+			{TrackID: 10, Position: 0, DurationSeconds: 180},
+			{TrackID: 11, Position: 1, DurationSeconds: 180},
+		},
+	}
+	repo := new(MockPlaySessionRepository)
+	repo.On("ListByStatus", []models.PlaySessionStatus{models.PlaySessionPlaying, models.PlaySessionPaused}).Return([]*models.PlaySession{session}, nil)
+	repo.On("Update", mock.Anything).Return(nil)
+	engine := newTestPlaybackEngine(repo, &fakeClock{now: now})
+
+	// Act
+	err := engine.Start(context.Background())
+	engine.Wait()
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, repo.events, 1, "la track déjà démarrée ne doit pas être republiée")
+	assert.Equal(t, int64(11), repo.events[0].TrackID)
+	assert.Equal(t, now.Add(80*time.Second), repo.events[0].PlayedAt)
+	assert.Equal(t, models.PlaySessionFinished, session.Status)
+}
+
+func TestPlaybackEngine_CancelKeepsSessionPlaying(t *testing.T) {
+	// Arrange
+	repo := new(MockPlaySessionRepository)
+	repo.On("ListByStatus", []models.PlaySessionStatus{models.PlaySessionPlaying, models.PlaySessionPaused}).Return([]*models.PlaySession{}, nil)
+	repo.On("Create", mock.Anything).Return(nil)
+	repo.On("Update", mock.Anything).Return(nil)
+	engine := newTestPlaybackEngine(repo, &manualClock{now: time.Now()})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, engine.Start(ctx))
+
+	playlist := models.Playlist{ID: 1, Tracks: []models.PlaylistTrack{
+		{TrackID: 10, Track: models.Track{ID: 10, DurationSeconds: 180}},
+	}}
+	session, err := engine.Play(playlist, models.PlayOptions{})
+	require.NoError(t, err)
+
+	// Act
+	cancel()
+	engine.Wait()
+
+	// Assert
+	assert.Equal(t, models.PlaySessionPlaying, session.Status)
+	assert.Equal(t, 0, session.CurrentIndex)
+	assert.NotNil(t, session.TrackStartedAt)
+}
+
+func TestPlaybackEngine_Stop(t *testing.T) {
+	// Arrange
+	repo := new(MockPlaySessionRepository)
+	repo.On("Create", mock.Anything).Return(nil)
+	repo.On("Update", mock.Anything).Return(nil)
+	engine := newTestPlaybackEngine(repo, &manualClock{now: time.Now()})
+
+	playlist := models.Playlist{ID: 1, Tracks: []models.PlaylistTrack{
+		{TrackID: 10, Track: models.Track{ID: 10, DurationSeconds: 180}},
+	}}
+	session, err := engine.Play(playlist, models.PlayOptions{})
+	require.NoError(t, err)
+	repo.On("GetByID", session.ID).Return(session, nil)
+
+	// Act
+	stopped, err := engine.Stop(session.ID)
+	engine.Wait()
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, models.PlaySessionStopped, stopped.Status)
+	require.Len(t, repo.endedEvents, 1)
+	assert.Equal(t, models.TrackEndStopped, repo.endedEvents[0].Reason)
+
+	_, err = engine.Stop(session.ID)
+	assert.ErrorIs(t, err, domainErrors.ErrPlaySessionNotActive)
+}
+
+func TestPlaybackEngine_PauseResumeSkipBack(t *testing.T) {
+	// Arrange
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	c := &manualClock{now: start}
+	repo := new(MockPlaySessionRepository)
+	repo.On("Create", mock.Anything).Return(nil)
+	repo.On("Update", mock.Anything).Return(nil)
+	engine := newTestPlaybackEngine(repo, c)
+
+	playlist := models.Playlist{ID: 1, Tracks: []models.PlaylistTrack{
+		{TrackID: 10, Position: 0, Track: models.Track{ID: 10, DurationSeconds: 180}},
+		{TrackID: 11, Position: 1, Track: models.Track{ID: 11, DurationSeconds: 180}},
+	}}
+	session, err := engine.Play(playlist, models.PlayOptions{})
+	require.NoError(t, err)
+	repo.On("GetByID", session.ID).Return(session, nil)
+
+	// Act & Assert : pause après 30 secondes, le temps écoulé est figé
+	c.Advance(30 * time.Second)
+	paused, err := engine.Pause(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.PlaySessionPaused, paused.Status)
+
+	_, err = engine.Pause(session.ID)
+	assert.ErrorIs(t, err, domainErrors.ErrPlaySessionNotPlaying)
+
+	c.Advance(100 * time.Second)
+	resumed, err := engine.Resume(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.PlaySessionPlaying, resumed.Status)
+	assert.Equal(t, 30*time.Second, resumed.Elapsed(c.Now()))
+
+	// Skip après 40 secondes de lecture effective
+	c.Advance(10 * time.Second)
+	skipped, err := engine.Skip(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, skipped.CurrentIndex)
+	require.Len(t, repo.endedEvents, 1)
+	assert.Equal(t, models.TrackEndSkipped, repo.endedEvents[0].Reason)
+	assert.Equal(t, 40, repo.endedEvents[0].PlayedSeconds)
+	assert.Equal(t, 1, repo.endedEvents[0].Sequence)
+
+	// Retour sur la première track, rejouée sous un nouveau rang
+	back, err := engine.Back(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, back.CurrentIndex)
+	require.Len(t, repo.events, 3)
+	assert.Equal(t, int64(10), repo.events[2].TrackID)
+	assert.Equal(t, 3, repo.events[2].Sequence)
+	assert.Equal(t, models.TrackEndBack, repo.endedEvents[1].Reason)
+
+	_, err = engine.Stop(session.ID)
+	require.NoError(t, err)
+	engine.Wait()
+	assert.Equal(t, models.PlaySessionStopped, session.Status)
+}
+
+func TestPlaybackEngine_InsertTrackPlaysAfterCurrentTrack(t *testing.T) {
+	// Arrange
+	repo := new(MockPlaySessionRepository)
+	repo.On("Create", mock.Anything).Return(nil)
+	repo.On("Update", mock.Anything).Return(nil)
+	engine := newTestPlaybackEngine(repo, &manualClock{now: time.Now()})
+
+	playlist := models.Playlist{ID: 1, Tracks: []models.PlaylistTrack{
+		{TrackID: 10, Position: 0, Track: models.Track{ID: 10, DurationSeconds: 180}},
+		{TrackID: 11, Position: 1, Track: models.Track{ID: 11, DurationSeconds: 180}},
+	}}
+	session, err := engine.Play(playlist, models.PlayOptions{})
+	require.NoError(t, err)
+	repo.On("GetByID", session.ID).Return(session, nil)
+
+	// Act
+	inserted, err := engine.InsertTrack(session.ID, models.Track{ID: 20, Title: "Live", Artist: "Guest"})
+	require.NoError(t, err)
+	_, err = engine.Skip(session.ID)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, inserted.Items, 3)
+	assert.Equal(t, int64(20), inserted.Items[1].TrackID)
+	assert.Equal(t, 1, inserted.Items[1].Position)
+	assert.Equal(t, constants.DefaultTrackDurationSeconds, inserted.Items[1].DurationSeconds)
+	require.Len(t, repo.events, 2)
+	assert.Equal(t, int64(20), repo.events[1].TrackID)
+	assert.Equal(t, int64(1), repo.events[1].PlaylistID)
+
+	_, err = engine.Stop(session.ID)
+	require.NoError(t, err)
+	engine.Wait()
+}
@@ -2,16 +2,22 @@ package services
 
 import (
 	"fmt"
+	"radioking-app/internal/domain/models"
 )
 
 type PlayPlaylistResult struct {
 	PlaylistID  int
+	SessionID   int64
 	TracksCount int
+	Shuffle     models.ShuffleMode
+	Repeat      models.RepeatMode
+	Seed        *int64
+	TrackIDs    []int64 // Ordre de la file, mélangée puis réordonnée selon les règles de rotation
 	Message     string
 }
 
 type IPlaylistApplicationService interface {
-	PlayPlaylist(playlistID int) (*PlayPlaylistResult, error)
+	PlayPlaylist(playlistID int, options models.PlayOptions) (*PlayPlaylistResult, error)
 }
 
 type PlaylistApplicationService struct {
@@ -29,21 +35,26 @@ func NewPlaylistApplicationService(
 	}
 }
 
-func (s *PlaylistApplicationService) PlayPlaylist(playlistID int) (*PlayPlaylistResult, error) {
+func (s *PlaylistApplicationService) PlayPlaylist(playlistID int, options models.PlayOptions) (*PlayPlaylistResult, error) {
 
 	playlist, err := s.playlistService.GetPlaylist(playlistID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get playlist %d: %w", playlistID, err)
 	}
 
-	err = s.playlistPlayService.PlayPlaylist(*playlist)
+	session, err := s.playlistPlayService.PlayPlaylist(*playlist, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to play playlist %d: %w", playlistID, err)
 	}
 
 	return &PlayPlaylistResult{
 		PlaylistID:  playlistID,
+		SessionID:   session.ID,
 		TracksCount: len(playlist.Tracks),
+		Shuffle:     session.Shuffle,
+		Repeat:      session.Repeat,
+		Seed:        session.Seed,
+		TrackIDs:    session.TrackIDs(),
 		Message:     "Playlist is being played",
 	}, nil
 }
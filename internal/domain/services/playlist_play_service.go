@@ -1,64 +1,38 @@
 package services
 
 import (
-	"fmt"
 	"log"
 	"radioking-app/internal/domain/models"
-	"radioking-app/internal/infrastructure/messaging"
-	"time"
-
-	"github.com/google/uuid"
 )
 
 type PlaylistPlayService struct {
-	playlistService  IPlaylistService
-	messagePublisher messaging.MessagePublisher
+	playlistService IPlaylistService
+	engine          IPlaybackEngine
 }
 
-func NewPlaylistPlayService(playlistService IPlaylistService, publisher messaging.MessagePublisher) *PlaylistPlayService {
+func NewPlaylistPlayService(playlistService IPlaylistService, engine IPlaybackEngine) *PlaylistPlayService {
 	return &PlaylistPlayService{
-		playlistService:  playlistService,
-		messagePublisher: publisher,
+		playlistService: playlistService,
+		engine:          engine,
 	}
 }
 
-func (s *PlaylistPlayService) PlayPlaylist(playlist models.Playlist) error {
+// PlayPlaylist lance la lecture en temps réel de la playlist, les événements sont publiés
+// au démarrage effectif de chaque track
+func (s *PlaylistPlayService) PlayPlaylist(playlist models.Playlist, options models.PlayOptions) (*models.PlaySession, error) {
 
 	if len(playlist.Tracks) == 0 {
 		log.Printf("Playlist %d is empty, nothing to play", playlist.ID)
-		return nil
+	} else {
+		log.Printf("Starting to play playlist %d with %d tracks", playlist.ID, len(playlist.Tracks))
 	}
 
-	log.Printf("Starting to play playlist %d with %d tracks", playlist.ID, len(playlist.Tracks))
-
-	err := sendTracksEvents(playlist, s)
+	session, err := s.engine.Play(playlist, options)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	log.Printf("Successfully published %d track events for playlist %d", len(playlist.Tracks), playlist.ID)
-	return nil
-}
-
-func sendTracksEvents(playlist models.Playlist, s *PlaylistPlayService) error {
-	for position, track := range playlist.Tracks {
-		event := models.TrackPlayedEvent{
-			PlaylistID: playlist.ID,
-			TrackID:    track.ID,
-			TrackTitle: track.Title,
-			Artist:     track.Artist,
-			Position:   position, // 0-based position
-			PlayedAt:   time.Now(),
-			EventID:    uuid.New().String(),
-		}
-
-		err := s.messagePublisher.PublishTrackPlayedEvent(event)
-		if err != nil {
-			log.Printf("Failed to publish event for track %d (position %d): %v", track.ID, position, err)
-			return fmt.Errorf("failed to publish event for track %d: %w", track.ID, err)
-		}
-
-		log.Printf("Published event for track '%s' by '%s' at position %d", track.Title, track.Artist, position)
-	}
-	return nil
+	log.Printf("Play session %d started for playlist %d (shuffle=%s, repeat=%s)",
+		session.ID, playlist.ID, session.Shuffle, session.Repeat)
+	return session, nil
 }
@@ -3,5 +3,5 @@ package services
 import "radioking-app/internal/domain/models"
 
 type IPlaylistPlayService interface {
-	PlayPlaylist(playlist models.Playlist) error
+	PlayPlaylist(playlist models.Playlist, options models.PlayOptions) (*models.PlaySession, error)
 }
@@ -1,19 +1,29 @@
 package services
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"radioking-app/internal/domain/constants"
 	domainErrors "radioking-app/internal/domain/errors"
 	"radioking-app/internal/domain/models"
 	"radioking-app/internal/infrastructure/repositories"
+	"regexp"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// isrcPattern code ISRC normalisé : pays, déclarant, année et numéro d'enregistrement
+var isrcPattern = regexp.MustCompile(`^[A-Z]{2}[A-Z0-9]{3}[0-9]{7}$`)
+
 type PlaylistService struct {
 	Repo repositories.IPlaylistRepository
+	// RecordEvents enregistre les événements de création, modification et suppression des playlists
+	// dans l'outbox, dans la transaction du changement
+	RecordEvents bool
 }
 
 func (service *PlaylistService) CreatePlaylist(playlist *models.Playlist) error {
@@ -21,28 +31,34 @@ func (service *PlaylistService) CreatePlaylist(playlist *models.Playlist) error
 		return err
 	}
 
-	if err := service.Repo.Create(playlist); err != nil {
-		return domainErrors.NewInternalError("failed to create playlist", err)
-	}
+	assignTrackPositions(playlist)
 
+	if err := service.Repo.Create(playlist, service.changedEvents(models.EventPlaylistCreated)); err != nil {
+		return playlistTracksError("failed to create playlist", err)
+	}
 	return nil
 }
 
 func (service *PlaylistService) validatePlaylist(playlist *models.Playlist) error {
-	if strings.TrimSpace(playlist.Name) == "" {
-		return domainErrors.ErrEmptyPlaylistName
-	}
-
-	if len(playlist.Name) > constants.MaxPlaylistNameLength {
-		return domainErrors.NewValidationError(fmt.Sprintf("playlist name too long (max %d characters)", constants.MaxPlaylistNameLength))
+	if err := validatePlaylistName(playlist.Name); err != nil {
+		return err
 	}
 
 	if len(playlist.Tracks) > constants.MaxTracksPerPlaylist {
 		return domainErrors.ErrTooManyTracks
 	}
 
-	for i, track := range playlist.Tracks {
-		if err := service.validateTrack(&track); err != nil {
+	referenced := make(map[int64]bool, len(playlist.Tracks))
+	for i, entry := range playlist.Tracks {
+		// Une track référencée par ID existe déjà dans le catalogue, seule son unicité est vérifiée
+		if entry.TrackID != 0 {
+			if referenced[entry.TrackID] {
+				return domainErrors.ErrTrackAlreadyInPlaylist
+			}
+			referenced[entry.TrackID] = true
+			continue
+		}
+		if err := service.validateTrack(&entry.Track); err != nil {
 			return domainErrors.NewValidationError(fmt.Sprintf("track %d invalid: %s", i+1, err.Error()))
 		}
 	}
@@ -51,6 +67,10 @@ func (service *PlaylistService) validatePlaylist(playlist *models.Playlist) erro
 }
 
 func (service *PlaylistService) validateTrack(track *models.Track) error {
+	return validateTrack(track)
+}
+
+func validateTrack(track *models.Track) error {
 	if strings.TrimSpace(track.Title) == "" {
 		return domainErrors.ErrEmptyTrackTitle
 	}
@@ -63,15 +83,83 @@ func (service *PlaylistService) validateTrack(track *models.Track) error {
 	if len(track.Artist) > constants.MaxArtistNameLength {
 		return domainErrors.NewValidationError(fmt.Sprintf("artist name too long (max %d characters)", constants.MaxArtistNameLength))
 	}
+	if track.DurationSeconds < 0 || track.DurationSeconds > constants.MaxTrackDurationSeconds {
+		return domainErrors.ErrInvalidTrackDuration
+	}
+	if track.ISRC != "" && !isrcPattern.MatchString(track.ISRC) {
+		return domainErrors.ErrInvalidISRC
+	}
+	if track.Album != nil && len(track.Album.Title) > constants.MaxAlbumTitleLength {
+		return domainErrors.NewValidationError(fmt.Sprintf("album title too long (max %d characters)", constants.MaxAlbumTitleLength))
+	}
 	return nil
 }
 
-func (service *PlaylistService) ListPlaylists() ([]*models.Playlist, error) {
-	playlists, err := service.Repo.GetAll()
+func (service *PlaylistService) ListPlaylists(query models.PlaylistListQuery) (*models.PlaylistPage, error) {
+	if query.Limit == 0 {
+		query.Limit = constants.DefaultPageSize
+	}
+	if query.Limit < 0 || query.Limit > constants.MaxPageSize {
+		return nil, domainErrors.ErrInvalidPageLimit
+	}
+
+	if query.SortBy == "" {
+		query.SortBy = models.PlaylistSortCreatedAt
+	}
+	if query.Order == "" {
+		query.Order = models.SortOrderAsc
+	}
+	if (query.SortBy != models.PlaylistSortCreatedAt && query.SortBy != models.PlaylistSortUpdatedAt) ||
+		(query.Order != models.SortOrderAsc && query.Order != models.SortOrderDesc) {
+		return nil, domainErrors.ErrInvalidSort
+	}
+
+	var after *models.PlaylistCursor
+	if query.Cursor != "" {
+		cursor, err := decodePlaylistCursor(query.Cursor)
+		if err != nil {
+			return nil, domainErrors.ErrInvalidCursor
+		}
+		after = cursor
+	}
+
+	// Une playlist de plus que demandé permet de savoir s'il existe une page suivante
+	limit := query.Limit
+	query.Limit++
+	playlists, err := service.Repo.List(query, after)
 	if err != nil {
 		return nil, domainErrors.NewInternalError("failed to list playlists", err)
 	}
-	return playlists, nil
+
+	page := &models.PlaylistPage{Playlists: playlists}
+	if len(playlists) > limit {
+		page.Playlists = playlists[:limit]
+		last := page.Playlists[limit-1]
+		sortValue := last.CreatedAt
+		if query.SortBy == models.PlaylistSortUpdatedAt {
+			sortValue = last.UpdatedAt
+		}
+		page.NextCursor = encodePlaylistCursor(models.PlaylistCursor{SortValue: sortValue, ID: last.ID})
+	}
+	return page, nil
+}
+
+func encodePlaylistCursor(cursor models.PlaylistCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodePlaylistCursor(encoded string) (*models.PlaylistCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor models.PlaylistCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
 }
 
 func (service *PlaylistService) GetPlaylist(id int) (*models.Playlist, error) {
@@ -88,3 +176,218 @@ func (service *PlaylistService) GetPlaylist(id int) (*models.Playlist, error) {
 	}
 	return playlist, nil
 }
+
+func (service *PlaylistService) UpdatePlaylist(id int, playlist *models.Playlist) error {
+	existing, err := service.GetPlaylist(id)
+	if err != nil {
+		return err
+	}
+
+	if err := service.validatePlaylist(playlist); err != nil {
+		return err
+	}
+
+	playlist.ID = existing.ID
+	playlist.CreatedAt = existing.CreatedAt
+	assignTrackPositions(playlist)
+
+	if err := service.Repo.Update(playlist, service.changedEvents(models.EventPlaylistUpdated)); err != nil {
+		return playlistTracksError("failed to update playlist", err)
+	}
+	return nil
+}
+
+func validatePlaylistName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return domainErrors.ErrEmptyPlaylistName
+	}
+
+	if len(name) > constants.MaxPlaylistNameLength {
+		return domainErrors.NewValidationError(fmt.Sprintf("playlist name too long (max %d characters)", constants.MaxPlaylistNameLength))
+	}
+	return nil
+}
+
+// RenamePlaylist modifie le nom de la playlist seul, ses tracks ne sont pas réécrites
+func (service *PlaylistService) RenamePlaylist(id int, name string) (*models.Playlist, error) {
+	if err := validatePlaylistName(name); err != nil {
+		return nil, err
+	}
+
+	playlist, err := service.GetPlaylist(id)
+	if err != nil {
+		return nil, err
+	}
+
+	playlist.Name = name
+	if err := service.Repo.Rename(playlist, service.changedEvents(models.EventPlaylistUpdated)); err != nil {
+		return nil, domainErrors.NewInternalError("failed to rename playlist", err)
+	}
+	return playlist, nil
+}
+
+func (service *PlaylistService) DeletePlaylist(id int) error {
+	if id <= 0 {
+		return domainErrors.ErrInvalidPlaylistID
+	}
+
+	if err := service.Repo.Delete(id, service.deletedEvents()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domainErrors.ErrPlaylistNotFound
+		}
+		return domainErrors.NewInternalError("failed to delete playlist", err)
+	}
+	return nil
+}
+
+func (service *PlaylistService) AddTrack(playlistID int, entry *models.PlaylistTrack, position *int) error {
+	playlist, err := service.GetPlaylist(playlistID)
+	if err != nil {
+		return err
+	}
+
+	if entry.TrackID == 0 {
+		if err := service.validateTrack(&entry.Track); err != nil {
+			return err
+		}
+	}
+
+	// Sans position la track est ajoutée en fin de playlist
+	entry.Position = len(playlist.Tracks)
+	if position != nil {
+		if *position < 0 || *position > len(playlist.Tracks) {
+			return domainErrors.ErrInvalidTrackPosition
+		}
+		entry.Position = *position
+	}
+	entry.PlaylistID = playlist.ID
+
+	if err := service.Repo.AddTrack(entry, constants.MaxTracksPerPlaylist, service.changedEvents(models.EventPlaylistUpdated)); err != nil {
+		return playlistTracksError("failed to add track", err)
+	}
+	return nil
+}
+
+func (service *PlaylistService) RemoveTrack(playlistID int, trackID int) error {
+	entry, err := service.getPlaylistTrack(playlistID, trackID)
+	if err != nil {
+		return err
+	}
+
+	if err := service.Repo.RemoveTrack(entry, service.changedEvents(models.EventPlaylistUpdated)); err != nil {
+		return domainErrors.NewInternalError("failed to remove track", err)
+	}
+	return nil
+}
+
+func (service *PlaylistService) MoveTrack(playlistID int, trackID int, position int) (*models.Playlist, error) {
+	playlist, err := service.GetPlaylist(playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := findPlaylistTrack(playlist, trackID)
+	if entry == nil {
+		return nil, domainErrors.ErrTrackNotFound
+	}
+
+	if position < 0 || position >= len(playlist.Tracks) {
+		return nil, domainErrors.ErrInvalidTrackPosition
+	}
+
+	if err := service.Repo.MoveTrack(entry, position, service.changedEvents(models.EventPlaylistUpdated)); err != nil {
+		return nil, domainErrors.NewInternalError("failed to move track", err)
+	}
+	return service.GetPlaylist(playlistID)
+}
+
+// changedEvents retourne les événements de modification à enregistrer, aucun si RecordEvents est désactivé
+func (service *PlaylistService) changedEvents(eventType models.EventType) repositories.PlaylistEvents {
+	if !service.RecordEvents {
+		return nil
+	}
+	return playlistChangedEvents(eventType)
+}
+
+// playlistChangedEvents construit, dans la transaction du changement, l'événement portant l'état de la
+// playlist, tracks comprises, après sa création ou sa modification. Un échec annule le changement.
+func playlistChangedEvents(eventType models.EventType) repositories.PlaylistEvents {
+	return func(playlist *models.Playlist) ([]models.OutboxMessage, error) {
+		trackIDs := make([]int64, 0, len(playlist.Tracks))
+		for _, entry := range playlist.Tracks {
+			trackIDs = append(trackIDs, entry.TrackID)
+		}
+		message, err := newOutboxMessage(eventType, time.Now(), playlist.ID, models.PlaylistChangedEvent{
+			PlaylistID: playlist.ID,
+			Name:       playlist.Name,
+			TrackIDs:   trackIDs,
+			UpdatedAt:  playlist.UpdatedAt.UTC(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []models.OutboxMessage{message}, nil
+	}
+}
+
+// deletedEvents construit, dans la transaction de la suppression, l'événement de la playlist supprimée
+func (service *PlaylistService) deletedEvents() repositories.PlaylistEvents {
+	if !service.RecordEvents {
+		return nil
+	}
+
+	return func(playlist *models.Playlist) ([]models.OutboxMessage, error) {
+		deletedAt := time.Now().UTC()
+		message, err := newOutboxMessage(models.EventPlaylistDeleted, deletedAt, playlist.ID, models.PlaylistDeletedEvent{
+			PlaylistID: playlist.ID,
+			DeletedAt:  deletedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []models.OutboxMessage{message}, nil
+	}
+}
+
+func (service *PlaylistService) getPlaylistTrack(playlistID int, trackID int) (*models.PlaylistTrack, error) {
+	playlist, err := service.GetPlaylist(playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := findPlaylistTrack(playlist, trackID)
+	if entry == nil {
+		return nil, domainErrors.ErrTrackNotFound
+	}
+	return entry, nil
+}
+
+// findPlaylistTrack retrouve l'entrée de la playlist correspondant à la track du catalogue
+func findPlaylistTrack(playlist *models.Playlist, trackID int) *models.PlaylistTrack {
+	for i := range playlist.Tracks {
+		if playlist.Tracks[i].TrackID == int64(trackID) {
+			return &playlist.Tracks[i]
+		}
+	}
+	return nil
+}
+
+// assignTrackPositions aligne la position persistée des tracks sur leur ordre dans la playlist
+func assignTrackPositions(playlist *models.Playlist) {
+	for i := range playlist.Tracks {
+		playlist.Tracks[i].Position = i
+	}
+}
+
+// playlistTracksError traduit les erreurs du repository liées à la résolution des tracks
+func playlistTracksError(message string, err error) error {
+	switch {
+	case errors.Is(err, repositories.ErrTrackAlreadyInPlaylist):
+		return domainErrors.ErrTrackAlreadyInPlaylist
+	case errors.Is(err, repositories.ErrTooManyTracks):
+		return domainErrors.ErrTooManyTracks
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return domainErrors.ErrTrackNotFound
+	}
+	return domainErrors.NewInternalError(message, err)
+}
@@ -4,6 +4,12 @@ import "radioking-app/internal/domain/models"
 
 type IPlaylistService interface {
 	CreatePlaylist(playlist *models.Playlist) error
-	ListPlaylists() ([]*models.Playlist, error)
+	ListPlaylists(query models.PlaylistListQuery) (*models.PlaylistPage, error)
 	GetPlaylist(id int) (*models.Playlist, error)
+	UpdatePlaylist(id int, playlist *models.Playlist) error
+	RenamePlaylist(id int, name string) (*models.Playlist, error)
+	DeletePlaylist(id int) error
+	AddTrack(playlistID int, entry *models.PlaylistTrack, position *int) error
+	RemoveTrack(playlistID int, trackID int) error
+	MoveTrack(playlistID int, trackID int, position int) (*models.Playlist, error)
 }
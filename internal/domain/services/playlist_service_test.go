@@ -2,29 +2,34 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"radioking-app/internal/domain/constants"
 	domainErrors "radioking-app/internal/domain/errors"
 	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/repositories"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
 )
 
-// MockPlaylistRepository est un mock du repository
+// MockPlaylistRepository est un mock du repository. Les événements enregistrés avec une modification
+// réussie sont décodés dans events.
 type MockPlaylistRepository struct {
 	mock.Mock
+	events []models.Event
 }
 
-func (m *MockPlaylistRepository) Create(playlist *models.Playlist) error {
+func (m *MockPlaylistRepository) Create(playlist *models.Playlist, events repositories.PlaylistEvents) error {
 	args := m.Called(playlist)
-	return args.Error(0)
+	return m.record(args.Error(0), playlist, events)
 }
 
-func (m *MockPlaylistRepository) GetAll() ([]*models.Playlist, error) {
-	args := m.Called()
+func (m *MockPlaylistRepository) List(query models.PlaylistListQuery, after *models.PlaylistCursor) ([]*models.Playlist, error) {
+	args := m.Called(query, after)
 	return args.Get(0).([]*models.Playlist), args.Error(1)
 }
 
@@ -36,6 +41,59 @@ func (m *MockPlaylistRepository) GetByID(id int) (*models.Playlist, error) {
 	return args.Get(0).(*models.Playlist), args.Error(1)
 }
 
+func (m *MockPlaylistRepository) Update(playlist *models.Playlist, events repositories.PlaylistEvents) error {
+	args := m.Called(playlist)
+	return m.record(args.Error(0), playlist, events)
+}
+
+func (m *MockPlaylistRepository) Rename(playlist *models.Playlist, events repositories.PlaylistEvents) error {
+	args := m.Called(playlist)
+	return m.record(args.Error(0), playlist, events)
+}
+
+func (m *MockPlaylistRepository) Delete(id int, events repositories.PlaylistEvents) error {
+	args := m.Called(id)
+	return m.record(args.Error(0), &models.Playlist{ID: int64(id)}, events)
+}
+
+func (m *MockPlaylistRepository) AddTrack(entry *models.PlaylistTrack, maxTracks int, events repositories.PlaylistEvents) error {
+	args := m.Called(entry, maxTracks)
+	return m.record(args.Error(0), &models.Playlist{ID: entry.PlaylistID}, events)
+}
+
+func (m *MockPlaylistRepository) RemoveTrack(entry *models.PlaylistTrack, events repositories.PlaylistEvents) error {
+	args := m.Called(entry)
+	return m.record(args.Error(0), &models.Playlist{ID: entry.PlaylistID}, events)
+}
+
+func (m *MockPlaylistRepository) MoveTrack(entry *models.PlaylistTrack, position int, events repositories.PlaylistEvents) error {
+	args := m.Called(entry, position)
+	return m.record(args.Error(0), &models.Playlist{ID: entry.PlaylistID}, events)
+}
+
+// record construit les événements de la modification réussie à partir de playlist, comme la transaction du repository
+func (m *MockPlaylistRepository) record(err error, playlist *models.Playlist, events repositories.PlaylistEvents) error {
+	if err != nil || events == nil {
+		return err
+	}
+	messages, err := events(playlist)
+	if err != nil {
+		return err
+	}
+	return m.decode(messages)
+}
+
+func (m *MockPlaylistRepository) decode(messages []models.OutboxMessage) error {
+	for _, message := range messages {
+		event, err := message.Event()
+		if err != nil {
+			return err
+		}
+		m.events = append(m.events, event)
+	}
+	return nil
+}
+
 func TestPlaylistService_CreatePlaylist_Success(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockPlaylistRepository)
@@ -43,8 +101,8 @@ func TestPlaylistService_CreatePlaylist_Success(t *testing.T) {
 
 	playlist := &models.Playlist{
 		Name: "Test Playlist",
-		Tracks: []models.Track{
-			{Title: "Song 1", Artist: "Artist 1"},
+		Tracks: []models.PlaylistTrack{
+			{Track: models.Track{Title: "Song 1", Artist: "Artist 1"}},
 		},
 	}
 
@@ -65,7 +123,7 @@ func TestPlaylistService_CreatePlaylist_EmptyName(t *testing.T) {
 
 	playlist := &models.Playlist{
 		Name:   "",
-		Tracks: []models.Track{},
+		Tracks: []models.PlaylistTrack{},
 	}
 
 	// Act
@@ -89,7 +147,7 @@ func TestPlaylistService_CreatePlaylist_NameTooLong(t *testing.T) {
 
 	playlist := &models.Playlist{
 		Name:   string(longName),
-		Tracks: []models.Track{},
+		Tracks: []models.PlaylistTrack{},
 	}
 
 	// Act
@@ -108,9 +166,9 @@ func TestPlaylistService_CreatePlaylist_TooManyTracks(t *testing.T) {
 	mockRepo := new(MockPlaylistRepository)
 	service := &PlaylistService{Repo: mockRepo}
 
-	tracks := make([]models.Track, constants.MaxTracksPerPlaylist+1)
+	tracks := make([]models.PlaylistTrack, constants.MaxTracksPerPlaylist+1)
 	for i := range tracks {
-		tracks[i] = models.Track{Title: "Song", Artist: "Artist"}
+		tracks[i] = models.PlaylistTrack{Track: models.Track{Title: "Song", Artist: "Artist"}}
 	}
 
 	playlist := &models.Playlist{
@@ -134,8 +192,8 @@ func TestPlaylistService_CreatePlaylist_InvalidTrack(t *testing.T) {
 
 	playlist := &models.Playlist{
 		Name: "Test Playlist",
-		Tracks: []models.Track{
-			{Title: "", Artist: "Artist 1"}, // Empty title
+		Tracks: []models.PlaylistTrack{
+			{Track: models.Track{Title: "", Artist: "Artist 1"}}, // Empty title
 		},
 	}
 
@@ -158,7 +216,7 @@ func TestPlaylistService_CreatePlaylist_RepoError(t *testing.T) {
 
 	playlist := &models.Playlist{
 		Name:   "Test Playlist",
-		Tracks: []models.Track{},
+		Tracks: []models.PlaylistTrack{},
 	}
 
 	expectedErr := errors.New("database error")
@@ -182,8 +240,8 @@ func TestPlaylistService_GetPlaylist_Success(t *testing.T) {
 
 	expectedPlaylist := &models.Playlist{
 		Name: "Test Playlist",
-		Tracks: []models.Track{
-			{Title: "Song 1", Artist: "Artist 1"},
+		Tracks: []models.PlaylistTrack{
+			{Track: models.Track{Title: "Song 1", Artist: "Artist 1"}},
 		},
 	}
 
@@ -273,28 +331,30 @@ func TestPlaylistService_ListPlaylists_Success(t *testing.T) {
 	expectedPlaylists := []*models.Playlist{
 		{
 			Name: "Playlist 1",
-			Tracks: []models.Track{
-				{Title: "Song 1", Artist: "Artist 1"},
+			Tracks: []models.PlaylistTrack{
+				{Track: models.Track{Title: "Song 1", Artist: "Artist 1"}},
 			},
 		},
 		{
 			Name: "Playlist 2",
-			Tracks: []models.Track{
-				{Title: "Song 2", Artist: "Artist 2"},
-				{Title: "Song 3", Artist: "Artist 3"},
+			Tracks: []models.PlaylistTrack{
+				{Track: models.Track{Title: "Song 2", Artist: "Artist 2"}},
+				{Track: models.Track{Title: "Song 3", Artist: "Artist 3"}},
 			},
 		},
 	}
 
-	mockRepo.On("GetAll").Return(expectedPlaylists, nil)
+	mockRepo.On("List", mock.AnythingOfType("models.PlaylistListQuery"), (*models.PlaylistCursor)(nil)).
+		Return(expectedPlaylists, nil)
 
 	// Act
-	result, err := service.ListPlaylists()
+	result, err := service.ListPlaylists(models.PlaylistListQuery{})
 
 	// Assert
 	assert.NoError(t, err)
-	assert.Equal(t, expectedPlaylists, result)
-	assert.Len(t, result, 2)
+	assert.Equal(t, expectedPlaylists, result.Playlists)
+	assert.Len(t, result.Playlists, 2)
+	assert.Empty(t, result.NextCursor)
 	mockRepo.AssertExpectations(t)
 }
 
@@ -304,15 +364,16 @@ func TestPlaylistService_ListPlaylists_EmptyResult(t *testing.T) {
 	service := &PlaylistService{Repo: mockRepo}
 
 	expectedPlaylists := []*models.Playlist{}
-	mockRepo.On("GetAll").Return(expectedPlaylists, nil)
+	mockRepo.On("List", mock.AnythingOfType("models.PlaylistListQuery"), (*models.PlaylistCursor)(nil)).
+		Return(expectedPlaylists, nil)
 
 	// Act
-	result, err := service.ListPlaylists()
+	result, err := service.ListPlaylists(models.PlaylistListQuery{})
 
 	// Assert
 	assert.NoError(t, err)
-	assert.Equal(t, expectedPlaylists, result)
-	assert.Len(t, result, 0)
+	assert.Equal(t, expectedPlaylists, result.Playlists)
+	assert.Len(t, result.Playlists, 0)
 	mockRepo.AssertExpectations(t)
 }
 
@@ -322,10 +383,11 @@ func TestPlaylistService_ListPlaylists_RepoError(t *testing.T) {
 	service := &PlaylistService{Repo: mockRepo}
 
 	expectedErr := errors.New("database connection failed")
-	mockRepo.On("GetAll").Return([]*models.Playlist(nil), expectedErr)
+	mockRepo.On("List", mock.AnythingOfType("models.PlaylistListQuery"), (*models.PlaylistCursor)(nil)).
+		Return([]*models.Playlist(nil), expectedErr)
 
 	// Act
-	result, err := service.ListPlaylists()
+	result, err := service.ListPlaylists(models.PlaylistListQuery{})
 
 	// Assert
 	assert.Error(t, err)
@@ -337,6 +399,371 @@ func TestPlaylistService_ListPlaylists_RepoError(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestPlaylistService_ListPlaylists_NextCursor(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockPlaylistRepository)
+	service := &PlaylistService{Repo: mockRepo}
+
+	createdAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	playlists := []*models.Playlist{
+		{ID: 1, Name: "Playlist 1", CreatedAt: createdAt},
+		{ID: 2, Name: "Playlist 2", CreatedAt: createdAt.Add(time.Minute)},
+		{ID: 3, Name: "Playlist 3", CreatedAt: createdAt.Add(2 * time.Minute)},
+	}
+
+	expectedQuery := models.PlaylistListQuery{
+		Limit:  3,
+		SortBy: models.PlaylistSortCreatedAt,
+		Order:  models.SortOrderAsc,
+	}
+	mockRepo.On("List", expectedQuery, (*models.PlaylistCursor)(nil)).Return(playlists, nil)
+
+	// Act
+	result, err := service.ListPlaylists(models.PlaylistListQuery{Limit: 2})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, result.Playlists, 2)
+	assert.NotEmpty(t, result.NextCursor)
+
+	cursor, err := decodePlaylistCursor(result.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), cursor.ID)
+	assert.True(t, createdAt.Add(time.Minute).Equal(cursor.SortValue))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPlaylistService_ListPlaylists_InvalidParameters(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   models.PlaylistListQuery
+		wantErr error
+	}{
+		{name: "limit too high", query: models.PlaylistListQuery{Limit: constants.MaxPageSize + 1}, wantErr: domainErrors.ErrInvalidPageLimit},
+		{name: "negative limit", query: models.PlaylistListQuery{Limit: -1}, wantErr: domainErrors.ErrInvalidPageLimit},
+		{name: "unknown sort", query: models.PlaylistListQuery{SortBy: "name"}, wantErr: domainErrors.ErrInvalidSort},
+		{name: "unknown order", query: models.PlaylistListQuery{Order: "up"}, wantErr: domainErrors.ErrInvalidSort},
+		{name: "malformed cursor", query: models.PlaylistListQuery{Cursor: "%%%"}, wantErr: domainErrors.ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPlaylistRepository)
+			service := &PlaylistService{Repo: mockRepo}
+
+			result, err := service.ListPlaylists(tt.query)
+
+			assert.Nil(t, result)
+			assert.Equal(t, tt.wantErr, err)
+			mockRepo.AssertNotCalled(t, "List")
+		})
+	}
+}
+
+func TestPlaylistService_UpdatePlaylist_Success(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockPlaylistRepository)
+	service := &PlaylistService{Repo: mockRepo}
+
+	existing := &models.Playlist{
+		ID:   1,
+		Name: "Old Name",
+		Tracks: []models.PlaylistTrack{
+			{PlaylistID: 1, TrackID: 10, Track: models.Track{ID: 10, Title: "Song 1", Artist: "Artist 1"}},
+		},
+	}
+	playlist := &models.Playlist{
+		Name: "New Name",
+		Tracks: []models.PlaylistTrack{
+			{TrackID: 10},
+			{Track: models.Track{Title: "Song 2", Artist: "Artist 2"}},
+		},
+	}
+
+	mockRepo.On("GetByID", 1).Return(existing, nil)
+	mockRepo.On("Update", playlist).Return(nil)
+
+	// Act
+	err := service.UpdatePlaylist(1, playlist)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), playlist.ID)
+	assert.Equal(t, 1, playlist.Tracks[1].Position)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPlaylistService_UpdatePlaylist_DuplicateTrack(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockPlaylistRepository)
+	service := &PlaylistService{Repo: mockRepo}
+
+	existing := &models.Playlist{ID: 1, Name: "Playlist"}
+	playlist := &models.Playlist{
+		Name: "Playlist",
+		Tracks: []models.PlaylistTrack{
+			{TrackID: 42},
+			{TrackID: 42},
+		},
+	}
+
+	mockRepo.On("GetByID", 1).Return(existing, nil)
+
+	// Act
+	err := service.UpdatePlaylist(1, playlist)
+
+	// Assert
+	assert.Equal(t, domainErrors.ErrTrackAlreadyInPlaylist, err)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestPlaylistService_UpdatePlaylist_UnknownTrack(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockPlaylistRepository)
+	service := &PlaylistService{Repo: mockRepo}
+
+	existing := &models.Playlist{ID: 1, Name: "Playlist"}
+	playlist := &models.Playlist{
+		Name:   "Playlist",
+		Tracks: []models.PlaylistTrack{{TrackID: 42}},
+	}
+
+	mockRepo.On("GetByID", 1).Return(existing, nil)
+	mockRepo.On("Update", playlist).Return(fmt.Errorf("failed to load track 42: %w", gorm.ErrRecordNotFound))
+
+	// Act
+	err := service.UpdatePlaylist(1, playlist)
+
+	// Assert
+	assert.Equal(t, domainErrors.ErrTrackNotFound, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPlaylistService_UpdatePlaylist_EmptyName(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockPlaylistRepository)
+	service := &PlaylistService{Repo: mockRepo}
+
+	mockRepo.On("GetByID", 1).Return(&models.Playlist{ID: 1, Name: "Playlist"}, nil)
+
+	// Act
+	err := service.UpdatePlaylist(1, &models.Playlist{Name: "  "})
+
+	// Assert
+	assert.Equal(t, domainErrors.ErrEmptyPlaylistName, err)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestPlaylistService_UpdatePlaylist_NotFound(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockPlaylistRepository)
+	service := &PlaylistService{Repo: mockRepo}
+
+	mockRepo.On("GetByID", 999).Return(nil, gorm.ErrRecordNotFound)
+
+	// Act
+	err := service.UpdatePlaylist(999, &models.Playlist{Name: "Playlist"})
+
+	// Assert
+	assert.Equal(t, domainErrors.ErrPlaylistNotFound, err)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestPlaylistService_RenamePlaylist_Success(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockPlaylistRepository)
+	service := &PlaylistService{Repo: mockRepo}
+
+	existing := &models.Playlist{ID: 1, Name: "Old Name"}
+	mockRepo.On("GetByID", 1).Return(existing, nil)
+	mockRepo.On("Rename", existing).Return(nil)
+
+	// Act
+	result, err := service.RenamePlaylist(1, "New Name")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "New Name", result.Name)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPlaylistService_RenamePlaylist_EmptyName(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockPlaylistRepository)
+	service := &PlaylistService{Repo: mockRepo}
+
+	mockRepo.On("GetByID", 1).Return(&models.Playlist{ID: 1, Name: "Old Name"}, nil)
+
+	// Act
+	result, err := service.RenamePlaylist(1, "")
+
+	// Assert
+	assert.Nil(t, result)
+	assert.Equal(t, domainErrors.ErrEmptyPlaylistName, err)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestPlaylistService_DeletePlaylist_Success(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockPlaylistRepository)
+	service := &PlaylistService{Repo: mockRepo}
+
+	mockRepo.On("Delete", 1).Return(nil)
+
+	// Act
+	err := service.DeletePlaylist(1)
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPlaylistService_DeletePlaylist_NotFound(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockPlaylistRepository)
+	service := &PlaylistService{Repo: mockRepo}
+
+	mockRepo.On("Delete", 999).Return(gorm.ErrRecordNotFound)
+
+	// Act
+	err := service.DeletePlaylist(999)
+
+	// Assert
+	assert.Equal(t, domainErrors.ErrPlaylistNotFound, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPlaylistService_DeletePlaylist_InvalidID(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockPlaylistRepository)
+	service := &PlaylistService{Repo: mockRepo}
+
+	// Act
+	err := service.DeletePlaylist(0)
+
+	// Assert
+	assert.Equal(t, domainErrors.ErrInvalidPlaylistID, err)
+	mockRepo.AssertNotCalled(t, "Delete")
+}
+
+func TestPlaylistService_AddTrack_Append(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockPlaylistRepository)
+	service := &PlaylistService{Repo: mockRepo}
+
+	existing := &models.Playlist{
+		ID: 1,
+		Tracks: []models.PlaylistTrack{
+			{PlaylistID: 1, TrackID: 10, Position: 0, Track: models.Track{ID: 10, Title: "Song 1", Artist: "Artist 1"}},
+		},
+	}
+	entry := &models.PlaylistTrack{Track: models.Track{Title: "Song 2", Artist: "Artist 2"}}
+
+	mockRepo.On("GetByID", 1).Return(existing, nil)
+	mockRepo.On("AddTrack", entry, constants.MaxTracksPerPlaylist).Return(nil)
+
+	// Act
+	err := service.AddTrack(1, entry, nil)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), entry.PlaylistID)
+	assert.Equal(t, 1, entry.Position)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPlaylistService_AddTrack_InvalidPosition(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockPlaylistRepository)
+	service := &PlaylistService{Repo: mockRepo}
+
+	mockRepo.On("GetByID", 1).Return(&models.Playlist{ID: 1}, nil)
+	position := 2
+
+	// Act
+	err := service.AddTrack(1, &models.PlaylistTrack{Track: models.Track{Title: "Song", Artist: "Artist"}}, &position)
+
+	// Assert
+	assert.Equal(t, domainErrors.ErrInvalidTrackPosition, err)
+	mockRepo.AssertNotCalled(t, "AddTrack")
+}
+
+func TestPlaylistService_AddTrack_TooManyTracks(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockPlaylistRepository)
+	service := &PlaylistService{Repo: mockRepo}
+
+	// La playlist lue avant l'ajout a encore de la place, le dépôt compte les tracks dans sa transaction
+	mockRepo.On("GetByID", 1).Return(&models.Playlist{ID: 1}, nil)
+	mockRepo.On("AddTrack", mock.Anything, constants.MaxTracksPerPlaylist).Return(repositories.ErrTooManyTracks)
+
+	// Act
+	err := service.AddTrack(1, &models.PlaylistTrack{Track: models.Track{Title: "Song", Artist: "Artist"}}, nil)
+
+	// Assert
+	assert.Equal(t, domainErrors.ErrTooManyTracks, err)
+	assert.Empty(t, mockRepo.events)
+}
+
+func TestPlaylistService_MoveTrack_Success(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockPlaylistRepository)
+	service := &PlaylistService{Repo: mockRepo}
+
+	existing := &models.Playlist{
+		ID: 1,
+		Tracks: []models.PlaylistTrack{
+			{PlaylistID: 1, TrackID: 10, Position: 0, Track: models.Track{ID: 10, Title: "Song 1", Artist: "Artist 1"}},
+			{PlaylistID: 1, TrackID: 11, Position: 1, Track: models.Track{ID: 11, Title: "Song 2", Artist: "Artist 2"}},
+		},
+	}
+
+	mockRepo.On("GetByID", 1).Return(existing, nil)
+	mockRepo.On("MoveTrack", &existing.Tracks[1], 0).Return(nil)
+
+	// Act
+	_, err := service.MoveTrack(1, 11, 0)
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPlaylistService_MoveTrack_OutOfRange(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockPlaylistRepository)
+	service := &PlaylistService{Repo: mockRepo}
+
+	existing := &models.Playlist{
+		ID:     1,
+		Tracks: []models.PlaylistTrack{{PlaylistID: 1, TrackID: 10, Track: models.Track{ID: 10, Title: "Song 1", Artist: "Artist 1"}}},
+	}
+	mockRepo.On("GetByID", 1).Return(existing, nil)
+
+	// Act
+	result, err := service.MoveTrack(1, 10, 1)
+
+	// Assert
+	assert.Nil(t, result)
+	assert.Equal(t, domainErrors.ErrInvalidTrackPosition, err)
+	mockRepo.AssertNotCalled(t, "MoveTrack")
+}
+
+func TestPlaylistService_RemoveTrack_NotFound(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockPlaylistRepository)
+	service := &PlaylistService{Repo: mockRepo}
+
+	mockRepo.On("GetByID", 1).Return(&models.Playlist{ID: 1}, nil)
+
+	// Act
+	err := service.RemoveTrack(1, 42)
+
+	// Assert
+	assert.Equal(t, domainErrors.ErrTrackNotFound, err)
+	mockRepo.AssertNotCalled(t, "RemoveTrack")
+}
+
 // Tests for validation methods (private methods tested through public methods)
 
 func TestPlaylistService_ValidateTrack_EmptyTitle(t *testing.T) {
@@ -433,3 +860,52 @@ func TestPlaylistService_ValidateTrack_WhitespaceOnly(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, domainErrors.ErrEmptyTrackTitle, err)
 }
+
+func TestPlaylistService_EmitsPlaylistEvents(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockPlaylistRepository)
+	service := &PlaylistService{Repo: mockRepo, RecordEvents: true}
+
+	playlist := &models.Playlist{Name: "Morning Show", Tracks: []models.PlaylistTrack{{TrackID: 12}, {TrackID: 7}}}
+	stored := &models.Playlist{ID: 3, Name: "Morning Show", Tracks: []models.PlaylistTrack{{TrackID: 12}, {TrackID: 7}}}
+	mockRepo.On("Create", playlist).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Playlist).ID = 3
+	}).Return(nil)
+	mockRepo.On("GetByID", 3).Return(stored, nil)
+	mockRepo.On("Rename", stored).Return(nil)
+	mockRepo.On("Delete", 3).Return(nil)
+
+	// Act
+	assert.NoError(t, service.CreatePlaylist(playlist))
+	_, err := service.RenamePlaylist(3, "Morning Show")
+	assert.NoError(t, err)
+	assert.NoError(t, service.DeletePlaylist(3))
+
+	// Assert
+	assert.Len(t, mockRepo.events, 3)
+	for i, eventType := range []models.EventType{models.EventPlaylistCreated, models.EventPlaylistUpdated, models.EventPlaylistDeleted} {
+		assert.Equal(t, eventType, mockRepo.events[i].Type)
+		assert.Equal(t, int64(3), mockRepo.events[i].PartitionKey)
+		assert.NotEmpty(t, mockRepo.events[i].ID)
+	}
+	var created models.PlaylistChangedEvent
+	assert.NoError(t, mockRepo.events[0].DecodePayload(&created))
+	assert.Equal(t, []int64{12, 7}, created.TrackIDs)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPlaylistService_WithoutRecordEventsEmitsNothing(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockPlaylistRepository)
+	service := &PlaylistService{Repo: mockRepo}
+	mockRepo.On("Create", mock.Anything).Return(nil)
+	mockRepo.On("Delete", 3).Return(nil)
+
+	// Act
+	assert.NoError(t, service.CreatePlaylist(&models.Playlist{Name: "Morning Show"}))
+	assert.NoError(t, service.DeletePlaylist(3))
+
+	// Assert
+	assert.Empty(t, mockRepo.events)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"radioking-app/internal/domain/models"
+	"time"
+)
+
+// rotationPlay passage d'une track, déjà joué ou prévu dans la file en cours de construction
+type rotationPlay struct {
+	trackID  int64
+	artistID int64
+	at       time.Time
+}
+
+// RotationChecker vérifie les règles de rotation au fil de la construction d'une file de lecture.
+// Chaque passage retenu est enregistré pour être pris en compte par les passages suivants.
+// Un checker nil n'applique aucune règle.
+type RotationChecker struct {
+	rules []*models.RotationRule
+	plays []rotationPlay
+}
+
+func newRotationChecker(rules []*models.RotationRule, history []*models.TrackPlay) *RotationChecker {
+	checker := &RotationChecker{rules: rules}
+	for _, play := range history {
+		checker.Record(play.TrackID, play.Track.ArtistID, play.PlayedAt)
+	}
+	return checker
+}
+
+// Check retourne les règles enfreintes par un passage de la track à l'instant at
+func (c *RotationChecker) Check(trackID int64, artistID int64, at time.Time) []models.RotationViolation {
+	if c == nil {
+		return nil
+	}
+
+	var violations []models.RotationViolation
+	for _, rule := range c.rules {
+		// Les tracks non rattachées à un artiste échappent aux règles sur l'artiste
+		if rule.Subject == models.RotationArtist && artistID == 0 {
+			continue
+		}
+
+		violation := models.RotationViolation{Rule: *rule}
+		windowStart := at.Add(-rule.Window())
+		for _, play := range c.plays {
+			if !play.at.After(windowStart) || play.at.After(at) {
+				continue
+			}
+			if (rule.Subject == models.RotationArtist && play.artistID == artistID) ||
+				(rule.Subject == models.RotationTrack && play.trackID == trackID) {
+				violation.Plays++
+				if play.at.After(violation.LastPlayedAt) {
+					violation.LastPlayedAt = play.at
+				}
+			}
+		}
+
+		if violation.Plays >= rule.MaxPlays {
+			violations = append(violations, violation)
+		}
+	}
+	return violations
+}
+
+// Record prend en compte un passage de la track à l'instant at
+func (c *RotationChecker) Record(trackID int64, artistID int64, at time.Time) {
+	if c == nil {
+		return
+	}
+	c.plays = append(c.plays, rotationPlay{trackID: trackID, artistID: artistID, at: at})
+}
+
+// arrangeForRotation reconstruit la file en prenant à chaque rang la première track qui n'enfreint
+// aucune règle. Quand toutes les tracks restantes en enfreignent une, la première est jouée quand même.
+func arrangeForRotation(items []models.PlaySessionItem, checker *RotationChecker, startsAt time.Time) []models.PlaySessionItem {
+	if checker == nil || len(checker.rules) == 0 {
+		return items
+	}
+
+	remaining := items
+	ordered := make([]models.PlaySessionItem, 0, len(items))
+	at := startsAt
+	for len(remaining) > 0 {
+		pick := 0
+		for i, item := range remaining {
+			if len(checker.Check(item.TrackID, item.ArtistID, at)) == 0 {
+				pick = i
+				break
+			}
+		}
+
+		item := remaining[pick]
+		ordered = append(ordered, item)
+		checker.Record(item.TrackID, item.ArtistID, at)
+		at = at.Add(item.Duration())
+		remaining = append(remaining[:pick:pick], remaining[pick+1:]...)
+	}
+	return ordered
+}
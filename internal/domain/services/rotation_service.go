@@ -0,0 +1,181 @@
+package services
+
+import (
+	"errors"
+	"radioking-app/internal/domain/constants"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/repositories"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RotationService gère les règles de rotation et les vérifie contre l'historique des lectures
+type RotationService struct {
+	ruleRepo        repositories.IRotationRuleRepository
+	playRepo        TrackPlayRepository
+	playlistService IPlaylistService
+}
+
+func NewRotationService(ruleRepo repositories.IRotationRuleRepository, playRepo TrackPlayRepository,
+	playlistService IPlaylistService) *RotationService {
+	return &RotationService{
+		ruleRepo:        ruleRepo,
+		playRepo:        playRepo,
+		playlistService: playlistService,
+	}
+}
+
+func (s *RotationService) CreateRule(rule *models.RotationRule) error {
+	if err := validateRotationRule(rule); err != nil {
+		return err
+	}
+
+	if err := s.ruleRepo.Create(rule); err != nil {
+		return domainErrors.NewInternalError("failed to create rotation rule", err)
+	}
+	return nil
+}
+
+func (s *RotationService) ListRules() ([]*models.RotationRule, error) {
+	rules, err := s.ruleRepo.List(false)
+	if err != nil {
+		return nil, domainErrors.NewInternalError("failed to list rotation rules", err)
+	}
+	return rules, nil
+}
+
+func (s *RotationService) GetRule(id int) (*models.RotationRule, error) {
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidRotationRuleID
+	}
+
+	rule, err := s.ruleRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainErrors.ErrRotationRuleNotFound
+		}
+		return nil, domainErrors.NewInternalError("failed to get rotation rule", err)
+	}
+	return rule, nil
+}
+
+func (s *RotationService) UpdateRule(id int, rule *models.RotationRule) error {
+	existing, err := s.GetRule(id)
+	if err != nil {
+		return err
+	}
+
+	if err := validateRotationRule(rule); err != nil {
+		return err
+	}
+
+	rule.ID = existing.ID
+	rule.CreatedAt = existing.CreatedAt
+	if err := s.ruleRepo.Update(rule); err != nil {
+		return domainErrors.NewInternalError("failed to update rotation rule", err)
+	}
+	return nil
+}
+
+func (s *RotationService) DeleteRule(id int) error {
+	if id <= 0 {
+		return domainErrors.ErrInvalidRotationRuleID
+	}
+
+	if err := s.ruleRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domainErrors.ErrRotationRuleNotFound
+		}
+		return domainErrors.NewInternalError("failed to delete rotation rule", err)
+	}
+	return nil
+}
+
+// NewChecker charge les règles actives et les lectures de leur plus grande fenêtre avant startsAt
+func (s *RotationService) NewChecker(startsAt time.Time) (*RotationChecker, error) {
+	rules, err := s.ruleRepo.List(true)
+	if err != nil {
+		return nil, domainErrors.NewInternalError("failed to load rotation rules", err)
+	}
+	if len(rules) == 0 {
+		return newRotationChecker(nil, nil), nil
+	}
+
+	var window time.Duration
+	for _, rule := range rules {
+		window = max(window, rule.Window())
+	}
+
+	history, err := s.playRepo.ListSince(startsAt.Add(-window))
+	if err != nil {
+		return nil, domainErrors.NewInternalError("failed to load play history", err)
+	}
+	return newRotationChecker(rules, history), nil
+}
+
+// DryRunPlaylist construit la file de lecture de la playlist comme le ferait le moteur de lecture
+// à startsAt, sans la jouer, et rapporte les règles enfreintes par chaque track
+func (s *RotationService) DryRunPlaylist(playlistID int, options models.PlayOptions, startsAt time.Time) (*models.RotationReport, error) {
+	playlist, err := s.playlistService.GetPlaylist(playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, options, err := planPlaySessionItems(*playlist, options, s, startsAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Le rapport repart de l'historique : le checker utilisé pour ordonner la file a déjà enregistré ses passages
+	checker, err := s.NewChecker(startsAt)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.RotationReport{StartsAt: startsAt}
+	at := startsAt
+	for _, item := range items {
+		violations := checker.Check(item.TrackID, item.ArtistID, at)
+		checker.Record(item.TrackID, item.ArtistID, at)
+
+		report.Items = append(report.Items, models.ProgramItem{
+			StartsAt:   at,
+			PlaylistID: item.SourcePlaylistID(playlist.ID),
+			Track: models.Track{
+				ID:              item.TrackID,
+				Title:           item.Title,
+				Artist:          item.Artist,
+				ArtistID:        item.ArtistID,
+				DurationSeconds: item.DurationSeconds,
+			},
+			DurationSeconds: item.DurationSeconds,
+			Violations:      violations,
+		})
+		report.ViolationsCount += len(violations)
+		at = at.Add(item.Duration())
+	}
+	return report, nil
+}
+
+func validateRotationRule(rule *models.RotationRule) error {
+	rule.Name = strings.TrimSpace(rule.Name)
+	if rule.Name == "" || len(rule.Name) > constants.MaxRotationRuleNameLength {
+		return domainErrors.ErrInvalidRotationRule
+	}
+	if rule.Subject != models.RotationArtist && rule.Subject != models.RotationTrack {
+		return domainErrors.ErrInvalidRotationRule
+	}
+	if rule.WindowMinutes <= 0 || rule.WindowMinutes > constants.MaxRotationWindowMinutes {
+		return domainErrors.ErrInvalidRotationRule
+	}
+	if rule.MaxPlays == 0 {
+		rule.MaxPlays = 1
+	}
+	if rule.MaxPlays < 0 {
+		return domainErrors.ErrInvalidRotationRule
+	}
+	return nil
+}
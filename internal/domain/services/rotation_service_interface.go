@@ -0,0 +1,16 @@
+package services
+
+import (
+	"radioking-app/internal/domain/models"
+	"time"
+)
+
+type IRotationService interface {
+	CreateRule(rule *models.RotationRule) error
+	ListRules() ([]*models.RotationRule, error)
+	GetRule(id int) (*models.RotationRule, error)
+	UpdateRule(id int, rule *models.RotationRule) error
+	DeleteRule(id int) error
+	NewChecker(startsAt time.Time) (*RotationChecker, error)
+	DryRunPlaylist(playlistID int, options models.PlayOptions, startsAt time.Time) (*models.RotationReport, error)
+}
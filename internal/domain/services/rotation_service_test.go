@@ -0,0 +1,153 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRotationRuleRepository is a mock implementation of IRotationRuleRepository
+type MockRotationRuleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRotationRuleRepository) Create(rule *models.RotationRule) error {
+	args := m.Called(rule)
+	return args.Error(0)
+}
+
+func (m *MockRotationRuleRepository) List(enabledOnly bool) ([]*models.RotationRule, error) {
+	args := m.Called(enabledOnly)
+	return args.Get(0).([]*models.RotationRule), args.Error(1)
+}
+
+func (m *MockRotationRuleRepository) GetByID(id int) (*models.RotationRule, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RotationRule), args.Error(1)
+}
+
+func (m *MockRotationRuleRepository) Update(rule *models.RotationRule) error {
+	args := m.Called(rule)
+	return args.Error(0)
+}
+
+func (m *MockRotationRuleRepository) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+var (
+	artistSeparation = &models.RotationRule{ID: 1, Name: "artist", Subject: models.RotationArtist, WindowMinutes: 30, MaxPlays: 1}
+	trackFrequency   = &models.RotationRule{ID: 2, Name: "track", Subject: models.RotationTrack, WindowMinutes: 180, MaxPlays: 2}
+)
+
+func TestRotationChecker_Check(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	history := []*models.TrackPlay{
+		{TrackID: 1, PlayedAt: now.Add(-20 * time.Minute), Track: models.Track{ArtistID: 10}},
+		{TrackID: 2, PlayedAt: now.Add(-2 * time.Hour), Track: models.Track{ArtistID: 20}},
+		{TrackID: 2, PlayedAt: now.Add(-40 * time.Minute), Track: models.Track{ArtistID: 20}},
+	}
+	checker := newRotationChecker([]*models.RotationRule{artistSeparation, trackFrequency}, history)
+
+	// Act & Assert
+	violations := checker.Check(3, 10, now)
+	require.Len(t, violations, 1)
+	assert.Equal(t, artistSeparation.ID, violations[0].Rule.ID)
+	assert.Equal(t, now.Add(-20*time.Minute), violations[0].LastPlayedAt)
+
+	// Fenêtre de 30 minutes écoulée pour l'artiste, mais deux passages de la track en 3 heures
+	violations = checker.Check(2, 20, now)
+	require.Len(t, violations, 1)
+	assert.Equal(t, trackFrequency.ID, violations[0].Rule.ID)
+	assert.Equal(t, 2, violations[0].Plays)
+
+	assert.Empty(t, checker.Check(3, 10, now.Add(15*time.Minute)))
+	assert.Empty(t, checker.Check(4, 0, now))
+
+	// Les passages prévus comptent comme l'historique
+	checker.Record(5, 50, now)
+	assert.Len(t, checker.Check(6, 50, now.Add(3*time.Minute)), 1)
+}
+
+func TestArrangeForRotation_SeparatesArtists(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	checker := newRotationChecker([]*models.RotationRule{artistSeparation}, []*models.TrackPlay{
+		{TrackID: 9, PlayedAt: now.Add(-5 * time.Minute), Track: models.Track{ArtistID: 1}},
+	})
+	items := []models.PlaySessionItem{
+		{TrackID: 1, ArtistID: 1, DurationSeconds: 600},
+		{TrackID: 2, ArtistID: 1, DurationSeconds: 600},
+		{TrackID: 3, ArtistID: 2, DurationSeconds: 600},
+		{TrackID: 4, ArtistID: 3, DurationSeconds: 600},
+		{TrackID: 5, ArtistID: 4, DurationSeconds: 600},
+	}
+
+	// Act
+	ordered := arrangeForRotation(items, checker, now)
+
+	// Assert
+	// L'artiste 1 vient d'être joué : ses tracks attendent la fin de la fenêtre de 30 minutes
+	assert.Equal(t, []int64{3, 4, 5, 1, 2}, trackIDs(ordered))
+}
+
+func TestRotationService_NewChecker_LoadsLargestWindow(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	ruleRepo := new(MockRotationRuleRepository)
+	ruleRepo.On("List", true).Return([]*models.RotationRule{artistSeparation, trackFrequency}, nil)
+	playRepo := new(MockTrackPlayRepository)
+	playRepo.On("ListSince", now.Add(-3*time.Hour)).Return([]*models.TrackPlay{
+		{TrackID: 1, PlayedAt: now.Add(-time.Minute), Track: models.Track{ArtistID: 10}},
+	}, nil)
+	service := NewRotationService(ruleRepo, playRepo, &PlaylistService{})
+
+	// Act
+	checker, err := service.NewChecker(now)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, checker.Check(2, 10, now), 1)
+	playRepo.AssertExpectations(t)
+}
+
+func TestRotationService_CreateRule_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    models.RotationRule
+		wantErr error
+	}{
+		{name: "valid separation", rule: models.RotationRule{Name: "artist", Subject: models.RotationArtist, WindowMinutes: 30}},
+		{name: "unknown subject", rule: models.RotationRule{Name: "album", Subject: "album", WindowMinutes: 30}, wantErr: domainErrors.ErrInvalidRotationRule},
+		{name: "empty window", rule: models.RotationRule{Name: "track", Subject: models.RotationTrack}, wantErr: domainErrors.ErrInvalidRotationRule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ruleRepo := new(MockRotationRuleRepository)
+			ruleRepo.On("Create", mock.Anything).Return(nil)
+			service := NewRotationService(ruleRepo, new(MockTrackPlayRepository), &PlaylistService{})
+
+			err := service.CreateRule(&tt.rule)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				ruleRepo.AssertNotCalled(t, "Create", mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, tt.rule.MaxPlays)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"math/rand/v2"
+	"radioking-app/internal/domain/constants"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const previewDateLayout = "2006-01-02"
+
+// ScheduleService construit le programme des heures de la grille à partir de leur clock.
+// La grille est programmée dans le fuseau de la station. Les tirages sont seedés par l'heure programmée.
+// Les tracks tirées par un slot rule qui enfreindraient une règle de rotation sont écartées ;
+// les playlists et jingles, choisis par la programmation, sont conservés et leurs infractions signalées.
+type ScheduleService struct {
+	gridRepo        repositories.IGridRepository
+	playlistService IPlaylistService
+	trackRepo       repositories.ITrackRepository
+	rotation        IRotationService
+	location        *time.Location
+}
+
+func NewScheduleService(gridRepo repositories.IGridRepository, playlistService IPlaylistService,
+	trackRepo repositories.ITrackRepository, rotation IRotationService, location *time.Location) *ScheduleService {
+	return &ScheduleService{
+		gridRepo:        gridRepo,
+		playlistService: playlistService,
+		trackRepo:       trackRepo,
+		rotation:        rotation,
+		location:        location,
+	}
+}
+
+// BuildHour retourne le programme de l'heure de la station contenant startsAt, sans clock si
+// l'heure n'est pas programmée dans la grille
+func (s *ScheduleService) BuildHour(startsAt time.Time) (*models.HourProgram, error) {
+	startsAt = stationHour(startsAt, s.location)
+
+	checker, err := s.newChecker(startsAt)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildHour(startsAt, checker)
+}
+
+func (s *ScheduleService) buildHour(startsAt time.Time, checker *RotationChecker) (*models.HourProgram, error) {
+	program := &models.HourProgram{StartsAt: startsAt}
+
+	entry, err := s.gridRepo.Get(int(startsAt.Weekday()), startsAt.Hour())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return program, nil
+		}
+		return nil, domainErrors.NewInternalError("failed to get grid entry", err)
+	}
+
+	program.Clock = entry.Clock
+	if program.Items, err = s.fillClock(entry.Clock, startsAt, checker); err != nil {
+		return nil, err
+	}
+	return program, nil
+}
+
+// Preview retourne le programme des heures de la station qui couvrent la journée du fuseau demandé,
+// celui de la station par défaut. Les horaires sont exprimés dans le fuseau demandé.
+func (s *ScheduleService) Preview(date string, timezone string) ([]*models.HourProgram, error) {
+	location := s.location
+	if timezone != "" {
+		var err error
+		if location, err = loadLocation(timezone); err != nil {
+			return nil, err
+		}
+	}
+
+	day, err := time.ParseInLocation(previewDateLayout, date, location)
+	if err != nil {
+		return nil, domainErrors.ErrInvalidDate
+	}
+
+	// La grille est lue à l'heure de la station, comme à l'antenne
+	first := stationHour(day, s.location)
+
+	// Les règles de rotation tiennent compte des heures précédentes de la journée
+	checker, err := s.newChecker(first)
+	if err != nil {
+		return nil, err
+	}
+
+	// Les journées de changement d'heure comptent 23 ou 25 heures
+	var programs []*models.HourProgram
+	end := day.AddDate(0, 0, 1)
+	for startsAt := first; startsAt.Before(end); startsAt = startsAt.Add(time.Hour) {
+		program, err := s.buildHour(startsAt, checker)
+		if err != nil {
+			return nil, err
+		}
+		program.StartsAt = program.StartsAt.In(location)
+		for i := range program.Items {
+			program.Items[i].StartsAt = program.Items[i].StartsAt.In(location)
+		}
+		programs = append(programs, program)
+	}
+	return programs, nil
+}
+
+// DryRunHour rapporte les règles de rotation enfreintes par le programme de l'heure
+func (s *ScheduleService) DryRunHour(startsAt time.Time) (*models.RotationReport, error) {
+	program, err := s.BuildHour(startsAt)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.RotationReport{StartsAt: program.StartsAt, Clock: program.Clock, Items: program.Items}
+	for _, item := range program.Items {
+		report.ViolationsCount += len(item.Violations)
+	}
+	return report, nil
+}
+
+// newChecker checker de rotation à partir de startsAt, nil quand la rotation n'est pas configurée
+func (s *ScheduleService) newChecker(startsAt time.Time) (*RotationChecker, error) {
+	if s.rotation == nil {
+		return nil, nil
+	}
+	return s.rotation.NewChecker(startsAt)
+}
+
+// fillClock enchaîne les tracks de chaque slot jusqu'à la fin de l'heure
+func (s *ScheduleService) fillClock(clock *models.Clock, startsAt time.Time, checker *RotationChecker) ([]models.ProgramItem, error) {
+	var items []models.ProgramItem
+	offset := 0
+
+	for _, slot := range clock.Slots {
+		if offset >= constants.MaxSlotDurationSeconds {
+			break
+		}
+
+		candidates, err := s.slotCandidates(slot, startsAt)
+		if err != nil {
+			return nil, err
+		}
+
+		slotElapsed := 0
+		for _, candidate := range candidates {
+			if slot.DurationSeconds > 0 && slotElapsed >= slot.DurationSeconds {
+				break
+			}
+			if offset >= constants.MaxSlotDurationSeconds {
+				break
+			}
+
+			at := startsAt.Add(time.Duration(offset) * time.Second)
+			violations := checker.Check(candidate.track.ID, candidate.track.ArtistID, at)
+			if len(violations) > 0 && slot.Type == models.ClockSlotRule {
+				continue
+			}
+			checker.Record(candidate.track.ID, candidate.track.ArtistID, at)
+
+			duration := trackDuration(candidate.track)
+			items = append(items, models.ProgramItem{
+				StartsAt:        at,
+				SlotType:        slot.Type,
+				PlaylistID:      candidate.playlistID,
+				Track:           candidate.track,
+				DurationSeconds: duration,
+				Violations:      violations,
+			})
+			offset += duration
+			slotElapsed += duration
+		}
+	}
+	return items, nil
+}
+
+// slotCandidate track pouvant être jouée par un slot, avec la playlist dont elle provient
+type slotCandidate struct {
+	track      models.Track
+	playlistID int64
+}
+
+// slotCandidates retourne les tracks du slot dans leur ordre de passage. Une playlist ou une
+// track supprimée depuis la création de la clock laisse le slot vide.
+func (s *ScheduleService) slotCandidates(slot models.ClockSlot, startsAt time.Time) ([]slotCandidate, error) {
+	var candidates []slotCandidate
+
+	switch slot.Type {
+	case models.ClockSlotPlaylist:
+		if slot.PlaylistID == nil {
+			return nil, nil
+		}
+		playlist, err := s.playlistService.GetPlaylist(int(*slot.PlaylistID))
+		if err != nil {
+			if errors.Is(err, domainErrors.ErrPlaylistNotFound) {
+				log.Printf("Playlist %d of clock slot %d not found, slot skipped", *slot.PlaylistID, slot.ID)
+				return nil, nil
+			}
+			return nil, err
+		}
+		for _, entry := range playlist.Tracks {
+			candidates = append(candidates, slotCandidate{track: entry.Track, playlistID: playlist.ID})
+		}
+	case models.ClockSlotJingle:
+		if slot.TrackID == nil {
+			return nil, nil
+		}
+		track, err := s.trackRepo.GetByID(int(*slot.TrackID))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Printf("Jingle %d of clock slot %d not found, slot skipped", *slot.TrackID, slot.ID)
+				return nil, nil
+			}
+			return nil, domainErrors.NewInternalError("failed to get jingle", err)
+		}
+		candidates = append(candidates, slotCandidate{track: *track})
+	case models.ClockSlotRule:
+		tracks, err := s.trackRepo.Search(models.TrackCriteria{
+			ArtistID: slot.ArtistID,
+			AlbumID:  slot.AlbumID,
+			Limit:    constants.MaxRuleCandidates,
+		})
+		if err != nil {
+			return nil, domainErrors.NewInternalError("failed to search rule tracks", err)
+		}
+		for _, track := range tracks {
+			candidates = append(candidates, slotCandidate{track: *track})
+		}
+	}
+
+	if slot.Shuffle {
+		random := rand.New(rand.NewPCG(uint64(startsAt.Unix()), uint64(slot.Position)))
+		random.Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+	}
+	return candidates, nil
+}
+
+// stationHour début de l'heure de la station contenant at
+func stationHour(at time.Time, location *time.Location) time.Time {
+	at = at.In(location)
+	return time.Date(at.Year(), at.Month(), at.Day(), at.Hour(), 0, 0, 0, location)
+}
+
+// loadLocation charge le fuseau IANA demandé, celui du serveur s'il n'est pas précisé
+func loadLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, domainErrors.ErrInvalidTimezone
+	}
+	return location, nil
+}
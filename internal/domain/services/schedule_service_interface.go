@@ -0,0 +1,12 @@
+package services
+
+import (
+	"radioking-app/internal/domain/models"
+	"time"
+)
+
+type IScheduleService interface {
+	BuildHour(startsAt time.Time) (*models.HourProgram, error)
+	Preview(date string, timezone string) ([]*models.HourProgram, error)
+	DryRunHour(startsAt time.Time) (*models.RotationReport, error)
+}
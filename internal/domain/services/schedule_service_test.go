@@ -0,0 +1,209 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// MockGridRepository is a mock implementation of IGridRepository
+type MockGridRepository struct {
+	mock.Mock
+}
+
+func (m *MockGridRepository) List() ([]*models.GridEntry, error) {
+	args := m.Called()
+	return args.Get(0).([]*models.GridEntry), args.Error(1)
+}
+
+func (m *MockGridRepository) Get(weekday int, hour int) (*models.GridEntry, error) {
+	args := m.Called(weekday, hour)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GridEntry), args.Error(1)
+}
+
+func (m *MockGridRepository) Assign(entry *models.GridEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockGridRepository) Delete(weekday int, hour int) error {
+	args := m.Called(weekday, hour)
+	return args.Error(0)
+}
+
+// MockTrackRepository is a mock implementation of ITrackRepository
+type MockTrackRepository struct {
+	mock.Mock
+}
+
+func (m *MockTrackRepository) Create(track *models.Track) error {
+	args := m.Called(track)
+	return args.Error(0)
+}
+
+func (m *MockTrackRepository) List(limit int, afterID int64) ([]*models.Track, error) {
+	args := m.Called(limit, afterID)
+	return args.Get(0).([]*models.Track), args.Error(1)
+}
+
+func (m *MockTrackRepository) GetByID(id int) (*models.Track, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Track), args.Error(1)
+}
+
+func (m *MockTrackRepository) FindByTitleAndArtist(title string, artist string) (*models.Track, error) {
+	args := m.Called(title, artist)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Track), args.Error(1)
+}
+
+func (m *MockTrackRepository) Search(criteria models.TrackCriteria) ([]*models.Track, error) {
+	args := m.Called(criteria)
+	return args.Get(0).([]*models.Track), args.Error(1)
+}
+
+func (m *MockTrackRepository) Update(track *models.Track) error {
+	args := m.Called(track)
+	return args.Error(0)
+}
+
+func (m *MockTrackRepository) Delete(id int, events repositories.PlaylistEvents) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func buildCatalogTracks(count int, durationSeconds int) []*models.Track {
+	tracks := make([]*models.Track, 0, count)
+	for i := 1; i <= count; i++ {
+		tracks = append(tracks, &models.Track{ID: int64(i), Title: "Track", ArtistID: int64(i), DurationSeconds: durationSeconds})
+	}
+	return tracks
+}
+
+func TestScheduleService_BuildHour_FillsSlots(t *testing.T) {
+	// Arrange
+	playlistID, jingleID := int64(4), int64(100)
+	startsAt := time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC) // Lundi
+	clock := &models.Clock{ID: 1, Name: "Morning", Slots: []models.ClockSlot{
+		{Position: 0, Type: models.ClockSlotJingle, TrackID: &jingleID},
+		{Position: 1, Type: models.ClockSlotPlaylist, PlaylistID: &playlistID, DurationSeconds: 400},
+		{Position: 2, Type: models.ClockSlotRule, DurationSeconds: 3600, Shuffle: true},
+	}}
+
+	gridRepo := new(MockGridRepository)
+	gridRepo.On("Get", 1, 8).Return(&models.GridEntry{Weekday: 1, Hour: 8, ClockID: 1, Clock: clock}, nil)
+
+	playlistRepo := new(MockPlaylistRepository)
+	playlist := &models.Playlist{ID: playlistID}
+	for i, track := range buildCatalogTracks(5, 180) {
+		playlist.Tracks = append(playlist.Tracks, models.PlaylistTrack{PlaylistID: playlistID, TrackID: track.ID, Position: i, Track: *track})
+	}
+	playlistRepo.On("GetByID", 4).Return(playlist, nil)
+
+	trackRepo := new(MockTrackRepository)
+	trackRepo.On("GetByID", 100).Return(&models.Track{ID: jingleID, DurationSeconds: 20}, nil)
+	trackRepo.On("Search", mock.Anything).Return(buildCatalogTracks(30, 200), nil)
+
+	service := NewScheduleService(gridRepo, &PlaylistService{Repo: playlistRepo}, trackRepo, nil, time.UTC)
+
+	// Act
+	program, err := service.BuildHour(startsAt.Add(25 * time.Minute))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, startsAt, program.StartsAt)
+	require.NotEmpty(t, program.Items)
+
+	// Jingle, puis 3 tracks de la playlist pour atteindre 400 secondes, puis la règle jusqu'à la fin de l'heure
+	assert.Equal(t, models.ClockSlotJingle, program.Items[0].SlotType)
+	for _, item := range program.Items[1:4] {
+		assert.Equal(t, playlistID, item.PlaylistID)
+	}
+	assert.Equal(t, startsAt.Add(20*time.Second+540*time.Second), program.Items[4].StartsAt)
+	assert.Equal(t, models.ClockSlotRule, program.Items[4].SlotType)
+
+	last := program.Items[len(program.Items)-1]
+	assert.True(t, last.StartsAt.Before(startsAt.Add(time.Hour)))
+	assert.False(t, last.StartsAt.Add(time.Duration(last.DurationSeconds)*time.Second).Before(startsAt.Add(time.Hour)))
+
+	// Le tirage de la règle est reproductible pour une même heure
+	again, err := service.BuildHour(startsAt)
+	require.NoError(t, err)
+	assert.Equal(t, program.Items, again.Items)
+}
+
+func TestScheduleService_BuildHour_Unscheduled(t *testing.T) {
+	// Arrange
+	gridRepo := new(MockGridRepository)
+	gridRepo.On("Get", 0, 3).Return(nil, gorm.ErrRecordNotFound)
+	service := NewScheduleService(gridRepo, &PlaylistService{}, new(MockTrackRepository), nil, time.UTC)
+
+	// Act
+	program, err := service.BuildHour(time.Date(2024, 1, 14, 3, 0, 0, 0, time.UTC))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Nil(t, program.Clock)
+	assert.Empty(t, program.Items)
+}
+
+func TestScheduleService_Preview_DaylightSavingDay(t *testing.T) {
+	// Arrange
+	gridRepo := new(MockGridRepository)
+	gridRepo.On("Get", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	service := NewScheduleService(gridRepo, &PlaylistService{}, new(MockTrackRepository), nil, paris)
+
+	// Act
+	programs, err := service.Preview("2024-03-31", "")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, programs, 23)
+
+	_, err = service.Preview("2024-03-31", "Mars/Olympus")
+	assert.Error(t, err)
+}
+
+func TestScheduleService_Preview_ReadsGridAtStationTime(t *testing.T) {
+	// Arrange : station à Paris, prévisualisation depuis New York
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	clock := &models.Clock{ID: 1, Name: "Morning"}
+	gridRepo := new(MockGridRepository)
+	gridRepo.On("Get", 1, 6).Return(&models.GridEntry{Weekday: 1, Hour: 6, ClockID: 1, Clock: clock}, nil)
+	gridRepo.On("Get", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	service := NewScheduleService(gridRepo, &PlaylistService{}, new(MockTrackRepository), nil, paris)
+
+	// Act
+	programs, err := service.Preview("2024-01-15", "America/New_York")
+
+	// Assert : lundi 6h à Paris, à l'antenne à minuit à New York
+	require.NoError(t, err)
+	require.Len(t, programs, 24)
+	assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, newYork), programs[0].StartsAt)
+	assert.Equal(t, newYork, programs[0].StartsAt.Location())
+	assert.Equal(t, clock, programs[0].Clock)
+	for _, program := range programs[1:] {
+		assert.Nil(t, program.Clock)
+	}
+}
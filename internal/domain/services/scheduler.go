@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"log"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/repositories"
+	"sync"
+	"time"
+)
+
+// Scheduler lance à chaque début d'heure de la station la clock programmée dans la grille. La session
+// de l'heure précédente est arrêtée : une seule heure programmée est à l'antenne à la fois.
+type Scheduler struct {
+	schedule     IScheduleService
+	playService  IPlaylistPlayService
+	engine       IPlaybackEngine
+	sessionsRepo repositories.IPlaySessionRepository
+	clock        clock
+	location     *time.Location
+
+	wg sync.WaitGroup
+}
+
+func NewScheduler(schedule IScheduleService, playService IPlaylistPlayService, engine IPlaybackEngine,
+	sessionsRepo repositories.IPlaySessionRepository, location *time.Location) *Scheduler {
+	return &Scheduler{
+		schedule:     schedule,
+		playService:  playService,
+		engine:       engine,
+		sessionsRepo: sessionsRepo,
+		clock:        realClock{},
+		location:     location,
+	}
+}
+
+// Start programme les heures suivantes jusqu'à l'annulation de ctx. L'heure en cours n'est pas
+// relancée : sa session, si elle existe, est reprise par le moteur de lecture.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		for {
+			now := s.clock.Now()
+			next := stationHour(now, s.location).Add(time.Hour)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.clock.After(next.Sub(now)):
+				if _, err := s.AirHour(next); err != nil {
+					log.Printf("Failed to air scheduled hour %s: %v", next.Format(time.RFC3339), err)
+				}
+			}
+		}
+	}()
+}
+
+// Wait attend l'arrêt du scheduler, après annulation du contexte passé à Start
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// AirHour arrête l'heure programmée précédente puis lance la clock de l'heure commençant à startsAt
+func (s *Scheduler) AirHour(startsAt time.Time) (*models.PlaySession, error) {
+	program, err := s.schedule.BuildHour(startsAt)
+	if err != nil {
+		return nil, err
+	}
+
+	s.stopScheduledSessions()
+
+	if program.Clock == nil || len(program.Items) == 0 {
+		log.Printf("Nothing scheduled at %s", program.StartsAt.Format(time.RFC3339))
+		return nil, nil
+	}
+
+	playlist := models.Playlist{Name: program.Clock.Name}
+	for i, item := range program.Items {
+		playlist.Tracks = append(playlist.Tracks, models.PlaylistTrack{
+			PlaylistID: item.PlaylistID,
+			TrackID:    item.Track.ID,
+			Position:   i,
+			Track:      item.Track,
+		})
+	}
+
+	log.Printf("Airing clock '%s' at %s with %d tracks", program.Clock.Name, program.StartsAt.Format(time.RFC3339), len(program.Items))
+	return s.playService.PlayPlaylist(playlist, models.PlayOptions{ClockID: &program.Clock.ID})
+}
+
+// stopScheduledSessions arrête les sessions programmées encore actives, les lectures
+// lancées à la demande ne sont pas concernées
+func (s *Scheduler) stopScheduledSessions() {
+	sessions, err := s.sessionsRepo.ListByStatus(models.PlaySessionPlaying, models.PlaySessionPaused)
+	if err != nil {
+		log.Printf("Failed to load active play sessions: %v", err)
+		return
+	}
+
+	for _, session := range sessions {
+		if session.ClockID == nil {
+			continue
+		}
+		if _, err := s.engine.Stop(session.ID); err != nil {
+			log.Printf("Failed to stop scheduled play session %d: %v", session.ID, err)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"radioking-app/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubScheduleService retourne toujours le même programme
+type stubScheduleService struct {
+	program *models.HourProgram
+}
+
+func (s *stubScheduleService) BuildHour(time.Time) (*models.HourProgram, error) {
+	return s.program, nil
+}
+
+func (s *stubScheduleService) Preview(string, string) ([]*models.HourProgram, error) { return nil, nil }
+
+func (s *stubScheduleService) DryRunHour(time.Time) (*models.RotationReport, error) { return nil, nil }
+
+// hourRecordingScheduleService enregistre la première heure programmée puis annule le scheduler
+type hourRecordingScheduleService struct {
+	stubScheduleService
+	hours  chan time.Time
+	cancel context.CancelFunc
+}
+
+func (s *hourRecordingScheduleService) BuildHour(startsAt time.Time) (*models.HourProgram, error) {
+	s.cancel()
+	select {
+	case s.hours <- startsAt:
+	default:
+	}
+	return s.program, nil
+}
+
+// recordingPlayService enregistre les playlists lancées
+type recordingPlayService struct {
+	playlists []models.Playlist
+	options   []models.PlayOptions
+}
+
+func (s *recordingPlayService) PlayPlaylist(playlist models.Playlist, options models.PlayOptions) (*models.PlaySession, error) {
+	s.playlists = append(s.playlists, playlist)
+	s.options = append(s.options, options)
+	return &models.PlaySession{ID: 10, ClockID: options.ClockID}, nil
+}
+
+// stopRecordingEngine enregistre les sessions arrêtées
+type stopRecordingEngine struct {
+	IPlaybackEngine
+	stopped []int64
+}
+
+func (e *stopRecordingEngine) Stop(id int64) (*models.PlaySession, error) {
+	e.stopped = append(e.stopped, id)
+	return &models.PlaySession{ID: id, Status: models.PlaySessionStopped}, nil
+}
+
+func TestScheduler_AirHour_ReplacesPreviousScheduledSession(t *testing.T) {
+	// Arrange
+	clockID := int64(3)
+	startsAt := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	program := &models.HourProgram{
+		StartsAt: startsAt,
+		Clock:    &models.Clock{ID: clockID, Name: "Morning"},
+		Items: []models.ProgramItem{
+			{StartsAt: startsAt, SlotType: models.ClockSlotJingle, Track: models.Track{ID: 100}, DurationSeconds: 20},
+			{StartsAt: startsAt.Add(20 * time.Second), SlotType: models.ClockSlotPlaylist, PlaylistID: 4, Track: models.Track{ID: 1}, DurationSeconds: 180},
+		},
+	}
+
+	repo := new(MockPlaySessionRepository)
+	repo.On("ListByStatus", mock.Anything).Return([]*models.PlaySession{
+		{ID: 1, Status: models.PlaySessionPlaying},                    // Lecture à la demande
+		{ID: 2, Status: models.PlaySessionPlaying, ClockID: &clockID}, // Heure précédente
+	}, nil)
+	engine := &stopRecordingEngine{}
+	playService := &recordingPlayService{}
+	scheduler := NewScheduler(&stubScheduleService{program: program}, playService, engine, repo, time.UTC)
+
+	// Act
+	session, err := scheduler.AirHour(startsAt)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), session.ID)
+	assert.Equal(t, []int64{2}, engine.stopped)
+
+	require.Len(t, playService.playlists, 1)
+	tracks := playService.playlists[0].Tracks
+	require.Len(t, tracks, 2)
+	assert.Equal(t, int64(100), tracks[0].TrackID)
+	assert.Equal(t, int64(4), tracks[1].PlaylistID)
+	assert.Equal(t, &clockID, playService.options[0].ClockID)
+}
+
+func TestScheduler_AirHour_NothingScheduled(t *testing.T) {
+	// Arrange
+	repo := new(MockPlaySessionRepository)
+	repo.On("ListByStatus", mock.Anything).Return([]*models.PlaySession{}, nil)
+	playService := &recordingPlayService{}
+	scheduler := NewScheduler(&stubScheduleService{program: &models.HourProgram{}}, playService, &stopRecordingEngine{}, repo, time.UTC)
+
+	// Act
+	session, err := scheduler.AirHour(time.Now())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Nil(t, session)
+	assert.Empty(t, playService.playlists)
+}
+
+func TestScheduler_Start_AirsAtStationHour(t *testing.T) {
+	// Arrange : la station est décalée d'une demi-heure par rapport au serveur
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	schedule := &hourRecordingScheduleService{
+		stubScheduleService: stubScheduleService{program: &models.HourProgram{}},
+		hours:               make(chan time.Time, 1),
+		cancel:              cancel,
+	}
+	repo := new(MockPlaySessionRepository)
+	repo.On("ListByStatus", mock.Anything).Return([]*models.PlaySession{}, nil)
+	scheduler := NewScheduler(schedule, &recordingPlayService{}, &stopRecordingEngine{}, repo, kolkata)
+	scheduler.clock = &fakeClock{now: time.Date(2024, 1, 15, 10, 10, 0, 0, time.UTC)}
+
+	// Act
+	scheduler.Start(ctx)
+	scheduler.Wait()
+
+	// Assert : 15h40 à Kolkata, l'heure suivante commence à 16h
+	require.Len(t, schedule.hours, 1)
+	assert.Equal(t, time.Date(2024, 1, 15, 16, 0, 0, 0, kolkata), <-schedule.hours)
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"radioking-app/internal/domain/constants"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/repositories"
+	"time"
+)
+
+// StatsService agrège l'historique des lectures sur une période. Les histogrammes comptent les
+// lectures par heure du jour et par jour de la semaine dans le fuseau de la période.
+type StatsService struct {
+	repo  repositories.IStatsRepository
+	clock clock
+}
+
+func NewStatsService(repo repositories.IStatsRepository) *StatsService {
+	return &StatsService{repo: repo, clock: realClock{}}
+}
+
+// ResolvePeriod interprète la période demandée, par défaut les 7 derniers jours
+func (s *StatsService) ResolvePeriod(query models.StatsQuery) (models.StatsPeriod, error) {
+	location, err := loadLocation(query.Timezone)
+	if err != nil {
+		return models.StatsPeriod{}, err
+	}
+
+	to := s.clock.Now().In(location)
+	if query.To != "" {
+		if to, err = parseStatsBound(query.To, location, true); err != nil {
+			return models.StatsPeriod{}, err
+		}
+	}
+
+	from := to.AddDate(0, 0, -constants.DefaultStatsRangeDays)
+	if query.From != "" {
+		if from, err = parseStatsBound(query.From, location, false); err != nil {
+			return models.StatsPeriod{}, err
+		}
+	}
+
+	if !from.Before(to) {
+		return models.StatsPeriod{}, domainErrors.ErrInvalidStatsRange
+	}
+	return models.StatsPeriod{From: from, To: to, Location: location}, nil
+}
+
+func (s *StatsService) TopTracks(period models.StatsPeriod, limit int) ([]models.TrackPlayCount, error) {
+	limit, err := statsLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repo.TopTracks(period.From, period.To, limit)
+	if err != nil {
+		return nil, domainErrors.NewInternalError("failed to get top tracks", err)
+	}
+	return counts, nil
+}
+
+func (s *StatsService) TopArtists(period models.StatsPeriod, limit int) ([]models.ArtistPlayCount, error) {
+	limit, err := statsLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repo.TopArtists(period.From, period.To, limit)
+	if err != nil {
+		return nil, domainErrors.NewInternalError("failed to get top artists", err)
+	}
+	return counts, nil
+}
+
+func (s *StatsService) PlaylistTotals(period models.StatsPeriod) ([]models.PlaylistPlayCount, error) {
+	counts, err := s.repo.PlaylistTotals(period.From, period.To)
+	if err != nil {
+		return nil, domainErrors.NewInternalError("failed to get playlist totals", err)
+	}
+	return counts, nil
+}
+
+// HourlyPlays nombre de lectures pour chaque heure du jour, de 0 à 23
+func (s *StatsService) HourlyPlays(period models.StatsPeriod) ([]int, error) {
+	return s.histogram(period, 24, func(playedAt time.Time) int { return playedAt.Hour() })
+}
+
+// WeekdayPlays nombre de lectures pour chaque jour de la semaine, de 0 (dimanche) à 6 (samedi)
+func (s *StatsService) WeekdayPlays(period models.StatsPeriod) ([]int, error) {
+	return s.histogram(period, 7, func(playedAt time.Time) int { return int(playedAt.Weekday()) })
+}
+
+func (s *StatsService) histogram(period models.StatsPeriod, size int, bucket func(time.Time) int) ([]int, error) {
+	counts := make([]int, size)
+
+	// Une heure UTC ne tombe entièrement dans une heure locale que si le décalage du fuseau est
+	// un nombre entier d'heures ; sinon chaque lecture est ramenée au fuseau à partir de sa date exacte
+	if !wholeHourOffset(period) {
+		playTimes, err := s.repo.PlayTimes(period.From, period.To)
+		if err != nil {
+			return nil, domainErrors.NewInternalError("failed to get play times", err)
+		}
+		for _, playedAt := range playTimes {
+			counts[bucket(playedAt.In(period.Location))]++
+		}
+		return counts, nil
+	}
+
+	buckets, err := s.repo.HourlyPlays(period.From, period.To)
+	if err != nil {
+		return nil, domainErrors.NewInternalError("failed to get hourly plays", err)
+	}
+	for _, hour := range buckets {
+		counts[bucket(hour.Start.In(period.Location))] += hour.Plays
+	}
+	return counts, nil
+}
+
+// wholeHourOffset indique si le décalage du fuseau de la période avec UTC reste un nombre entier
+// d'heures sur toute la période, changements d'heure compris
+func wholeHourOffset(period models.StatsPeriod) bool {
+	whole := func(at time.Time) bool {
+		_, offset := at.In(period.Location).Zone()
+		return offset%int(time.Hour/time.Second) == 0
+	}
+	for at := period.From.UTC().Truncate(time.Hour); at.Before(period.To); at = at.Add(time.Hour) {
+		if !whole(at) {
+			return false
+		}
+	}
+	return whole(period.To)
+}
+
+// RebuildRollups recalcule les compteurs pré-agrégés depuis l'historique des lectures
+func (s *StatsService) RebuildRollups() (int, error) {
+	count, err := s.repo.RebuildRollups()
+	if err != nil {
+		return 0, domainErrors.NewInternalError("failed to rebuild play rollups", err)
+	}
+	return count, nil
+}
+
+// parseStatsBound lit une date ou un horodatage RFC 3339. Une date de fin est incluse : la
+// période s'arrête au début du jour suivant.
+func parseStatsBound(value string, location *time.Location, end bool) (time.Time, error) {
+	if day, err := time.ParseInLocation(previewDateLayout, value, location); err == nil {
+		if end {
+			return day.AddDate(0, 0, 1), nil
+		}
+		return day, nil
+	}
+
+	instant, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, domainErrors.ErrInvalidDate
+	}
+	return instant.In(location), nil
+}
+
+func statsLimit(limit int) (int, error) {
+	if limit == 0 {
+		return constants.DefaultStatsLimit, nil
+	}
+	if limit < 0 || limit > constants.MaxStatsLimit {
+		return 0, domainErrors.ErrInvalidStatsLimit
+	}
+	return limit, nil
+}
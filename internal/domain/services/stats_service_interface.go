@@ -0,0 +1,13 @@
+package services
+
+import "radioking-app/internal/domain/models"
+
+type IStatsService interface {
+	ResolvePeriod(query models.StatsQuery) (models.StatsPeriod, error)
+	TopTracks(period models.StatsPeriod, limit int) ([]models.TrackPlayCount, error)
+	TopArtists(period models.StatsPeriod, limit int) ([]models.ArtistPlayCount, error)
+	PlaylistTotals(period models.StatsPeriod) ([]models.PlaylistPlayCount, error)
+	HourlyPlays(period models.StatsPeriod) ([]int, error)
+	WeekdayPlays(period models.StatsPeriod) ([]int, error)
+	RebuildRollups() (int, error)
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockStatsRepository is a mock implementation of IStatsRepository
+type MockStatsRepository struct {
+	mock.Mock
+}
+
+func (m *MockStatsRepository) TopTracks(from time.Time, to time.Time, limit int) ([]models.TrackPlayCount, error) {
+	args := m.Called(from, to, limit)
+	return args.Get(0).([]models.TrackPlayCount), args.Error(1)
+}
+
+func (m *MockStatsRepository) TopArtists(from time.Time, to time.Time, limit int) ([]models.ArtistPlayCount, error) {
+	args := m.Called(from, to, limit)
+	return args.Get(0).([]models.ArtistPlayCount), args.Error(1)
+}
+
+func (m *MockStatsRepository) PlaylistTotals(from time.Time, to time.Time) ([]models.PlaylistPlayCount, error) {
+	args := m.Called(from, to)
+	return args.Get(0).([]models.PlaylistPlayCount), args.Error(1)
+}
+
+func (m *MockStatsRepository) HourlyPlays(from time.Time, to time.Time) ([]models.PlayBucket, error) {
+	args := m.Called(from, to)
+	return args.Get(0).([]models.PlayBucket), args.Error(1)
+}
+
+func (m *MockStatsRepository) PlayTimes(from time.Time, to time.Time) ([]time.Time, error) {
+	args := m.Called(from, to)
+	return args.Get(0).([]time.Time), args.Error(1)
+}
+
+func (m *MockStatsRepository) RebuildRollups() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func TestStatsService_ResolvePeriod(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		query    models.StatsQuery
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  error
+	}{
+		{
+			name:     "default range",
+			query:    models.StatsQuery{Timezone: "UTC"},
+			wantFrom: now.AddDate(0, 0, -7),
+			wantTo:   now,
+		},
+		{
+			name:     "dates in timezone, end date included",
+			query:    models.StatsQuery{From: "2024-01-01", To: "2024-01-07", Timezone: "Europe/Paris"},
+			wantFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, paris),
+			wantTo:   time.Date(2024, 1, 8, 0, 0, 0, 0, paris),
+		},
+		{
+			name:     "RFC 3339 bounds",
+			query:    models.StatsQuery{From: "2024-01-01T06:00:00Z", To: "2024-01-01T08:00:00Z", Timezone: "UTC"},
+			wantFrom: time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "empty range",
+			query:   models.StatsQuery{From: "2024-01-08", To: "2024-01-07"},
+			wantErr: domainErrors.ErrInvalidStatsRange,
+		},
+		{
+			name:    "invalid date",
+			query:   models.StatsQuery{From: "15/01/2024"},
+			wantErr: domainErrors.ErrInvalidDate,
+		},
+		{
+			name:    "invalid timezone",
+			query:   models.StatsQuery{Timezone: "Mars/Olympus"},
+			wantErr: domainErrors.ErrInvalidTimezone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewStatsService(new(MockStatsRepository))
+			service.clock = &manualClock{now: now}
+
+			period, err := service.ResolvePeriod(tt.query)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.wantFrom.Equal(period.From), "from %v", period.From)
+			assert.True(t, tt.wantTo.Equal(period.To), "to %v", period.To)
+		})
+	}
+}
+
+func TestStatsService_HistogramsFollowTimezone(t *testing.T) {
+	// Arrange : 23h UTC le dimanche correspond à minuit le lundi à Paris
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	period := models.StatsPeriod{From: time.Date(2024, 1, 14, 0, 0, 0, 0, paris), To: time.Date(2024, 1, 16, 0, 0, 0, 0, paris), Location: paris}
+
+	repo := new(MockStatsRepository)
+	repo.On("HourlyPlays", period.From, period.To).Return([]models.PlayBucket{
+		{Start: time.Date(2024, 1, 14, 23, 0, 0, 0, time.UTC), Plays: 1},
+		{Start: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), Plays: 2},
+	}, nil)
+	service := NewStatsService(repo)
+
+	// Act
+	hourly, err := service.HourlyPlays(period)
+	require.NoError(t, err)
+	weekdays, err := service.WeekdayPlays(period)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, hourly, 24)
+	assert.Equal(t, 1, hourly[0])
+	assert.Equal(t, 2, hourly[10])
+	require.Len(t, weekdays, 7)
+	assert.Equal(t, 3, weekdays[time.Monday])
+	assert.Equal(t, 0, weekdays[time.Sunday])
+}
+
+func TestStatsService_HistogramsFollowHalfHourTimezone(t *testing.T) {
+	// Arrange : Kolkata est décalé de 5h30, 18h40 UTC le dimanche correspond à 0h10 le lundi
+	// et 23h30 UTC à 5h le lundi ; les lectures sont ramenées au fuseau depuis leur date exacte
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+	period := models.StatsPeriod{From: time.Date(2024, 1, 14, 0, 0, 0, 0, kolkata), To: time.Date(2024, 1, 16, 0, 0, 0, 0, kolkata), Location: kolkata}
+
+	repo := new(MockStatsRepository)
+	repo.On("PlayTimes", period.From, period.To).Return([]time.Time{
+		time.Date(2024, 1, 14, 18, 20, 0, 0, time.UTC),
+		time.Date(2024, 1, 14, 18, 40, 0, 0, time.UTC),
+		time.Date(2024, 1, 14, 23, 30, 0, 0, time.UTC),
+	}, nil)
+	service := NewStatsService(repo)
+
+	// Act
+	hourly, err := service.HourlyPlays(period)
+	require.NoError(t, err)
+	weekdays, err := service.WeekdayPlays(period)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, hourly, 24)
+	assert.Equal(t, 1, hourly[23])
+	assert.Equal(t, 1, hourly[0])
+	assert.Equal(t, 1, hourly[5])
+	require.Len(t, weekdays, 7)
+	assert.Equal(t, 1, weekdays[time.Sunday])
+	assert.Equal(t, 2, weekdays[time.Monday])
+	repo.AssertNotCalled(t, "HourlyPlays", mock.Anything, mock.Anything)
+}
+
+func TestStatsService_InvalidLimit(t *testing.T) {
+	// Arrange
+	service := NewStatsService(new(MockStatsRepository))
+
+	// Act
+	_, err := service.TopTracks(models.StatsPeriod{}, 1000)
+
+	// Assert
+	assert.ErrorIs(t, err, domainErrors.ErrInvalidStatsLimit)
+}
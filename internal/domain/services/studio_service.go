@@ -0,0 +1,80 @@
+package services
+
+import (
+	"log"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/repositories"
+)
+
+// StudioService pilote les sessions depuis la console des opérateurs avec les mêmes services
+// que l'API HTTP, et leur diffuse chaque changement d'état des sessions.
+type StudioService struct {
+	engine             IPlaybackEngine
+	applicationService IPlaylistApplicationService
+	trackService       ITrackService
+	sessionsRepo       repositories.IPlaySessionRepository
+	updates            *broadcaster[models.PlaySession]
+}
+
+func NewStudioService(engine IPlaybackEngine, applicationService IPlaylistApplicationService,
+	trackService ITrackService, sessionsRepo repositories.IPlaySessionRepository) *StudioService {
+	return &StudioService{
+		engine:             engine,
+		applicationService: applicationService,
+		trackService:       trackService,
+		sessionsRepo:       sessionsRepo,
+		updates:            newBroadcaster[models.PlaySession]("Studio"),
+	}
+}
+
+// ActiveSessions retourne les sessions en lecture ou en pause
+func (s *StudioService) ActiveSessions() ([]*models.PlaySession, error) {
+	sessions, err := s.sessionsRepo.ListByStatus(models.PlaySessionPlaying, models.PlaySessionPaused)
+	if err != nil {
+		return nil, domainErrors.NewInternalError("failed to get active play sessions", err)
+	}
+	return sessions, nil
+}
+
+// Execute applique la commande et retourne le nouvel état de la session concernée
+func (s *StudioService) Execute(command models.StudioCommand) (*models.PlaySession, error) {
+	log.Printf("Studio command %s on session %d by %s", command.Type, command.SessionID, command.Operator)
+
+	switch command.Type {
+	case models.StudioPlay:
+		result, err := s.applicationService.PlayPlaylist(command.PlaylistID, command.Options)
+		if err != nil {
+			return nil, err
+		}
+		return s.engine.GetSession(result.SessionID)
+	case models.StudioInsert:
+		track, err := s.trackService.GetTrack(command.TrackID)
+		if err != nil {
+			return nil, err
+		}
+		return s.engine.InsertTrack(command.SessionID, *track)
+	case models.StudioPause:
+		return s.engine.Pause(command.SessionID)
+	case models.StudioResume:
+		return s.engine.Resume(command.SessionID)
+	case models.StudioSkip:
+		return s.engine.Skip(command.SessionID)
+	case models.StudioBack:
+		return s.engine.Back(command.SessionID)
+	case models.StudioStop:
+		return s.engine.Stop(command.SessionID)
+	default:
+		return nil, domainErrors.ErrUnknownStudioCommand
+	}
+}
+
+// Subscribe retourne les changements d'état de toutes les sessions et la fonction de désabonnement
+func (s *StudioService) Subscribe() (<-chan models.PlaySession, func()) {
+	return s.updates.subscribe()
+}
+
+// SessionChanged diffuse le nouvel état d'une session aux consoles connectées
+func (s *StudioService) SessionChanged(session models.PlaySession) {
+	s.updates.publish(session)
+}
@@ -0,0 +1,9 @@
+package services
+
+import "radioking-app/internal/domain/models"
+
+type IStudioService interface {
+	ActiveSessions() ([]*models.PlaySession, error)
+	Execute(command models.StudioCommand) (*models.PlaySession, error)
+	Subscribe() (<-chan models.PlaySession, func())
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStudioService_ExecuteBroadcastsSessionChanges(t *testing.T) {
+	// Arrange
+	repo := new(MockPlaySessionRepository)
+	repo.On("Create", mock.Anything).Return(nil)
+	repo.On("Update", mock.Anything).Return(nil)
+	engine := newTestPlaybackEngine(repo, &manualClock{now: time.Now()})
+
+	trackRepo := new(MockTrackRepository)
+	trackRepo.On("GetByID", 20).Return(&models.Track{ID: 20, Title: "Live", DurationSeconds: 90}, nil)
+	studio := NewStudioService(engine, nil, NewTrackService(trackRepo), repo)
+	engine.AddListener(studio)
+
+	session, err := engine.Play(models.Playlist{ID: 1, Tracks: []models.PlaylistTrack{
+		{TrackID: 10, Track: models.Track{ID: 10, DurationSeconds: 180}},
+	}}, models.PlayOptions{})
+	require.NoError(t, err)
+	repo.On("GetByID", session.ID).Return(session, nil)
+
+	updates, unsubscribe := studio.Subscribe()
+	defer unsubscribe()
+
+	// Act
+	inserted, err := studio.Execute(models.StudioCommand{Type: models.StudioInsert, SessionID: session.ID, TrackID: 20, Operator: "dj"})
+	require.NoError(t, err)
+	_, err = studio.Execute(models.StudioCommand{Type: models.StudioStop, SessionID: session.ID, Operator: "dj"})
+	require.NoError(t, err)
+	engine.Wait()
+
+	// Assert
+	require.Len(t, inserted.Items, 2)
+	assert.Equal(t, "Live", inserted.Items[1].Title)
+	require.Len(t, updates, 2)
+	assert.Len(t, (<-updates).Items, 2)
+	assert.Equal(t, models.PlaySessionStopped, (<-updates).Status)
+}
+
+func TestStudioService_ExecuteUnknownCommand(t *testing.T) {
+	// Arrange
+	studio := NewStudioService(nil, nil, nil, nil)
+
+	// Act
+	_, err := studio.Execute(models.StudioCommand{Type: "rewind", SessionID: 1})
+
+	// Assert
+	assert.ErrorIs(t, err, domainErrors.ErrUnknownStudioCommand)
+}
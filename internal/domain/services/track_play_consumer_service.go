@@ -2,20 +2,36 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	domainErrors "radioking-app/internal/domain/errors"
 	"radioking-app/internal/domain/models"
 	"radioking-app/internal/infrastructure/messaging"
 	"sync"
+	"sync/atomic"
 )
 
+// TrackEventListener est notifié des événements de lecture une fois enregistrés
+type TrackEventListener interface {
+	TrackPlayed(event models.TrackPlayedEvent)
+	TrackEnded(event models.TrackEndedEvent)
+}
+
 type TrackPlayConsumerService struct {
 	consumer     messaging.MessageConsumer
 	trackPlaySvc ITrackPlayService
+	listeners    []TrackEventListener
 	stopChan     chan struct{}
+	stopOnce     sync.Once
 	wg           sync.WaitGroup
 	isRunning    bool
 	mu           sync.Mutex
+
+	played          atomic.Int64
+	ended           atomic.Int64
+	duplicatePlayed atomic.Int64
+	duplicateEnded  atomic.Int64
 }
 
 func NewTrackPlayConsumerService(consumer messaging.MessageConsumer, trackPlaySvc ITrackPlayService) *TrackPlayConsumerService {
@@ -26,6 +42,11 @@ func NewTrackPlayConsumerService(consumer messaging.MessageConsumer, trackPlaySv
 	}
 }
 
+// AddListener abonne listener aux événements consommés, à appeler avant Start
+func (s *TrackPlayConsumerService) AddListener(listener TrackEventListener) {
+	s.listeners = append(s.listeners, listener)
+}
+
 func (s *TrackPlayConsumerService) Start(ctx context.Context) error {
 	s.mu.Lock()
 	if s.isRunning {
@@ -35,13 +56,14 @@ func (s *TrackPlayConsumerService) Start(ctx context.Context) error {
 	s.isRunning = true
 	s.mu.Unlock()
 
-	// Create handler function for processing events
-	handler := func(event models.TrackPlayedEvent) error {
-		return s.trackPlaySvc.RecordTrackPlay(event)
+	// La consommation s'arrête à l'annulation de ctx ou à l'appel de Stop
+	consumeCtx, cancel := context.WithCancel(ctx)
+	err := s.consumer.Subscribe(consumeCtx, models.EventTrackPlayed, s.onTrackPlayed)
+	if err == nil {
+		err = s.consumer.Subscribe(consumeCtx, models.EventTrackEnded, s.onTrackEnded)
 	}
-
-	err := s.consumer.ConsumeTrackPlayedEvents(ctx, handler)
 	if err != nil {
+		cancel()
 		s.mu.Lock()
 		s.isRunning = false
 		s.mu.Unlock()
@@ -59,6 +81,7 @@ func (s *TrackPlayConsumerService) Start(ctx context.Context) error {
 			s.isRunning = false
 			s.mu.Unlock()
 		}()
+		defer cancel()
 
 		select {
 		case <-ctx.Done():
@@ -71,18 +94,96 @@ func (s *TrackPlayConsumerService) Start(ctx context.Context) error {
 	return nil
 }
 
-func (s *TrackPlayConsumerService) Stop() {
-	s.mu.Lock()
-	if !s.isRunning {
-		s.mu.Unlock()
-		return
+func (s *TrackPlayConsumerService) onTrackPlayed(envelope models.Event) error {
+	var event models.TrackPlayedEvent
+	if err := envelope.DecodePayload(&event); err != nil {
+		return err
 	}
-	s.mu.Unlock()
+	return s.handleTrackPlayed(event)
+}
 
-	close(s.stopChan)
+func (s *TrackPlayConsumerService) onTrackEnded(envelope models.Event) error {
+	var event models.TrackEndedEvent
+	if err := envelope.DecodePayload(&event); err != nil {
+		return err
+	}
+	return s.handleTrackEnded(event)
+}
+
+// Name nom de la projection de l'historique des lectures : les événements rejoués depuis le journal
+// sont appliqués comme des événements consommés
+func (s *TrackPlayConsumerService) Name() string {
+	return "track-plays"
+}
+
+func (s *TrackPlayConsumerService) EventTypes() []models.EventType {
+	return []models.EventType{models.EventTrackPlayed, models.EventTrackEnded}
+}
+
+// Apply applique un événement rejoué comme un événement consommé : une lecture déjà enregistrée est ignorée
+func (s *TrackPlayConsumerService) Apply(event models.Event) error {
+	switch event.Type {
+	case models.EventTrackPlayed:
+		return s.onTrackPlayed(event)
+	case models.EventTrackEnded:
+		return s.onTrackEnded(event)
+	default:
+		return fmt.Errorf("unexpected event type %s", event.Type)
+	}
+}
+
+// handleTrackPlayed enregistre la lecture puis notifie les listeners. Une redélivrance est
+// comptée et acquittée sans notifier les listeners une seconde fois.
+func (s *TrackPlayConsumerService) handleTrackPlayed(event models.TrackPlayedEvent) error {
+	if err := s.trackPlaySvc.RecordTrackPlay(event); err != nil {
+		if errors.Is(err, domainErrors.ErrDuplicateEvent) {
+			s.duplicatePlayed.Add(1)
+			log.Printf("Skipped duplicate track played event: EventID=%s", event.EventID)
+			return nil
+		}
+		return err
+	}
+	s.played.Add(1)
+	for _, listener := range s.listeners {
+		listener.TrackPlayed(event)
+	}
+	return nil
+}
+
+func (s *TrackPlayConsumerService) handleTrackEnded(event models.TrackEndedEvent) error {
+	if err := s.trackPlaySvc.RecordTrackEnd(event); err != nil {
+		if errors.Is(err, domainErrors.ErrDuplicateEvent) {
+			s.duplicateEnded.Add(1)
+			log.Printf("Skipped duplicate track ended event: EventID=%s", event.EventID)
+			return nil
+		}
+		return err
+	}
+	s.ended.Add(1)
+	for _, listener := range s.listeners {
+		listener.TrackEnded(event)
+	}
+	return nil
+}
+
+// Stats compteurs des événements traités et des doublons ignorés depuis le démarrage
+func (s *TrackPlayConsumerService) Stats() models.ConsumerStats {
+	return models.ConsumerStats{
+		PlayedEvents:          s.played.Load(),
+		EndedEvents:           s.ended.Load(),
+		DuplicatePlayedEvents: s.duplicatePlayed.Load(),
+		DuplicateEndedEvents:  s.duplicateEnded.Load(),
+	}
+}
+
+// Stop arrête la consommation et attend la fin du traitement des messages déjà reçus,
+// acquittés avant la fermeture du consommateur
+func (s *TrackPlayConsumerService) Stop() {
+	s.stopOnce.Do(func() { close(s.stopChan) })
 	s.wg.Wait()
 
 	if s.consumer != nil {
+		s.consumer.Wait()
 		s.consumer.Close()
 	}
 
@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+	"radioking-app/internal/domain/models"
+)
+
+type ITrackPlayConsumerService interface {
+	AddListener(listener TrackEventListener)
+	Start(ctx context.Context) error
+	Stop()
+	Stats() models.ConsumerStats
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockMessageConsumer conserve les handlers enregistrés pour leur livrer des événements.
+// calls trace l'attente des messages en cours et la fermeture.
+type MockMessageConsumer struct {
+	mock.Mock
+	ctx      context.Context
+	handlers map[models.EventType]func(models.Event) error
+	calls    []string
+}
+
+func (m *MockMessageConsumer) Subscribe(ctx context.Context, eventType models.EventType, handler func(models.Event) error) error {
+	if m.handlers == nil {
+		m.ctx = ctx
+		m.handlers = map[models.EventType]func(models.Event) error{}
+	}
+	m.handlers[eventType] = handler
+	return nil
+}
+
+// deliver livre l'événement au handler de son type
+func (m *MockMessageConsumer) deliver(t *testing.T, event interface{ Envelope() (models.Event, error) }) error {
+	envelope, err := event.Envelope()
+	require.NoError(t, err)
+	return m.handlers[envelope.Type](envelope)
+}
+
+func (m *MockMessageConsumer) Wait() {
+	// Les messages en cours ne sont attendus qu'une fois la consommation arrêtée
+	if m.ctx != nil && m.ctx.Err() == nil {
+		m.calls = append(m.calls, "wait before cancel")
+		return
+	}
+	m.calls = append(m.calls, "wait")
+}
+
+func (m *MockMessageConsumer) Close() error {
+	m.calls = append(m.calls, "close")
+	return nil
+}
+
+// countingListener compte les notifications reçues
+type countingListener struct {
+	played int
+	ended  int
+}
+
+func (l *countingListener) TrackPlayed(event models.TrackPlayedEvent) { l.played++ }
+func (l *countingListener) TrackEnded(event models.TrackEndedEvent)   { l.ended++ }
+
+func TestTrackPlayConsumerService_SkipsRedeliveredEvents(t *testing.T) {
+	// Arrange : le dépôt reconnaît la seconde livraison de chaque événement
+	repo := new(MockTrackPlayRepository)
+	repo.On("Create", mock.AnythingOfType("*models.TrackPlay")).Return(nil).Once()
+	repo.On("Create", mock.AnythingOfType("*models.TrackPlay")).Return(repositories.ErrDuplicateEvent).Once()
+	repo.On("RecordEnd", mock.AnythingOfType("*models.TrackPlay")).Return(nil).Once()
+	repo.On("RecordEnd", mock.AnythingOfType("*models.TrackPlay")).Return(repositories.ErrDuplicateEvent).Once()
+
+	consumer := new(MockMessageConsumer)
+	listener := &countingListener{}
+	service := NewTrackPlayConsumerService(consumer, NewTrackPlayService(repo))
+	service.AddListener(listener)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, service.Start(ctx))
+	defer service.Stop()
+
+	played := models.TrackPlayedEvent{SessionID: 1, Sequence: 1, PlaylistID: 1, TrackID: 1, PlayedAt: time.Now(), EventID: "played"}
+	ended := models.TrackEndedEvent{SessionID: 1, Sequence: 1, PlaylistID: 1, TrackID: 1, EndedAt: time.Now(), EventID: "ended"}
+
+	// Act : chaque événement est livré deux fois, les doublons sont acquittés sans erreur
+	assert.NoError(t, consumer.deliver(t, played))
+	assert.NoError(t, consumer.deliver(t, played))
+	assert.NoError(t, consumer.deliver(t, ended))
+	assert.NoError(t, consumer.deliver(t, ended))
+
+	// Assert
+	assert.Equal(t, 1, listener.played)
+	assert.Equal(t, 1, listener.ended)
+	assert.Equal(t, models.ConsumerStats{PlayedEvents: 1, EndedEvents: 1, DuplicatePlayedEvents: 1, DuplicateEndedEvents: 1}, service.Stats())
+	repo.AssertExpectations(t)
+}
+
+func TestTrackPlayConsumerService_StopDrainsBeforeClosing(t *testing.T) {
+	// Arrange
+	consumer := new(MockMessageConsumer)
+	service := NewTrackPlayConsumerService(consumer, NewTrackPlayService(new(MockTrackPlayRepository)))
+	require.NoError(t, service.Start(context.Background()))
+
+	// Act
+	service.Stop()
+	service.Stop()
+
+	// Assert : la consommation est annulée, puis les messages en cours sont attendus avant la fermeture
+	assert.ErrorIs(t, consumer.ctx.Err(), context.Canceled)
+	assert.Equal(t, []string{"wait", "close", "wait", "close"}, consumer.calls)
+}
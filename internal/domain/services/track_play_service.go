@@ -1,15 +1,21 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	domainErrors "radioking-app/internal/domain/errors"
 	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/repositories"
+	"time"
 )
 
 type TrackPlayRepository interface {
 	Create(trackPlay *models.TrackPlay) error
-	GetByPlaylistID(playlistID int) ([]*models.TrackPlay, error)
-	GetByTrackID(trackID int) ([]*models.TrackPlay, error)
+	RecordEnd(trackPlay *models.TrackPlay) error
+	GetByPlaylistID(playlistID int, from, to time.Time, limit int) ([]*models.TrackPlay, error)
+	GetByTrackID(trackID int, from, to time.Time, limit int) ([]*models.TrackPlay, error)
+	ListSince(since time.Time) ([]*models.TrackPlay, error)
 }
 
 type TrackPlayService struct {
@@ -28,9 +34,19 @@ func (s *TrackPlayService) RecordTrackPlay(event models.TrackPlayedEvent) error
 		TrackID:    event.TrackID,
 		Position:   event.Position,
 		PlayedAt:   event.PlayedAt,
+		Sequence:   event.Sequence,
+	}
+	if event.SessionID != 0 {
+		trackPlay.SessionID = &event.SessionID
+	}
+	if event.EventID != "" {
+		trackPlay.EventID = &event.EventID
 	}
 
 	if err := s.repository.Create(trackPlay); err != nil {
+		if errors.Is(err, repositories.ErrDuplicateEvent) {
+			return domainErrors.ErrDuplicateEvent
+		}
 		return fmt.Errorf("failed to record track play: %w", err)
 	}
 
@@ -40,16 +56,59 @@ func (s *TrackPlayService) RecordTrackPlay(event models.TrackPlayedEvent) error
 	return nil
 }
 
-func (s *TrackPlayService) GetPlaylistPlays(playlistID int) ([]*models.TrackPlay, error) {
-	plays, err := s.repository.GetByPlaylistID(playlistID)
+// RecordTrackEnd marque la fin de la lecture : complète si la track a été jouée jusqu'au bout,
+// partielle après un skip, un retour ou un arrêt
+func (s *TrackPlayService) RecordTrackEnd(event models.TrackEndedEvent) error {
+	trackPlay := &models.TrackPlay{
+		PlaylistID:    event.PlaylistID,
+		TrackID:       event.TrackID,
+		Position:      event.Position,
+		PlayedAt:      event.PlayedAt,
+		SessionID:     &event.SessionID,
+		Sequence:      event.Sequence,
+		EndedAt:       &event.EndedAt,
+		PlayedSeconds: &event.PlayedSeconds,
+		EndReason:     event.Reason,
+	}
+	if event.EventID != "" {
+		trackPlay.EndEventID = &event.EventID
+	}
+
+	if err := s.repository.RecordEnd(trackPlay); err != nil {
+		if errors.Is(err, repositories.ErrDuplicateEvent) {
+			return domainErrors.ErrDuplicateEvent
+		}
+		return fmt.Errorf("failed to record track end: %w", err)
+	}
+
+	log.Printf("Recorded track end: SessionID=%d, Sequence=%d, TrackID=%d, Reason=%s, PlayedSeconds=%d",
+		event.SessionID, event.Sequence, event.TrackID, event.Reason, event.PlayedSeconds)
+
+	return nil
+}
+
+// GetPlaylistPlays retourne au plus limit lectures de la playlist sur la période, les plus récentes en premier
+func (s *TrackPlayService) GetPlaylistPlays(playlistID int, period models.StatsPeriod, limit int) ([]*models.TrackPlay, error) {
+	limit, err := statsLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	plays, err := s.repository.GetByPlaylistID(playlistID, period.From, period.To, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get playlist plays: %w", err)
 	}
 	return plays, nil
 }
 
-func (s *TrackPlayService) GetTrackPlays(trackID int) ([]*models.TrackPlay, error) {
-	plays, err := s.repository.GetByTrackID(trackID)
+// GetTrackPlays retourne au plus limit lectures de la track sur la période, les plus récentes en premier
+func (s *TrackPlayService) GetTrackPlays(trackID int, period models.StatsPeriod, limit int) ([]*models.TrackPlay, error) {
+	limit, err := statsLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	plays, err := s.repository.GetByTrackID(trackID, period.From, period.To, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get track plays: %w", err)
 	}
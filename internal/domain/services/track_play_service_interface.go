@@ -4,6 +4,7 @@ import "radioking-app/internal/domain/models"
 
 type ITrackPlayService interface {
 	RecordTrackPlay(event models.TrackPlayedEvent) error
-	GetPlaylistPlays(playlistID int) ([]*models.TrackPlay, error)
-	GetTrackPlays(trackID int) ([]*models.TrackPlay, error)
+	RecordTrackEnd(event models.TrackEndedEvent) error
+	GetPlaylistPlays(playlistID int, period models.StatsPeriod, limit int) ([]*models.TrackPlay, error)
+	GetTrackPlays(trackID int, period models.StatsPeriod, limit int) ([]*models.TrackPlay, error)
 }
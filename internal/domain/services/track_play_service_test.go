@@ -5,7 +5,10 @@ import (
 	"testing"
 	"time"
 
+	"radioking-app/internal/domain/constants"
+	domainErrors "radioking-app/internal/domain/errors"
 	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/repositories"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -21,13 +24,23 @@ func (m *MockTrackPlayRepository) Create(trackPlay *models.TrackPlay) error {
 	return args.Error(0)
 }
 
-func (m *MockTrackPlayRepository) GetByPlaylistID(playlistID int) ([]*models.TrackPlay, error) {
-	args := m.Called(playlistID)
+func (m *MockTrackPlayRepository) RecordEnd(trackPlay *models.TrackPlay) error {
+	args := m.Called(trackPlay)
+	return args.Error(0)
+}
+
+func (m *MockTrackPlayRepository) GetByPlaylistID(playlistID int, from, to time.Time, limit int) ([]*models.TrackPlay, error) {
+	args := m.Called(playlistID, from, to, limit)
+	return args.Get(0).([]*models.TrackPlay), args.Error(1)
+}
+
+func (m *MockTrackPlayRepository) GetByTrackID(trackID int, from, to time.Time, limit int) ([]*models.TrackPlay, error) {
+	args := m.Called(trackID, from, to, limit)
 	return args.Get(0).([]*models.TrackPlay), args.Error(1)
 }
 
-func (m *MockTrackPlayRepository) GetByTrackID(trackID int) ([]*models.TrackPlay, error) {
-	args := m.Called(trackID)
+func (m *MockTrackPlayRepository) ListSince(since time.Time) ([]*models.TrackPlay, error) {
+	args := m.Called(since)
 	return args.Get(0).([]*models.TrackPlay), args.Error(1)
 }
 
@@ -88,6 +101,89 @@ func TestTrackPlayService_RecordTrackPlay(t *testing.T) {
 	}
 }
 
+func TestTrackPlayService_RecordDuplicateEvents(t *testing.T) {
+	// Arrange
+	repo := new(MockTrackPlayRepository)
+	repo.On("Create", mock.MatchedBy(func(play *models.TrackPlay) bool {
+		return play.EventID != nil && *play.EventID == "played-event-id"
+	})).Return(repositories.ErrDuplicateEvent)
+	repo.On("RecordEnd", mock.MatchedBy(func(play *models.TrackPlay) bool {
+		return play.EndEventID != nil && *play.EndEventID == "ended-event-id"
+	})).Return(repositories.ErrDuplicateEvent)
+	service := NewTrackPlayService(repo)
+
+	// Act
+	playErr := service.RecordTrackPlay(models.TrackPlayedEvent{PlaylistID: 1, TrackID: 1, PlayedAt: time.Now(), EventID: "played-event-id"})
+	endErr := service.RecordTrackEnd(models.TrackEndedEvent{SessionID: 1, Sequence: 1, PlaylistID: 1, TrackID: 1, EventID: "ended-event-id"})
+
+	// Assert
+	assert.ErrorIs(t, playErr, domainErrors.ErrDuplicateEvent)
+	assert.ErrorIs(t, endErr, domainErrors.ErrDuplicateEvent)
+	repo.AssertExpectations(t)
+}
+
+func TestTrackPlayService_RecordTrackEnd(t *testing.T) {
+	event := models.TrackEndedEvent{
+		SessionID:     3,
+		Sequence:      2,
+		PlaylistID:    1,
+		TrackID:       1,
+		PlayedAt:      time.Now().Add(-40 * time.Second),
+		EndedAt:       time.Now(),
+		PlayedSeconds: 40,
+		Reason:        models.TrackEndSkipped,
+		EventID:       "test-event-id",
+	}
+
+	tests := []struct {
+		name    string
+		mockFn  func(*MockTrackPlayRepository)
+		wantErr bool
+	}{
+		{
+			name: "partial play recorded",
+			mockFn: func(repo *MockTrackPlayRepository) {
+				repo.On("RecordEnd", mock.MatchedBy(func(play *models.TrackPlay) bool {
+					return *play.SessionID == 3 && play.Sequence == 2 && *play.PlayedSeconds == 40 && !play.IsComplete()
+				})).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "repository error",
+			mockFn: func(repo *MockTrackPlayRepository) {
+				repo.On("RecordEnd", mock.AnythingOfType("*models.TrackPlay")).Return(errors.New("database error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockTrackPlayRepository)
+			tt.mockFn(repo)
+
+			service := NewTrackPlayService(repo)
+			err := service.RecordTrackEnd(event)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "failed to record track end")
+			} else {
+				assert.NoError(t, err)
+			}
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+var testPlaysPeriod = models.StatsPeriod{
+	From:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	To:       time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	Location: time.UTC,
+}
+
 func TestTrackPlayService_GetPlaylistPlays(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -104,7 +200,7 @@ func TestTrackPlayService_GetPlaylistPlays(t *testing.T) {
 					{ID: 1, PlaylistID: 1, TrackID: 1, Position: 0},
 					{ID: 2, PlaylistID: 1, TrackID: 2, Position: 1},
 				}
-				repo.On("GetByPlaylistID", 1).Return(plays, nil)
+				repo.On("GetByPlaylistID", 1, testPlaysPeriod.From, testPlaysPeriod.To, constants.DefaultStatsLimit).Return(plays, nil)
 			},
 			want: []*models.TrackPlay{
 				{ID: 1, PlaylistID: 1, TrackID: 1, Position: 0},
@@ -116,7 +212,7 @@ func TestTrackPlayService_GetPlaylistPlays(t *testing.T) {
 			name:       "repository error",
 			playlistID: 1,
 			mockFn: func(repo *MockTrackPlayRepository) {
-				repo.On("GetByPlaylistID", 1).Return([]*models.TrackPlay(nil), errors.New("database error"))
+				repo.On("GetByPlaylistID", 1, testPlaysPeriod.From, testPlaysPeriod.To, constants.DefaultStatsLimit).Return([]*models.TrackPlay(nil), errors.New("database error"))
 			},
 			want:    nil,
 			wantErr: true,
@@ -129,7 +225,7 @@ func TestTrackPlayService_GetPlaylistPlays(t *testing.T) {
 			tt.mockFn(repo)
 
 			service := NewTrackPlayService(repo)
-			result, err := service.GetPlaylistPlays(tt.playlistID)
+			result, err := service.GetPlaylistPlays(tt.playlistID, testPlaysPeriod, 0)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -161,7 +257,7 @@ func TestTrackPlayService_GetTrackPlays(t *testing.T) {
 					{ID: 1, PlaylistID: 1, TrackID: 1, Position: 0},
 					{ID: 3, PlaylistID: 2, TrackID: 1, Position: 2},
 				}
-				repo.On("GetByTrackID", 1).Return(plays, nil)
+				repo.On("GetByTrackID", 1, testPlaysPeriod.From, testPlaysPeriod.To, constants.DefaultStatsLimit).Return(plays, nil)
 			},
 			want: []*models.TrackPlay{
 				{ID: 1, PlaylistID: 1, TrackID: 1, Position: 0},
@@ -173,7 +269,7 @@ func TestTrackPlayService_GetTrackPlays(t *testing.T) {
 			name:    "repository error",
 			trackID: 1,
 			mockFn: func(repo *MockTrackPlayRepository) {
-				repo.On("GetByTrackID", 1).Return([]*models.TrackPlay(nil), errors.New("database error"))
+				repo.On("GetByTrackID", 1, testPlaysPeriod.From, testPlaysPeriod.To, constants.DefaultStatsLimit).Return([]*models.TrackPlay(nil), errors.New("database error"))
 			},
 			want:    nil,
 			wantErr: true,
@@ -186,7 +282,7 @@ func TestTrackPlayService_GetTrackPlays(t *testing.T) {
 			tt.mockFn(repo)
 
 			service := NewTrackPlayService(repo)
-			result, err := service.GetTrackPlays(tt.trackID)
+			result, err := service.GetTrackPlays(tt.trackID, testPlaysPeriod, 0)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -201,3 +297,16 @@ func TestTrackPlayService_GetTrackPlays(t *testing.T) {
 		})
 	}
 }
+
+func TestTrackPlayService_GetTrackPlays_InvalidLimit(t *testing.T) {
+	// Arrange
+	repo := new(MockTrackPlayRepository)
+	service := NewTrackPlayService(repo)
+
+	// Act
+	_, err := service.GetTrackPlays(1, testPlaysPeriod, constants.MaxStatsLimit+1)
+
+	// Assert
+	assert.ErrorIs(t, err, domainErrors.ErrInvalidStatsLimit)
+	repo.AssertNotCalled(t, "GetByTrackID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
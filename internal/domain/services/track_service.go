@@ -0,0 +1,146 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"radioking-app/internal/domain/constants"
+	domainErrors "radioking-app/internal/domain/errors"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/repositories"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// TrackService gère le catalogue de tracks partagé entre les playlists
+type TrackService struct {
+	repo repositories.ITrackRepository
+
+	// RecordEvents enregistre l'événement de modification de chaque playlist dont une track supprimée
+	// est retirée, dans la transaction de la suppression
+	RecordEvents bool
+}
+
+func NewTrackService(repo repositories.ITrackRepository) *TrackService {
+	return &TrackService{repo: repo}
+}
+
+func (s *TrackService) CreateTrack(track *models.Track) error {
+	track.ISRC = models.NormalizeISRC(track.ISRC)
+	if err := validateTrack(track); err != nil {
+		return err
+	}
+
+	if err := s.ensureUnique(track, 0); err != nil {
+		return err
+	}
+
+	if err := s.repo.Create(track); err != nil {
+		return domainErrors.NewInternalError("failed to create track", err)
+	}
+	return nil
+}
+
+func (s *TrackService) ListTracks(limit int, cursor string) (*models.TrackPage, error) {
+	if limit == 0 {
+		limit = constants.DefaultPageSize
+	}
+	if limit < 0 || limit > constants.MaxPageSize {
+		return nil, domainErrors.ErrInvalidPageLimit
+	}
+
+	var afterID int64
+	if cursor != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return nil, domainErrors.ErrInvalidCursor
+		}
+		if afterID, err = strconv.ParseInt(string(raw), 10, 64); err != nil {
+			return nil, domainErrors.ErrInvalidCursor
+		}
+	}
+
+	tracks, err := s.repo.List(limit+1, afterID)
+	if err != nil {
+		return nil, domainErrors.NewInternalError("failed to list tracks", err)
+	}
+
+	page := &models.TrackPage{Tracks: tracks}
+	if len(tracks) > limit {
+		page.Tracks = tracks[:limit]
+		lastID := page.Tracks[limit-1].ID
+		page.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(lastID, 10)))
+	}
+	return page, nil
+}
+
+func (s *TrackService) GetTrack(id int) (*models.Track, error) {
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidTrackID
+	}
+
+	track, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainErrors.ErrTrackNotFound
+		}
+		return nil, domainErrors.NewInternalError("failed to get track", err)
+	}
+	return track, nil
+}
+
+func (s *TrackService) UpdateTrack(id int, track *models.Track) error {
+	existing, err := s.GetTrack(id)
+	if err != nil {
+		return err
+	}
+
+	track.ISRC = models.NormalizeISRC(track.ISRC)
+	if err := validateTrack(track); err != nil {
+		return err
+	}
+
+	if err := s.ensureUnique(track, existing.ID); err != nil {
+		return err
+	}
+
+	track.ID = existing.ID
+	track.CreatedAt = existing.CreatedAt
+	if err := s.repo.Update(track); err != nil {
+		return domainErrors.NewInternalError("failed to update track", err)
+	}
+	return nil
+}
+
+func (s *TrackService) DeleteTrack(id int) error {
+	if id <= 0 {
+		return domainErrors.ErrInvalidTrackID
+	}
+
+	var events repositories.PlaylistEvents
+	if s.RecordEvents {
+		events = playlistChangedEvents(models.EventPlaylistUpdated)
+	}
+	if err := s.repo.Delete(id, events); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domainErrors.ErrTrackNotFound
+		}
+		return domainErrors.NewInternalError("failed to delete track", err)
+	}
+	return nil
+}
+
+// ensureUnique vérifie qu'aucune autre track du catalogue n'a le même titre et le même artiste
+func (s *TrackService) ensureUnique(track *models.Track, currentID int64) error {
+	existing, err := s.repo.FindByTitleAndArtist(track.Title, track.Artist)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return domainErrors.NewInternalError("failed to check track uniqueness", err)
+	}
+	if existing.ID != currentID {
+		return domainErrors.ErrTrackAlreadyExists
+	}
+	return nil
+}
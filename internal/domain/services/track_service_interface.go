@@ -0,0 +1,11 @@
+package services
+
+import "radioking-app/internal/domain/models"
+
+type ITrackService interface {
+	CreateTrack(track *models.Track) error
+	ListTracks(limit int, cursor string) (*models.TrackPage, error)
+	GetTrack(id int) (*models.Track, error)
+	UpdateTrack(id int, track *models.Track) error
+	DeleteTrack(id int) error
+}
@@ -3,20 +3,46 @@ package db
 import (
 	"fmt"
 	"radioking-app/internal/domain/models"
+	"time"
 
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
 func InitDb() (*gorm.DB, error) {
-	db, err := gorm.Open(sqlite.Open("test.db"), &gorm.Config{})
+	// Les dates automatiques sont enregistrées en UTC : SQLite compare les dates comme du texte
+	db, err := gorm.Open(sqlite.Open("test.db"), &gorm.Config{NowFunc: func() time.Time { return time.Now().UTC() }})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	if err := db.AutoMigrate(&models.Playlist{}, &models.Track{}, &models.TrackPlay{}); err != nil {
+	if err := db.AutoMigrate(&models.Artist{}, &models.Album{}, &models.Playlist{}, &models.Track{}, &models.PlaylistTrack{}, &models.TrackPlay{}, &models.PlaySession{}, &models.Clock{}, &models.ClockSlot{}, &models.GridEntry{}, &models.RotationRule{}, &models.PlayRollup{}, &models.OutboxMessage{}, &models.StoredEvent{}, &schemaMigration{}); err != nil {
 		return nil, fmt.Errorf("failed to migrate database schema: %w", err)
 	}
 
+	if err := migrateTracksToCatalog(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate tracks to catalog: %w", err)
+	}
+
+	if err := migrateTrackArtists(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate track artists: %w", err)
+	}
+
+	if err := migrateDatesToUTC(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate dates to UTC: %w", err)
+	}
+
+	if err := migratePlayRollups(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate play rollups: %w", err)
+	}
+
+	if err := migratePlaySessions(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate play sessions: %w", err)
+	}
+
+	if err := migrateEventStore(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate event store: %w", err)
+	}
+
 	return db, nil
 }
@@ -0,0 +1,489 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"radioking-app/internal/domain/models"
+	"radioking-app/internal/infrastructure/repositories"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// legacyTracksConstraint clé étrangère de l'ancienne relation entre une playlist et ses tracks
+	legacyTracksConstraint = "fk_playlists_tracks"
+	// utcDateSuffix fin du texte d'une date UTC enregistrée par le pilote SQLite
+	utcDateSuffix = "%+00:00"
+)
+
+// legacyTrack représente une ligne de l'ancienne table tracks, où chaque track appartenait à une playlist
+type legacyTrack struct {
+	ID         int64
+	PlaylistID int64
+	Title      string
+	Artist     string
+	Position   int
+}
+
+// migrateTracksToCatalog convertit les tracks rattachées à une playlist en tracks du catalogue.
+// Les doublons (même titre et même artiste) sont fusionnés, les lectures sont rattachées
+// à la track conservée et la position est reportée dans playlist_tracks.
+func migrateTracksToCatalog(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&models.Track{}, "playlist_id") {
+		return nil
+	}
+
+	log.Println("Migrating tracks to the shared catalog")
+
+	// Les bases antérieures aux positions n'ont pas de colonne position : l'ordre d'ajout est conservé
+	columns, order := "id, playlist_id, title, artist", "playlist_id ASC, id ASC"
+	if db.Migrator().HasColumn("tracks", "position") {
+		columns, order = columns+", position", "playlist_id ASC, position ASC, id ASC"
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var rows []legacyTrack
+		if err := tx.Table("tracks").
+			Select(columns).
+			Order(order).
+			Scan(&rows).Error; err != nil {
+			return fmt.Errorf("failed to read legacy tracks: %w", err)
+		}
+
+		catalogIDs := make(map[[2]string]int64)
+		positions := make(map[int64]int)
+		inPlaylist := make(map[[2]int64]bool)
+		for _, row := range rows {
+			key := [2]string{row.Title, row.Artist}
+			catalogID, exists := catalogIDs[key]
+			if !exists {
+				catalogID = row.ID
+				catalogIDs[key] = catalogID
+			}
+
+			if exists {
+				if err := tx.Model(&models.TrackPlay{}).
+					Where("track_id = ?", row.ID).
+					Update("track_id", catalogID).Error; err != nil {
+					return fmt.Errorf("failed to remap track plays of track %d: %w", row.ID, err)
+				}
+				if err := tx.Exec("DELETE FROM tracks WHERE id = ?", row.ID).Error; err != nil {
+					return fmt.Errorf("failed to delete duplicate track %d: %w", row.ID, err)
+				}
+			}
+
+			if inPlaylist[[2]int64{row.PlaylistID, catalogID}] {
+				continue
+			}
+			inPlaylist[[2]int64{row.PlaylistID, catalogID}] = true
+
+			entry := models.PlaylistTrack{
+				PlaylistID: row.PlaylistID,
+				TrackID:    catalogID,
+				Position:   positions[row.PlaylistID],
+			}
+			if err := tx.Omit("Track").Create(&entry).Error; err != nil {
+				return fmt.Errorf("failed to create playlist track for track %d: %w", row.ID, err)
+			}
+			positions[row.PlaylistID]++
+		}
+
+		if tx.Migrator().HasIndex(&models.Track{}, "idx_tracks_playlist_id") {
+			if err := tx.Migrator().DropIndex(&models.Track{}, "idx_tracks_playlist_id"); err != nil {
+				return fmt.Errorf("failed to drop legacy index: %w", err)
+			}
+		}
+		// La contrainte de l'ancienne relation playlist -> tracks référence playlist_id
+		if tx.Migrator().HasConstraint("tracks", legacyTracksConstraint) {
+			if err := tx.Migrator().DropConstraint("tracks", legacyTracksConstraint); err != nil {
+				return fmt.Errorf("failed to drop legacy constraint: %w", err)
+			}
+		}
+		for _, column := range []string{"playlist_id", "position"} {
+			if tx.Migrator().HasColumn(&models.Track{}, column) {
+				if err := tx.Migrator().DropColumn(&models.Track{}, column); err != nil {
+					return fmt.Errorf("failed to drop legacy column %s: %w", column, err)
+				}
+			}
+		}
+		// SQLite reconstruit la table pour supprimer une colonne, sans ses index
+		if err := tx.AutoMigrate(&models.Track{}); err != nil {
+			return fmt.Errorf("failed to recreate tracks indexes: %w", err)
+		}
+
+		log.Printf("Migrated %d legacy tracks into %d catalog tracks", len(rows), len(catalogIDs))
+		return nil
+	})
+}
+
+// migrateTrackArtists rattache à un artiste les tracks créées avant l'introduction des artistes.
+// Les tracks qui deviennent identiques une fois les noms normalisés sont fusionnées.
+func migrateTrackArtists(db *gorm.DB) error {
+	var pending int64
+	if err := db.Model(&models.Track{}).Where("artist_id = 0").Count(&pending).Error; err != nil {
+		return fmt.Errorf("failed to count tracks without artist: %w", err)
+	}
+	if pending == 0 {
+		return nil
+	}
+
+	log.Printf("Linking %d tracks to artists", pending)
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var tracks []models.Track
+		if err := tx.Where("artist_id = 0").Order("id ASC").Find(&tracks).Error; err != nil {
+			return fmt.Errorf("failed to read tracks without artist: %w", err)
+		}
+
+		artists := make(map[string]models.Artist)
+		merged := 0
+		for _, track := range tracks {
+			key := models.NormalizeName(track.Artist)
+			artist, exists := artists[key]
+			if !exists {
+				if err := tx.Where(models.Artist{NormalizedName: key}).
+					Attrs(models.Artist{Name: models.CleanName(track.Artist)}).
+					FirstOrCreate(&artist).Error; err != nil {
+					return fmt.Errorf("failed to resolve artist of track %d: %w", track.ID, err)
+				}
+				artists[key] = artist
+			}
+
+			title := models.CleanName(track.Title)
+			var existing models.Track
+			err := tx.Where("artist_id = ? AND normalized_title = ?", artist.ID, models.NormalizeName(title)).
+				First(&existing).Error
+			if err == nil {
+				if err := mergeTrack(tx, existing.ID, track.ID); err != nil {
+					return err
+				}
+				merged++
+				continue
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("failed to look up duplicates of track %d: %w", track.ID, err)
+			}
+
+			if err := tx.Model(&models.Track{}).Where("id = ?", track.ID).Updates(map[string]interface{}{
+				"title":            title,
+				"normalized_title": models.NormalizeName(title),
+				"artist":           artist.Name,
+				"artist_id":        artist.ID,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to link track %d to its artist: %w", track.ID, err)
+			}
+		}
+
+		log.Printf("Linked tracks to %d artists, merged %d duplicate tracks", len(artists), merged)
+		return nil
+	})
+}
+
+// mergeTrack reporte les entrées de playlist et les lectures de la track dupliquée sur la track
+// conservée, puis supprime la track dupliquée
+func mergeTrack(tx *gorm.DB, keepID, duplicateID int64) error {
+	var entries []models.PlaylistTrack
+	if err := tx.Where("track_id = ?", duplicateID).Find(&entries).Error; err != nil {
+		return fmt.Errorf("failed to read playlist tracks of track %d: %w", duplicateID, err)
+	}
+
+	for _, entry := range entries {
+		var count int64
+		if err := tx.Model(&models.PlaylistTrack{}).
+			Where("playlist_id = ? AND track_id = ?", entry.PlaylistID, keepID).
+			Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check playlist %d: %w", entry.PlaylistID, err)
+		}
+
+		if count == 0 {
+			if err := tx.Model(&models.PlaylistTrack{}).Where("id = ?", entry.ID).
+				Update("track_id", keepID).Error; err != nil {
+				return fmt.Errorf("failed to remap playlist track %d: %w", entry.ID, err)
+			}
+			continue
+		}
+
+		// La playlist contient déjà la track conservée : l'entrée en double est retirée
+		if err := tx.Delete(&models.PlaylistTrack{}, entry.ID).Error; err != nil {
+			return fmt.Errorf("failed to delete playlist track %d: %w", entry.ID, err)
+		}
+		if err := tx.Model(&models.PlaylistTrack{}).
+			Where("playlist_id = ? AND position > ?", entry.PlaylistID, entry.Position).
+			Update("position", gorm.Expr("position - 1")).Error; err != nil {
+			return fmt.Errorf("failed to shift positions of playlist %d: %w", entry.PlaylistID, err)
+		}
+	}
+
+	if err := tx.Model(&models.TrackPlay{}).Where("track_id = ?", duplicateID).
+		Update("track_id", keepID).Error; err != nil {
+		return fmt.Errorf("failed to remap track plays of track %d: %w", duplicateID, err)
+	}
+	// La track dupliquée n'a plus d'historique : elle est supprimée définitivement
+	if err := tx.Unscoped().Delete(&models.Track{}, duplicateID).Error; err != nil {
+		return fmt.Errorf("failed to delete duplicate track %d: %w", duplicateID, err)
+	}
+	return nil
+}
+
+// migratePlaySessions renseigne le début du segment de lecture des sessions créées avant
+// l'introduction des pauses, pour que le temps écoulé de leur track courante soit conservé
+func migratePlaySessions(db *gorm.DB) error {
+	return db.Model(&models.PlaySession{}).
+		Where("status = ? AND track_started_at IS NOT NULL AND track_resumed_at IS NULL", models.PlaySessionPlaying).
+		Update("track_resumed_at", gorm.Expr("track_started_at")).Error
+}
+
+// schemaMigration migration de données déjà appliquée, qui n'est plus rejouée aux démarrages suivants
+type schemaMigration struct {
+	Name      string `gorm:"primaryKey;size:100"`
+	AppliedAt time.Time
+}
+
+// runOnce applique migrate et l'enregistre sous name dans une même transaction, sauf si elle l'a déjà été
+func runOnce(db *gorm.DB, name string, migrate func(tx *gorm.DB) error) error {
+	var applied int64
+	if err := db.Model(&schemaMigration{}).Where("name = ?", name).Count(&applied).Error; err != nil {
+		return fmt.Errorf("failed to read migration %s: %w", name, err)
+	}
+	if applied > 0 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := migrate(tx); err != nil {
+			return err
+		}
+		return tx.Create(&schemaMigration{Name: name, AppliedAt: time.Now().UTC()}).Error
+	})
+}
+
+// utcDateColumns colonnes de dates comparées ou triées en SQL, par table
+var utcDateColumns = []struct {
+	table   string
+	columns []string
+}{
+	{"track_plays", []string{"played_at", "ended_at"}},
+	{"playlists", []string{"created_at", "updated_at", "deleted_at"}},
+	{"play_sessions", []string{"created_at", "updated_at", "track_started_at", "track_resumed_at"}},
+}
+
+// legacyDate date d'une ligne enregistrée avant sa conversion en UTC
+type legacyDate struct {
+	ID    int64
+	Value time.Time
+}
+
+// migrateDatesToUTC réécrit en UTC les dates enregistrées dans le fuseau du serveur ou de leur événement :
+// SQLite compare les dates comme du texte, les filtres et les curseurs les classeraient mal
+func migrateDatesToUTC(db *gorm.DB) error {
+	return runOnce(db, "dates_to_utc", func(tx *gorm.DB) error {
+		for _, table := range utcDateColumns {
+			for _, column := range table.columns {
+				if err := convertColumnToUTC(tx, table.table, column); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func convertColumnToUTC(tx *gorm.DB, table, column string) error {
+	var rows []legacyDate
+	if err := tx.Table(table).
+		Select(fmt.Sprintf("id, %s AS value", column)).
+		Where(fmt.Sprintf("%s NOT LIKE ?", column), utcDateSuffix).
+		Order("id ASC").
+		Scan(&rows).Error; err != nil {
+		return fmt.Errorf("failed to read %s.%s to convert: %w", table, column, err)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	log.Printf("Converting %d %s.%s dates to UTC", len(rows), table, column)
+
+	for _, row := range rows {
+		if err := tx.Table(table).Where("id = ?", row.ID).UpdateColumn(column, row.Value.UTC()).Error; err != nil {
+			return fmt.Errorf("failed to convert %s.%s of row %d: %w", table, column, row.ID, err)
+		}
+	}
+	return nil
+}
+
+// migratePlayRollups calcule les compteurs pré-agrégés d'une base dont l'historique des lectures
+// précède leur introduction, sans quoi les statistiques lues dans les compteurs seraient vides
+func migratePlayRollups(db *gorm.DB) error {
+	var rollups int64
+	if err := db.Model(&models.PlayRollup{}).Count(&rollups).Error; err != nil {
+		return fmt.Errorf("failed to count play rollups: %w", err)
+	}
+	if rollups > 0 {
+		return nil
+	}
+
+	var plays int64
+	if err := db.Model(&models.TrackPlay{}).Count(&plays).Error; err != nil {
+		return fmt.Errorf("failed to count track plays: %w", err)
+	}
+	if plays == 0 {
+		return nil
+	}
+
+	log.Printf("Building play rollups from %d track plays", plays)
+
+	count, err := repositories.NewStatsRepository(db).RebuildRollups()
+	if err != nil {
+		return err
+	}
+	log.Printf("Built %d play rollups", count)
+	return nil
+}
+
+// migrateEventStore initialise le journal des événements à sa création. Les messages encore présents dans
+// l'outbox y sont repris dans leur ordre d'enregistrement, précédés des événements track.played et track.ended
+// reconstitués à partir des autres lectures de track_plays. Les événements des playlists déjà supprimés
+// de l'outbox ne peuvent pas être reconstitués.
+func migrateEventStore(db *gorm.DB) error {
+	var stored int64
+	if err := db.Model(&models.StoredEvent{}).Count(&stored).Error; err != nil {
+		return fmt.Errorf("failed to count stored events: %w", err)
+	}
+	if stored > 0 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var messages []models.OutboxMessage
+		if err := tx.Order("id ASC").Find(&messages).Error; err != nil {
+			return fmt.Errorf("failed to read outbox messages: %w", err)
+		}
+
+		outbox := make([]models.StoredEvent, 0, len(messages))
+		recorded := make(map[string]bool, len(messages))
+		for _, message := range messages {
+			event, err := message.Event()
+			if err != nil {
+				return fmt.Errorf("failed to read outbox message %d: %w", message.ID, err)
+			}
+			entry := models.NewStoredEvent(event)
+			entry.RecordedAt = message.CreatedAt.UTC()
+			outbox = append(outbox, entry)
+			recorded[event.ID] = true
+		}
+
+		events, err := trackPlayEvents(tx, recorded)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 && len(outbox) == 0 {
+			return nil
+		}
+
+		log.Printf("Migrating %d outbox messages and %d track play events to the event store", len(outbox), len(events))
+		events = append(events, outbox...)
+		return tx.CreateInBatches(&events, 100).Error
+	})
+}
+
+// trackPlayEvents reconstitue, dans leur ordre chronologique, les événements des lectures absents de recorded.
+// Une lecture sans identifiant d'événement reçoit celui de l'événement reconstitué : le rejeu la reconnaît
+// ainsi comme déjà enregistrée. Seules les lectures d'une session ont un événement de fin.
+func trackPlayEvents(tx *gorm.DB, recorded map[string]bool) ([]models.StoredEvent, error) {
+	var plays []models.TrackPlay
+	// Les tracks supprimées depuis restent référencées par leurs lectures
+	if err := tx.Preload("Track", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("played_at ASC, id ASC").Find(&plays).Error; err != nil {
+		return nil, fmt.Errorf("failed to read track plays: %w", err)
+	}
+
+	now := time.Now().UTC()
+	var events []models.StoredEvent
+	add := func(event models.Event, err error) error {
+		if err != nil {
+			return err
+		}
+		entry := models.NewStoredEvent(event)
+		entry.RecordedAt = now
+		events = append(events, entry)
+		return nil
+	}
+
+	for _, play := range plays {
+		var sessionID int64
+		if play.SessionID != nil {
+			sessionID = *play.SessionID
+		}
+
+		if play.EventID == nil || !recorded[*play.EventID] {
+			eventID, err := playEventID(tx, play.ID, "event_id", play.EventID)
+			if err != nil {
+				return nil, err
+			}
+			err = add(models.TrackPlayedEvent{
+				PlaylistID:      play.PlaylistID,
+				TrackID:         play.TrackID,
+				TrackTitle:      play.Track.Title,
+				Artist:          play.Track.Artist,
+				ArtistID:        play.Track.ArtistID,
+				Position:        play.Position,
+				SessionID:       sessionID,
+				Sequence:        play.Sequence,
+				DurationSeconds: play.Track.DurationSeconds,
+				PlayedAt:        play.PlayedAt.UTC(),
+				EventID:         eventID,
+			}.Envelope())
+			if err != nil {
+				return nil, fmt.Errorf("failed to rebuild start event of track play %d: %w", play.ID, err)
+			}
+		}
+
+		if play.SessionID == nil || play.EndedAt == nil || (play.EndEventID != nil && recorded[*play.EndEventID]) {
+			continue
+		}
+		eventID, err := playEventID(tx, play.ID, "end_event_id", play.EndEventID)
+		if err != nil {
+			return nil, err
+		}
+		var playedSeconds int
+		if play.PlayedSeconds != nil {
+			playedSeconds = *play.PlayedSeconds
+		}
+		err = add(models.TrackEndedEvent{
+			SessionID:     sessionID,
+			Sequence:      play.Sequence,
+			PlaylistID:    play.PlaylistID,
+			TrackID:       play.TrackID,
+			Position:      play.Position,
+			PlayedAt:      play.PlayedAt.UTC(),
+			EndedAt:       play.EndedAt.UTC(),
+			PlayedSeconds: playedSeconds,
+			Reason:        play.EndReason,
+			EventID:       eventID,
+		}.Envelope())
+		if err != nil {
+			return nil, fmt.Errorf("failed to rebuild end event of track play %d: %w", play.ID, err)
+		}
+	}
+
+	// Une fin se place entre les débuts qui l'entourent
+	sort.SliceStable(events, func(i, j int) bool { return events[i].OccurredAt.Before(events[j].OccurredAt) })
+	return events, nil
+}
+
+// playEventID identifiant de l'événement de la lecture id, attribué et enregistré dans column s'il manque
+func playEventID(tx *gorm.DB, id int64, column string, eventID *string) (string, error) {
+	if eventID != nil {
+		return *eventID, nil
+	}
+
+	generated := uuid.New().String()
+	if err := tx.Model(&models.TrackPlay{}).Where("id = ?", id).UpdateColumn(column, generated).Error; err != nil {
+		return "", fmt.Errorf("failed to set %s of track play %d: %w", column, id, err)
+	}
+	return generated, nil
+}
@@ -0,0 +1,308 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"radioking-app/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// Schémas des versions précédentes de la base : tracks rattachées à une playlist, sans position
+// à l'origine puis avec position
+
+type baselinePlaylist struct {
+	ID        int64           `gorm:"primaryKey;autoIncrement"`
+	Name      string          `gorm:"size:255;not null"`
+	Tracks    []baselineTrack `gorm:"foreignKey:PlaylistID"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (baselinePlaylist) TableName() string { return "playlists" }
+
+type baselineTrack struct {
+	ID         int64  `gorm:"primaryKey;autoIncrement"`
+	PlaylistID int64  `gorm:"index;not null"`
+	Title      string `gorm:"size:255;not null"`
+	Artist     string `gorm:"size:255;not null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (baselineTrack) TableName() string { return "tracks" }
+
+type positionedPlaylist struct {
+	ID        int64             `gorm:"primaryKey;autoIncrement"`
+	Name      string            `gorm:"size:255;not null"`
+	Tracks    []positionedTrack `gorm:"foreignKey:PlaylistID"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (positionedPlaylist) TableName() string { return "playlists" }
+
+type positionedTrack struct {
+	ID         int64  `gorm:"primaryKey;autoIncrement"`
+	PlaylistID int64  `gorm:"index;not null"`
+	Title      string `gorm:"size:255;not null"`
+	Artist     string `gorm:"size:255;not null"`
+	Position   int    `gorm:"not null;default:0"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (positionedTrack) TableName() string { return "tracks" }
+
+type legacyTrackPlay struct {
+	ID         int64     `gorm:"primaryKey"`
+	PlaylistID int64     `gorm:"not null;index"`
+	TrackID    int64     `gorm:"not null;index"`
+	Position   int       `gorm:"not null"`
+	PlayedAt   time.Time `gorm:"not null;index"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+func (legacyTrackPlay) TableName() string { return "track_plays" }
+
+// openLegacyDb crée test.db dans un répertoire temporaire, qui devient le répertoire courant
+func openLegacyDb(t *testing.T) *gorm.DB {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	legacy, err := gorm.Open(sqlite.Open("test.db"), &gorm.Config{})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, _ := legacy.DB()
+		_ = sqlDB.Close()
+	})
+	return legacy
+}
+
+func closeDb(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+}
+
+func assertMigratedCatalog(t *testing.T, db *gorm.DB, expectedTitles []string) {
+	t.Helper()
+	assert.False(t, db.Migrator().HasColumn("tracks", "playlist_id"))
+	assert.False(t, db.Migrator().HasColumn("tracks", "position"))
+	assert.True(t, db.Migrator().HasIndex(&models.Track{}, "idx_tracks_artist_title"))
+
+	var tracks []models.Track
+	require.NoError(t, db.Order("id ASC").Find(&tracks).Error)
+	assert.Len(t, tracks, 2, "le doublon est fusionné")
+
+	var entries []models.PlaylistTrack
+	require.NoError(t, db.Preload("Track").Where("playlist_id = ?", 1).Order("position ASC").Find(&entries).Error)
+	titles := make([]string, 0, len(entries))
+	for i, entry := range entries {
+		assert.Equal(t, i, entry.Position)
+		titles = append(titles, entry.Track.Title)
+	}
+	assert.Equal(t, expectedTitles, titles)
+
+	var play models.TrackPlay
+	require.NoError(t, db.First(&play).Error)
+	assert.Equal(t, tracks[0].ID, play.TrackID, "la lecture du doublon est rattachée à la track conservée")
+}
+
+func TestInitDb_MigratesBaselineTracks(t *testing.T) {
+	// Arrange
+	legacy := openLegacyDb(t)
+	require.NoError(t, legacy.AutoMigrate(&baselinePlaylist{}, &baselineTrack{}, &legacyTrackPlay{}))
+	require.NoError(t, legacy.Create(&baselinePlaylist{ID: 1, Name: "Morning", Tracks: []baselineTrack{
+		{ID: 1, Title: "Song A", Artist: "Artist"},
+		{ID: 2, Title: "Song B", Artist: "Artist"},
+	}}).Error)
+	require.NoError(t, legacy.Create(&baselinePlaylist{ID: 2, Name: "Evening", Tracks: []baselineTrack{
+		{ID: 3, Title: "Song A", Artist: "Artist"},
+	}}).Error)
+	require.NoError(t, legacy.Create(&legacyTrackPlay{PlaylistID: 2, TrackID: 3, PlayedAt: time.Now()}).Error)
+
+	// Act
+	db, err := InitDb()
+
+	// Assert
+	require.NoError(t, err)
+	defer closeDb(t, db)
+	assertMigratedCatalog(t, db, []string{"Song A", "Song B"})
+}
+
+func TestInitDb_MigratesPositionedTracks(t *testing.T) {
+	// Arrange
+	legacy := openLegacyDb(t)
+	require.NoError(t, legacy.AutoMigrate(&positionedPlaylist{}, &positionedTrack{}, &legacyTrackPlay{}))
+	require.NoError(t, legacy.Create(&positionedPlaylist{ID: 1, Name: "Morning", Tracks: []positionedTrack{
+		{ID: 1, Title: "Song A", Artist: "Artist", Position: 1},
+		{ID: 2, Title: "Song B", Artist: "Artist", Position: 0},
+	}}).Error)
+	require.NoError(t, legacy.Create(&positionedPlaylist{ID: 2, Name: "Evening", Tracks: []positionedTrack{
+		{ID: 3, Title: "Song A", Artist: "Artist", Position: 0},
+	}}).Error)
+	require.NoError(t, legacy.Create(&legacyTrackPlay{PlaylistID: 2, TrackID: 3, PlayedAt: time.Now()}).Error)
+
+	// Act
+	db, err := InitDb()
+
+	// Assert
+	require.NoError(t, err)
+	defer closeDb(t, db)
+	assertMigratedCatalog(t, db, []string{"Song B", "Song A"})
+}
+
+func TestInitDb_ConvertsDatesToUTC(t *testing.T) {
+	// Arrange : lectures, playlists et sessions enregistrées dans le fuseau du serveur ou de leur événement
+	legacy := openLegacyDb(t)
+	require.NoError(t, legacy.AutoMigrate(&models.TrackPlay{}, &models.Playlist{}, &models.PlaySession{}))
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	playedAt := time.Date(2024, 1, 15, 0, 30, 0, 0, paris)
+	endedAt := playedAt.Add(3 * time.Minute)
+	require.NoError(t, legacy.Create(&models.TrackPlay{PlaylistID: 1, TrackID: 1, PlayedAt: playedAt, EndedAt: &endedAt}).Error)
+	require.NoError(t, legacy.Create(&models.TrackPlay{PlaylistID: 1, TrackID: 1, PlayedAt: playedAt.Add(time.Hour)}).Error)
+	require.NoError(t, legacy.Create(&models.Playlist{Name: "Morning", CreatedAt: playedAt, UpdatedAt: endedAt}).Error)
+	require.NoError(t, legacy.Create(&models.PlaySession{
+		PlaylistID: 1, Status: models.PlaySessionPaused, TrackStartedAt: &playedAt, CreatedAt: playedAt, UpdatedAt: endedAt,
+	}).Error)
+
+	// Act
+	db, err := InitDb()
+
+	// Assert
+	require.NoError(t, err)
+	defer closeDb(t, db)
+
+	for _, table := range utcDateColumns {
+		for _, column := range table.columns {
+			var remaining int64
+			require.NoError(t, db.Table(table.table).Where(column+" NOT LIKE ?", utcDateSuffix).Count(&remaining).Error)
+			assert.Zero(t, remaining, table.table+"."+column)
+		}
+	}
+
+	// 00:30 à Paris est la veille en UTC : la lecture appartient au 14 janvier
+	var plays []models.TrackPlay
+	require.NoError(t, db.Where("played_at < ?", time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)).Find(&plays).Error)
+	require.Len(t, plays, 1)
+	assert.True(t, playedAt.Equal(plays[0].PlayedAt))
+	require.NotNil(t, plays[0].EndedAt)
+	assert.True(t, endedAt.Equal(*plays[0].EndedAt))
+
+	var playlist models.Playlist
+	require.NoError(t, db.First(&playlist).Error)
+	assert.True(t, playedAt.Equal(playlist.CreatedAt))
+
+	var session models.PlaySession
+	require.NoError(t, db.First(&session).Error)
+	require.NotNil(t, session.TrackStartedAt)
+	assert.True(t, playedAt.Equal(*session.TrackStartedAt))
+	assert.Nil(t, session.TrackResumedAt)
+}
+
+func TestInitDb_ConvertsDatesToUTCOnce(t *testing.T) {
+	// Arrange : conversion déjà appliquée par un démarrage précédent
+	legacy := openLegacyDb(t)
+	require.NoError(t, legacy.AutoMigrate(&models.TrackPlay{}, &schemaMigration{}))
+	require.NoError(t, legacy.Create(&schemaMigration{Name: "dates_to_utc", AppliedAt: time.Now().UTC()}).Error)
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	require.NoError(t, legacy.Create(&models.TrackPlay{PlaylistID: 1, TrackID: 1, PlayedAt: time.Date(2024, 1, 15, 0, 30, 0, 0, paris)}).Error)
+
+	// Act
+	db, err := InitDb()
+
+	// Assert : la table n'est plus parcourue
+	require.NoError(t, err)
+	defer closeDb(t, db)
+
+	var remaining int64
+	require.NoError(t, db.Model(&models.TrackPlay{}).Where("played_at NOT LIKE ?", utcDateSuffix).Count(&remaining).Error)
+	assert.Equal(t, int64(1), remaining)
+}
+
+func TestInitDb_BuildsMissingPlayRollups(t *testing.T) {
+	// Arrange : historique enregistré avant l'introduction des compteurs
+	legacy := openLegacyDb(t)
+	require.NoError(t, legacy.AutoMigrate(&models.Artist{}, &models.Track{}, &models.TrackPlay{}))
+	artist := models.Artist{Name: "Artist", NormalizedName: "artist"}
+	require.NoError(t, legacy.Create(&artist).Error)
+	track := models.Track{Title: "Song A", NormalizedTitle: "song a", Artist: artist.Name, ArtistID: artist.ID}
+	require.NoError(t, legacy.Create(&track).Error)
+	playedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, legacy.Create(&models.TrackPlay{PlaylistID: 1, TrackID: track.ID, PlayedAt: playedAt.Add(time.Duration(i) * time.Minute)}).Error)
+	}
+
+	// Act
+	db, err := InitDb()
+
+	// Assert
+	require.NoError(t, err)
+	defer closeDb(t, db)
+
+	var rollup models.PlayRollup
+	require.NoError(t, db.Where("subject = ? AND granularity = ? AND subject_id = ?",
+		models.RollupTrack, models.RollupDay, track.ID).Take(&rollup).Error)
+	assert.Equal(t, 3, rollup.Plays)
+}
+
+func TestInitDb_RebuildsEventStoreFromTrackPlays(t *testing.T) {
+	// Arrange : une lecture antérieure aux événements, et une lecture de session dont seul le début est encore dans l'outbox
+	legacy := openLegacyDb(t)
+	require.NoError(t, legacy.AutoMigrate(&models.Artist{}, &models.Track{}, &models.TrackPlay{}, &models.OutboxMessage{}))
+	track := models.Track{Title: "Song A", NormalizedTitle: "song a", Artist: "Artist", ArtistID: 3, DurationSeconds: 200}
+	require.NoError(t, legacy.Create(&track).Error)
+
+	playedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	sessionID, playedSeconds, startEventID := int64(5), 180, "session-play-started"
+	endedAt := playedAt.Add(8 * time.Minute)
+	require.NoError(t, legacy.Create(&models.TrackPlay{PlaylistID: 1, TrackID: track.ID, Position: 2, PlayedAt: playedAt}).Error)
+	require.NoError(t, legacy.Create(&models.TrackPlay{PlaylistID: 1, TrackID: track.ID, PlayedAt: playedAt.Add(5 * time.Minute),
+		SessionID: &sessionID, Sequence: 1, EndedAt: &endedAt, PlayedSeconds: &playedSeconds, EndReason: models.TrackEndCompleted,
+		EventID: &startEventID}).Error)
+	started, err := models.NewTrackPlayedOutboxMessage(models.TrackPlayedEvent{PlaylistID: 1, TrackID: track.ID, SessionID: sessionID,
+		Sequence: 1, PlayedAt: playedAt.Add(5 * time.Minute), EventID: startEventID})
+	require.NoError(t, err)
+	require.NoError(t, legacy.Create(&started).Error)
+
+	// Act
+	db, err := InitDb()
+
+	// Assert : les événements reconstitués précèdent l'outbox, sans doublon du début encore présent
+	require.NoError(t, err)
+	defer closeDb(t, db)
+
+	var stored []models.StoredEvent
+	require.NoError(t, db.Order("position ASC").Find(&stored).Error)
+	require.Len(t, stored, 3)
+	assert.Equal(t, []models.EventType{models.EventTrackPlayed, models.EventTrackEnded, models.EventTrackPlayed},
+		[]models.EventType{stored[0].EventType, stored[1].EventType, stored[2].EventType})
+	assert.Equal(t, startEventID, stored[2].EventID)
+
+	var played models.TrackPlayedEvent
+	require.NoError(t, stored[0].Event().DecodePayload(&played))
+	assert.Equal(t, "Song A", played.TrackTitle)
+	assert.Equal(t, int64(3), played.ArtistID)
+	assert.Equal(t, 2, played.Position)
+	assert.Equal(t, 200, played.DurationSeconds)
+	var ended models.TrackEndedEvent
+	require.NoError(t, stored[1].Event().DecodePayload(&ended))
+	assert.Equal(t, models.TrackEndedEvent{SessionID: sessionID, Sequence: 1, PlaylistID: 1, TrackID: track.ID,
+		PlayedAt: playedAt.Add(5 * time.Minute), EndedAt: endedAt, PlayedSeconds: 180, Reason: models.TrackEndCompleted,
+		EventID: stored[1].EventID}, ended)
+
+	// Les lectures portent les identifiants des événements reconstitués, que le rejeu reconnaît
+	var plays []models.TrackPlay
+	require.NoError(t, db.Order("id ASC").Find(&plays).Error)
+	require.NotNil(t, plays[0].EventID)
+	assert.Equal(t, stored[0].EventID, *plays[0].EventID)
+	require.NotNil(t, plays[1].EndEventID)
+	assert.Equal(t, stored[1].EventID, *plays[1].EndEventID)
+}
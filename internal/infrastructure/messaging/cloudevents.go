@@ -0,0 +1,225 @@
+package messaging
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"radioking-app/internal/domain/models"
+	"strconv"
+	"strings"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Modes d'encodage CloudEvents des messages publiés : "structured" place les attributs et le payload dans
+// le corps JSON, "binary" place les attributs dans les en-têtes AMQP et le payload seul dans le corps
+const (
+	CloudEventsStructured = "structured"
+	CloudEventsBinary     = "binary"
+)
+
+const (
+	cloudEventsSpecVersion = "1.0"
+	cloudEventsSource      = "/radioking"
+	cloudEventsTypePrefix  = "com.radioking."
+	cloudEventsContentType = "application/cloudevents+json"
+	eventDataContentType   = "application/json"
+)
+
+// Préfixes des attributs CloudEvents en mode binaire : le binding AMQP recommande "cloudEvents_",
+// "cloudEvents:" est accepté en lecture
+const (
+	cloudEventsHeaderPrefix       = "cloudEvents_"
+	cloudEventsLegacyHeaderPrefix = "cloudEvents:"
+)
+
+// cloudEvent événement CloudEvents 1.0 en mode structuré. Les extensions schemaversion et partitionkey
+// portent la version du schéma du payload et la clé d'ordonnancement des messages.
+type cloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	SchemaVersion   int             `json:"schemaversion"`
+	PartitionKey    string          `json:"partitionkey,omitempty"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// wireMessage message tel que transporté par le broker
+type wireMessage struct {
+	ContentType string
+	Headers     amqp.Table
+	Body        []byte
+	// Redelivered le message a déjà été remis à un consommateur sans être acquitté, comme amqp.Delivery.Redelivered
+	Redelivered bool
+}
+
+// encodeEvent encode l'événement en message CloudEvents selon mode
+func encodeEvent(event models.Event, mode string) (wireMessage, error) {
+	partitionKey := ""
+	if event.PartitionKey != 0 {
+		partitionKey = strconv.FormatInt(event.PartitionKey, 10)
+	}
+
+	switch mode {
+	case CloudEventsStructured:
+		body, err := json.Marshal(cloudEvent{
+			SpecVersion:     cloudEventsSpecVersion,
+			ID:              event.ID,
+			Source:          cloudEventsSource,
+			Type:            cloudEventsTypePrefix + string(event.Type),
+			Time:            event.Time,
+			DataContentType: eventDataContentType,
+			SchemaVersion:   event.Version,
+			PartitionKey:    partitionKey,
+			Data:            event.Payload,
+		})
+		if err != nil {
+			return wireMessage{}, fmt.Errorf("failed to marshal %s event: %w", event.Type, err)
+		}
+		return wireMessage{ContentType: cloudEventsContentType, Headers: amqp.Table{}, Body: body}, nil
+	case CloudEventsBinary:
+		headers := amqp.Table{
+			cloudEventsHeaderPrefix + "specversion":   cloudEventsSpecVersion,
+			cloudEventsHeaderPrefix + "id":            event.ID,
+			cloudEventsHeaderPrefix + "source":        cloudEventsSource,
+			cloudEventsHeaderPrefix + "type":          cloudEventsTypePrefix + string(event.Type),
+			cloudEventsHeaderPrefix + "time":          event.Time.Format(time.RFC3339Nano),
+			cloudEventsHeaderPrefix + "schemaversion": int32(event.Version),
+		}
+		if partitionKey != "" {
+			headers[cloudEventsHeaderPrefix+"partitionkey"] = partitionKey
+		}
+		return wireMessage{ContentType: eventDataContentType, Headers: headers, Body: event.Payload}, nil
+	default:
+		return wireMessage{}, fmt.Errorf("unknown cloudevents mode %q", mode)
+	}
+}
+
+// decodeEvent décode un message CloudEvents, binaire ou structuré. Un événement de lecture publié seul,
+// sans enveloppe, par les versions précédentes de l'application est lu comme la version 0 de eventType.
+func decodeEvent(msg wireMessage, eventType models.EventType) (models.Event, error) {
+	if _, ok := cloudEventsAttribute(msg.Headers, "specversion"); ok {
+		return decodeBinaryEvent(msg)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(msg.ContentType); mediaType == cloudEventsContentType {
+		return decodeStructuredEvent(msg.Body)
+	}
+	return decodePlainEvent(msg.Body, eventType)
+}
+
+func decodeStructuredEvent(body []byte) (models.Event, error) {
+	var ce cloudEvent
+	if err := json.Unmarshal(body, &ce); err != nil {
+		return models.Event{}, fmt.Errorf("failed to unmarshal cloudevent: %w", err)
+	}
+	if ce.SpecVersion != cloudEventsSpecVersion {
+		return models.Event{}, fmt.Errorf("unsupported cloudevents specversion %q", ce.SpecVersion)
+	}
+	if ce.DataContentType != "" && ce.DataContentType != eventDataContentType {
+		return models.Event{}, fmt.Errorf("unsupported cloudevent datacontenttype %q", ce.DataContentType)
+	}
+	return newDecodedEvent(ce.ID, ce.Type, ce.Time, ce.SchemaVersion, ce.PartitionKey, ce.Data)
+}
+
+func decodeBinaryEvent(msg wireMessage) (models.Event, error) {
+	specVersion, _ := cloudEventsAttribute(msg.Headers, "specversion")
+	if specVersion != cloudEventsSpecVersion {
+		return models.Event{}, fmt.Errorf("unsupported cloudevents specversion %v", specVersion)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(msg.ContentType); mediaType != "" && mediaType != eventDataContentType {
+		return models.Event{}, fmt.Errorf("unsupported cloudevent content type %q", msg.ContentType)
+	}
+
+	id, _ := cloudEventsAttribute(msg.Headers, "id")
+	eventType, _ := cloudEventsAttribute(msg.Headers, "type")
+	partitionKey, _ := cloudEventsAttribute(msg.Headers, "partitionkey")
+
+	var at time.Time
+	switch value, _ := cloudEventsAttribute(msg.Headers, "time"); value := value.(type) {
+	case time.Time:
+		at = value
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return models.Event{}, fmt.Errorf("invalid cloudevent time %q: %w", value, err)
+		}
+		at = parsed
+	}
+
+	version := 0
+	switch value, _ := cloudEventsAttribute(msg.Headers, "schemaversion"); value := value.(type) {
+	case int32:
+		version = int(value)
+	case int64:
+		version = int(value)
+	case string:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return models.Event{}, fmt.Errorf("invalid cloudevent schemaversion %q", value)
+		}
+		version = parsed
+	}
+
+	return newDecodedEvent(asString(id), asString(eventType), at, version, asString(partitionKey), msg.Body)
+}
+
+func newDecodedEvent(id string, ceType string, at time.Time, version int, partitionKey string, data json.RawMessage) (models.Event, error) {
+	if id == "" || ceType == "" {
+		return models.Event{}, fmt.Errorf("cloudevent without id or type")
+	}
+	eventType, ok := strings.CutPrefix(ceType, cloudEventsTypePrefix)
+	if !ok {
+		return models.Event{}, fmt.Errorf("unexpected cloudevent type %q", ceType)
+	}
+
+	event := models.Event{
+		ID:      id,
+		Type:    models.EventType(eventType),
+		Version: version,
+		Time:    at.UTC(),
+		Payload: data,
+	}
+	if partitionKey != "" {
+		key, err := strconv.ParseInt(partitionKey, 10, 64)
+		if err != nil {
+			return models.Event{}, fmt.Errorf("invalid cloudevent partitionkey %q", partitionKey)
+		}
+		event.PartitionKey = key
+	}
+	return event, nil
+}
+
+// decodePlainEvent décode un événement de lecture publié sans enveloppe
+func decodePlainEvent(body []byte, eventType models.EventType) (models.Event, error) {
+	var plain struct {
+		EventID    string `json:"event_id"`
+		PlaylistID int64  `json:"playlist_id"`
+	}
+	if err := json.Unmarshal(body, &plain); err != nil {
+		return models.Event{}, fmt.Errorf("failed to unmarshal %s event: %w", eventType, err)
+	}
+	return models.Event{
+		ID:           plain.EventID,
+		Type:         eventType,
+		PartitionKey: plain.PlaylistID,
+		Payload:      body,
+	}, nil
+}
+
+// cloudEventsAttribute attribut CloudEvents name d'un message en mode binaire
+func cloudEventsAttribute(headers amqp.Table, name string) (interface{}, bool) {
+	if value, ok := headers[cloudEventsHeaderPrefix+name]; ok {
+		return value, true
+	}
+	value, ok := headers[cloudEventsLegacyHeaderPrefix+name]
+	return value, ok
+}
+
+func asString(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
@@ -0,0 +1,137 @@
+package messaging
+
+import (
+	"encoding/json"
+	"radioking-app/internal/domain/models"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTrackPlayedEvent(t *testing.T) models.Event {
+	t.Helper()
+	event, err := models.TrackPlayedEvent{
+		PlaylistID: 12,
+		TrackID:    48,
+		PlayedAt:   time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+		EventID:    "evt-1",
+	}.Envelope()
+	require.NoError(t, err)
+	return event
+}
+
+func TestCloudEvents_StructuredRoundTrip(t *testing.T) {
+	// Arrange
+	event := newTestTrackPlayedEvent(t)
+
+	// Act
+	msg, err := encodeEvent(event, CloudEventsStructured)
+	require.NoError(t, err)
+	decoded, err := decodeEvent(msg, models.EventTrackPlayed)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, cloudEventsContentType, msg.ContentType)
+	var attributes map[string]any
+	require.NoError(t, json.Unmarshal(msg.Body, &attributes))
+	assert.Equal(t, "1.0", attributes["specversion"])
+	assert.Equal(t, "com.radioking.track.played", attributes["type"])
+	assert.Equal(t, "/radioking", attributes["source"])
+	assert.Equal(t, "12", attributes["partitionkey"])
+	assert.EqualValues(t, 1, attributes["schemaversion"])
+	assert.Equal(t, event.ID, decoded.ID)
+	assert.Equal(t, event.Type, decoded.Type)
+	assert.Equal(t, event.Version, decoded.Version)
+	assert.True(t, event.Time.Equal(decoded.Time))
+	assert.Equal(t, event.PartitionKey, decoded.PartitionKey)
+	assert.JSONEq(t, string(event.Payload), string(decoded.Payload))
+}
+
+func TestCloudEvents_BinaryRoundTrip(t *testing.T) {
+	// Arrange
+	event := newTestTrackPlayedEvent(t)
+
+	// Act
+	msg, err := encodeEvent(event, CloudEventsBinary)
+	require.NoError(t, err)
+	decoded, err := decodeEvent(msg, models.EventTrackPlayed)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", msg.ContentType)
+	assert.Equal(t, "com.radioking.track.played", msg.Headers["cloudEvents_type"])
+	assert.Equal(t, int32(1), msg.Headers["cloudEvents_schemaversion"])
+	assert.JSONEq(t, string(event.Payload), string(msg.Body))
+	assert.Equal(t, event.ID, decoded.ID)
+	assert.Equal(t, event.Version, decoded.Version)
+	assert.True(t, event.Time.Equal(decoded.Time))
+	assert.Equal(t, event.PartitionKey, decoded.PartitionKey)
+}
+
+func TestCloudEvents_BinaryAcceptsColonPrefix(t *testing.T) {
+	// Arrange
+	msg := wireMessage{
+		ContentType: "application/json",
+		Headers: amqp.Table{
+			"cloudEvents:specversion":   "1.0",
+			"cloudEvents:id":            "evt-1",
+			"cloudEvents:source":        "/radioking",
+			"cloudEvents:type":          "com.radioking.playlist.deleted",
+			"cloudEvents:time":          time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+			"cloudEvents:schemaversion": int64(1),
+		},
+		Body: []byte(`{"playlist_id":12}`),
+	}
+
+	// Act
+	decoded, err := decodeEvent(msg, models.EventPlaylistDeleted)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, models.EventPlaylistDeleted, decoded.Type)
+	assert.Equal(t, 1, decoded.Version)
+}
+
+func TestCloudEvents_PlainMessageUpcast(t *testing.T) {
+	// Arrange : une lecture publiée seule, sans enveloppe
+	event := newTestTrackPlayedEvent(t)
+	var consumed []models.Event
+	decode := eventDecoder(models.EventTrackPlayed, func(event models.Event) error {
+		consumed = append(consumed, event)
+		return nil
+	})
+
+	// Act
+	decoded, err := decode(wireMessage{ContentType: "application/json", Body: event.Payload})
+	require.NoError(t, err)
+	require.NoError(t, decoded.process())
+
+	// Assert
+	require.Len(t, consumed, 1)
+	assert.Equal(t, "evt-1", consumed[0].ID)
+	assert.Equal(t, 1, consumed[0].Version)
+	assert.Equal(t, int64(12), consumed[0].PartitionKey)
+	assert.JSONEq(t, string(event.Payload), string(consumed[0].Payload))
+}
+
+func TestCloudEvents_UnknownVersionRejected(t *testing.T) {
+	// Arrange
+	event := newTestTrackPlayedEvent(t)
+	event.Version = 2
+	msg, err := encodeEvent(event, CloudEventsStructured)
+	require.NoError(t, err)
+
+	// Act
+	_, err = eventDecoder(models.EventTrackPlayed, func(models.Event) error { return nil })(msg)
+
+	// Assert
+	assert.EqualError(t, err, "unsupported track.played event version 2, latest known is 1")
+}
+
+func TestCloudEvents_UnknownMode(t *testing.T) {
+	_, err := encodeEvent(newTestTrackPlayedEvent(t), "xml")
+	assert.EqualError(t, err, `unknown cloudevents mode "xml"`)
+}
@@ -0,0 +1,165 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"radioking-app/internal/config"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+var ErrConnectionClosed = errors.New("rabbitmq connection closed")
+
+// Connection maintient la connexion à RabbitMQ. Après une coupure, la connexion est rétablie avec un
+// délai doublé à chaque échec ; les canaux sont recréés par leurs utilisateurs via Channel, qui attend
+// la reconnexion.
+type Connection struct {
+	url      string
+	minDelay time.Duration
+	maxDelay time.Duration
+	dial     func(url string) (*amqp.Connection, error)
+
+	mu        sync.Mutex
+	conn      *amqp.Connection
+	ready     chan struct{} // fermé tant que conn est ouverte
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// Dial ouvre la connexion : au démarrage, un broker injoignable reste une erreur
+func Dial(cfg config.RabbitMQConfig) (*Connection, error) {
+	c := newConnection(cfg.URL, cfg.ReconnectDelay, cfg.ReconnectMaxDelay, amqp.Dial)
+
+	conn, err := c.dial(c.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+	c.connected(conn)
+	return c, nil
+}
+
+func newConnection(url string, minDelay, maxDelay time.Duration, dial func(string) (*amqp.Connection, error)) *Connection {
+	return &Connection{
+		url:      url,
+		minDelay: max(minDelay, 10*time.Millisecond),
+		maxDelay: max(maxDelay, minDelay),
+		dial:     dial,
+		ready:    make(chan struct{}),
+		closed:   make(chan struct{}),
+	}
+}
+
+// Channel ouvre un canal, en attendant la reconnexion si la connexion est coupée
+func (c *Connection) Channel(ctx context.Context) (*amqp.Channel, error) {
+	for {
+		c.mu.Lock()
+		conn, ready := c.conn, c.ready
+		c.mu.Unlock()
+
+		if conn != nil {
+			channel, err := conn.Channel()
+			if err == nil {
+				return channel, nil
+			}
+			if !errors.Is(err, amqp.ErrClosed) {
+				return nil, fmt.Errorf("failed to open channel: %w", err)
+			}
+			c.disconnected(conn)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("rabbitmq connection unavailable: %w", ctx.Err())
+		case <-c.closed:
+			return nil, ErrConnectionClosed
+		case <-ready:
+		}
+	}
+}
+
+// Close ferme la connexion et arrête les tentatives de reconnexion
+func (c *Connection) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+
+		c.mu.Lock()
+		conn := c.conn
+		c.conn = nil
+		c.mu.Unlock()
+
+		if conn != nil {
+			conn.Close()
+		}
+	})
+	return nil
+}
+
+func (c *Connection) connected(conn *amqp.Connection) {
+	c.mu.Lock()
+	c.conn = conn
+	close(c.ready)
+	c.mu.Unlock()
+
+	go c.watch(conn)
+}
+
+// disconnected oublie conn, une seule fois, et lance la reconnexion
+func (c *Connection) disconnected(conn *amqp.Connection) {
+	c.mu.Lock()
+	if c.conn != conn {
+		c.mu.Unlock()
+		return
+	}
+	c.conn = nil
+	c.ready = make(chan struct{})
+	c.mu.Unlock()
+
+	go c.reconnect()
+}
+
+// watch attend la fermeture de conn, fermée immédiatement si elle l'est déjà
+func (c *Connection) watch(conn *amqp.Connection) {
+	closeErr := <-conn.NotifyClose(make(chan *amqp.Error, 1))
+
+	select {
+	case <-c.closed:
+		return
+	default:
+	}
+
+	log.Printf("RabbitMQ connection lost: %v", closeErr)
+	c.disconnected(conn)
+}
+
+func (c *Connection) reconnect() {
+	for attempt := 1; ; attempt++ {
+		delay := retryDelay(c.minDelay, c.maxDelay, attempt)
+		select {
+		case <-c.closed:
+			return
+		case <-time.After(delay):
+		}
+
+		conn, err := c.dial(c.url)
+		if err != nil {
+			log.Printf("Failed to reconnect to RabbitMQ (attempt %d): %v", attempt, err)
+			continue
+		}
+
+		select {
+		case <-c.closed:
+			conn.Close()
+			return
+		default:
+		}
+
+		log.Printf("RabbitMQ connection restored after %d attempts", attempt)
+		c.connected(conn)
+		return
+	}
+}
@@ -0,0 +1,54 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnection_ChannelWaitsForReconnection(t *testing.T) {
+	// Arrange : le broker reste injoignable
+	var dials atomic.Int32
+	c := newConnection("amqp://localhost:5672", 10*time.Millisecond, 20*time.Millisecond, func(string) (*amqp.Connection, error) {
+		dials.Add(1)
+		return nil, errors.New("connection refused")
+	})
+	go c.reconnect()
+
+	// Act
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := c.Channel(ctx)
+
+	// Assert : les tentatives se poursuivent pendant l'attente du canal
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.GreaterOrEqual(t, dials.Load(), int32(2))
+}
+
+func TestConnection_CloseStopsReconnection(t *testing.T) {
+	// Arrange
+	var dials atomic.Int32
+	c := newConnection("amqp://localhost:5672", 10*time.Millisecond, 10*time.Millisecond, func(string) (*amqp.Connection, error) {
+		dials.Add(1)
+		return nil, errors.New("connection refused")
+	})
+	go c.reconnect()
+	require.Eventually(t, func() bool { return dials.Load() > 0 }, time.Second, 5*time.Millisecond)
+
+	// Act
+	require.NoError(t, c.Close())
+	_, err := c.Channel(context.Background())
+
+	// Assert
+	assert.ErrorIs(t, err, ErrConnectionClosed)
+	stopped := dials.Load()
+	time.Sleep(50 * time.Millisecond)
+	assert.LessOrEqual(t, dials.Load(), stopped+1, "une tentative en cours peut encore aboutir à un échec")
+	assert.NoError(t, c.Close())
+}
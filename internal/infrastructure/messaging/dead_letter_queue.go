@@ -0,0 +1,14 @@
+package messaging
+
+import "radioking-app/internal/domain/models"
+
+// DeadLetterQueue accès d'administration aux messages écartés
+type DeadLetterQueue interface {
+	// Peek retourne au plus limit messages sans les retirer de la queue
+	Peek(limit int) ([]models.DeadLetter, error)
+	// Replay republie dans leur queue d'origine les messages d'identifiants ids, ou tous si ids est vide
+	Replay(ids []string) (int, error)
+	// Purge supprime tous les messages et retourne leur nombre
+	Purge() (int, error)
+	Close() error
+}
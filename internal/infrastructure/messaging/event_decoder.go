@@ -0,0 +1,32 @@
+package messaging
+
+import (
+	"fmt"
+	"log"
+	"radioking-app/internal/domain/models"
+)
+
+// eventDecoder décode les événements de type eventType, convertis dans la version courante de leur
+// schéma et traités par handler dans l'ordre de leur clé. Un message d'un autre type est illisible
+// pour ce consommateur.
+func eventDecoder(eventType models.EventType, handler func(models.Event) error) func(msg wireMessage) (decodedMessage, error) {
+	return func(msg wireMessage) (decodedMessage, error) {
+		event, err := decodeEvent(msg, eventType)
+		if err != nil {
+			return decodedMessage{}, err
+		}
+		if event.Type != eventType {
+			return decodedMessage{}, fmt.Errorf("unexpected event type %q, expected %s", event.Type, eventType)
+		}
+
+		received := event.Version
+		event, err = Upcast(event)
+		if err != nil {
+			return decodedMessage{}, err
+		}
+
+		log.Printf("Consumed %s event: ID=%s, Version=%d, PartitionKey=%d, Redelivered=%t",
+			event.Type, event.ID, received, event.PartitionKey, msg.Redelivered)
+		return decodedMessage{key: event.PartitionKey, process: func() error { return handler(event) }}, nil
+	}
+}
@@ -0,0 +1,382 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"log"
+	"radioking-app/internal/config"
+	"radioking-app/internal/domain/models"
+	"sync"
+	"time"
+)
+
+// Pilotes de messagerie
+const (
+	DriverRabbitMQ = "rabbitmq"
+	DriverMemory   = "memory"
+)
+
+var ErrBusClosed = errors.New("memory bus closed")
+
+// MemoryBus broker en mémoire, pour les tests et le développement sans RabbitMQ. Il route les messages
+// selon les bindings de la topologie, perd comme RabbitMQ un message qu'aucune queue ne reçoit, et
+// reproduit le consommateur RabbitMQ : au plus Prefetch messages non acquittés par queue, traités par
+// Workers workers, retentés avec un délai croissant puis écartés.
+// Les messages, y compris ceux en attente de retentative, sont perdus à la fermeture du bus.
+type MemoryBus struct {
+	config   config.RabbitMQConfig
+	bindings []BindingSpec
+
+	mu          sync.Mutex
+	queues      map[string]*memoryQueue
+	deadLetters []memoryDeadLetter
+
+	closed    chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+type memoryMessage struct {
+	id          string
+	wire        wireMessage
+	retryCount  int
+	redelivered bool
+}
+
+// memoryDeadLetter message écarté, conservé tel que publié pour être rejoué
+type memoryDeadLetter struct {
+	letter  models.DeadLetter
+	message memoryMessage
+}
+
+type memoryQueue struct {
+	messages []memoryMessage
+	ready    chan struct{} // signale un message disponible
+}
+
+// memoryDelivery message remis à un consommateur, qui occupe une place du prefetch jusqu'à Ack ou Nack
+type memoryDelivery struct {
+	bus     *MemoryBus
+	queue   string
+	message memoryMessage
+	release func()
+	once    sync.Once
+}
+
+func NewMemoryBus(cfg config.RabbitMQConfig) *MemoryBus {
+	bus := &MemoryBus{
+		config: cfg,
+		queues: make(map[string]*memoryQueue),
+		closed: make(chan struct{}),
+	}
+	for _, binding := range NewTopology(cfg).Bindings {
+		if binding.Exchange == cfg.Exchange {
+			bus.bindings = append(bus.bindings, binding)
+		}
+	}
+
+	log.Printf("In-memory message bus started for exchange %s", cfg.Exchange)
+	return bus
+}
+
+// Publish dépose une copie du message CloudEvents dans chaque queue liée à la clé de routage de
+// l'événement ; comme avec RabbitMQ, un message qu'aucun binding ne route n'est pas conservé
+func (b *MemoryBus) Publish(event models.Event) error {
+	wire, err := encodeEvent(event, b.config.CloudEventsMode)
+	if err != nil {
+		return err
+	}
+	key := routingKey(b.config, event.Type)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.isClosed() {
+		return ErrBusClosed
+	}
+
+	message := memoryMessage{id: event.ID, wire: wire}
+	for _, binding := range b.bindings {
+		if topicMatches(binding.RoutingKey, key) {
+			b.enqueue(binding.Queue, message, false)
+		}
+	}
+	return nil
+}
+
+func (b *MemoryBus) Subscribe(ctx context.Context, eventType models.EventType, handler func(models.Event) error) error {
+	queue, err := subscribedQueue(b.config, eventType)
+	if err != nil {
+		return err
+	}
+	return b.consume(ctx, queue, eventDecoder(eventType, handler))
+}
+
+// consume traite les messages de queue jusqu'à l'annulation de ctx ou la fermeture du bus
+func (b *MemoryBus) consume(ctx context.Context, queue string, decode func(msg wireMessage) (decodedMessage, error)) error {
+	if b.isClosed() {
+		return ErrBusClosed
+	}
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.deliver(ctx, queue, decode)
+		log.Printf("Stopped in-memory consumer of queue %s", queue)
+	}()
+	return nil
+}
+
+// deliver répartit les messages entre les workers puis attend la fin des messages en cours.
+// Un message reçu mais pas encore confié à un worker à l'arrêt est remis en queue, marqué redélivré.
+func (b *MemoryBus) deliver(ctx context.Context, queue string, decode func(msg wireMessage) (decodedMessage, error)) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-b.closed:
+			cancel()
+		}
+	}()
+
+	pool := newWorkerPool(b.config.Workers)
+	defer pool.drain()
+
+	var unacked chan struct{}
+	if b.config.Prefetch > 0 {
+		unacked = make(chan struct{}, b.config.Prefetch)
+	}
+
+	for {
+		if unacked != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case unacked <- struct{}{}:
+			}
+		}
+
+		message, ok := b.receive(ctx, queue)
+		if !ok {
+			return
+		}
+		delivery := &memoryDelivery{bus: b, queue: queue, message: message, release: func() {
+			if unacked != nil {
+				<-unacked
+			}
+		}}
+
+		wire := message.wire
+		wire.Redelivered = message.redelivered
+		decoded, err := decode(wire)
+		if err != nil {
+			log.Printf("%v", err)
+			b.deadLetter(delivery, err)
+			continue
+		}
+
+		submitted := pool.trySubmit(ctx, decoded.key, func() {
+			if err := decoded.process(); err != nil {
+				log.Printf("Failed to process message from queue %s: %v", queue, err)
+				b.retry(delivery, err)
+			} else {
+				delivery.Ack()
+			}
+		})
+		if !submitted {
+			delivery.Nack(true)
+			return
+		}
+	}
+}
+
+// receive attend le premier message de queue, jusqu'à l'annulation de ctx
+func (b *MemoryBus) receive(ctx context.Context, name string) (memoryMessage, bool) {
+	for ctx.Err() == nil {
+		b.mu.Lock()
+		queue := b.queue(name)
+		if len(queue.messages) > 0 {
+			message := queue.messages[0]
+			queue.messages = queue.messages[1:]
+			if len(queue.messages) > 0 {
+				queue.signal()
+			}
+			b.mu.Unlock()
+			return message, true
+		}
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+		case <-queue.ready:
+		}
+	}
+	return memoryMessage{}, false
+}
+
+// retry remet le message dans sa queue après le délai de sa prochaine tentative,
+// ou l'écarte s'il a épuisé ses tentatives
+func (b *MemoryBus) retry(delivery *memoryDelivery, cause error) {
+	attempt := delivery.message.retryCount + 1
+	if attempt > b.config.MaxRetries {
+		b.deadLetter(delivery, cause)
+		return
+	}
+
+	message := memoryMessage{id: delivery.message.id, wire: delivery.message.wire, retryCount: attempt}
+	delay := retryDelay(b.config.RetryBaseDelay, b.config.RetryMaxDelay, attempt)
+	go func() {
+		select {
+		case <-b.closed:
+		case <-time.After(delay):
+			b.mu.Lock()
+			b.enqueue(delivery.queue, message, false)
+			b.mu.Unlock()
+		}
+	}()
+	delivery.Ack()
+	log.Printf("Message from queue %s scheduled for retry %d/%d in %s", delivery.queue, attempt, b.config.MaxRetries, delay)
+}
+
+// deadLetter écarte le message avec la cause de son échec
+func (b *MemoryBus) deadLetter(delivery *memoryDelivery, cause error) {
+	b.mu.Lock()
+	b.deadLetters = append(b.deadLetters, delivery.deadLetter(cause.Error()))
+	b.mu.Unlock()
+
+	delivery.Ack()
+	log.Printf("Message %s from queue %s dead-lettered: %v", delivery.message.id, delivery.queue, cause)
+}
+
+// enqueue dépose le message en fin de queue, ou en tête pour une redélivrance. Appelé sous b.mu.
+func (b *MemoryBus) enqueue(name string, message memoryMessage, front bool) {
+	queue := b.queue(name)
+	if front {
+		queue.messages = append([]memoryMessage{message}, queue.messages...)
+	} else {
+		queue.messages = append(queue.messages, message)
+	}
+	queue.signal()
+}
+
+// queue retourne la queue name, créée à sa première utilisation. Appelé sous b.mu.
+func (b *MemoryBus) queue(name string) *memoryQueue {
+	queue, ok := b.queues[name]
+	if !ok {
+		queue = &memoryQueue{ready: make(chan struct{}, 1)}
+		b.queues[name] = queue
+	}
+	return queue
+}
+
+func (q *memoryQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (b *MemoryBus) Peek(limit int) ([]models.DeadLetter, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	letters := make([]models.DeadLetter, 0, min(limit, len(b.deadLetters)))
+	for _, dead := range b.deadLetters[:cap(letters)] {
+		letters = append(letters, dead.letter)
+	}
+	return letters, nil
+}
+
+func (b *MemoryBus) Replay(ids []string) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.isClosed() {
+		return 0, ErrBusClosed
+	}
+
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	var kept []memoryDeadLetter
+	replayed := 0
+	for _, dead := range b.deadLetters {
+		if len(wanted) > 0 && !wanted[dead.letter.MessageID] {
+			kept = append(kept, dead)
+			continue
+		}
+		b.enqueue(dead.letter.Queue, memoryMessage{id: dead.message.id, wire: dead.message.wire}, false)
+		log.Printf("Replayed dead letter %s to queue %s", dead.letter.MessageID, dead.letter.Queue)
+		replayed++
+	}
+	b.deadLetters = kept
+	return replayed, nil
+}
+
+func (b *MemoryBus) Purge() (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	purged := len(b.deadLetters)
+	b.deadLetters = nil
+	return purged, nil
+}
+
+// Wait attend la fin du traitement des messages déjà reçus, après annulation du contexte des Consume
+func (b *MemoryBus) Wait() {
+	b.wg.Wait()
+}
+
+// Close arrête le bus pour la publication comme pour la consommation
+func (b *MemoryBus) Close() error {
+	b.closeOnce.Do(func() { close(b.closed) })
+	return nil
+}
+
+func (b *MemoryBus) isClosed() bool {
+	select {
+	case <-b.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Ack acquitte le message, qui libère sa place du prefetch
+func (d *memoryDelivery) Ack() {
+	d.once.Do(d.release)
+}
+
+// Nack rejette le message : remis en tête de queue et marqué redélivré si requeue,
+// écarté sinon comme par l'exchange des messages écartés de la queue
+func (d *memoryDelivery) Nack(requeue bool) {
+	d.once.Do(func() {
+		d.release()
+		d.bus.mu.Lock()
+		defer d.bus.mu.Unlock()
+
+		if requeue {
+			message := d.message
+			message.redelivered = true
+			d.bus.enqueue(d.queue, message, true)
+			return
+		}
+		d.bus.deadLetters = append(d.bus.deadLetters, d.deadLetter("rejected by broker: rejected"))
+	})
+}
+
+func (d *memoryDelivery) deadLetter(reason string) memoryDeadLetter {
+	return memoryDeadLetter{
+		letter: models.DeadLetter{
+			MessageID:  d.message.id,
+			Queue:      d.queue,
+			RoutingKey: d.queue,
+			Reason:     reason,
+			RetryCount: d.message.retryCount,
+			FailedAt:   time.Now().UTC().Truncate(time.Second),
+			Body:       d.message.wire.Body,
+		},
+		message: d.message,
+	}
+}
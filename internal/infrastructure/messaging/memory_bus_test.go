@@ -0,0 +1,285 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"radioking-app/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMemoryBus() *MemoryBus {
+	cfg := newTestRabbitMQConfig()
+	cfg.RetryBaseDelay = time.Millisecond
+	cfg.RetryMaxDelay = 10 * time.Millisecond
+	cfg.Workers = 2
+	cfg.Prefetch = 4
+	return NewMemoryBus(cfg)
+}
+
+func newTestEvent(eventType models.EventType, id string, playlistID int64) models.Event {
+	event, err := models.NewEvent(eventType, id, time.Now(), playlistID, map[string]int64{"playlist_id": playlistID})
+	if err != nil {
+		panic(err)
+	}
+	return event
+}
+
+// queued nombre de messages en attente dans queue
+func (b *MemoryBus) queued(queue string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.queue(queue).messages)
+}
+
+func TestMemoryBus_RoutesByRoutingKey(t *testing.T) {
+	// Arrange
+	bus := newTestMemoryBus()
+	defer bus.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	var played []models.Event
+
+	// Act
+	require.NoError(t, bus.Subscribe(ctx, models.EventTrackPlayed, func(event models.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		played = append(played, event)
+		return nil
+	}))
+	require.NoError(t, bus.Publish(newTestEvent(models.EventTrackPlayed, "played", 1)))
+	require.NoError(t, bus.Publish(newTestEvent(models.EventTrackEnded, "ended", 1)))
+	unrouted := bus.Publish(newTestEvent(models.EventPlaylistCreated, "created", 1))
+
+	// Assert
+	assert.NoError(t, unrouted, "aucune queue n'est liée aux événements des playlists, ils sont perdus")
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(played) == 1
+	}, time.Second, time.Millisecond)
+	cancel()
+	bus.Wait()
+	assert.Equal(t, "played", played[0].ID)
+	assert.Equal(t, 1, bus.queued("track_ended"), "l'événement de fin attend dans sa propre queue")
+}
+
+func TestMemoryBus_RetriesThenDeadLettersAndReplays(t *testing.T) {
+	// Arrange
+	bus := newTestMemoryBus()
+	defer bus.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var attempts atomic.Int32
+	var failing atomic.Bool
+	failing.Store(true)
+
+	require.NoError(t, bus.Subscribe(ctx, models.EventTrackEnded, func(models.Event) error {
+		attempts.Add(1)
+		if failing.Load() {
+			return errors.New("database locked")
+		}
+		return nil
+	}))
+
+	// Act : le traitement échoue au premier passage et aux deux tentatives
+	require.NoError(t, bus.Publish(newTestEvent(models.EventTrackEnded, "ended", 1)))
+
+	// Assert
+	var letters []models.DeadLetter
+	require.Eventually(t, func() bool {
+		letters, _ = bus.Peek(10)
+		return len(letters) == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Equal(t, "track_ended", letters[0].Queue)
+	assert.Equal(t, "database locked", letters[0].Reason)
+	assert.Equal(t, 2, letters[0].RetryCount)
+
+	// Un message rejoué repart pour un nouveau cycle de tentatives
+	failing.Store(false)
+	replayed, err := bus.Replay([]string{letters[0].MessageID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, replayed)
+	require.Eventually(t, func() bool { return attempts.Load() == 4 }, time.Second, time.Millisecond)
+	letters, _ = bus.Peek(10)
+	assert.Empty(t, letters)
+}
+
+func TestMemoryBus_UndecodableMessageDeadLettered(t *testing.T) {
+	// Arrange
+	bus := newTestMemoryBus()
+	defer bus.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bus.mu.Lock()
+	bus.enqueue("track_played", memoryMessage{id: "garbage", wire: wireMessage{Body: []byte("not json")}}, false)
+	ended, err := encodeEvent(newTestEvent(models.EventTrackEnded, "misrouted", 1), CloudEventsStructured)
+	require.NoError(t, err)
+	bus.enqueue("track_played", memoryMessage{id: "misrouted", wire: ended}, false)
+	bus.mu.Unlock()
+
+	// Act
+	require.NoError(t, bus.Subscribe(ctx, models.EventTrackPlayed, func(models.Event) error {
+		t.Error("un message illisible ne doit pas être traité")
+		return nil
+	}))
+
+	// Assert
+	require.Eventually(t, func() bool {
+		letters, _ := bus.Peek(10)
+		return len(letters) == 2
+	}, time.Second, time.Millisecond)
+	letters, _ := bus.Peek(10)
+	assert.Contains(t, letters[1].Reason, `unexpected event type "track.ended"`)
+	purged, err := bus.Purge()
+	require.NoError(t, err)
+	assert.Equal(t, 2, purged)
+}
+
+func TestMemoryBus_NackRequeuesAtHeadAsRedelivered(t *testing.T) {
+	// Arrange
+	bus := newTestMemoryBus()
+	bus.mu.Lock()
+	bus.enqueue("track_played", memoryMessage{id: "second", wire: wireMessage{Body: []byte("second")}}, false)
+	bus.mu.Unlock()
+	released := 0
+	first := memoryMessage{id: "first", wire: wireMessage{Body: []byte("first")}}
+	delivery := &memoryDelivery{bus: bus, queue: "track_played", message: first, release: func() { released++ }}
+
+	// Act
+	delivery.Nack(true)
+	delivery.Ack()
+
+	// Assert : le consommateur reçoit le message rejeté en premier, marqué redélivré
+	assert.Equal(t, 1, released, "un message n'est réglé qu'une fois")
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	redelivered := map[string]bool{}
+	var order []string
+	require.NoError(t, bus.consume(ctx, "track_played", func(msg wireMessage) (decodedMessage, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		id := string(msg.Body)
+		order = append(order, id)
+		redelivered[id] = msg.Redelivered
+		return decodedMessage{process: func() error { return nil }}, nil
+	}))
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(order) == 2
+	}, time.Second, time.Millisecond)
+	cancel()
+	bus.Wait()
+	assert.Equal(t, []string{"first", "second"}, order)
+	assert.Equal(t, map[string]bool{"first": true, "second": false}, redelivered)
+}
+
+func TestMemoryBus_StopDrainsMessagesInProgress(t *testing.T) {
+	// Arrange : un seul worker, occupé par le premier message
+	bus := newTestMemoryBus()
+	bus.config.Workers = 1
+	defer bus.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	started, release := make(chan struct{}, 2), make(chan struct{})
+	var processed atomic.Int32
+
+	require.NoError(t, bus.Subscribe(ctx, models.EventTrackPlayed, func(models.Event) error {
+		started <- struct{}{}
+		<-release
+		processed.Add(1)
+		return nil
+	}))
+	require.NoError(t, bus.Publish(newTestEvent(models.EventTrackPlayed, "first", 1)))
+	<-started
+	require.NoError(t, bus.Publish(newTestEvent(models.EventTrackPlayed, "second", 1)))
+
+	// Act
+	cancel()
+	close(release)
+	bus.Wait()
+
+	// Assert : le message en cours est traité, le suivant reste en queue pour le prochain consommateur
+	assert.Equal(t, int32(1), processed.Load())
+	assert.Equal(t, 1, bus.queued("track_played"))
+}
+
+func TestMemoryBus_ClosedBusRejectsPublications(t *testing.T) {
+	bus := newTestMemoryBus()
+	require.NoError(t, bus.Close())
+
+	assert.ErrorIs(t, bus.Publish(newTestEvent(models.EventTrackPlayed, "played", 1)), ErrBusClosed)
+	assert.ErrorIs(t, bus.Subscribe(context.Background(), models.EventTrackPlayed, nil), ErrBusClosed)
+}
+
+func TestMemoryBus_SubscribesToConfiguredEventTypes(t *testing.T) {
+	// Arrange : seuls les événements de création de playlist sont souscrits
+	cfg := newTestRabbitMQConfig()
+	cfg.SubscribedEvents = []string{string(models.EventPlaylistCreated)}
+	bus := NewMemoryBus(cfg)
+	defer bus.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	received := make(chan models.Event, 1)
+
+	// Act
+	require.NoError(t, bus.Subscribe(ctx, models.EventPlaylistCreated, func(event models.Event) error {
+		received <- event
+		return nil
+	}))
+	require.NoError(t, bus.Publish(newTestEvent(models.EventPlaylistCreated, "created", 1)))
+	unrouted := bus.Publish(newTestEvent(models.EventPlaylistUpdated, "updated", 1))
+
+	// Assert
+	select {
+	case event := <-received:
+		assert.Equal(t, "created", event.ID)
+	case <-time.After(time.Second):
+		t.Fatal("playlist.created event not delivered")
+	}
+	cancel()
+	bus.Wait()
+	assert.NoError(t, unrouted)
+	assert.Equal(t, 0, bus.queued("track_played"))
+	_, err := subscribedQueue(bus.config, models.EventPlaylistDeleted)
+	assert.ErrorContains(t, err, "subscribed_events")
+}
+
+func TestMemoryBus_KeepsNothingForEventsWithoutSubscriber(t *testing.T) {
+	// Arrange : la configuration par défaut ne souscrit à aucun événement des playlists
+	bus := newTestMemoryBus()
+	defer bus.Close()
+	eventTypes := []models.EventType{models.EventPlaylistCreated, models.EventPlaylistUpdated,
+		models.EventPlaylistDeleted, models.EventPlaylistPlayed}
+
+	// Act
+	for i := 0; i < 1000; i++ {
+		eventType := eventTypes[i%len(eventTypes)]
+		require.NoError(t, bus.Publish(newTestEvent(eventType, fmt.Sprintf("%s-%d", eventType, i), int64(i))))
+	}
+
+	// Assert
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	for name, queue := range bus.queues {
+		assert.Empty(t, queue.messages, "queue %s", name)
+	}
+	assert.Empty(t, bus.deadLetters)
+}
+
+func TestTopicMatches(t *testing.T) {
+	assert.True(t, topicMatches("track.played", "track.played"))
+	assert.False(t, topicMatches("track.played", "track.ended"))
+	assert.True(t, topicMatches("track.*", "track.ended"))
+	assert.False(t, topicMatches("*", "track.ended"))
+	assert.True(t, topicMatches("#", "track.ended"))
+	assert.True(t, topicMatches("track.#", "track"))
+	assert.True(t, topicMatches("#.ended", "session.track.ended"))
+}
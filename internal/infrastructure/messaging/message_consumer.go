@@ -6,6 +6,9 @@ import (
 )
 
 type MessageConsumer interface {
-	ConsumeTrackPlayedEvents(ctx context.Context, handler func(models.TrackPlayedEvent) error) error
+	// Subscribe traite les événements de type eventType, consommés depuis la queue liée à leur clé de routage
+	Subscribe(ctx context.Context, eventType models.EventType, handler func(models.Event) error) error
+	// Wait attend la fin du traitement des messages déjà reçus, après annulation du contexte des Subscribe
+	Wait()
 	Close() error
 }
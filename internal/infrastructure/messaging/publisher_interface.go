@@ -1,8 +1,16 @@
 package messaging
 
-import "radioking-app/internal/domain/models"
+import (
+	"errors"
+	"radioking-app/internal/domain/models"
+)
+
+// ErrUnroutable aucune queue n'est liée à la clé de routage du message : il est renvoyé par le broker
+var ErrUnroutable = errors.New("message not routed to any queue")
 
 type MessagePublisher interface {
-	PublishTrackPlayedEvent(event models.TrackPlayedEvent) error
+	// Publish publie l'événement avec la clé de routage de son type. Un événement d'un type consommé
+	// qu'aucune queue ne reçoit retourne ErrUnroutable ; celui d'un type sans consommateur est perdu.
+	Publish(event models.Event) error
 	Close() error
 }
@@ -2,46 +2,105 @@ package messaging
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"radioking-app/internal/config"
 	"radioking-app/internal/domain/models"
+	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+// RabbitMQConsumer consomme chaque queue sur son propre canal, limité à Prefetch messages non acquittés
+// et traités par Workers workers. Après une coupure, le canal est recréé et le consommateur réenregistré
+// dès que la connexion est rétablie.
 type RabbitMQConsumer struct {
-	conn    *amqp.Connection
-	channel *amqp.Channel
-	config  config.RabbitMQConfig
+	conn   *Connection
+	config config.RabbitMQConfig
+	wg     sync.WaitGroup
 }
 
-func NewRabbitMQConsumer(cfg config.RabbitMQConfig) (*RabbitMQConsumer, error) {
-	conn, err := amqp.Dial(cfg.URL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
-	}
+// decodedMessage message décodé : process le traite, après les messages précédents de même clé
+type decodedMessage struct {
+	key     int64
+	process func() error
+}
 
-	channel, err := conn.Channel()
+func NewRabbitMQConsumer(cfg config.RabbitMQConfig) (*RabbitMQConsumer, error) {
+	conn, err := Dial(cfg)
 	if err != nil {
-		conn.Close()
-		return nil, fmt.Errorf("failed to open channel: %w", err)
+		return nil, err
 	}
 
 	consumer := &RabbitMQConsumer{
-		conn:    conn,
-		channel: channel,
-		config:  cfg,
+		conn:   conn,
+		config: cfg,
 	}
 
 	log.Printf("RabbitMQ Consumer connected to %s", cfg.URL)
 	return consumer, nil
 }
 
-func (c *RabbitMQConsumer) ConsumeTrackPlayedEvents(ctx context.Context, handler func(models.TrackPlayedEvent) error) error {
-	msgs, err := c.channel.Consume(
-		c.config.Queue,
+func (c *RabbitMQConsumer) Subscribe(ctx context.Context, eventType models.EventType, handler func(models.Event) error) error {
+	queue, err := subscribedQueue(c.config, eventType)
+	if err != nil {
+		return err
+	}
+	return c.consume(ctx, queue, eventDecoder(eventType, handler))
+}
+
+// consume décode chaque message avec decode : un message illisible est écarté,
+// un message dont le traitement échoue est retenté puis écarté après MaxRetries tentatives.
+// Seul le premier enregistrement du consommateur peut échouer ; ensuite il est réenregistré
+// après chaque coupure jusqu'à l'annulation de ctx.
+func (c *RabbitMQConsumer) consume(ctx context.Context, queue string, decode func(msg wireMessage) (decodedMessage, error)) error {
+	channel, msgs, err := c.subscribe(ctx, queue)
+	if err != nil {
+		return err
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		for {
+			c.deliver(ctx, queue, channel, msgs, decode)
+			channel.Close()
+			if ctx.Err() != nil {
+				log.Printf("Context cancelled, stopping RabbitMQ consumer of queue %s", queue)
+				return
+			}
+
+			log.Printf("RabbitMQ consumer of queue %s interrupted, registering again", queue)
+			channel, msgs, err = c.resubscribe(ctx, queue)
+			if err != nil {
+				return
+			}
+			log.Printf("RabbitMQ consumer of queue %s registered again", queue)
+		}
+	}()
+
+	return nil
+}
+
+// subscribe ouvre un canal et y enregistre le consommateur de queue
+func (c *RabbitMQConsumer) subscribe(ctx context.Context, queue string) (*amqp.Channel, <-chan amqp.Delivery, error) {
+	channel, err := c.conn.Channel(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if c.config.Prefetch > 0 {
+		if err := channel.Qos(c.config.Prefetch, 0, false); err != nil {
+			channel.Close()
+			return nil, nil, fmt.Errorf("failed to set prefetch: %w", err)
+		}
+	}
+
+	msgs, err := channel.Consume(
+		queue,
 		"",
 		false,
 		false,
@@ -50,50 +109,123 @@ func (c *RabbitMQConsumer) ConsumeTrackPlayedEvents(ctx context.Context, handler
 		nil,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to register consumer: %w", err)
+		channel.Close()
+		return nil, nil, fmt.Errorf("failed to register consumer: %w", err)
+	}
+	return channel, msgs, nil
+}
+
+// resubscribe réenregistre le consommateur avec un délai croissant, jusqu'à l'annulation de ctx
+func (c *RabbitMQConsumer) resubscribe(ctx context.Context, queue string) (*amqp.Channel, <-chan amqp.Delivery, error) {
+	for attempt := 1; ; attempt++ {
+		channel, msgs, err := c.subscribe(ctx, queue)
+		if err == nil {
+			return channel, msgs, nil
+		}
+		if ctx.Err() != nil || errors.Is(err, ErrConnectionClosed) {
+			return nil, nil, err
+		}
+
+		delay := retryDelay(c.config.ReconnectDelay, c.config.ReconnectMaxDelay, attempt)
+		log.Printf("Failed to register consumer of queue %s, retrying in %s: %v", queue, delay, err)
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(delay):
+		}
 	}
+}
 
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				log.Println("Context cancelled, stopping RabbitMQ consumer")
-				return
-			case msg, ok := <-msgs:
-				if !ok {
-					log.Println("RabbitMQ messages channel closed")
-					return
-				}
+// deliver répartit les messages entre les workers jusqu'à l'annulation de ctx ou la fermeture du canal,
+// puis attend la fin des messages en cours. Les messages reçus mais pas encore confiés à un worker sont
+// remis en queue par la fermeture du canal.
+func (c *RabbitMQConsumer) deliver(ctx context.Context, queue string, channel *amqp.Channel, msgs <-chan amqp.Delivery,
+	decode func(msg wireMessage) (decodedMessage, error)) {
+	pool := newWorkerPool(c.config.Workers)
+	defer pool.drain()
 
-				var event models.TrackPlayedEvent
-				if err := json.Unmarshal(msg.Body, &event); err != nil {
-					log.Printf("Failed to unmarshal track played event: %v", err)
-					msg.Nack(false, false)
-					continue
-				}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-msgs:
+			if !ok {
+				log.Printf("RabbitMQ messages channel of queue %s closed", queue)
+				return
+			}
 
-				log.Printf("Consumed track played event: PlaylistID=%d, TrackID=%d, Position=%d",
-					event.PlaylistID, event.TrackID, event.Position)
+			decoded, err := decode(wireMessage{ContentType: msg.ContentType, Headers: msg.Headers, Body: msg.Body, Redelivered: msg.Redelivered})
+			if err != nil {
+				log.Printf("%v", err)
+				c.deadLetter(channel, queue, msg, err)
+				continue
+			}
 
-				if err := handler(event); err != nil {
-					log.Printf("Failed to process track played event: %v", err)
-					msg.Nack(false, true)
+			pool.submit(decoded.key, func() {
+				if err := decoded.process(); err != nil {
+					log.Printf("Failed to process message from queue %s: %v", queue, err)
+					c.retry(channel, queue, msg, err)
 				} else {
 					msg.Ack(false)
 				}
-			}
+			})
 		}
-	}()
+	}
+}
 
-	return nil
+// retry republie le message dans la queue d'attente de sa prochaine tentative,
+// ou l'écarte s'il a épuisé ses tentatives
+func (c *RabbitMQConsumer) retry(channel *amqp.Channel, queue string, msg amqp.Delivery, cause error) {
+	attempt := retryCount(msg.Headers) + 1
+	if attempt > c.config.MaxRetries {
+		c.deadLetter(channel, queue, msg, cause)
+		return
+	}
+
+	headers := copyHeaders(msg.Headers)
+	headers[RetryCountHeader] = int32(attempt)
+	headers[LastErrorHeader] = cause.Error()
+	delay := retryDelay(c.config.RetryBaseDelay, c.config.RetryMaxDelay, attempt)
+	c.republish(channel, msg, "", retryQueueName(queue, delay), headers)
+	log.Printf("Message from queue %s scheduled for retry %d/%d in %s", queue, attempt, c.config.MaxRetries, delay)
 }
 
-func (c *RabbitMQConsumer) Close() error {
-	if c.channel != nil {
-		c.channel.Close()
+// deadLetter publie le message dans l'exchange des messages écartés avec la cause de son échec
+func (c *RabbitMQConsumer) deadLetter(channel *amqp.Channel, queue string, msg amqp.Delivery, cause error) {
+	headers := copyHeaders(msg.Headers)
+	headers[LastErrorHeader] = cause.Error()
+	headers[OriginalQueueHeader] = queue
+	headers[FailedAtHeader] = time.Now().UTC().Format(time.RFC3339)
+	if msg.MessageId == "" {
+		msg.MessageId = uuid.New().String()
 	}
-	if c.conn != nil {
-		c.conn.Close()
+	c.republish(channel, msg, c.config.DeadLetterExchange, queue, headers)
+	log.Printf("Message %s from queue %s dead-lettered: %v", msg.MessageId, queue, cause)
+}
+
+// republish acquitte le message une fois sa copie publiée sur le canal de sa réception. Si la publication
+// échoue, le message est rejeté vers l'exchange des messages écartés déclaré sur la queue plutôt que perdu.
+func (c *RabbitMQConsumer) republish(channel *amqp.Channel, msg amqp.Delivery, exchange string, routingKey string, headers amqp.Table) {
+	err := channel.Publish(exchange, routingKey, false, false, amqp.Publishing{
+		Headers:      headers,
+		ContentType:  msg.ContentType,
+		MessageId:    msg.MessageId,
+		Timestamp:    msg.Timestamp,
+		Body:         msg.Body,
+		DeliveryMode: amqp.Persistent,
+	})
+	if err != nil {
+		log.Printf("Failed to republish message to %s: %v", routingKey, err)
+		msg.Nack(false, false)
+		return
 	}
-	return nil
+	msg.Ack(false)
+}
+
+func (c *RabbitMQConsumer) Wait() {
+	c.wg.Wait()
+}
+
+func (c *RabbitMQConsumer) Close() error {
+	return c.conn.Close()
 }
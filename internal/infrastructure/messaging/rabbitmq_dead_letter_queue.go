@@ -0,0 +1,198 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"radioking-app/internal/config"
+	"radioking-app/internal/domain/models"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// RabbitMQDeadLetterQueue lit la queue des messages écartés message par message. Les messages
+// lus sont gardés non acquittés jusqu'à la fin de l'opération, puis remis en queue s'ils ne sont
+// pas rejoués : les opérations sont donc sérialisées. Chaque opération ouvre son propre canal.
+type RabbitMQDeadLetterQueue struct {
+	conn   *Connection
+	config config.RabbitMQConfig
+	mu     sync.Mutex
+}
+
+func NewRabbitMQDeadLetterQueue(cfg config.RabbitMQConfig) (*RabbitMQDeadLetterQueue, error) {
+	conn, err := Dial(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &RabbitMQDeadLetterQueue{conn: conn, config: cfg}, nil
+}
+
+// channel ouvre le canal d'une opération, en attendant au plus ConfirmTimeout une reconnexion
+func (q *RabbitMQDeadLetterQueue) channel() (*amqp.Channel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), q.config.ConfirmTimeout)
+	defer cancel()
+	return q.conn.Channel(ctx)
+}
+
+func (q *RabbitMQDeadLetterQueue) Peek(limit int) ([]models.DeadLetter, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	channel, err := q.channel()
+	if err != nil {
+		return nil, err
+	}
+	defer channel.Close()
+
+	var held []amqp.Delivery
+	defer func() { requeue(held) }()
+
+	letters := make([]models.DeadLetter, 0, limit)
+	for len(letters) < limit {
+		msg, ok, err := channel.Get(q.config.DeadLetterQueue, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dead letter: %w", err)
+		}
+		if !ok {
+			break
+		}
+		held = append(held, msg)
+		letters = append(letters, toDeadLetter(msg))
+	}
+	return letters, nil
+}
+
+func (q *RabbitMQDeadLetterQueue) Replay(ids []string) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	channel, err := q.channel()
+	if err != nil {
+		return 0, err
+	}
+	defer channel.Close()
+
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	var held []amqp.Delivery
+	defer func() { requeue(held) }()
+
+	// Seuls les messages présents au départ sont lus : un message rejoué peut revenir pendant l'opération
+	replayed, pending := 0, 1
+	for read := 0; read < pending; read++ {
+		msg, ok, err := channel.Get(q.config.DeadLetterQueue, false)
+		if err != nil {
+			return replayed, fmt.Errorf("failed to get dead letter: %w", err)
+		}
+		if !ok {
+			break
+		}
+		if read == 0 {
+			pending = int(msg.MessageCount) + 1
+		}
+		if len(wanted) > 0 && !wanted[msg.MessageId] {
+			held = append(held, msg)
+			continue
+		}
+
+		if err := q.replay(channel, msg); err != nil {
+			held = append(held, msg)
+			return replayed, err
+		}
+		replayed++
+	}
+	return replayed, nil
+}
+
+// replay republie le message dans sa queue d'origine pour un nouveau cycle de tentatives
+func (q *RabbitMQDeadLetterQueue) replay(channel *amqp.Channel, msg amqp.Delivery) error {
+	letter := toDeadLetter(msg)
+	if letter.Queue == "" {
+		return fmt.Errorf("dead letter %s has no original queue", msg.MessageId)
+	}
+
+	headers := copyHeaders(msg.Headers)
+	for _, key := range []string{RetryCountHeader, LastErrorHeader, OriginalQueueHeader, FailedAtHeader, "x-death"} {
+		delete(headers, key)
+	}
+	err := channel.Publish("", letter.Queue, false, false, amqp.Publishing{
+		Headers:      headers,
+		ContentType:  msg.ContentType,
+		MessageId:    msg.MessageId,
+		Timestamp:    msg.Timestamp,
+		Body:         msg.Body,
+		DeliveryMode: amqp.Persistent,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to replay dead letter %s: %w", msg.MessageId, err)
+	}
+
+	log.Printf("Replayed dead letter %s to queue %s", msg.MessageId, letter.Queue)
+	return msg.Ack(false)
+}
+
+func (q *RabbitMQDeadLetterQueue) Purge() (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	channel, err := q.channel()
+	if err != nil {
+		return 0, err
+	}
+	defer channel.Close()
+
+	count, err := channel.QueuePurge(q.config.DeadLetterQueue, false)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge dead letters: %w", err)
+	}
+	return count, nil
+}
+
+func (q *RabbitMQDeadLetterQueue) Close() error {
+	return q.conn.Close()
+}
+
+// requeue remet en queue les messages lus et non rejoués
+func requeue(held []amqp.Delivery) {
+	for _, msg := range held {
+		if err := msg.Nack(false, true); err != nil {
+			log.Printf("Failed to requeue dead letter %s: %v", msg.MessageId, err)
+		}
+	}
+}
+
+// toDeadLetter un message rejeté par RabbitMQ (x-death) n'a pas les en-têtes posés par le consommateur
+func toDeadLetter(msg amqp.Delivery) models.DeadLetter {
+	letter := models.DeadLetter{
+		MessageID:  msg.MessageId,
+		RoutingKey: msg.RoutingKey,
+		RetryCount: retryCount(msg.Headers),
+		Body:       msg.Body,
+	}
+	letter.Queue, _ = msg.Headers[OriginalQueueHeader].(string)
+	letter.Reason, _ = msg.Headers[LastErrorHeader].(string)
+	if failedAt, ok := msg.Headers[FailedAtHeader].(string); ok {
+		letter.FailedAt, _ = time.Parse(time.RFC3339, failedAt)
+	}
+
+	if deaths, ok := msg.Headers["x-death"].([]interface{}); ok && len(deaths) > 0 {
+		if death, ok := deaths[0].(amqp.Table); ok {
+			if letter.Queue == "" {
+				letter.Queue, _ = death["queue"].(string)
+			}
+			if letter.Reason == "" {
+				reason, _ := death["reason"].(string)
+				letter.Reason = "rejected by broker: " + reason
+			}
+			if letter.FailedAt.IsZero() {
+				letter.FailedAt, _ = death["time"].(time.Time)
+			}
+		}
+	}
+	return letter
+}
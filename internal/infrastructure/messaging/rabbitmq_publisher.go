@@ -1,76 +1,169 @@
 package messaging
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"log"
 	"radioking-app/internal/config"
 	"radioking-app/internal/domain/models"
+	"sync"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+// RabbitMQPublisher publie en mode confirm : un message n'est publié qu'une fois confirmé par le broker,
+// dans le délai ConfirmTimeout qui inclut l'attente d'une reconnexion. Le canal est recréé après une coupure.
+// Les messages sont obligatoires : un message qu'aucune queue ne reçoit est renvoyé et la publication échoue.
 type RabbitMQPublisher struct {
-	conn    *amqp.Connection
+	conn   *Connection
+	config config.RabbitMQConfig
+
+	mu      sync.Mutex
 	channel *amqp.Channel
-	config  config.RabbitMQConfig
+	returns chan amqp.Return
 }
 
 func NewRabbitMQPublisher(cfg config.RabbitMQConfig) (*RabbitMQPublisher, error) {
-	conn, err := amqp.Dial(cfg.URL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	if cfg.CloudEventsMode != CloudEventsStructured && cfg.CloudEventsMode != CloudEventsBinary {
+		return nil, fmt.Errorf("unknown cloudevents mode %q", cfg.CloudEventsMode)
 	}
 
-	channel, err := conn.Channel()
+	conn, err := Dial(cfg)
 	if err != nil {
-		conn.Close()
-		return nil, fmt.Errorf("failed to open channel: %w", err)
+		return nil, err
 	}
 
 	publisher := &RabbitMQPublisher{
-		conn:    conn,
-		channel: channel,
-		config:  cfg,
+		conn:   conn,
+		config: cfg,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConfirmTimeout)
+	defer cancel()
+	if _, err := publisher.confirmChannel(ctx); err != nil {
+		conn.Close()
+		return nil, err
 	}
 
 	log.Printf("RabbitMQ Publisher connected to %s", cfg.URL)
 	return publisher, nil
 }
 
-func (p *RabbitMQPublisher) PublishTrackPlayedEvent(event models.TrackPlayedEvent) error {
-	body, err := json.Marshal(event)
+// Publish publie l'événement au format CloudEvents, dans le mode CloudEventsMode de la configuration
+func (p *RabbitMQPublisher) Publish(event models.Event) error {
+	msg, err := encodeEvent(event, p.config.CloudEventsMode)
 	if err != nil {
-		return fmt.Errorf("failed to marshal event: %w", err)
+		return err
+	}
+
+	if err := p.publish(routingKey(p.config, event.Type), consumed(p.config, event.Type), event, msg); err != nil {
+		return err
 	}
 
-	err = p.channel.Publish(
+	log.Printf("Published %s event: ID=%s, Version=%d, PartitionKey=%d", event.Type, event.ID, event.Version, event.PartitionKey)
+	return nil
+}
+
+func (p *RabbitMQPublisher) publish(routingKey string, mandatory bool, event models.Event, msg wireMessage) error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.config.ConfirmTimeout)
+	defer cancel()
+
+	// Les publications sont sérialisées : chacune attend sa confirmation avant la suivante
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	channel, err := p.confirmChannel(ctx)
+	if err != nil {
+		return err
+	}
+
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(
+		ctx,
 		p.config.Exchange,
-		p.config.RoutingKey,
-		false,
+		routingKey,
+		mandatory,
 		false,
 		amqp.Publishing{
-			ContentType:  "application/json",
-			Body:         body,
+			Headers:      msg.Headers,
+			ContentType:  msg.ContentType,
+			MessageId:    event.ID,
+			Timestamp:    event.Time,
+			Body:         msg.Body,
 			DeliveryMode: amqp.Persistent,
 		},
 	)
 
 	if err != nil {
+		p.resetChannel()
 		return fmt.Errorf("failed to publish message: %w", err)
 	}
 
-	log.Printf("Published track played event: PlaylistID=%d, TrackID=%d, Position=%d",
-		event.PlaylistID, event.TrackID, event.Position)
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		// Sans confirmation, l'état du canal est inconnu : le suivant repart d'un canal neuf
+		p.resetChannel()
+		return fmt.Errorf("failed to confirm message: %w", err)
+	}
+	if !acked {
+		return fmt.Errorf("message rejected by broker")
+	}
+	if returned, ok := returnedMessage(p.returns, event.ID); ok {
+		return fmt.Errorf("%w: %s %s", ErrUnroutable, routingKey, returned.ReplyText)
+	}
 	return nil
 }
 
-func (p *RabbitMQPublisher) Close() error {
+// returnedMessage cherche le retour du message messageID parmi les retours reçus. Le broker renvoie un
+// message obligatoire avant de le confirmer : son retour est reçu quand la confirmation arrive.
+// Les retours d'une publication précédente abandonnée sont écartés.
+func returnedMessage(returns <-chan amqp.Return, messageID string) (amqp.Return, bool) {
+	for {
+		select {
+		case returned, open := <-returns:
+			if !open {
+				return amqp.Return{}, false
+			}
+			if returned.MessageId == messageID {
+				return returned, true
+			}
+		default:
+			return amqp.Return{}, false
+		}
+	}
+}
+
+// confirmChannel retourne le canal de publication, recréé en mode confirm s'il a été fermé
+func (p *RabbitMQPublisher) confirmChannel(ctx context.Context) (*amqp.Channel, error) {
+	if p.channel != nil && !p.channel.IsClosed() {
+		return p.channel, nil
+	}
+
+	channel, err := p.conn.Channel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := channel.Confirm(false); err != nil {
+		channel.Close()
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	// Une seule publication est en attente de confirmation à la fois, donc au plus un retour
+	p.returns = channel.NotifyReturn(make(chan amqp.Return, 1))
+	p.channel = channel
+	return channel, nil
+}
+
+func (p *RabbitMQPublisher) resetChannel() {
 	if p.channel != nil {
 		p.channel.Close()
+		p.channel = nil
+		p.returns = nil
 	}
-	if p.conn != nil {
-		p.conn.Close()
-	}
-	return nil
+}
+
+func (p *RabbitMQPublisher) Close() error {
+	p.mu.Lock()
+	p.resetChannel()
+	p.mu.Unlock()
+	return p.conn.Close()
 }
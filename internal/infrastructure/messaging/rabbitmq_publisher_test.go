@@ -0,0 +1,39 @@
+package messaging
+
+import (
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReturnedMessage_MatchesMessageID(t *testing.T) {
+	// Arrange : le retour d'une publication abandonnée précède celui du message publié
+	returns := make(chan amqp.Return, 2)
+	returns <- amqp.Return{MessageId: "abandoned", ReplyText: "NO_ROUTE"}
+	returns <- amqp.Return{MessageId: "published", ReplyText: "NO_ROUTE"}
+
+	// Act
+	returned, ok := returnedMessage(returns, "published")
+	_, again := returnedMessage(returns, "published")
+
+	// Assert
+	assert.True(t, ok)
+	assert.Equal(t, "NO_ROUTE", returned.ReplyText)
+	assert.False(t, again, "les retours sont consommés")
+}
+
+func TestReturnedMessage_NoReturn(t *testing.T) {
+	// Arrange
+	open := make(chan amqp.Return, 1)
+	closed := make(chan amqp.Return)
+	close(closed)
+
+	// Act
+	_, fromOpen := returnedMessage(open, "published")
+	_, fromClosed := returnedMessage(closed, "published")
+
+	// Assert
+	assert.False(t, fromOpen)
+	assert.False(t, fromClosed)
+}
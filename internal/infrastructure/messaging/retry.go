@@ -0,0 +1,58 @@
+package messaging
+
+import (
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// En-têtes portés par les messages retentés ou écartés
+const (
+	RetryCountHeader    = "x-retry-count"
+	LastErrorHeader     = "x-last-error"
+	OriginalQueueHeader = "x-original-queue"
+	FailedAtHeader      = "x-failed-at"
+)
+
+// retryDelay attente avant la tentative attempt (à partir de 1) : base, puis doublée à chaque tentative, au plus max
+func retryDelay(base time.Duration, max time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
+
+// retryQueueName queue d'attente des messages de queue retentés après delay. Le délai fait partie du nom :
+// un changement de retry_base_delay déclare de nouvelles queues au lieu de redéclarer les existantes
+// avec un autre TTL, ce que le broker refuserait.
+func retryQueueName(queue string, delay time.Duration) string {
+	return fmt.Sprintf("%s.retry.%dms", queue, delay.Milliseconds())
+}
+
+// retryCount nombre de tentatives déjà effectuées, 0 pour un premier traitement
+func retryCount(headers amqp.Table) int {
+	switch count := headers[RetryCountHeader].(type) {
+	case int32:
+		return int(count)
+	case int64:
+		return int(count)
+	case int:
+		return count
+	default:
+		return 0
+	}
+}
+
+// copyHeaders copie les en-têtes du message pour les compléter avant de le republier
+func copyHeaders(headers amqp.Table) amqp.Table {
+	copied := amqp.Table{}
+	for key, value := range headers {
+		copied[key] = value
+	}
+	return copied
+}
@@ -0,0 +1,43 @@
+package messaging
+
+import (
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryDelay_DoublesUpToMax(t *testing.T) {
+	base, max := time.Second, 10*time.Second
+
+	assert.Equal(t, time.Second, retryDelay(base, max, 1))
+	assert.Equal(t, 2*time.Second, retryDelay(base, max, 2))
+	assert.Equal(t, 8*time.Second, retryDelay(base, max, 4))
+	assert.Equal(t, max, retryDelay(base, max, 5))
+	assert.Equal(t, max, retryDelay(base, max, 60))
+}
+
+func TestRetryCount_ReadsHeader(t *testing.T) {
+	assert.Equal(t, 0, retryCount(nil))
+	assert.Equal(t, 2, retryCount(amqp.Table{RetryCountHeader: int32(2)}))
+	assert.Equal(t, 3, retryCount(amqp.Table{RetryCountHeader: int64(3)}))
+}
+
+func TestToDeadLetter_UsesBrokerDeathWhenNotDeadLetteredByConsumer(t *testing.T) {
+	failedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	msg := amqp.Delivery{
+		MessageId:  "id",
+		RoutingKey: "track_played",
+		Body:       []byte(`{}`),
+		Headers: amqp.Table{"x-death": []interface{}{
+			amqp.Table{"queue": "track_played", "reason": "rejected", "time": failedAt},
+		}},
+	}
+
+	letter := toDeadLetter(msg)
+
+	assert.Equal(t, "track_played", letter.Queue)
+	assert.Equal(t, "rejected by broker: rejected", letter.Reason)
+	assert.Equal(t, failedAt, letter.FailedAt)
+}
@@ -0,0 +1,63 @@
+package messaging
+
+import (
+	"fmt"
+	"radioking-app/internal/config"
+	"radioking-app/internal/domain/models"
+	"strings"
+)
+
+// routingKey clé de routage des événements de type eventType : celles des événements de lecture
+// sont configurables, les autres événements sont routés par leur type
+func routingKey(cfg config.RabbitMQConfig, eventType models.EventType) string {
+	switch eventType {
+	case models.EventTrackPlayed:
+		return cfg.RoutingKey
+	case models.EventTrackEnded:
+		return cfg.EndedRoutingKey
+	default:
+		return string(eventType)
+	}
+}
+
+// subscribedQueue queue consommée à laquelle sont routés les événements de type eventType
+func subscribedQueue(cfg config.RabbitMQConfig, eventType models.EventType) (string, error) {
+	key := routingKey(cfg, eventType)
+	for _, binding := range consumedQueues(cfg) {
+		if topicMatches(binding.RoutingKey, key) {
+			return binding.Queue, nil
+		}
+	}
+	return "", fmt.Errorf("no queue bound to %s events, add the type to subscribed_events", eventType)
+}
+
+// consumed indique si une queue consommée reçoit les événements de type eventType. Ils sont alors publiés
+// en mandatory pour qu'un événement renvoyé par le broker reste à délivrer ; les autres n'ont pas de destinataire.
+func consumed(cfg config.RabbitMQConfig, eventType models.EventType) bool {
+	_, err := subscribedQueue(cfg, eventType)
+	return err == nil
+}
+
+// topicMatches applique les règles d'un exchange topic : "*" remplace exactement un mot, "#" zéro ou plusieurs
+func topicMatches(pattern string, routingKey string) bool {
+	return matchWords(strings.Split(pattern, "."), strings.Split(routingKey, "."))
+}
+
+func matchWords(pattern []string, words []string) bool {
+	if len(pattern) == 0 {
+		return len(words) == 0
+	}
+	switch pattern[0] {
+	case "#":
+		for i := 0; i <= len(words); i++ {
+			if matchWords(pattern[1:], words[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(words) > 0 && matchWords(pattern[1:], words[1:])
+	default:
+		return len(words) > 0 && words[0] == pattern[0] && matchWords(pattern[1:], words[1:])
+	}
+}
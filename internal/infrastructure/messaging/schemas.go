@@ -0,0 +1,68 @@
+package messaging
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"radioking-app/internal/domain/models"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// schemaFiles schémas JSON publiés des payloads, un fichier <type>.v<version>.json par version.
+// Un schéma publié n'est plus modifié : un changement incompatible publie une nouvelle version.
+//
+//go:embed schemas/*.json
+var schemaFiles embed.FS
+
+var ErrSchemaNotFound = errors.New("event schema not found")
+
+// EventSchema schéma JSON du payload des événements de type eventType dans version
+func EventSchema(eventType models.EventType, version int) ([]byte, error) {
+	schema, err := schemaFiles.ReadFile(schemaFileName(eventType, version))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s v%d", ErrSchemaNotFound, eventType, version)
+	}
+	return schema, err
+}
+
+// EventSchemas schémas publiés, par type puis par version
+func EventSchemas() ([]models.EventSchemaRef, error) {
+	entries, err := schemaFiles.ReadDir("schemas")
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]models.EventSchemaRef, 0, len(entries))
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		separator := strings.LastIndex(name, ".v")
+		if separator < 0 {
+			return nil, fmt.Errorf("invalid schema file name %s", entry.Name())
+		}
+		version, err := strconv.Atoi(name[separator+2:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema file name %s", entry.Name())
+		}
+		eventType := models.EventType(name[:separator])
+		refs = append(refs, models.EventSchemaRef{
+			Type:    eventType,
+			Version: version,
+			Current: version == eventType.Version(),
+		})
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Type != refs[j].Type {
+			return refs[i].Type < refs[j].Type
+		}
+		return refs[i].Version < refs[j].Version
+	})
+	return refs, nil
+}
+
+func schemaFileName(eventType models.EventType, version int) string {
+	return fmt.Sprintf("schemas/%s.v%d.json", eventType, version)
+}
@@ -0,0 +1,285 @@
+package messaging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"radioking-app/internal/domain/models"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Ces tests protègent les consommateurs des changements incompatibles des payloads. Chaque fichier de
+// testdata/events est un message publié dans une version du schéma de son type, et doit rester lisible
+// par la version courante. Un changement incompatible d'un payload passe donc par une nouvelle version :
+// schéma publié, upcaster depuis la version précédente et message d'exemple.
+
+// payloadTypes payload émis pour chaque type d'événement
+var payloadTypes = map[models.EventType]any{
+	models.EventTrackPlayed:     models.TrackPlayedEvent{},
+	models.EventTrackEnded:      models.TrackEndedEvent{},
+	models.EventPlaylistCreated: models.PlaylistChangedEvent{},
+	models.EventPlaylistUpdated: models.PlaylistChangedEvent{},
+	models.EventPlaylistDeleted: models.PlaylistDeletedEvent{},
+	models.EventPlaylistPlayed:  models.PlaylistPlayedEvent{},
+}
+
+// jsonSchema sous-ensemble de JSON Schema utilisé par les schémas publiés
+type jsonSchema struct {
+	Type                 any                    `json:"type"`
+	Format               string                 `json:"format"`
+	Enum                 []any                  `json:"enum"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+}
+
+func loadSchema(t *testing.T, eventType models.EventType, version int) *jsonSchema {
+	t.Helper()
+	raw, err := EventSchema(eventType, version)
+	require.NoError(t, err)
+	var schema jsonSchema
+	require.NoError(t, json.Unmarshal(raw, &schema), "%s v%d", eventType, version)
+	return &schema
+}
+
+func (s *jsonSchema) types() []string {
+	switch value := s.Type.(type) {
+	case string:
+		return []string{value}
+	case []any:
+		types := make([]string, 0, len(value))
+		for _, item := range value {
+			types = append(types, fmt.Sprint(item))
+		}
+		return types
+	}
+	return nil
+}
+
+// validate vérifie value, décodé avec UseNumber, contre le schéma
+func (s *jsonSchema) validate(path string, value any) error {
+	actual := jsonTypeOf(value)
+	if types := s.types(); len(types) > 0 && !slices.Contains(types, actual) &&
+		!(actual == "integer" && slices.Contains(types, "number")) {
+		return fmt.Errorf("%s: %s instead of %v", path, actual, types)
+	}
+	if len(s.Enum) > 0 && !slices.Contains(s.Enum, value) {
+		return fmt.Errorf("%s: %v not in %v", path, value, s.Enum)
+	}
+	if s.Format == "date-time" {
+		if _, err := time.Parse(time.RFC3339Nano, value.(string)); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := value[name]; !ok {
+				return fmt.Errorf("%s: missing required property %s", path, name)
+			}
+		}
+		for name, property := range value {
+			schema, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("%s: unknown property %s", path, name)
+				}
+				continue
+			}
+			if err := schema.validate(path+"."+name, property); err != nil {
+				return err
+			}
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range value {
+				if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func jsonTypeOf(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// goJSONType type JSON de la sérialisation d'une valeur de type t
+func goJSONType(t reflect.Type) string {
+	if t == reflect.TypeOf(time.Time{}) {
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return goJSONType(t.Elem())
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func TestEventSchemas_DescribeCurrentPayloads(t *testing.T) {
+	for _, eventType := range models.EventTypes() {
+		t.Run(string(eventType), func(t *testing.T) {
+			payload, ok := payloadTypes[eventType]
+			require.True(t, ok, "payload de %s inconnu des tests", eventType)
+			schema := loadSchema(t, eventType, eventType.Version())
+
+			payloadType := reflect.TypeOf(payload)
+			var fields []string
+			for i := 0; i < payloadType.NumField(); i++ {
+				field := payloadType.Field(i)
+				name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+				if name == "-" || !field.IsExported() {
+					continue
+				}
+				fields = append(fields, name)
+
+				property, ok := schema.Properties[name]
+				if !assert.True(t, ok, "champ %s absent du schéma", name) {
+					continue
+				}
+				expected := goJSONType(field.Type)
+				assert.Contains(t, property.types(), expected, "type du champ %s", name)
+				if field.Type.Kind() == reflect.Pointer && !strings.Contains(options, "omitempty") {
+					assert.Contains(t, property.types(), "null", "champ %s nullable", name)
+				}
+				if expected == "array" && assert.NotNil(t, property.Items, "éléments du champ %s", name) {
+					assert.Contains(t, property.Items.types(), goJSONType(field.Type.Elem()), "éléments du champ %s", name)
+				}
+				assert.Equal(t, !strings.Contains(options, "omitempty"), slices.Contains(schema.Required, name),
+					"champ %s requis s'il est toujours émis", name)
+			}
+
+			for name := range schema.Properties {
+				assert.Contains(t, fields, name, "propriété %s absente du payload", name)
+			}
+		})
+	}
+}
+
+func TestEventSchemas_PublishedMessagesStayReadable(t *testing.T) {
+	samples, err := filepath.Glob("testdata/events/*.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, samples)
+
+	for _, sample := range samples {
+		t.Run(filepath.Base(sample), func(t *testing.T) {
+			eventType, version := sampleVersion(t, sample)
+			body, err := os.ReadFile(sample)
+			require.NoError(t, err)
+			msg := wireMessage{ContentType: eventDataContentType, Body: body}
+			if version > 0 {
+				msg.ContentType = cloudEventsContentType
+			}
+
+			var consumed models.Event
+			decoded, err := eventDecoder(eventType, func(event models.Event) error {
+				consumed = event
+				return nil
+			})(msg)
+			require.NoError(t, err)
+			require.NoError(t, decoded.process())
+
+			assert.Equal(t, eventType.Version(), consumed.Version)
+			assert.NotEmpty(t, consumed.ID)
+			assert.NotZero(t, consumed.PartitionKey)
+
+			decoder := json.NewDecoder(bytes.NewReader(consumed.Payload))
+			decoder.UseNumber()
+			var payload any
+			require.NoError(t, decoder.Decode(&payload))
+			require.NoError(t, loadSchema(t, eventType, eventType.Version()).validate("payload", payload))
+
+			target := reflect.New(reflect.TypeOf(payloadTypes[eventType]))
+			strict := json.NewDecoder(bytes.NewReader(consumed.Payload))
+			strict.DisallowUnknownFields()
+			require.NoError(t, strict.Decode(target.Interface()))
+		})
+	}
+}
+
+func TestUpcast_TrackPlayedV0KeepsKnownFields(t *testing.T) {
+	// Arrange : une lecture sans enveloppe qui porte déjà l'artiste
+	event := models.Event{Type: models.EventTrackPlayed, Payload: json.RawMessage(`{"track_id":48,"artist_id":7}`)}
+
+	// Act
+	upcast, err := Upcast(event)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, upcast.Version)
+	assert.JSONEq(t, `{"track_id":48,"artist_id":7,"duration_seconds":0}`, string(upcast.Payload))
+}
+
+func TestEventSchemas_EveryVersionPublishedWithSample(t *testing.T) {
+	refs, err := EventSchemas()
+	require.NoError(t, err)
+
+	published := make(map[models.EventType][]int)
+	for _, ref := range refs {
+		published[ref.Type] = append(published[ref.Type], ref.Version)
+		assert.FileExists(t, filepath.Join("testdata/events", fmt.Sprintf("%s.v%d.json", ref.Type, ref.Version)))
+	}
+
+	for _, eventType := range models.EventTypes() {
+		versions := published[eventType]
+		sort.Ints(versions)
+		expected := make([]int, 0, eventType.Version())
+		for version := 1; version <= eventType.Version(); version++ {
+			expected = append(expected, version)
+		}
+		assert.Equal(t, expected, versions, "versions publiées de %s", eventType)
+	}
+}
+
+func sampleVersion(t *testing.T, sample string) (models.EventType, int) {
+	t.Helper()
+	name := strings.TrimSuffix(filepath.Base(sample), ".json")
+	separator := strings.LastIndex(name, ".v")
+	require.Positive(t, separator, "nom de fichier %s", sample)
+	version, err := strconv.Atoi(name[separator+2:])
+	require.NoError(t, err)
+	return models.EventType(name[:separator]), version
+}
@@ -0,0 +1,204 @@
+package messaging
+
+import (
+	"fmt"
+	"log"
+	"radioking-app/internal/config"
+	"radioking-app/internal/domain/models"
+	"strings"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Modes de provisionnement de la topologie au démarrage
+const (
+	TopologyDeclare = "declare"
+	TopologyVerify  = "verify"
+)
+
+type ExchangeSpec struct {
+	Name string
+	Kind string
+}
+
+type QueueSpec struct {
+	Name string
+	Args amqp.Table
+}
+
+type BindingSpec struct {
+	Queue      string
+	Exchange   string
+	RoutingKey string
+}
+
+// Topology exchanges, queues et bindings utilisés par l'application, tous durables
+type Topology struct {
+	Exchanges []ExchangeSpec
+	Queues    []QueueSpec
+	Bindings  []BindingSpec
+}
+
+// topologyChannel opérations d'un canal AMQP nécessaires au provisionnement, implémentées par *amqp.Channel
+type topologyChannel interface {
+	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+	ExchangeDeclarePassive(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	QueueDeclarePassive(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error
+}
+
+// consumedQueues queues consommées, liées à l'exchange des événements par leur clé de routage : celles des
+// événements de lecture, configurées, puis une queue par autre type souscrit, nommée d'après son type
+func consumedQueues(cfg config.RabbitMQConfig) []BindingSpec {
+	bindings := []BindingSpec{
+		{Queue: cfg.Queue, Exchange: cfg.Exchange, RoutingKey: cfg.RoutingKey},
+		{Queue: cfg.EndedQueue, Exchange: cfg.Exchange, RoutingKey: cfg.EndedRoutingKey},
+	}
+
+	seen := map[models.EventType]bool{models.EventTrackPlayed: true, models.EventTrackEnded: true}
+	for _, subscribed := range cfg.SubscribedEvents {
+		eventType := models.EventType(strings.TrimSpace(subscribed))
+		if eventType == "" || seen[eventType] {
+			continue
+		}
+		seen[eventType] = true
+		bindings = append(bindings, BindingSpec{
+			Queue:      strings.ReplaceAll(string(eventType), ".", "_"),
+			Exchange:   cfg.Exchange,
+			RoutingKey: routingKey(cfg, eventType),
+		})
+	}
+	return bindings
+}
+
+// NewTopology décrit l'exchange des événements, les queues consommées et leurs queues de
+// retentative, ainsi que l'exchange et la queue des messages écartés
+func NewTopology(cfg config.RabbitMQConfig) Topology {
+	topology := Topology{
+		Exchanges: []ExchangeSpec{
+			{Name: cfg.Exchange, Kind: amqp.ExchangeTopic},
+			{Name: cfg.DeadLetterExchange, Kind: amqp.ExchangeTopic},
+		},
+		Queues:   []QueueSpec{{Name: cfg.DeadLetterQueue}},
+		Bindings: []BindingSpec{{Queue: cfg.DeadLetterQueue, Exchange: cfg.DeadLetterExchange, RoutingKey: "#"}},
+	}
+
+	for _, binding := range consumedQueues(cfg) {
+		queue := binding.Queue
+		// Un message rejeté sans remise en queue part directement dans la queue des messages écartés
+		topology.Queues = append(topology.Queues, QueueSpec{
+			Name: queue,
+			Args: amqp.Table{"x-dead-letter-exchange": cfg.DeadLetterExchange},
+		})
+		topology.Bindings = append(topology.Bindings, binding)
+
+		// Chaque délai de retentative a sa propre queue, dont le TTL renvoie le message dans la queue d'origine.
+		// Les tentatives plafonnées à retry_max_delay partagent la même queue.
+		declared := make(map[time.Duration]bool)
+		for attempt := 1; attempt <= cfg.MaxRetries; attempt++ {
+			delay := retryDelay(cfg.RetryBaseDelay, cfg.RetryMaxDelay, attempt)
+			if declared[delay] {
+				continue
+			}
+			declared[delay] = true
+			topology.Queues = append(topology.Queues, QueueSpec{
+				Name: retryQueueName(queue, delay),
+				Args: amqp.Table{
+					"x-message-ttl":             delay.Milliseconds(),
+					"x-dead-letter-exchange":    "",
+					"x-dead-letter-routing-key": queue,
+				},
+			})
+		}
+	}
+	return topology
+}
+
+// Declare déclare la topologie. Les déclarations sont idempotentes tant que les arguments des queues
+// existantes sont inchangés : sinon le broker refuse la déclaration et la queue doit être supprimée.
+// Le TTL des queues de retentative, qui dépend de la configuration, fait partie de leur nom.
+func (t Topology) Declare(channel topologyChannel) error {
+	for _, exchange := range t.Exchanges {
+		if err := channel.ExchangeDeclare(exchange.Name, exchange.Kind, true, false, false, false, nil); err != nil {
+			return fmt.Errorf("failed to declare exchange %s: %w", exchange.Name, err)
+		}
+	}
+	for _, queue := range t.Queues {
+		if _, err := channel.QueueDeclare(queue.Name, true, false, false, false, queue.Args); err != nil {
+			return fmt.Errorf("failed to declare queue %s: %w", queue.Name, err)
+		}
+	}
+	for _, binding := range t.Bindings {
+		if err := channel.QueueBind(binding.Queue, binding.RoutingKey, binding.Exchange, false, nil); err != nil {
+			return fmt.Errorf("failed to bind queue %s to exchange %s: %w", binding.Queue, binding.Exchange, err)
+		}
+	}
+	return nil
+}
+
+// Verify vérifie que les exchanges et les queues existent avec leurs arguments, sans rien créer : une
+// entité existante est redéclarée telle qu'attendue, ce que le broker refuse si ses arguments diffèrent.
+// AMQP ne permet pas de lire les bindings : ils ne sont pas vérifiés. Le broker ferme le canal à la
+// première erreur, la vérification s'arrête donc à la première entité manquante ou différente.
+func (t Topology) Verify(channel topologyChannel) error {
+	for _, exchange := range t.Exchanges {
+		if err := channel.ExchangeDeclarePassive(exchange.Name, exchange.Kind, true, false, false, false, nil); err != nil {
+			return fmt.Errorf("exchange %s is missing: %w", exchange.Name, err)
+		}
+		if err := channel.ExchangeDeclare(exchange.Name, exchange.Kind, true, false, false, false, nil); err != nil {
+			return fmt.Errorf("exchange %s differs from the expected one: %w", exchange.Name, err)
+		}
+	}
+	for _, queue := range t.Queues {
+		if _, err := channel.QueueDeclarePassive(queue.Name, true, false, false, false, nil); err != nil {
+			return fmt.Errorf("queue %s is missing: %w", queue.Name, err)
+		}
+		if _, err := channel.QueueDeclare(queue.Name, true, false, false, false, queue.Args); err != nil {
+			return fmt.Errorf("queue %s differs from the expected one: %w", queue.Name, err)
+		}
+	}
+	return nil
+}
+
+// Provision déclare ou vérifie la topologie selon mode
+func (t Topology) Provision(channel topologyChannel, mode string) error {
+	switch mode {
+	case TopologyDeclare, "":
+		return t.Declare(channel)
+	case TopologyVerify:
+		return t.Verify(channel)
+	default:
+		return fmt.Errorf("unknown topology mode %q", mode)
+	}
+}
+
+// InitRabbitMQInfrastructure déclare ou vérifie la topologie de l'application selon le mode de la configuration
+func InitRabbitMQInfrastructure(cfg config.RabbitMQConfig) error {
+	return ProvisionTopology(cfg, cfg.Topology)
+}
+
+// ProvisionTopology déclare ou vérifie la topologie décrite par cfg selon mode
+func ProvisionTopology(cfg config.RabbitMQConfig, mode string) error {
+	conn, err := amqp.Dial(cfg.URL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+	defer conn.Close()
+
+	channel, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer channel.Close()
+
+	topology := NewTopology(cfg)
+	if err := topology.Provision(channel, mode); err != nil {
+		return err
+	}
+
+	log.Printf("RabbitMQ topology of exchange %s provisioned (%s): %d exchanges, %d queues, %d bindings",
+		cfg.Exchange, mode, len(topology.Exchanges), len(topology.Queues), len(topology.Bindings))
+	return nil
+}
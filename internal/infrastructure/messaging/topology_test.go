@@ -0,0 +1,270 @@
+package messaging
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"radioking-app/internal/config"
+	"radioking-app/internal/domain/models"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBroker broker en mémoire qui applique les règles de déclaration de RabbitMQ : une
+// redéclaration doit être identique et une erreur ferme le canal
+type fakeBroker struct {
+	exchanges map[string]string
+	queues    map[string]amqp.Table
+	bindings  map[BindingSpec]bool
+	closed    bool
+}
+
+func newFakeBroker() *fakeBroker {
+	return &fakeBroker{exchanges: map[string]string{}, queues: map[string]amqp.Table{}, bindings: map[BindingSpec]bool{}}
+}
+
+// reopen simule l'ouverture d'un nouveau canal
+func (b *fakeBroker) reopen() *fakeBroker {
+	b.closed = false
+	return b
+}
+
+func (b *fakeBroker) fail(code int, reason string) error {
+	b.closed = true
+	return &amqp.Error{Code: code, Reason: reason}
+}
+
+func (b *fakeBroker) ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error {
+	if b.closed {
+		return amqp.ErrClosed
+	}
+	if existing, ok := b.exchanges[name]; ok && existing != kind {
+		return b.fail(amqp.PreconditionFailed, "inequivalent arg 'type' for exchange "+name)
+	}
+	b.exchanges[name] = kind
+	return nil
+}
+
+func (b *fakeBroker) ExchangeDeclarePassive(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error {
+	if b.closed {
+		return amqp.ErrClosed
+	}
+	if _, ok := b.exchanges[name]; !ok {
+		return b.fail(amqp.NotFound, "no exchange "+name)
+	}
+	return nil
+}
+
+func (b *fakeBroker) QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error) {
+	if b.closed {
+		return amqp.Queue{}, amqp.ErrClosed
+	}
+	if existing, ok := b.queues[name]; ok && !reflect.DeepEqual(existing, args) {
+		return amqp.Queue{}, b.fail(amqp.PreconditionFailed, "inequivalent arguments for queue "+name)
+	}
+	b.queues[name] = args
+	return amqp.Queue{Name: name}, nil
+}
+
+func (b *fakeBroker) QueueDeclarePassive(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error) {
+	if b.closed {
+		return amqp.Queue{}, amqp.ErrClosed
+	}
+	if _, ok := b.queues[name]; !ok {
+		return amqp.Queue{}, b.fail(amqp.NotFound, "no queue "+name)
+	}
+	return amqp.Queue{Name: name}, nil
+}
+
+func (b *fakeBroker) QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error {
+	if b.closed {
+		return amqp.ErrClosed
+	}
+	if _, ok := b.queues[name]; !ok {
+		return b.fail(amqp.NotFound, "no queue "+name)
+	}
+	if _, ok := b.exchanges[exchange]; !ok {
+		return b.fail(amqp.NotFound, "no exchange "+exchange)
+	}
+	b.bindings[BindingSpec{Queue: name, Exchange: exchange, RoutingKey: key}] = true
+	return nil
+}
+
+func newTestRabbitMQConfig() config.RabbitMQConfig {
+	return config.RabbitMQConfig{
+		Exchange:           "playlist_events",
+		Queue:              "track_played",
+		RoutingKey:         "track.played",
+		EndedQueue:         "track_ended",
+		EndedRoutingKey:    "track.ended",
+		MaxRetries:         2,
+		RetryBaseDelay:     time.Second,
+		RetryMaxDelay:      time.Minute,
+		DeadLetterExchange: "playlist_events.dlx",
+		DeadLetterQueue:    "playlist_events.dead",
+		CloudEventsMode:    CloudEventsStructured,
+	}
+}
+
+func TestTopology_DeclareFromConfig(t *testing.T) {
+	// Arrange
+	broker := newFakeBroker()
+
+	// Act
+	err := NewTopology(newTestRabbitMQConfig()).Declare(broker)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"playlist_events": "topic", "playlist_events.dlx": "topic"}, broker.exchanges)
+	assert.Len(t, broker.queues, 7)
+	assert.Equal(t, amqp.Table{"x-dead-letter-exchange": "playlist_events.dlx"}, broker.queues["track_played"])
+	assert.Equal(t, amqp.Table{
+		"x-message-ttl":             int64(2000),
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": "track_ended",
+	}, broker.queues["track_ended.retry.2000ms"])
+	assert.Equal(t, map[BindingSpec]bool{
+		{Queue: "playlist_events.dead", Exchange: "playlist_events.dlx", RoutingKey: "#"}: true,
+		{Queue: "track_played", Exchange: "playlist_events", RoutingKey: "track.played"}:  true,
+		{Queue: "track_ended", Exchange: "playlist_events", RoutingKey: "track.ended"}:    true,
+	}, broker.bindings)
+}
+
+func TestTopology_DeclareQueuePerSubscribedEvent(t *testing.T) {
+	// Arrange : les événements de lecture ont déjà leurs queues configurées
+	broker := newFakeBroker()
+	cfg := newTestRabbitMQConfig()
+	cfg.SubscribedEvents = []string{"playlist.created", "playlist.played", "track.played", "playlist.created"}
+
+	// Act
+	err := NewTopology(cfg).Declare(broker)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, broker.queues, 13)
+	assert.Equal(t, amqp.Table{"x-dead-letter-exchange": "playlist_events.dlx"}, broker.queues["playlist_created"])
+	assert.Contains(t, broker.queues, "playlist_played.retry.2000ms")
+	assert.True(t, broker.bindings[BindingSpec{Queue: "playlist_created", Exchange: "playlist_events", RoutingKey: "playlist.created"}])
+	assert.True(t, broker.bindings[BindingSpec{Queue: "playlist_played", Exchange: "playlist_events", RoutingKey: "playlist.played"}])
+	assert.Len(t, broker.bindings, 5)
+
+	// Seuls les types consommés sont publiés en mandatory
+	assert.True(t, consumed(cfg, models.EventPlaylistCreated))
+	assert.False(t, consumed(cfg, models.EventPlaylistUpdated))
+	assert.False(t, consumed(newTestRabbitMQConfig(), models.EventPlaylistCreated))
+	assert.True(t, consumed(newTestRabbitMQConfig(), models.EventTrackEnded))
+}
+
+func TestTopology_DeclareIsIdempotent(t *testing.T) {
+	// Arrange
+	broker := newFakeBroker()
+	topology := NewTopology(newTestRabbitMQConfig())
+	require.NoError(t, topology.Declare(broker))
+
+	// Act
+	err := topology.Declare(broker)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, broker.queues, 7)
+	assert.Len(t, broker.bindings, 3)
+}
+
+func TestTopology_DeclareNamesRetryQueuesByDelay(t *testing.T) {
+	// Arrange : les queues de retentative existent avec un autre délai de base
+	broker := newFakeBroker()
+	cfg := newTestRabbitMQConfig()
+	cfg.RetryBaseDelay = 2 * time.Second
+	require.NoError(t, NewTopology(cfg).Declare(broker))
+
+	// Act
+	err := NewTopology(newTestRabbitMQConfig()).Declare(broker.reopen())
+
+	// Assert : les délais 1 s et 2 s ont leurs queues, celle de 4 s reste de l'ancienne configuration
+	require.NoError(t, err)
+	assert.Contains(t, broker.queues, "track_played.retry.1000ms")
+	assert.Contains(t, broker.queues, "track_played.retry.2000ms")
+	assert.Contains(t, broker.queues, "track_played.retry.4000ms")
+}
+
+func TestTopology_DeclareSharesCappedRetryQueue(t *testing.T) {
+	// Arrange : les tentatives au-delà de la deuxième attendent retry_max_delay
+	cfg := newTestRabbitMQConfig()
+	cfg.MaxRetries = 4
+	cfg.RetryMaxDelay = 2 * time.Second
+
+	// Act
+	topology := NewTopology(cfg)
+
+	// Assert
+	var names []string
+	for _, queue := range topology.Queues {
+		names = append(names, queue.Name)
+	}
+	assert.Equal(t, []string{"playlist_events.dead",
+		"track_played", "track_played.retry.1000ms", "track_played.retry.2000ms",
+		"track_ended", "track_ended.retry.1000ms", "track_ended.retry.2000ms"}, names)
+}
+
+func TestTopology_DeclareRejectsChangedArguments(t *testing.T) {
+	// Arrange : la queue a été créée à la main, sans l'exchange des messages écartés
+	broker := newFakeBroker()
+	broker.queues["track_played"] = nil
+
+	// Act
+	err := NewTopology(newTestRabbitMQConfig()).Declare(broker)
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "queue track_played")
+	var amqpErr *amqp.Error
+	require.True(t, errors.As(err, &amqpErr))
+	assert.Equal(t, amqp.PreconditionFailed, amqpErr.Code)
+}
+
+func TestTopology_VerifyOnly(t *testing.T) {
+	// Arrange
+	broker := newFakeBroker()
+	topology := NewTopology(newTestRabbitMQConfig())
+
+	// Act & Assert : la vérification ne crée rien
+	err := topology.Provision(broker, TopologyVerify)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exchange playlist_events is missing")
+	assert.Empty(t, broker.exchanges)
+
+	require.NoError(t, topology.Provision(broker.reopen(), TopologyDeclare))
+	assert.NoError(t, topology.Provision(broker, TopologyVerify))
+
+	delete(broker.queues, "track_ended.retry.1000ms")
+	err = topology.Verify(broker)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "queue track_ended.retry.1000ms is missing")
+
+	assert.Error(t, topology.Provision(broker.reopen(), "create"))
+}
+
+func TestTopology_VerifyRejectsChangedArguments(t *testing.T) {
+	// Arrange : la queue existe sans ses arguments
+	broker := newFakeBroker()
+	topology := NewTopology(newTestRabbitMQConfig())
+	require.NoError(t, topology.Declare(broker))
+	broker.queues["track_ended"] = nil
+	queues := len(broker.queues)
+
+	// Act
+	err := topology.Verify(broker)
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "queue track_ended differs from the expected one")
+	var amqpErr *amqp.Error
+	require.True(t, errors.As(err, &amqpErr))
+	assert.Equal(t, amqp.PreconditionFailed, amqpErr.Code)
+	assert.Nil(t, broker.queues["track_ended"], "la queue n'est pas modifiée")
+	assert.Len(t, broker.queues, queues)
+}
@@ -0,0 +1,73 @@
+package messaging
+
+import (
+	"encoding/json"
+	"fmt"
+	"radioking-app/internal/domain/models"
+)
+
+// upcaster convertit un payload de sa version vers la version suivante
+type upcaster func(payload json.RawMessage) (json.RawMessage, error)
+
+// upcasters des payloads de chaque type, indexés par version d'origine. La version 0 est celle des
+// événements de lecture publiés sans enveloppe.
+var upcasters = map[models.EventType]map[int]upcaster{
+	models.EventTrackPlayed:    {0: trackPlayedV0},
+	models.EventTrackEnded:     {0: unchangedPayload},
+	models.EventPlaylistPlayed: {1: playlistPlayedV1},
+}
+
+// Upcast convertit le payload de l'événement jusqu'à la version courante de son type. Un payload d'une
+// version plus récente que celle connue de l'application est illisible.
+func Upcast(event models.Event) (models.Event, error) {
+	current := event.Type.Version()
+	if event.Version > current {
+		return models.Event{}, fmt.Errorf("unsupported %s event version %d, latest known is %d", event.Type, event.Version, current)
+	}
+
+	for event.Version < current {
+		up, ok := upcasters[event.Type][event.Version]
+		if !ok {
+			return models.Event{}, fmt.Errorf("no upcaster for %s event version %d", event.Type, event.Version)
+		}
+		payload, err := up(event.Payload)
+		if err != nil {
+			return models.Event{}, fmt.Errorf("failed to upcast %s event version %d: %w", event.Type, event.Version, err)
+		}
+		event.Payload = payload
+		event.Version++
+	}
+	return event, nil
+}
+
+func unchangedPayload(payload json.RawMessage) (json.RawMessage, error) {
+	return payload, nil
+}
+
+// trackPlayedV0 complète les premières lectures publiées, sans artist_id ni duration_seconds :
+// inconnus de l'événement, ils valent 0
+func trackPlayedV0(payload json.RawMessage) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, err
+	}
+	for _, name := range []string{"artist_id", "duration_seconds"} {
+		if _, ok := fields[name]; !ok {
+			fields[name] = json.RawMessage("0")
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// playlistPlayedV1 complète les démarrages de session publiés sans l'ordre de la file : inconnu de
+// l'événement, track_ids vaut null
+func playlistPlayedV1(payload json.RawMessage) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, err
+	}
+	if _, ok := fields["track_ids"]; !ok {
+		fields["track_ids"] = json.RawMessage("null")
+	}
+	return json.Marshal(fields)
+}
@@ -0,0 +1,54 @@
+package messaging
+
+import (
+	"context"
+	"sync"
+)
+
+// workerPool traite les messages sur plusieurs workers. Les messages de même clé (la playlist de
+// l'événement) sont confiés au même worker et traités dans leur ordre de réception.
+type workerPool struct {
+	jobs []chan func()
+	wg   sync.WaitGroup
+}
+
+func newWorkerPool(workers int) *workerPool {
+	pool := &workerPool{jobs: make([]chan func(), max(workers, 1))}
+	for i := range pool.jobs {
+		pool.jobs[i] = make(chan func())
+		pool.wg.Add(1)
+		go func(jobs <-chan func()) {
+			defer pool.wg.Done()
+			for job := range jobs {
+				job()
+			}
+		}(pool.jobs[i])
+	}
+	return pool
+}
+
+// submit attend que le worker de key soit libre pour lui confier job
+func (p *workerPool) submit(key int64, job func()) {
+	p.jobs[uint64(key)%uint64(len(p.jobs))] <- job
+}
+
+// trySubmit comme submit, en renonçant à l'annulation de ctx : retourne false si job n'a pas été confié
+func (p *workerPool) trySubmit(ctx context.Context, key int64, job func()) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	select {
+	case p.jobs[uint64(key)%uint64(len(p.jobs))] <- job:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// drain attend la fin des messages confiés aux workers puis arrête ces derniers
+func (p *workerPool) drain() {
+	for _, jobs := range p.jobs {
+		close(jobs)
+	}
+	p.wg.Wait()
+}
@@ -0,0 +1,64 @@
+package messaging
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerPool_KeepsOrderPerKey(t *testing.T) {
+	// Arrange
+	pool := newWorkerPool(4)
+	var mu sync.Mutex
+	processed := map[int64][]int{}
+
+	// Act : trois playlists dont les messages arrivent entrelacés
+	for i := 0; i < 30; i++ {
+		key, rank := int64(i%3+1), i
+		pool.submit(key, func() {
+			time.Sleep(time.Duration(30-rank) * 100 * time.Microsecond)
+			mu.Lock()
+			processed[key] = append(processed[key], rank)
+			mu.Unlock()
+		})
+	}
+	pool.drain()
+
+	// Assert
+	for key, ranks := range processed {
+		assert.Len(t, ranks, 10)
+		assert.IsIncreasing(t, ranks, "playlist %d", key)
+	}
+}
+
+func TestWorkerPool_ProcessesKeysConcurrentlyAndDrains(t *testing.T) {
+	// Arrange
+	pool := newWorkerPool(2)
+	var running, maxRunning, done atomic.Int32
+	release := make(chan struct{})
+
+	// Act : deux clés confiées à des workers différents
+	for _, key := range []int64{0, 1} {
+		pool.submit(key, func() {
+			current := running.Add(1)
+			for {
+				seen := maxRunning.Load()
+				if current <= seen || maxRunning.CompareAndSwap(seen, current) {
+					break
+				}
+			}
+			<-release
+			running.Add(-1)
+			done.Add(1)
+		})
+	}
+	assert.Eventually(t, func() bool { return maxRunning.Load() == 2 }, time.Second, time.Millisecond)
+	close(release)
+	pool.drain()
+
+	// Assert : drain attend la fin des messages en cours
+	assert.Equal(t, int32(2), done.Load())
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"fmt"
+	"radioking-app/internal/domain/models"
+
+	"gorm.io/gorm"
+)
+
+type AlbumRepository struct {
+	DB *gorm.DB
+}
+
+func NewAlbumRepository(db *gorm.DB) *AlbumRepository {
+	return &AlbumRepository{DB: db}
+}
+
+func (r *AlbumRepository) GetByID(id int) (*models.Album, error) {
+	var album models.Album
+	if err := r.DB.Preload("Artist").First(&album, id).Error; err != nil {
+		return nil, err
+	}
+	return &album, nil
+}
+
+func (r *AlbumRepository) ListTracks(albumID int64) ([]*models.Track, error) {
+	var tracks []*models.Track
+	err := r.DB.Where("album_id = ?", albumID).
+		Order("title ASC, id ASC").
+		Find(&tracks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get album tracks: %w", err)
+	}
+	return tracks, nil
+}
+
+// ListPlaylists retourne les playlists contenant au moins une track de l'album
+func (r *AlbumRepository) ListPlaylists(albumID int64) ([]*models.Playlist, error) {
+	return listPlaylistsContaining(r.DB, "tracks.album_id = ?", albumID)
+}
@@ -0,0 +1,9 @@
+package repositories
+
+import "radioking-app/internal/domain/models"
+
+type IAlbumRepository interface {
+	GetByID(id int) (*models.Album, error)
+	ListTracks(albumID int64) ([]*models.Track, error)
+	ListPlaylists(albumID int64) ([]*models.Playlist, error)
+}
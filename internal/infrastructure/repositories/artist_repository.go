@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"fmt"
+	"radioking-app/internal/domain/models"
+
+	"gorm.io/gorm"
+)
+
+type ArtistRepository struct {
+	DB *gorm.DB
+}
+
+func NewArtistRepository(db *gorm.DB) *ArtistRepository {
+	return &ArtistRepository{DB: db}
+}
+
+func (r *ArtistRepository) GetByID(id int) (*models.Artist, error) {
+	var artist models.Artist
+	if err := r.DB.First(&artist, id).Error; err != nil {
+		return nil, err
+	}
+	return &artist, nil
+}
+
+// SearchByName retourne les artistes dont le nom normalisé contient celui recherché
+func (r *ArtistRepository) SearchByName(name string, limit int) ([]*models.Artist, error) {
+	var artists []*models.Artist
+	err := r.DB.Where("normalized_name LIKE ?", "%"+models.NormalizeName(name)+"%").
+		Order("normalized_name ASC, id ASC").
+		Limit(limit).
+		Find(&artists).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to search artists: %w", err)
+	}
+	return artists, nil
+}
+
+func (r *ArtistRepository) ListAlbums(artistID int64) ([]*models.Album, error) {
+	var albums []*models.Album
+	err := r.DB.Where("artist_id = ?", artistID).
+		Order("title ASC, id ASC").
+		Find(&albums).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get artist albums: %w", err)
+	}
+	return albums, nil
+}
+
+func (r *ArtistRepository) ListTracks(artistID int64) ([]*models.Track, error) {
+	var tracks []*models.Track
+	err := r.DB.Preload("Album").
+		Where("artist_id = ?", artistID).
+		Order("title ASC, id ASC").
+		Find(&tracks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get artist tracks: %w", err)
+	}
+	return tracks, nil
+}
+
+// ListPlaylists retourne les playlists contenant au moins une track de l'artiste
+func (r *ArtistRepository) ListPlaylists(artistID int64) ([]*models.Playlist, error) {
+	return listPlaylistsContaining(r.DB, "tracks.artist_id = ?", artistID)
+}
+
+// listPlaylistsContaining retourne les playlists contenant au moins une track vérifiant la condition
+func listPlaylistsContaining(db *gorm.DB, condition string, args ...interface{}) ([]*models.Playlist, error) {
+	var playlists []*models.Playlist
+	entries := db.Model(&models.PlaylistTrack{}).
+		Select("playlist_tracks.playlist_id").
+		Joins("JOIN tracks ON tracks.id = playlist_tracks.track_id").
+		Where(condition, args...)
+
+	err := db.Where("id IN (?)", entries).
+		Order("name ASC, id ASC").
+		Find(&playlists).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playlists: %w", err)
+	}
+	return playlists, nil
+}
@@ -0,0 +1,11 @@
+package repositories
+
+import "radioking-app/internal/domain/models"
+
+type IArtistRepository interface {
+	GetByID(id int) (*models.Artist, error)
+	SearchByName(name string, limit int) ([]*models.Artist, error)
+	ListAlbums(artistID int64) ([]*models.Album, error)
+	ListTracks(artistID int64) ([]*models.Track, error)
+	ListPlaylists(artistID int64) ([]*models.Playlist, error)
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"fmt"
+	"radioking-app/internal/domain/models"
+
+	"gorm.io/gorm"
+)
+
+// resolveArtist retrouve l'artiste de même nom normalisé ou le crée
+func resolveArtist(tx *gorm.DB, name string) (*models.Artist, error) {
+	var artist models.Artist
+	err := tx.Where(models.Artist{NormalizedName: models.NormalizeName(name)}).
+		Attrs(models.Artist{Name: models.CleanName(name)}).
+		FirstOrCreate(&artist).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve artist %q: %w", name, err)
+	}
+	return &artist, nil
+}
+
+// resolveAlbum retrouve l'album de l'artiste de même titre normalisé ou le crée
+func resolveAlbum(tx *gorm.DB, artistID int64, title string) (*models.Album, error) {
+	var album models.Album
+	err := tx.Where(models.Album{ArtistID: artistID, NormalizedTitle: models.NormalizeName(title)}).
+		Attrs(models.Album{Title: models.CleanName(title)}).
+		FirstOrCreate(&album).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve album %q: %w", title, err)
+	}
+	return &album, nil
+}
+
+// prepareCatalogTrack nettoie le titre de la track et la rattache à son artiste
+// et à son album, créés au besoin
+func prepareCatalogTrack(tx *gorm.DB, track *models.Track) error {
+	artist, err := resolveArtist(tx, track.Artist)
+	if err != nil {
+		return err
+	}
+
+	track.Title = models.CleanName(track.Title)
+	track.NormalizedTitle = models.NormalizeName(track.Title)
+	track.Artist = artist.Name
+	track.ArtistID = artist.ID
+
+	if track.Album == nil || models.CleanName(track.Album.Title) == "" {
+		track.Album = nil
+		track.AlbumID = nil
+		return nil
+	}
+
+	album, err := resolveAlbum(tx, artist.ID, track.Album.Title)
+	if err != nil {
+		return err
+	}
+	track.Album = album
+	track.AlbumID = &album.ID
+	return nil
+}
+
+// findCatalogTrack retrouve la track d'un artiste à partir de son titre normalisé
+func findCatalogTrack(tx *gorm.DB, artistID int64, normalizedTitle string) (*models.Track, error) {
+	var track models.Track
+	err := tx.Preload("Album").
+		Where("artist_id = ? AND normalized_title = ?", artistID, normalizedTitle).
+		First(&track).Error
+	if err != nil {
+		return nil, err
+	}
+	return &track, nil
+}
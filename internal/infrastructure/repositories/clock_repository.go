@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"fmt"
+	"radioking-app/internal/domain/models"
+
+	"gorm.io/gorm"
+)
+
+type ClockRepository struct {
+	DB *gorm.DB
+}
+
+func NewClockRepository(db *gorm.DB) *ClockRepository {
+	return &ClockRepository{DB: db}
+}
+
+func (r *ClockRepository) Create(clock *models.Clock) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Slots").Create(clock).Error; err != nil {
+			return fmt.Errorf("failed to create clock in database: %w", err)
+		}
+		return createClockSlots(tx, clock)
+	})
+}
+
+// orderedSlots trie les slots préchargés dans leur ordre de passage
+func orderedSlots(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC, id ASC")
+}
+
+func (r *ClockRepository) List() ([]*models.Clock, error) {
+	var clocks []*models.Clock
+	if err := r.DB.Preload("Slots", orderedSlots).Order("name ASC, id ASC").Find(&clocks).Error; err != nil {
+		return nil, fmt.Errorf("failed to get clocks from database: %w", err)
+	}
+	return clocks, nil
+}
+
+func (r *ClockRepository) GetByID(id int) (*models.Clock, error) {
+	var clock models.Clock
+	if err := r.DB.Preload("Slots", orderedSlots).First(&clock, id).Error; err != nil {
+		return nil, err
+	}
+	return &clock, nil
+}
+
+// Update renomme la clock et remplace ses slots
+func (r *ClockRepository) Update(clock *models.Clock) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(clock).Omit("Slots").Update("name", clock.Name).Error; err != nil {
+			return fmt.Errorf("failed to update clock in database: %w", err)
+		}
+
+		if err := tx.Where("clock_id = ?", clock.ID).Delete(&models.ClockSlot{}).Error; err != nil {
+			return fmt.Errorf("failed to delete clock slots: %w", err)
+		}
+		return createClockSlots(tx, clock)
+	})
+}
+
+// Delete supprime la clock, ses slots et les heures de la grille où elle était programmée
+func (r *ClockRepository) Delete(id int) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("clock_id = ?", id).Delete(&models.GridEntry{}).Error; err != nil {
+			return fmt.Errorf("failed to delete clock grid entries: %w", err)
+		}
+		if err := tx.Where("clock_id = ?", id).Delete(&models.ClockSlot{}).Error; err != nil {
+			return fmt.Errorf("failed to delete clock slots: %w", err)
+		}
+
+		result := tx.Delete(&models.Clock{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete clock from database: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// createClockSlots enregistre les slots de la clock en renumérotant leurs positions
+func createClockSlots(tx *gorm.DB, clock *models.Clock) error {
+	if len(clock.Slots) == 0 {
+		return nil
+	}
+
+	for i := range clock.Slots {
+		clock.Slots[i].ID = 0
+		clock.Slots[i].ClockID = clock.ID
+		clock.Slots[i].Position = i
+	}
+	if err := tx.Create(&clock.Slots).Error; err != nil {
+		return fmt.Errorf("failed to create clock slots: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package repositories
+
+import "radioking-app/internal/domain/models"
+
+type IClockRepository interface {
+	Create(clock *models.Clock) error
+	List() ([]*models.Clock, error)
+	GetByID(id int) (*models.Clock, error)
+	Update(clock *models.Clock) error
+	Delete(id int) error
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"fmt"
+	"radioking-app/internal/domain/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CueSheetRepository struct {
+	DB *gorm.DB
+}
+
+func NewCueSheetRepository(db *gorm.DB) *CueSheetRepository {
+	return &CueSheetRepository{DB: db}
+}
+
+// cueSheetRow lecture jointe aux métadonnées de sa track, de son album et de sa playlist
+type cueSheetRow struct {
+	PlayedAt        time.Time
+	PlayedSeconds   *int
+	TrackID         int64
+	Title           string
+	Artist          string
+	Album           string
+	ISRC            string
+	DurationSeconds int
+	PlaylistID      int64
+	PlaylistName    string
+}
+
+// Entries retourne les lectures démarrées dans [from, to), par ordre chronologique
+func (r *CueSheetRepository) Entries(from time.Time, to time.Time) ([]models.CueSheetEntry, error) {
+	var rows []cueSheetRow
+	err := r.DB.Table("track_plays").
+		Select("track_plays.played_at, track_plays.played_seconds, track_plays.track_id, track_plays.playlist_id, "+
+			"COALESCE(tracks.title, '') AS title, COALESCE(tracks.artist, '') AS artist, COALESCE(albums.title, '') AS album, "+
+			"COALESCE(tracks.isrc, '') AS isrc, COALESCE(tracks.duration_seconds, 0) AS duration_seconds, "+
+			"COALESCE(playlists.name, '') AS playlist_name").
+		Joins("LEFT JOIN tracks ON tracks.id = track_plays.track_id").
+		Joins("LEFT JOIN albums ON albums.id = tracks.album_id").
+		Joins("LEFT JOIN playlists ON playlists.id = track_plays.playlist_id").
+		Where("track_plays.played_at >= ? AND track_plays.played_at < ?", from.UTC(), to.UTC()).
+		Order("track_plays.played_at ASC, track_plays.id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cue sheet entries: %w", err)
+	}
+
+	entries := make([]models.CueSheetEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, models.CueSheetEntry{
+			PlayedAt:        row.PlayedAt,
+			PlayedSeconds:   row.PlayedSeconds,
+			TrackID:         row.TrackID,
+			Title:           row.Title,
+			Artist:          row.Artist,
+			Album:           row.Album,
+			ISRC:            row.ISRC,
+			DurationSeconds: row.DurationSeconds,
+			PlaylistID:      row.PlaylistID,
+			PlaylistName:    row.PlaylistName,
+		})
+	}
+	return entries, nil
+}
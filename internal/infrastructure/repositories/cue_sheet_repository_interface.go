@@ -0,0 +1,10 @@
+package repositories
+
+import (
+	"radioking-app/internal/domain/models"
+	"time"
+)
+
+type ICueSheetRepository interface {
+	Entries(from time.Time, to time.Time) ([]models.CueSheetEntry, error)
+}
@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"fmt"
+	"radioking-app/internal/domain/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventStoreRepository lit le journal des événements du domaine. Le journal n'est alimenté que par
+// l'enregistrement des messages de l'outbox, dans leur transaction.
+type EventStoreRepository struct {
+	DB *gorm.DB
+}
+
+func NewEventStoreRepository(db *gorm.DB) *EventStoreRepository {
+	return &EventStoreRepository{DB: db}
+}
+
+// appendStoredEvents ajoute les événements au journal dans la transaction tx qui les produit
+func appendStoredEvents(tx *gorm.DB, events []models.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	stored := make([]models.StoredEvent, 0, len(events))
+	for _, event := range events {
+		entry := models.NewStoredEvent(event)
+		entry.RecordedAt = now
+		stored = append(stored, entry)
+	}
+	if err := tx.Create(&stored).Error; err != nil {
+		return fmt.Errorf("failed to append events to the event store: %w", err)
+	}
+	return nil
+}
+
+// ListAfter retourne les événements de filter enregistrés après position, dans leur ordre d'enregistrement
+func (r *EventStoreRepository) ListAfter(filter models.EventStoreFilter, position int64, limit int) ([]*models.StoredEvent, error) {
+	query := r.DB.Where("position > ?", position)
+	if len(filter.Types) > 0 {
+		query = query.Where("event_type IN ?", filter.Types)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("occurred_at >= ?", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("occurred_at < ?", filter.To.UTC())
+	}
+
+	var events []*models.StoredEvent
+	if err := query.Order("position ASC").Limit(limit).Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to list stored events: %w", err)
+	}
+	return events, nil
+}
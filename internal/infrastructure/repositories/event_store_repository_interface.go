@@ -0,0 +1,7 @@
+package repositories
+
+import "radioking-app/internal/domain/models"
+
+type IEventStoreRepository interface {
+	ListAfter(filter models.EventStoreFilter, position int64, limit int) ([]*models.StoredEvent, error)
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"fmt"
+	"radioking-app/internal/domain/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GridRepository struct {
+	DB *gorm.DB
+}
+
+func NewGridRepository(db *gorm.DB) *GridRepository {
+	return &GridRepository{DB: db}
+}
+
+// List retourne la grille de la semaine, du dimanche au samedi
+func (r *GridRepository) List() ([]*models.GridEntry, error) {
+	var entries []*models.GridEntry
+	if err := r.DB.Preload("Clock").Order("weekday ASC, hour ASC").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to get grid from database: %w", err)
+	}
+	return entries, nil
+}
+
+// Get retourne la clock programmée à cette heure, avec ses slots
+func (r *GridRepository) Get(weekday int, hour int) (*models.GridEntry, error) {
+	var entry models.GridEntry
+	err := r.DB.Preload("Clock").Preload("Clock.Slots", orderedSlots).
+		Where("weekday = ? AND hour = ?", weekday, hour).
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Assign programme la clock à cette heure, en remplaçant la clock déjà programmée
+func (r *GridRepository) Assign(entry *models.GridEntry) error {
+	err := r.DB.Omit("Clock").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "weekday"}, {Name: "hour"}},
+		DoUpdates: clause.AssignmentColumns([]string{"clock_id", "updated_at"}),
+	}).Create(entry).Error
+	if err != nil {
+		return fmt.Errorf("failed to assign clock in grid: %w", err)
+	}
+	return nil
+}
+
+func (r *GridRepository) Delete(weekday int, hour int) error {
+	result := r.DB.Where("weekday = ? AND hour = ?", weekday, hour).Delete(&models.GridEntry{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete grid entry: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package repositories
+
+import "radioking-app/internal/domain/models"
+
+type IGridRepository interface {
+	List() ([]*models.GridEntry, error)
+	Get(weekday int, hour int) (*models.GridEntry, error)
+	Assign(entry *models.GridEntry) error
+	Delete(weekday int, hour int) error
+}
@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"fmt"
+	"radioking-app/internal/domain/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// maxOutboxErrorLength taille de la colonne last_error
+const maxOutboxErrorLength = 500
+
+type OutboxRepository struct {
+	DB *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{DB: db}
+}
+
+// createOutboxMessages enregistre les messages dans la transaction tx du changement d'état qui les produit,
+// et ajoute leurs événements au journal. Les dates sont en UTC : SQLite les compare sous forme de texte.
+func createOutboxMessages(tx *gorm.DB, messages []models.OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	events := make([]models.Event, 0, len(messages))
+	for _, message := range messages {
+		event, err := message.Event()
+		if err != nil {
+			return fmt.Errorf("failed to read outbox message %s: %w", message.EventID, err)
+		}
+		events = append(events, event)
+	}
+
+	now := time.Now().UTC()
+	for i := range messages {
+		messages[i].Status = models.OutboxPending
+		messages[i].CreatedAt = now
+		if messages[i].NextAttemptAt.IsZero() {
+			messages[i].NextAttemptAt = now
+		}
+	}
+	if err := tx.Create(&messages).Error; err != nil {
+		return fmt.Errorf("failed to create outbox messages: %w", err)
+	}
+	return appendStoredEvents(tx, events)
+}
+
+// ListPending retourne les messages à publier dans leur ordre d'enregistrement, y compris ceux
+// dont la prochaine tentative n'est pas encore arrivée : ils bloquent les messages suivants
+func (r *OutboxRepository) ListPending(limit int) ([]*models.OutboxMessage, error) {
+	var messages []*models.OutboxMessage
+	if err := r.DB.Where("status = ?", models.OutboxPending).
+		Order("id ASC").Limit(limit).Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to get pending outbox messages: %w", err)
+	}
+	return messages, nil
+}
+
+func (r *OutboxRepository) MarkSent(id int64, sentAt time.Time) error {
+	err := r.DB.Model(&models.OutboxMessage{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     models.OutboxSent,
+		"sent_at":    sentAt.UTC(),
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": "",
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox message %d as sent: %w", id, err)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) MarkFailed(id int64, lastError string, nextAttemptAt time.Time) error {
+	if len(lastError) > maxOutboxErrorLength {
+		lastError = lastError[:maxOutboxErrorLength]
+	}
+
+	err := r.DB.Model(&models.OutboxMessage{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"attempts":        gorm.Expr("attempts + 1"),
+		"last_error":      lastError,
+		"next_attempt_at": nextAttemptAt.UTC(),
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox message %d as failed: %w", id, err)
+	}
+	return nil
+}
+
+// MarkUndeliverable passe le message failed : il n'est plus retenté et ne bloque plus les suivants
+func (r *OutboxRepository) MarkUndeliverable(id int64, lastError string) error {
+	if len(lastError) > maxOutboxErrorLength {
+		lastError = lastError[:maxOutboxErrorLength]
+	}
+
+	err := r.DB.Model(&models.OutboxMessage{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     models.OutboxFailed,
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": lastError,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox message %d as undeliverable: %w", id, err)
+	}
+	return nil
+}
+
+// List retourne les messages les plus récents, de tous les statuts si status est vide
+func (r *OutboxRepository) List(status models.OutboxStatus, limit int) ([]*models.OutboxMessage, error) {
+	db := r.DB.Order("id DESC").Limit(limit)
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+
+	var messages []*models.OutboxMessage
+	if err := db.Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to get outbox messages: %w", err)
+	}
+	return messages, nil
+}
+
+func (r *OutboxRepository) GetByEventID(eventID string) (*models.OutboxMessage, error) {
+	var message models.OutboxMessage
+	if err := r.DB.Where("event_id = ?", eventID).First(&message).Error; err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+func (r *OutboxRepository) Stats() (models.OutboxStats, error) {
+	var row struct {
+		Pending int64
+		Sent    int64
+		Failing int64
+		Failed  int64
+	}
+	err := r.DB.Model(&models.OutboxMessage{}).
+		Select("COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0) AS pending, "+
+			"COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0) AS sent, "+
+			"COALESCE(SUM(CASE WHEN status = ? AND attempts > 0 THEN 1 ELSE 0 END), 0) AS failing, "+
+			"COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0) AS failed",
+			models.OutboxPending, models.OutboxSent, models.OutboxPending, models.OutboxFailed).
+		Scan(&row).Error
+	if err != nil {
+		return models.OutboxStats{}, fmt.Errorf("failed to get outbox stats: %w", err)
+	}
+
+	stats := models.OutboxStats{Pending: row.Pending, Sent: row.Sent, Failing: row.Failing, Failed: row.Failed}
+	if stats.Pending > 0 {
+		var oldest models.OutboxMessage
+		if err := r.DB.Where("status = ?", models.OutboxPending).Order("id ASC").First(&oldest).Error; err != nil {
+			return models.OutboxStats{}, fmt.Errorf("failed to get oldest pending outbox message: %w", err)
+		}
+		stats.OldestPendingAt = &oldest.CreatedAt
+	}
+	return stats, nil
+}
+
+// DeleteSentBefore supprime les messages publiés avant before, les messages pending et failed sont conservés
+func (r *OutboxRepository) DeleteSentBefore(before time.Time) (int64, error) {
+	result := r.DB.Where("status = ? AND sent_at < ?", models.OutboxSent, before.UTC()).Delete(&models.OutboxMessage{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete sent outbox messages: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
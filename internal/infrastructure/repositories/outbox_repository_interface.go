@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"radioking-app/internal/domain/models"
+	"time"
+)
+
+type IOutboxRepository interface {
+	ListPending(limit int) ([]*models.OutboxMessage, error)
+	MarkSent(id int64, sentAt time.Time) error
+	MarkFailed(id int64, lastError string, nextAttemptAt time.Time) error
+	MarkUndeliverable(id int64, lastError string) error
+	List(status models.OutboxStatus, limit int) ([]*models.OutboxMessage, error)
+	GetByEventID(eventID string) (*models.OutboxMessage, error)
+	Stats() (models.OutboxStats, error)
+	DeleteSentBefore(before time.Time) (int64, error)
+}
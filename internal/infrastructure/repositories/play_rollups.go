@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"fmt"
+	"radioking-app/internal/domain/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const rollupBatchSize = 500
+
+// rollupDelta variation des compteurs apportée par une lecture
+type rollupDelta struct {
+	plays         int
+	completePlays int
+	playedSeconds int
+}
+
+// deltaOf compteurs d'une lecture, sa fin n'est comptée qu'une fois reçue
+func deltaOf(trackPlay *models.TrackPlay) rollupDelta {
+	delta := rollupDelta{plays: 1}
+	if trackPlay.IsComplete() {
+		delta.completePlays = 1
+	}
+	if trackPlay.PlayedSeconds != nil {
+		delta.playedSeconds = *trackPlay.PlayedSeconds
+	}
+	return delta
+}
+
+func (d rollupDelta) minus(other rollupDelta) rollupDelta {
+	return rollupDelta{
+		plays:         d.plays - other.plays,
+		completePlays: d.completePlays - other.completePlays,
+		playedSeconds: d.playedSeconds - other.playedSeconds,
+	}
+}
+
+func (d rollupDelta) isZero() bool {
+	return d == rollupDelta{}
+}
+
+type rollupKey struct {
+	subject     models.RollupSubject
+	granularity models.RollupGranularity
+	bucketStart time.Time
+	subjectID   int64
+}
+
+// rollupSet cumule les variations de compteurs avant de les écrire en une fois
+type rollupSet map[rollupKey]*models.PlayRollup
+
+// add reporte delta sur les compteurs horaires et journaliers de la track, de son artiste et de
+// sa playlist. Une track sans artiste du catalogue (artistID 0) n'est pas comptée par artiste.
+func (s rollupSet) add(trackPlay *models.TrackPlay, artistID int64, delta rollupDelta) {
+	playedAt := trackPlay.PlayedAt.UTC()
+	buckets := map[models.RollupGranularity]time.Time{
+		models.RollupHour: playedAt.Truncate(time.Hour),
+		models.RollupDay:  time.Date(playedAt.Year(), playedAt.Month(), playedAt.Day(), 0, 0, 0, 0, time.UTC),
+	}
+	subjects := map[models.RollupSubject]int64{
+		models.RollupTrack:    trackPlay.TrackID,
+		models.RollupPlaylist: trackPlay.PlaylistID,
+	}
+	if artistID != 0 {
+		subjects[models.RollupArtist] = artistID
+	}
+
+	for granularity, bucketStart := range buckets {
+		for subject, subjectID := range subjects {
+			key := rollupKey{subject: subject, granularity: granularity, bucketStart: bucketStart, subjectID: subjectID}
+			rollup, ok := s[key]
+			if !ok {
+				rollup = &models.PlayRollup{Subject: subject, Granularity: granularity, BucketStart: bucketStart, SubjectID: subjectID}
+				s[key] = rollup
+			}
+			rollup.Plays += delta.plays
+			rollup.CompletePlays += delta.completePlays
+			rollup.PlayedSeconds += delta.playedSeconds
+		}
+	}
+}
+
+func (s rollupSet) rollups() []models.PlayRollup {
+	rollups := make([]models.PlayRollup, 0, len(s))
+	for _, rollup := range s {
+		rollups = append(rollups, *rollup)
+	}
+	return rollups
+}
+
+// save ajoute les variations aux compteurs existants, ou crée les compteurs manquants
+func (s rollupSet) save(tx *gorm.DB) error {
+	if len(s) == 0 {
+		return nil
+	}
+
+	err := tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "subject"}, {Name: "granularity"}, {Name: "bucket_start"}, {Name: "subject_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"plays":          gorm.Expr("play_rollups.plays + excluded.plays"),
+			"complete_plays": gorm.Expr("play_rollups.complete_plays + excluded.complete_plays"),
+			"played_seconds": gorm.Expr("play_rollups.played_seconds + excluded.played_seconds"),
+		}),
+	}).CreateInBatches(s.rollups(), rollupBatchSize).Error
+	if err != nil {
+		return fmt.Errorf("failed to update play rollups: %w", err)
+	}
+	return nil
+}
+
+// applyRollupDelta reporte la variation d'une lecture sur ses compteurs, dans la transaction tx.
+// L'artiste d'une track supprimée reste compté, comme lors de la reconstruction des compteurs.
+func applyRollupDelta(tx *gorm.DB, trackPlay *models.TrackPlay, delta rollupDelta) error {
+	if delta.isZero() {
+		return nil
+	}
+
+	var artistIDs []int64
+	if err := tx.Unscoped().Model(&models.Track{}).Where("id = ?", trackPlay.TrackID).Pluck("artist_id", &artistIDs).Error; err != nil {
+		return fmt.Errorf("failed to get track artist: %w", err)
+	}
+	var artistID int64
+	if len(artistIDs) > 0 {
+		artistID = artistIDs[0]
+	}
+
+	set := rollupSet{}
+	set.add(trackPlay, artistID, delta)
+	return set.save(tx)
+}
+
+// playWithArtist lecture accompagnée de l'artiste de sa track
+type playWithArtist struct {
+	models.TrackPlay
+	ArtistID int64
+}
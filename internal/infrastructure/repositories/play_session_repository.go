@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"fmt"
+	"radioking-app/internal/domain/models"
+
+	"gorm.io/gorm"
+)
+
+type PlaySessionRepository struct {
+	DB *gorm.DB
+}
+
+func NewPlaySessionRepository(db *gorm.DB) *PlaySessionRepository {
+	return &PlaySessionRepository{DB: db}
+}
+
+func (r *PlaySessionRepository) Create(session *models.PlaySession) error {
+	sessionToUTC(session)
+	if err := r.DB.Create(session).Error; err != nil {
+		return fmt.Errorf("failed to create play session in database: %w", err)
+	}
+	return nil
+}
+
+func (r *PlaySessionRepository) GetByID(id int64) (*models.PlaySession, error) {
+	var session models.PlaySession
+	if err := r.DB.First(&session, id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Update enregistre l'état de la session et les événements qu'il produit dans la même transaction
+func (r *PlaySessionRepository) Update(session *models.PlaySession, events ...models.OutboxMessage) error {
+	sessionToUTC(session)
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(session).Error; err != nil {
+			return fmt.Errorf("failed to update play session %d: %w", session.ID, err)
+		}
+		return createOutboxMessages(tx, events)
+	})
+}
+
+func (r *PlaySessionRepository) ListByStatus(statuses ...models.PlaySessionStatus) ([]*models.PlaySession, error) {
+	var sessions []*models.PlaySession
+	if err := r.DB.Where("status IN ?", statuses).Order("id ASC").Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get play sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// sessionToUTC enregistre les dates de la track courante en UTC, comme celles des lectures
+func sessionToUTC(session *models.PlaySession) {
+	if session.TrackStartedAt != nil {
+		startedAt := session.TrackStartedAt.UTC()
+		session.TrackStartedAt = &startedAt
+	}
+	if session.TrackResumedAt != nil {
+		resumedAt := session.TrackResumedAt.UTC()
+		session.TrackResumedAt = &resumedAt
+	}
+}
@@ -0,0 +1,10 @@
+package repositories
+
+import "radioking-app/internal/domain/models"
+
+type IPlaySessionRepository interface {
+	Create(session *models.PlaySession) error
+	GetByID(id int64) (*models.PlaySession, error)
+	Update(session *models.PlaySession, events ...models.OutboxMessage) error
+	ListByStatus(statuses ...models.PlaySessionStatus) ([]*models.PlaySession, error)
+}
@@ -1,12 +1,21 @@
 package repositories
 
 import (
+	"errors"
 	"fmt"
 	"radioking-app/internal/domain/models"
+	"strings"
 
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrTrackAlreadyInPlaylist une même track du catalogue ne peut apparaître qu'une fois par playlist
+	ErrTrackAlreadyInPlaylist = errors.New("track already in playlist")
+	// ErrTooManyTracks la playlist contient déjà le nombre maximal de tracks
+	ErrTooManyTracks = errors.New("too many tracks in playlist")
+)
+
 type PlaylistRepository struct {
 	DB *gorm.DB
 }
@@ -15,16 +24,66 @@ func NewPlaylistRepository(db *gorm.DB) *PlaylistRepository {
 	return &PlaylistRepository{DB: db}
 }
 
-func (r *PlaylistRepository) Create(playlist *models.Playlist) error {
-	if err := r.DB.Create(playlist).Error; err != nil {
-		return fmt.Errorf("failed to create playlist in database: %w", err)
-	}
-	return nil
+func (r *PlaylistRepository) Create(playlist *models.Playlist, events PlaylistEvents) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Tracks").Create(playlist).Error; err != nil {
+			return fmt.Errorf("failed to create playlist in database: %w", err)
+		}
+		if err := createPlaylistTracks(tx, playlist); err != nil {
+			return err
+		}
+		return createPlaylistEvents(tx, playlist.ID, events)
+	})
+}
+
+// orderedTracks trie les tracks préchargées selon leur position dans la playlist
+func orderedTracks(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC, id ASC")
+}
+
+// likeEscaper échappe les jokers de LIKE, avec \ comme caractère d'échappement
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern motif LIKE des valeurs contenant value telle quelle
+func containsPattern(value string) string {
+	return "%" + likeEscaper.Replace(value) + "%"
 }
 
-func (r *PlaylistRepository) GetAll() ([]*models.Playlist, error) {
+// List retourne au plus query.Limit playlists situées après le curseur dans l'ordre demandé
+func (r *PlaylistRepository) List(query models.PlaylistListQuery, after *models.PlaylistCursor) ([]*models.Playlist, error) {
+	column := "playlists.created_at"
+	if query.SortBy == models.PlaylistSortUpdatedAt {
+		column = "playlists.updated_at"
+	}
+	direction, comparator := "ASC", ">"
+	if query.Order == models.SortOrderDesc {
+		direction, comparator = "DESC", "<"
+	}
+
+	db := r.DB.Model(&models.Playlist{}).
+		Select("playlists.*, (SELECT COUNT(*) FROM playlist_tracks WHERE playlist_tracks.playlist_id = playlists.id) AS tracks_count")
+
+	if name := strings.TrimSpace(query.Name); name != "" {
+		db = db.Where(`LOWER(playlists.name) LIKE ? ESCAPE '\'`, containsPattern(strings.ToLower(name)))
+	}
+
+	if after != nil {
+		sortValue := after.SortValue.UTC()
+		db = db.Where(
+			fmt.Sprintf("(%s %s ?) OR (%s = ? AND playlists.id %s ?)", column, comparator, column, comparator),
+			sortValue, sortValue, after.ID,
+		)
+	}
+
+	if query.WithTracks {
+		db = db.Preload("Tracks", orderedTracks).Preload("Tracks.Track")
+	}
+
 	var playlists []*models.Playlist
-	if err := r.DB.Preload("Tracks").Find(&playlists).Error; err != nil {
+	err := db.Order(fmt.Sprintf("%s %s, playlists.id %s", column, direction, direction)).
+		Limit(query.Limit).
+		Find(&playlists).Error
+	if err != nil {
 		return nil, fmt.Errorf("failed to get playlists from database: %w", err)
 	}
 	return playlists, nil
@@ -32,7 +91,7 @@ func (r *PlaylistRepository) GetAll() ([]*models.Playlist, error) {
 
 func (r *PlaylistRepository) GetByID(id int) (*models.Playlist, error) {
 	var playlist models.Playlist
-	err := r.DB.Preload("Tracks").First(&playlist, id).Error
+	err := r.DB.Preload("Tracks", orderedTracks).Preload("Tracks.Track").First(&playlist, id).Error
 
 	if err != nil {
 		return nil, err
@@ -40,3 +99,225 @@ func (r *PlaylistRepository) GetByID(id int) (*models.Playlist, error) {
 
 	return &playlist, nil
 }
+
+// Update met à jour le nom de la playlist et remplace sa liste de tracks.
+// Les tracks du catalogue et leur historique de lecture sont conservés.
+func (r *PlaylistRepository) Update(playlist *models.Playlist, events PlaylistEvents) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(playlist).Omit("Tracks").Update("name", playlist.Name).Error; err != nil {
+			return fmt.Errorf("failed to update playlist in database: %w", err)
+		}
+
+		if err := tx.Where("playlist_id = ?", playlist.ID).Delete(&models.PlaylistTrack{}).Error; err != nil {
+			return fmt.Errorf("failed to delete playlist tracks: %w", err)
+		}
+		if err := createPlaylistTracks(tx, playlist); err != nil {
+			return err
+		}
+		return createPlaylistEvents(tx, playlist.ID, events)
+	})
+}
+
+// Rename met à jour le nom de la playlist seul, ses tracks ne sont pas modifiées
+func (r *PlaylistRepository) Rename(playlist *models.Playlist, events PlaylistEvents) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(playlist).Omit("Tracks").Update("name", playlist.Name).Error; err != nil {
+			return fmt.Errorf("failed to rename playlist in database: %w", err)
+		}
+		return createPlaylistEvents(tx, playlist.ID, events)
+	})
+}
+
+// Delete supprime la playlist et ses entrées. La playlist n'est supprimée que logiquement pour conserver
+// l'historique de lecture joué depuis cette playlist. Les événements construits à partir de la playlist
+// supprimée sont enregistrés dans la même transaction.
+func (r *PlaylistRepository) Delete(id int, events PlaylistEvents) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var playlist models.Playlist
+		if err := tx.Preload("Tracks", orderedTracks).First(&playlist, id).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("playlist_id = ?", id).Delete(&models.PlaylistTrack{}).Error; err != nil {
+			return fmt.Errorf("failed to delete playlist tracks: %w", err)
+		}
+		if err := tx.Delete(&models.Playlist{}, id).Error; err != nil {
+			return fmt.Errorf("failed to delete playlist from database: %w", err)
+		}
+		if events == nil {
+			return nil
+		}
+
+		messages, err := events(&playlist)
+		if err != nil {
+			return fmt.Errorf("failed to build events of playlist %d: %w", id, err)
+		}
+		return createOutboxMessages(tx, messages)
+	})
+}
+
+// AddTrack insère la track à sa position en décalant les tracks suivantes. Les tracks de la playlist
+// sont comptées dans la transaction de l'insertion : au-delà de maxTracks l'ajout est refusé.
+func (r *PlaylistRepository) AddTrack(entry *models.PlaylistTrack, maxTracks int, events PlaylistEvents) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var tracks int64
+		if err := tx.Model(&models.PlaylistTrack{}).
+			Where("playlist_id = ?", entry.PlaylistID).
+			Count(&tracks).Error; err != nil {
+			return fmt.Errorf("failed to count playlist tracks: %w", err)
+		}
+		if tracks >= int64(maxTracks) {
+			return ErrTooManyTracks
+		}
+
+		if err := resolveCatalogTrack(tx, entry); err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.PlaylistTrack{}).
+			Where("playlist_id = ? AND track_id = ?", entry.PlaylistID, entry.TrackID).
+			Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check playlist tracks: %w", err)
+		}
+		if count > 0 {
+			return ErrTrackAlreadyInPlaylist
+		}
+
+		if err := tx.Model(&models.PlaylistTrack{}).
+			Where("playlist_id = ? AND position >= ?", entry.PlaylistID, entry.Position).
+			Update("position", gorm.Expr("position + 1")).Error; err != nil {
+			return fmt.Errorf("failed to shift track positions: %w", err)
+		}
+		if err := tx.Omit("Track").Create(entry).Error; err != nil {
+			return fmt.Errorf("failed to add track to playlist: %w", err)
+		}
+		return createPlaylistEvents(tx, entry.PlaylistID, events)
+	})
+}
+
+// RemoveTrack retire la track de la playlist puis referme le trou dans les positions
+func (r *PlaylistRepository) RemoveTrack(entry *models.PlaylistTrack, events PlaylistEvents) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := removePlaylistTrack(tx, entry); err != nil {
+			return err
+		}
+		return createPlaylistEvents(tx, entry.PlaylistID, events)
+	})
+}
+
+// MoveTrack déplace la track à la position donnée en décalant les tracks intermédiaires.
+// Une track déjà à cette position ne modifie rien et n'enregistre aucun événement.
+func (r *PlaylistRepository) MoveTrack(entry *models.PlaylistTrack, position int, events PlaylistEvents) error {
+	if entry.Position == position {
+		return nil
+	}
+
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		shift := tx.Model(&models.PlaylistTrack{}).Where("playlist_id = ?", entry.PlaylistID)
+		var err error
+		if position < entry.Position {
+			err = shift.Where("position >= ? AND position < ?", position, entry.Position).
+				Update("position", gorm.Expr("position + 1")).Error
+		} else {
+			err = shift.Where("position > ? AND position <= ?", entry.Position, position).
+				Update("position", gorm.Expr("position - 1")).Error
+		}
+		if err != nil {
+			return fmt.Errorf("failed to shift track positions: %w", err)
+		}
+
+		if err := tx.Model(entry).Update("position", position).Error; err != nil {
+			return fmt.Errorf("failed to move track: %w", err)
+		}
+		return createPlaylistEvents(tx, entry.PlaylistID, events)
+	})
+}
+
+// createPlaylistEvents enregistre dans tx les événements construits à partir de l'état de la playlist id
+func createPlaylistEvents(tx *gorm.DB, id int64, events PlaylistEvents) error {
+	if events == nil {
+		return nil
+	}
+
+	var playlist models.Playlist
+	if err := tx.Preload("Tracks", orderedTracks).First(&playlist, id).Error; err != nil {
+		return fmt.Errorf("failed to read playlist %d: %w", id, err)
+	}
+	messages, err := events(&playlist)
+	if err != nil {
+		return fmt.Errorf("failed to build events of playlist %d: %w", id, err)
+	}
+	return createOutboxMessages(tx, messages)
+}
+
+// createPlaylistTracks crée les entrées de la playlist en résolvant chaque track dans le catalogue
+func createPlaylistTracks(tx *gorm.DB, playlist *models.Playlist) error {
+	seen := make(map[int64]bool, len(playlist.Tracks))
+	for i := range playlist.Tracks {
+		entry := &playlist.Tracks[i]
+		if err := resolveCatalogTrack(tx, entry); err != nil {
+			return err
+		}
+		if seen[entry.TrackID] {
+			return ErrTrackAlreadyInPlaylist
+		}
+		seen[entry.TrackID] = true
+
+		entry.ID = 0
+		entry.PlaylistID = playlist.ID
+		if err := tx.Omit("Track").Create(entry).Error; err != nil {
+			return fmt.Errorf("failed to add track to playlist: %w", err)
+		}
+	}
+	return nil
+}
+
+// resolveCatalogTrack charge la track référencée par TrackID ou retrouve/crée la track
+// du catalogue correspondant au titre et à l'artiste renseignés, aux noms normalisés près
+func resolveCatalogTrack(tx *gorm.DB, entry *models.PlaylistTrack) error {
+	if entry.TrackID != 0 {
+		if err := tx.First(&entry.Track, entry.TrackID).Error; err != nil {
+			return fmt.Errorf("failed to load track %d: %w", entry.TrackID, err)
+		}
+		return nil
+	}
+
+	track := models.Track{
+		Title:           entry.Track.Title,
+		Artist:          entry.Track.Artist,
+		Album:           entry.Track.Album,
+		DurationSeconds: entry.Track.DurationSeconds,
+	}
+	if err := prepareCatalogTrack(tx, &track); err != nil {
+		return err
+	}
+
+	existing, err := findCatalogTrack(tx, track.ArtistID, track.NormalizedTitle)
+	switch {
+	case err == nil:
+		track = *existing
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if err := tx.Omit("Album").Create(&track).Error; err != nil {
+			return fmt.Errorf("failed to create catalog track: %w", err)
+		}
+	default:
+		return fmt.Errorf("failed to resolve catalog track: %w", err)
+	}
+
+	entry.Track = track
+	entry.TrackID = track.ID
+	return nil
+}
+
+func removePlaylistTrack(tx *gorm.DB, entry *models.PlaylistTrack) error {
+	if err := tx.Delete(&models.PlaylistTrack{}, entry.ID).Error; err != nil {
+		return fmt.Errorf("failed to remove track from playlist: %w", err)
+	}
+	if err := tx.Model(&models.PlaylistTrack{}).
+		Where("playlist_id = ? AND position > ?", entry.PlaylistID, entry.Position).
+		Update("position", gorm.Expr("position - 1")).Error; err != nil {
+		return fmt.Errorf("failed to shift track positions: %w", err)
+	}
+	return nil
+}
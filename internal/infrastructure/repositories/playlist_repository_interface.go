@@ -2,8 +2,18 @@ package repositories
 
 import "radioking-app/internal/domain/models"
 
+// PlaylistEvents construit les événements d'une modification à partir de l'état de la playlist relu dans
+// sa transaction ; ils sont enregistrés dans l'outbox par cette même transaction. nil n'enregistre rien.
+type PlaylistEvents func(playlist *models.Playlist) ([]models.OutboxMessage, error)
+
 type IPlaylistRepository interface {
-	Create(playlist *models.Playlist) error
-	GetAll() ([]*models.Playlist, error)
+	Create(playlist *models.Playlist, events PlaylistEvents) error
+	List(query models.PlaylistListQuery, after *models.PlaylistCursor) ([]*models.Playlist, error)
 	GetByID(id int) (*models.Playlist, error)
+	Update(playlist *models.Playlist, events PlaylistEvents) error
+	Rename(playlist *models.Playlist, events PlaylistEvents) error
+	Delete(id int, events PlaylistEvents) error
+	AddTrack(entry *models.PlaylistTrack, maxTracks int, events PlaylistEvents) error
+	RemoveTrack(entry *models.PlaylistTrack, events PlaylistEvents) error
+	MoveTrack(entry *models.PlaylistTrack, position int, events PlaylistEvents) error
 }
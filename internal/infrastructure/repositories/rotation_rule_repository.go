@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"fmt"
+	"radioking-app/internal/domain/models"
+
+	"gorm.io/gorm"
+)
+
+type RotationRuleRepository struct {
+	DB *gorm.DB
+}
+
+func NewRotationRuleRepository(db *gorm.DB) *RotationRuleRepository {
+	return &RotationRuleRepository{DB: db}
+}
+
+func (r *RotationRuleRepository) Create(rule *models.RotationRule) error {
+	if err := r.DB.Create(rule).Error; err != nil {
+		return fmt.Errorf("failed to create rotation rule in database: %w", err)
+	}
+	return nil
+}
+
+func (r *RotationRuleRepository) List(enabledOnly bool) ([]*models.RotationRule, error) {
+	db := r.DB.Order("id ASC")
+	if enabledOnly {
+		db = db.Where("enabled = ?", true)
+	}
+
+	var rules []*models.RotationRule
+	if err := db.Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to get rotation rules from database: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *RotationRuleRepository) GetByID(id int) (*models.RotationRule, error) {
+	var rule models.RotationRule
+	if err := r.DB.First(&rule, id).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *RotationRuleRepository) Update(rule *models.RotationRule) error {
+	err := r.DB.Model(rule).Select("name", "subject", "window_minutes", "max_plays", "enabled").Updates(rule).Error
+	if err != nil {
+		return fmt.Errorf("failed to update rotation rule %d: %w", rule.ID, err)
+	}
+	return nil
+}
+
+func (r *RotationRuleRepository) Delete(id int) error {
+	result := r.DB.Delete(&models.RotationRule{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete rotation rule from database: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package repositories
+
+import "radioking-app/internal/domain/models"
+
+type IRotationRuleRepository interface {
+	Create(rule *models.RotationRule) error
+	List(enabledOnly bool) ([]*models.RotationRule, error)
+	GetByID(id int) (*models.RotationRule, error)
+	Update(rule *models.RotationRule) error
+	Delete(id int) error
+}
@@ -0,0 +1,205 @@
+package repositories
+
+import (
+	"fmt"
+	"radioking-app/internal/domain/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	completePlaysColumn = "SUM(CASE WHEN track_plays.end_reason = 'completed' THEN 1 ELSE 0 END) AS complete_plays"
+	rebuildBatchSize    = 1000
+	day                 = 24 * time.Hour
+)
+
+// StatsRepository agrège les lectures d'une période [from, to). Une période alignée sur les heures
+// est lue dans les compteurs pré-agrégés, les autres parcourent l'historique track_plays.
+type StatsRepository struct {
+	DB *gorm.DB
+}
+
+func NewStatsRepository(db *gorm.DB) *StatsRepository {
+	return &StatsRepository{DB: db}
+}
+
+func (r *StatsRepository) TopTracks(from time.Time, to time.Time, limit int) ([]models.TrackPlayCount, error) {
+	query := r.plays(from, to).
+		Select("track_plays.track_id, MAX(tracks.title) AS title, MAX(tracks.artist) AS artist, COUNT(*) AS plays, " + completePlaysColumn).
+		Joins("LEFT JOIN tracks ON tracks.id = track_plays.track_id").
+		Group("track_plays.track_id")
+	if alignedOnHours(from, to) {
+		query = r.rollups(models.RollupTrack, from, to).
+			Select("play_rollups.subject_id AS track_id, MAX(tracks.title) AS title, MAX(tracks.artist) AS artist, " +
+				"SUM(play_rollups.plays) AS plays, SUM(play_rollups.complete_plays) AS complete_plays").
+			Joins("LEFT JOIN tracks ON tracks.id = play_rollups.subject_id").
+			Group("play_rollups.subject_id")
+	}
+
+	var counts []models.TrackPlayCount
+	if err := query.Order("plays DESC, track_id ASC").Limit(limit).Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count track plays: %w", err)
+	}
+	return counts, nil
+}
+
+// TopArtists regroupe les lectures par artiste du catalogue. Comme dans les compteurs, les tracks
+// qui ne sont rattachées à aucun artiste sont ignorées.
+func (r *StatsRepository) TopArtists(from time.Time, to time.Time, limit int) ([]models.ArtistPlayCount, error) {
+	query := r.plays(from, to).
+		Select("tracks.artist_id, COALESCE(MAX(artists.name), '') AS name, COUNT(*) AS plays").
+		Joins("JOIN tracks ON tracks.id = track_plays.track_id").
+		Joins("LEFT JOIN artists ON artists.id = tracks.artist_id").
+		Where("tracks.artist_id <> 0").
+		Group("tracks.artist_id")
+	if alignedOnHours(from, to) {
+		query = r.rollups(models.RollupArtist, from, to).
+			Select("play_rollups.subject_id AS artist_id, COALESCE(MAX(artists.name), '') AS name, SUM(play_rollups.plays) AS plays").
+			Joins("LEFT JOIN artists ON artists.id = play_rollups.subject_id").
+			Group("play_rollups.subject_id")
+	}
+
+	var counts []models.ArtistPlayCount
+	if err := query.Order("plays DESC, artist_id ASC").Limit(limit).Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count artist plays: %w", err)
+	}
+	return counts, nil
+}
+
+func (r *StatsRepository) PlaylistTotals(from time.Time, to time.Time) ([]models.PlaylistPlayCount, error) {
+	query := r.plays(from, to).
+		Select("track_plays.playlist_id, COALESCE(MAX(playlists.name), '') AS name, COUNT(*) AS plays, " +
+			completePlaysColumn + ", COALESCE(SUM(track_plays.played_seconds), 0) AS played_seconds").
+		Joins("LEFT JOIN playlists ON playlists.id = track_plays.playlist_id").
+		Group("track_plays.playlist_id")
+	if alignedOnHours(from, to) {
+		query = r.rollups(models.RollupPlaylist, from, to).
+			Select("play_rollups.subject_id AS playlist_id, COALESCE(MAX(playlists.name), '') AS name, SUM(play_rollups.plays) AS plays, " +
+				"SUM(play_rollups.complete_plays) AS complete_plays, SUM(play_rollups.played_seconds) AS played_seconds").
+			Joins("LEFT JOIN playlists ON playlists.id = play_rollups.subject_id").
+			Group("play_rollups.subject_id")
+	}
+
+	var counts []models.PlaylistPlayCount
+	if err := query.Order("plays DESC, playlist_id ASC").Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count playlist plays: %w", err)
+	}
+	return counts, nil
+}
+
+// HourlyPlays nombre de lectures de chaque heure UTC de la période qui en compte au moins une.
+// Chaque lecture appartient à une seule playlist : les compteurs des playlists totalisent l'heure.
+func (r *StatsRepository) HourlyPlays(from time.Time, to time.Time) ([]models.PlayBucket, error) {
+	var buckets []models.PlayBucket
+	if alignedOnHours(from, to) {
+		err := r.DB.Table("play_rollups").
+			Select("play_rollups.bucket_start AS start, SUM(play_rollups.plays) AS plays").
+			Where("play_rollups.subject = ? AND play_rollups.granularity = ?", models.RollupPlaylist, models.RollupHour).
+			Where("play_rollups.bucket_start >= ? AND play_rollups.bucket_start < ?", from.UTC(), to.UTC()).
+			Group("play_rollups.bucket_start").
+			Order("play_rollups.bucket_start ASC").
+			Scan(&buckets).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to count hourly plays: %w", err)
+		}
+		return buckets, nil
+	}
+
+	var playedAt []time.Time
+	if err := r.plays(from, to).Order("track_plays.played_at ASC").Pluck("track_plays.played_at", &playedAt).Error; err != nil {
+		return nil, fmt.Errorf("failed to list play times: %w", err)
+	}
+	for _, at := range playedAt {
+		start := at.UTC().Truncate(time.Hour)
+		if len(buckets) > 0 && buckets[len(buckets)-1].Start.Equal(start) {
+			buckets[len(buckets)-1].Plays++
+			continue
+		}
+		buckets = append(buckets, models.PlayBucket{Start: start, Plays: 1})
+	}
+	return buckets, nil
+}
+
+// PlayTimes retourne les dates de lecture de la période, pour les histogrammes dans un fuseau
+// dont le décalage avec UTC n'est pas un nombre entier d'heures
+func (r *StatsRepository) PlayTimes(from time.Time, to time.Time) ([]time.Time, error) {
+	var playedAt []time.Time
+	if err := r.plays(from, to).Pluck("track_plays.played_at", &playedAt).Error; err != nil {
+		return nil, fmt.Errorf("failed to list play times: %w", err)
+	}
+	return playedAt, nil
+}
+
+// RebuildRollups recalcule tous les compteurs pré-agrégés à partir de l'historique track_plays
+// et retourne le nombre de compteurs écrits
+func (r *StatsRepository) RebuildRollups() (int, error) {
+	set := rollupSet{}
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM play_rollups").Error; err != nil {
+			return fmt.Errorf("failed to delete play rollups: %w", err)
+		}
+
+		var lastID int64
+		for {
+			var plays []playWithArtist
+			err := tx.Table("track_plays").
+				Select("track_plays.*, COALESCE(tracks.artist_id, 0) AS artist_id").
+				Joins("LEFT JOIN tracks ON tracks.id = track_plays.track_id").
+				Where("track_plays.id > ?", lastID).
+				Order("track_plays.id ASC").
+				Limit(rebuildBatchSize).
+				Scan(&plays).Error
+			if err != nil {
+				return fmt.Errorf("failed to get track plays: %w", err)
+			}
+			if len(plays) == 0 {
+				break
+			}
+
+			for i := range plays {
+				set.add(&plays[i].TrackPlay, plays[i].ArtistID, deltaOf(&plays[i].TrackPlay))
+			}
+			lastID = plays[len(plays)-1].ID
+		}
+
+		return set.save(tx)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(set), nil
+}
+
+// plays filtre l'historique sur la période, bornes en UTC comme les dates de lecture enregistrées
+func (r *StatsRepository) plays(from time.Time, to time.Time) *gorm.DB {
+	return r.DB.Table("track_plays").
+		Where("track_plays.played_at >= ? AND track_plays.played_at < ?", from.UTC(), to.UTC())
+}
+
+// rollups sélectionne les compteurs qui couvrent exactement la période : les jours UTC entiers,
+// complétés par les heures qui les précèdent et les suivent
+func (r *StatsRepository) rollups(subject models.RollupSubject, from time.Time, to time.Time) *gorm.DB {
+	from, to = from.UTC(), to.UTC()
+	dayStart := from.Truncate(day)
+	if dayStart.Before(from) {
+		dayStart = dayStart.Add(day)
+	}
+	dayEnd := to.Truncate(day)
+	if !dayStart.Before(dayEnd) {
+		dayStart, dayEnd = to, to
+	}
+
+	bucket := "(play_rollups.granularity = ? AND play_rollups.bucket_start >= ? AND play_rollups.bucket_start < ?)"
+	return r.DB.Table("play_rollups").
+		Where("play_rollups.subject = ?", subject).
+		Where(bucket+" OR "+bucket+" OR "+bucket,
+			models.RollupDay, dayStart, dayEnd,
+			models.RollupHour, from, dayStart,
+			models.RollupHour, dayEnd, to)
+}
+
+// alignedOnHours indique si la période peut être lue dans les compteurs horaires
+func alignedOnHours(from time.Time, to time.Time) bool {
+	return from.Equal(from.Truncate(time.Hour)) && to.Equal(to.Truncate(time.Hour))
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"radioking-app/internal/domain/models"
+	"time"
+)
+
+type IStatsRepository interface {
+	TopTracks(from time.Time, to time.Time, limit int) ([]models.TrackPlayCount, error)
+	TopArtists(from time.Time, to time.Time, limit int) ([]models.ArtistPlayCount, error)
+	PlaylistTotals(from time.Time, to time.Time) ([]models.PlaylistPlayCount, error)
+	HourlyPlays(from time.Time, to time.Time) ([]models.PlayBucket, error)
+	PlayTimes(from time.Time, to time.Time) ([]time.Time, error)
+	RebuildRollups() (int, error)
+}
@@ -1,11 +1,17 @@
 package repositories
 
 import (
+	"errors"
 	"radioking-app/internal/domain/models"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrDuplicateEvent l'événement a déjà été enregistré, il s'agit d'une redélivrance
+var ErrDuplicateEvent = errors.New("event already recorded")
+
 type TrackPlayRepository struct {
 	DB *gorm.DB
 }
@@ -14,14 +20,93 @@ func NewTrackPlayRepository(db *gorm.DB) *TrackPlayRepository {
 	return &TrackPlayRepository{DB: db}
 }
 
+// Create ignore une lecture de session déjà enregistrée, par exemple quand sa fin a été reçue en premier :
+// l'identifiant de l'événement lui est alors rattaché. Un événement déjà enregistré retourne ErrDuplicateEvent.
+// Les compteurs pré-agrégés sont mis à jour dans la même transaction.
 func (r *TrackPlayRepository) Create(trackPlay *models.TrackPlay) error {
-	return r.DB.Create(trackPlay).Error
+	toUTC(trackPlay)
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(trackPlay)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return attachLateEvent(tx, trackPlay)
+		}
+		return applyRollupDelta(tx, trackPlay, deltaOf(trackPlay))
+	})
+}
+
+// attachLateEvent rattache l'événement de début à la lecture de session créée par sa fin.
+// Sans lecture en attente de son début, l'événement est une redélivrance.
+func attachLateEvent(tx *gorm.DB, trackPlay *models.TrackPlay) error {
+	if trackPlay.EventID == nil {
+		return nil
+	}
+
+	result := tx.Model(&models.TrackPlay{}).
+		Where("session_id = ? AND sequence = ? AND event_id IS NULL", trackPlay.SessionID, trackPlay.Sequence).
+		Update("event_id", *trackPlay.EventID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDuplicateEvent
+	}
+	return nil
+}
+
+// RecordEnd complète la lecture identifiée par sa session et son rang, ou la crée si elle est inconnue.
+// Les compteurs reçoivent la différence avec l'état précédent de la lecture. Une fin déjà
+// enregistrée avec le même événement retourne ErrDuplicateEvent.
+func (r *TrackPlayRepository) RecordEnd(trackPlay *models.TrackPlay) error {
+	toUTC(trackPlay)
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var existing models.TrackPlay
+		err := tx.Where("session_id = ? AND sequence = ?", trackPlay.SessionID, trackPlay.Sequence).Take(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if err := tx.Create(trackPlay).Error; err != nil {
+				return err
+			}
+			return applyRollupDelta(tx, trackPlay, deltaOf(trackPlay))
+		}
+		if err != nil {
+			return err
+		}
+		if trackPlay.EndEventID != nil && existing.EndEventID != nil && *existing.EndEventID == *trackPlay.EndEventID {
+			return ErrDuplicateEvent
+		}
+
+		// Updates recopie les nouvelles valeurs dans existing : l'état précédent est lu avant
+		previous := deltaOf(&existing)
+		err = tx.Model(&existing).Select("ended_at", "played_seconds", "end_reason", "end_event_id").Updates(models.TrackPlay{
+			EndedAt:       trackPlay.EndedAt,
+			PlayedSeconds: trackPlay.PlayedSeconds,
+			EndReason:     trackPlay.EndReason,
+			EndEventID:    trackPlay.EndEventID,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		existing.EndedAt, existing.PlayedSeconds, existing.EndReason = trackPlay.EndedAt, trackPlay.PlayedSeconds, trackPlay.EndReason
+		existing.EndEventID = trackPlay.EndEventID
+		*trackPlay = existing
+		return applyRollupDelta(tx, &existing, deltaOf(&existing).minus(previous))
+	})
+}
+
+// unscoped précharge aussi les lignes supprimées logiquement, toujours référencées par l'historique
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
 }
 
-func (r *TrackPlayRepository) GetByPlaylistID(playlistID int) ([]*models.TrackPlay, error) {
+// ListSince retourne les lectures démarrées depuis since avec leur track, supprimée ou non, pour les règles de rotation
+func (r *TrackPlayRepository) ListSince(since time.Time) ([]*models.TrackPlay, error) {
 	var trackPlays []*models.TrackPlay
-	err := r.DB.Where("playlist_id = ?", playlistID).
-		Order("played_at DESC").
+	err := r.DB.Preload("Track", unscoped).
+		Where("played_at >= ?", since.UTC()).
+		Order("played_at ASC").
 		Find(&trackPlays).Error
 
 	if err != nil {
@@ -31,10 +116,12 @@ func (r *TrackPlayRepository) GetByPlaylistID(playlistID int) ([]*models.TrackPl
 	return trackPlays, nil
 }
 
-func (r *TrackPlayRepository) GetByTrackID(trackID int) ([]*models.TrackPlay, error) {
+// GetByPlaylistID retourne au plus limit lectures de la playlist démarrées dans [from, to), les plus récentes en premier
+func (r *TrackPlayRepository) GetByPlaylistID(playlistID int, from, to time.Time, limit int) ([]*models.TrackPlay, error) {
 	var trackPlays []*models.TrackPlay
-	err := r.DB.Where("track_id = ?", trackID).
-		Order("played_at DESC").
+	err := r.DB.Where("playlist_id = ? AND played_at >= ? AND played_at < ?", playlistID, from.UTC(), to.UTC()).
+		Order("played_at DESC, id DESC").
+		Limit(limit).
 		Find(&trackPlays).Error
 
 	if err != nil {
@@ -43,3 +130,28 @@ func (r *TrackPlayRepository) GetByTrackID(trackID int) ([]*models.TrackPlay, er
 
 	return trackPlays, nil
 }
+
+// GetByTrackID retourne au plus limit lectures de la track démarrées dans [from, to), les plus récentes en premier
+func (r *TrackPlayRepository) GetByTrackID(trackID int, from, to time.Time, limit int) ([]*models.TrackPlay, error) {
+	var trackPlays []*models.TrackPlay
+	err := r.DB.Where("track_id = ? AND played_at >= ? AND played_at < ?", trackID, from.UTC(), to.UTC()).
+		Order("played_at DESC, id DESC").
+		Limit(limit).
+		Find(&trackPlays).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return trackPlays, nil
+}
+
+// toUTC enregistre les dates en UTC : SQLite compare les dates comme du texte, les filtres
+// sur played_at ne sont fiables que si toutes les dates partagent le même fuseau
+func toUTC(trackPlay *models.TrackPlay) {
+	trackPlay.PlayedAt = trackPlay.PlayedAt.UTC()
+	if trackPlay.EndedAt != nil {
+		endedAt := trackPlay.EndedAt.UTC()
+		trackPlay.EndedAt = &endedAt
+	}
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"fmt"
+	"radioking-app/internal/domain/models"
+
+	"gorm.io/gorm"
+)
+
+type TrackRepository struct {
+	DB *gorm.DB
+}
+
+func NewTrackRepository(db *gorm.DB) *TrackRepository {
+	return &TrackRepository{DB: db}
+}
+
+// Create rattache la track à son artiste et à son album avant de l'enregistrer
+func (r *TrackRepository) Create(track *models.Track) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := prepareCatalogTrack(tx, track); err != nil {
+			return err
+		}
+		if err := tx.Omit("Album").Create(track).Error; err != nil {
+			return fmt.Errorf("failed to create track in database: %w", err)
+		}
+		return nil
+	})
+}
+
+// List retourne au plus limit tracks du catalogue d'ID supérieur à afterID
+func (r *TrackRepository) List(limit int, afterID int64) ([]*models.Track, error) {
+	var tracks []*models.Track
+	err := r.DB.Preload("Album").
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&tracks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tracks from database: %w", err)
+	}
+	return tracks, nil
+}
+
+func (r *TrackRepository) GetByID(id int) (*models.Track, error) {
+	var track models.Track
+	if err := r.DB.Preload("Album").First(&track, id).Error; err != nil {
+		return nil, err
+	}
+	return &track, nil
+}
+
+// FindByTitleAndArtist compare les noms normalisés : "Queen" et "queen " désignent le même artiste
+func (r *TrackRepository) FindByTitleAndArtist(title string, artist string) (*models.Track, error) {
+	var found models.Artist
+	if err := r.DB.Where("normalized_name = ?", models.NormalizeName(artist)).First(&found).Error; err != nil {
+		return nil, err
+	}
+	return findCatalogTrack(r.DB, found.ID, models.NormalizeName(title))
+}
+
+// Search retourne les tracks du catalogue correspondant aux critères, par ID croissant
+func (r *TrackRepository) Search(criteria models.TrackCriteria) ([]*models.Track, error) {
+	db := r.DB.Model(&models.Track{})
+	if criteria.ArtistID != nil {
+		db = db.Where("artist_id = ?", *criteria.ArtistID)
+	}
+	if criteria.AlbumID != nil {
+		db = db.Where("album_id = ?", *criteria.AlbumID)
+	}
+
+	var tracks []*models.Track
+	if err := db.Order("id ASC").Limit(criteria.Limit).Find(&tracks).Error; err != nil {
+		return nil, fmt.Errorf("failed to search tracks: %w", err)
+	}
+	return tracks, nil
+}
+
+func (r *TrackRepository) Update(track *models.Track) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := prepareCatalogTrack(tx, track); err != nil {
+			return err
+		}
+		if err := tx.Model(track).Omit("Album").Updates(map[string]interface{}{
+			"title":            track.Title,
+			"normalized_title": track.NormalizedTitle,
+			"artist":           track.Artist,
+			"artist_id":        track.ArtistID,
+			"album_id":         track.AlbumID,
+			"duration_seconds": track.DurationSeconds,
+			"isrc":             track.ISRC,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to update track in database: %w", err)
+		}
+		return nil
+	})
+}
+
+// Delete retire la track de toutes les playlists puis la supprime logiquement pour conserver son
+// historique de lecture. Les événements de chaque playlist modifiée sont enregistrés dans la même transaction.
+func (r *TrackRepository) Delete(id int, events PlaylistEvents) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.Track{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete track from database: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		var entries []models.PlaylistTrack
+		if err := tx.Where("track_id = ?", id).Order("playlist_id ASC").Find(&entries).Error; err != nil {
+			return fmt.Errorf("failed to get playlist tracks: %w", err)
+		}
+		for i := range entries {
+			if err := removePlaylistTrack(tx, &entries[i]); err != nil {
+				return err
+			}
+			if err := createPlaylistEvents(tx, entries[i].PlaylistID, events); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import "radioking-app/internal/domain/models"
+
+type ITrackRepository interface {
+	Create(track *models.Track) error
+	List(limit int, afterID int64) ([]*models.Track, error)
+	GetByID(id int) (*models.Track, error)
+	FindByTitleAndArtist(title string, artist string) (*models.Track, error)
+	Search(criteria models.TrackCriteria) ([]*models.Track, error)
+	Update(track *models.Track) error
+	Delete(id int, events PlaylistEvents) error
+}